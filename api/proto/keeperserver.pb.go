@@ -72,6 +72,53 @@ func (SecretType) EnumDescriptor() ([]byte, []int) {
 	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{0}
 }
 
+// SecretSortField order of listed secrets, names are encrypted by client, so client sorts secrets by name itself
+type SecretSortField int32
+
+const (
+	SecretSortField_CREATED SecretSortField = 0
+	SecretSortField_UPDATED SecretSortField = 1
+)
+
+// Enum value maps for SecretSortField.
+var (
+	SecretSortField_name = map[int32]string{
+		0: "CREATED",
+		1: "UPDATED",
+	}
+	SecretSortField_value = map[string]int32{
+		"CREATED": 0,
+		"UPDATED": 1,
+	}
+)
+
+func (x SecretSortField) Enum() *SecretSortField {
+	p := new(SecretSortField)
+	*p = x
+	return p
+}
+
+func (x SecretSortField) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SecretSortField) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_proto_keeperserver_proto_enumTypes[1].Descriptor()
+}
+
+func (SecretSortField) Type() protoreflect.EnumType {
+	return &file_api_proto_keeperserver_proto_enumTypes[1]
+}
+
+func (x SecretSortField) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SecretSortField.Descriptor instead.
+func (SecretSortField) EnumDescriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{1}
+}
+
 type PingRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -289,8 +336,19 @@ type MediaSecretMetadata struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
+	// name blind index of media filename
 	Name      string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Overwrite bool   `protobuf:"varint,2,opt,name=overwrite,proto3" json:"overwrite,omitempty"`
+	// encrypted_name media filename encrypted by client
+	EncryptedName []byte `protobuf:"bytes,3,opt,name=encrypted_name,json=encryptedName,proto3" json:"encrypted_name,omitempty"`
+	// expire_timestamp unix time after that media will be removed, 0 for endless media
+	ExpireTimestamp int64 `protobuf:"varint,4,opt,name=expire_timestamp,json=expireTimestamp,proto3" json:"expire_timestamp,omitempty"`
+	// max_reads count of downloads after that media will be removed, 0 for unlimited downloads
+	MaxReads int32 `protobuf:"varint,5,opt,name=max_reads,json=maxReads,proto3" json:"max_reads,omitempty"`
+	// folder blind index of folder that contains media
+	Folder string `protobuf:"bytes,6,opt,name=folder,proto3" json:"folder,omitempty"`
+	// encrypted_info media info encrypted by client: plain size, content type, checksum, original path and description
+	EncryptedInfo []byte `protobuf:"bytes,7,opt,name=encrypted_info,json=encryptedInfo,proto3" json:"encrypted_info,omitempty"`
 }
 
 func (x *MediaSecretMetadata) Reset() {
@@ -339,6 +397,41 @@ func (x *MediaSecretMetadata) GetOverwrite() bool {
 	return false
 }
 
+func (x *MediaSecretMetadata) GetEncryptedName() []byte {
+	if x != nil {
+		return x.EncryptedName
+	}
+	return nil
+}
+
+func (x *MediaSecretMetadata) GetExpireTimestamp() int64 {
+	if x != nil {
+		return x.ExpireTimestamp
+	}
+	return 0
+}
+
+func (x *MediaSecretMetadata) GetMaxReads() int32 {
+	if x != nil {
+		return x.MaxReads
+	}
+	return 0
+}
+
+func (x *MediaSecretMetadata) GetFolder() string {
+	if x != nil {
+		return x.Folder
+	}
+	return ""
+}
+
+func (x *MediaSecretMetadata) GetEncryptedInfo() []byte {
+	if x != nil {
+		return x.EncryptedInfo
+	}
+	return nil
+}
+
 type MediaSecret struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -386,22 +479,131 @@ func (x *MediaSecret) GetChunk() []byte {
 	return nil
 }
 
+// MediaUploadResume first package of stream that continues upload session
+type MediaUploadResume struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SessionId string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// offset count of bytes committed by session, data of stream continues from it
+	Offset int64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+}
+
+func (x *MediaUploadResume) Reset() {
+	*x = MediaUploadResume{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MediaUploadResume) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MediaUploadResume) ProtoMessage() {}
+
+func (x *MediaUploadResume) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MediaUploadResume.ProtoReflect.Descriptor instead.
+func (*MediaUploadResume) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MediaUploadResume) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *MediaUploadResume) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+// MediaUploadTrailer last package of upload stream, service compares checksum with received content
+type MediaUploadTrailer struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// sha256 checksum of whole media content, for upload session it includes content of previous streams
+	Sha256 []byte `protobuf:"bytes,1,opt,name=sha256,proto3" json:"sha256,omitempty"`
+}
+
+func (x *MediaUploadTrailer) Reset() {
+	*x = MediaUploadTrailer{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MediaUploadTrailer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MediaUploadTrailer) ProtoMessage() {}
+
+func (x *MediaUploadTrailer) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MediaUploadTrailer.ProtoReflect.Descriptor instead.
+func (*MediaUploadTrailer) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *MediaUploadTrailer) GetSha256() []byte {
+	if x != nil {
+		return x.Sha256
+	}
+	return nil
+}
+
+// UploadMediaSecretRequest first package of stream is metadata for upload by one stream
+// or resume for upload by session, other packages are data and optional trailer at the end
 type UploadMediaSecretRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	// Types that are assignable to Request:
-	//
 	//	*UploadMediaSecretRequest_Metadata
 	//	*UploadMediaSecretRequest_Data
+	//	*UploadMediaSecretRequest_Resume
+	//	*UploadMediaSecretRequest_Trailer
 	Request isUploadMediaSecretRequest_Request `protobuf_oneof:"request"`
 }
 
 func (x *UploadMediaSecretRequest) Reset() {
 	*x = UploadMediaSecretRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_keeperserver_proto_msgTypes[6]
+		mi := &file_api_proto_keeperserver_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -414,7 +616,7 @@ func (x *UploadMediaSecretRequest) String() string {
 func (*UploadMediaSecretRequest) ProtoMessage() {}
 
 func (x *UploadMediaSecretRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_keeperserver_proto_msgTypes[6]
+	mi := &file_api_proto_keeperserver_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -427,7 +629,7 @@ func (x *UploadMediaSecretRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadMediaSecretRequest.ProtoReflect.Descriptor instead.
 func (*UploadMediaSecretRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{6}
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{8}
 }
 
 func (m *UploadMediaSecretRequest) GetRequest() isUploadMediaSecretRequest_Request {
@@ -451,6 +653,20 @@ func (x *UploadMediaSecretRequest) GetData() *MediaSecret {
 	return nil
 }
 
+func (x *UploadMediaSecretRequest) GetResume() *MediaUploadResume {
+	if x, ok := x.GetRequest().(*UploadMediaSecretRequest_Resume); ok {
+		return x.Resume
+	}
+	return nil
+}
+
+func (x *UploadMediaSecretRequest) GetTrailer() *MediaUploadTrailer {
+	if x, ok := x.GetRequest().(*UploadMediaSecretRequest_Trailer); ok {
+		return x.Trailer
+	}
+	return nil
+}
+
 type isUploadMediaSecretRequest_Request interface {
 	isUploadMediaSecretRequest_Request()
 }
@@ -463,10 +679,22 @@ type UploadMediaSecretRequest_Data struct {
 	Data *MediaSecret `protobuf:"bytes,2,opt,name=data,proto3,oneof"`
 }
 
+type UploadMediaSecretRequest_Resume struct {
+	Resume *MediaUploadResume `protobuf:"bytes,3,opt,name=resume,proto3,oneof"`
+}
+
+type UploadMediaSecretRequest_Trailer struct {
+	Trailer *MediaUploadTrailer `protobuf:"bytes,4,opt,name=trailer,proto3,oneof"`
+}
+
 func (*UploadMediaSecretRequest_Metadata) isUploadMediaSecretRequest_Request() {}
 
 func (*UploadMediaSecretRequest_Data) isUploadMediaSecretRequest_Request() {}
 
+func (*UploadMediaSecretRequest_Resume) isUploadMediaSecretRequest_Request() {}
+
+func (*UploadMediaSecretRequest_Trailer) isUploadMediaSecretRequest_Request() {}
+
 type UploadMediaSecretResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -480,7 +708,7 @@ type UploadMediaSecretResponse struct {
 func (x *UploadMediaSecretResponse) Reset() {
 	*x = UploadMediaSecretResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_keeperserver_proto_msgTypes[7]
+		mi := &file_api_proto_keeperserver_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -493,7 +721,7 @@ func (x *UploadMediaSecretResponse) String() string {
 func (*UploadMediaSecretResponse) ProtoMessage() {}
 
 func (x *UploadMediaSecretResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_keeperserver_proto_msgTypes[7]
+	mi := &file_api_proto_keeperserver_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -506,7 +734,7 @@ func (x *UploadMediaSecretResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UploadMediaSecretResponse.ProtoReflect.Descriptor instead.
 func (*UploadMediaSecretResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{7}
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *UploadMediaSecretResponse) GetUuid() string {
@@ -535,13 +763,18 @@ type DownloadMediaSecretRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
+	// secret_name blind index of media filename
 	SecretName string `protobuf:"bytes,1,opt,name=secret_name,json=secretName,proto3" json:"secret_name,omitempty"`
+	// offset first byte of media content to download, for chunked media it must be start of chunk
+	Offset int64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// length count of bytes to download, 0 for content until end. Chunked media is sent by whole chunks
+	Length int64 `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"`
 }
 
 func (x *DownloadMediaSecretRequest) Reset() {
 	*x = DownloadMediaSecretRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_keeperserver_proto_msgTypes[8]
+		mi := &file_api_proto_keeperserver_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -554,7 +787,7 @@ func (x *DownloadMediaSecretRequest) String() string {
 func (*DownloadMediaSecretRequest) ProtoMessage() {}
 
 func (x *DownloadMediaSecretRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_keeperserver_proto_msgTypes[8]
+	mi := &file_api_proto_keeperserver_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -567,7 +800,7 @@ func (x *DownloadMediaSecretRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DownloadMediaSecretRequest.ProtoReflect.Descriptor instead.
 func (*DownloadMediaSecretRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{8}
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *DownloadMediaSecretRequest) GetSecretName() string {
@@ -577,18 +810,37 @@ func (x *DownloadMediaSecretRequest) GetSecretName() string {
 	return ""
 }
 
+func (x *DownloadMediaSecretRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *DownloadMediaSecretRequest) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
 type DownloadMediaSecretResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	SecretPart *MediaSecret `protobuf:"bytes,1,opt,name=secretPart,proto3" json:"secretPart,omitempty"`
+	// chunked true if media is stored by chunks, then every part is one whole encrypted chunk
+	Chunked bool `protobuf:"varint,2,opt,name=chunked,proto3" json:"chunked,omitempty"`
+	// sha256 checksum of whole media content, set in first part if service knows it. Chunks of chunked media
+	// are verified by their hashes, so it's empty for them
+	Sha256 []byte `protobuf:"bytes,3,opt,name=sha256,proto3" json:"sha256,omitempty"`
 }
 
 func (x *DownloadMediaSecretResponse) Reset() {
 	*x = DownloadMediaSecretResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_keeperserver_proto_msgTypes[9]
+		mi := &file_api_proto_keeperserver_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -601,7 +853,7 @@ func (x *DownloadMediaSecretResponse) String() string {
 func (*DownloadMediaSecretResponse) ProtoMessage() {}
 
 func (x *DownloadMediaSecretResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_keeperserver_proto_msgTypes[9]
+	mi := &file_api_proto_keeperserver_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -614,7 +866,7 @@ func (x *DownloadMediaSecretResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DownloadMediaSecretResponse.ProtoReflect.Descriptor instead.
 func (*DownloadMediaSecretResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{9}
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *DownloadMediaSecretResponse) GetSecretPart() *MediaSecret {
@@ -624,35 +876,45 @@ func (x *DownloadMediaSecretResponse) GetSecretPart() *MediaSecret {
 	return nil
 }
 
-type Secret struct {
+func (x *DownloadMediaSecretResponse) GetChunked() bool {
+	if x != nil {
+		return x.Chunked
+	}
+	return false
+}
+
+func (x *DownloadMediaSecretResponse) GetSha256() []byte {
+	if x != nil {
+		return x.Sha256
+	}
+	return nil
+}
+
+type MediaUploadSessionRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SecretType      SecretType `protobuf:"varint,1,opt,name=secret_type,json=secretType,proto3,enum=keeperservice.grpc.SecretType" json:"secret_type,omitempty"`
-	Name            string     `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	CreateTimestamp int64      `protobuf:"varint,3,opt,name=create_timestamp,json=createTimestamp,proto3" json:"create_timestamp,omitempty"`
-	UpdateTimestamp int64      `protobuf:"varint,4,opt,name=update_timestamp,json=updateTimestamp,proto3" json:"update_timestamp,omitempty"`
-	Content         []byte     `protobuf:"bytes,5,opt,name=content,proto3" json:"content,omitempty"`
+	SessionId string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
 }
 
-func (x *Secret) Reset() {
-	*x = Secret{}
+func (x *MediaUploadSessionRequest) Reset() {
+	*x = MediaUploadSessionRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_keeperserver_proto_msgTypes[10]
+		mi := &file_api_proto_keeperserver_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *Secret) String() string {
+func (x *MediaUploadSessionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Secret) ProtoMessage() {}
+func (*MediaUploadSessionRequest) ProtoMessage() {}
 
-func (x *Secret) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_keeperserver_proto_msgTypes[10]
+func (x *MediaUploadSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -663,72 +925,45 @@ func (x *Secret) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Secret.ProtoReflect.Descriptor instead.
-func (*Secret) Descriptor() ([]byte, []int) {
-	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{10}
-}
-
-func (x *Secret) GetSecretType() SecretType {
-	if x != nil {
-		return x.SecretType
-	}
-	return SecretType_CREDENTIALS
+// Deprecated: Use MediaUploadSessionRequest.ProtoReflect.Descriptor instead.
+func (*MediaUploadSessionRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *Secret) GetName() string {
+func (x *MediaUploadSessionRequest) GetSessionId() string {
 	if x != nil {
-		return x.Name
+		return x.SessionId
 	}
 	return ""
 }
 
-func (x *Secret) GetCreateTimestamp() int64 {
-	if x != nil {
-		return x.CreateTimestamp
-	}
-	return 0
-}
-
-func (x *Secret) GetUpdateTimestamp() int64 {
-	if x != nil {
-		return x.UpdateTimestamp
-	}
-	return 0
-}
-
-func (x *Secret) GetContent() []byte {
-	if x != nil {
-		return x.Content
-	}
-	return nil
-}
-
-type SecretListRequest struct {
+type MediaUploadSessionResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SecretType SecretType `protobuf:"varint,1,opt,name=secret_type,json=secretType,proto3,enum=keeperservice.grpc.SecretType" json:"secret_type,omitempty"`
-	Error      string     `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	SessionId string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// offset count of bytes committed by session
+	Offset int64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
 }
 
-func (x *SecretListRequest) Reset() {
-	*x = SecretListRequest{}
+func (x *MediaUploadSessionResponse) Reset() {
+	*x = MediaUploadSessionResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_keeperserver_proto_msgTypes[11]
+		mi := &file_api_proto_keeperserver_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SecretListRequest) String() string {
+func (x *MediaUploadSessionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SecretListRequest) ProtoMessage() {}
+func (*MediaUploadSessionResponse) ProtoMessage() {}
 
-func (x *SecretListRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_keeperserver_proto_msgTypes[11]
+func (x *MediaUploadSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -739,51 +974,51 @@ func (x *SecretListRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SecretListRequest.ProtoReflect.Descriptor instead.
-func (*SecretListRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use MediaUploadSessionResponse.ProtoReflect.Descriptor instead.
+func (*MediaUploadSessionResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *SecretListRequest) GetSecretType() SecretType {
+func (x *MediaUploadSessionResponse) GetSessionId() string {
 	if x != nil {
-		return x.SecretType
+		return x.SessionId
 	}
-	return SecretType_CREDENTIALS
+	return ""
 }
 
-func (x *SecretListRequest) GetError() string {
+func (x *MediaUploadSessionResponse) GetOffset() int64 {
 	if x != nil {
-		return x.Error
+		return x.Offset
 	}
-	return ""
+	return 0
 }
 
-type SecretListResponse struct {
+type MediaChunksRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Secrets []*Secret `protobuf:"bytes,1,rep,name=secrets,proto3" json:"secrets,omitempty"`
-	Error   string    `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	// hashes hex encoded sha256 of encrypted chunks
+	Hashes []string `protobuf:"bytes,1,rep,name=hashes,proto3" json:"hashes,omitempty"`
 }
 
-func (x *SecretListResponse) Reset() {
-	*x = SecretListResponse{}
+func (x *MediaChunksRequest) Reset() {
+	*x = MediaChunksRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_keeperserver_proto_msgTypes[12]
+		mi := &file_api_proto_keeperserver_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SecretListResponse) String() string {
+func (x *MediaChunksRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SecretListResponse) ProtoMessage() {}
+func (*MediaChunksRequest) ProtoMessage() {}
 
-func (x *SecretListResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_keeperserver_proto_msgTypes[12]
+func (x *MediaChunksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -794,52 +1029,44 @@ func (x *SecretListResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SecretListResponse.ProtoReflect.Descriptor instead.
-func (*SecretListResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use MediaChunksRequest.ProtoReflect.Descriptor instead.
+func (*MediaChunksRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *SecretListResponse) GetSecrets() []*Secret {
+func (x *MediaChunksRequest) GetHashes() []string {
 	if x != nil {
-		return x.Secrets
+		return x.Hashes
 	}
 	return nil
 }
 
-func (x *SecretListResponse) GetError() string {
-	if x != nil {
-		return x.Error
-	}
-	return ""
-}
-
-type SecretSetRequest struct {
+type MediaChunksResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SecretType SecretType `protobuf:"varint,1,opt,name=secret_type,json=secretType,proto3,enum=keeperservice.grpc.SecretType" json:"secret_type,omitempty"`
-	Name       string     `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Content    []byte     `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	// missing hashes of chunks that must be uploaded before commit of media
+	Missing []string `protobuf:"bytes,1,rep,name=missing,proto3" json:"missing,omitempty"`
 }
 
-func (x *SecretSetRequest) Reset() {
-	*x = SecretSetRequest{}
+func (x *MediaChunksResponse) Reset() {
+	*x = MediaChunksResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_keeperserver_proto_msgTypes[13]
+		mi := &file_api_proto_keeperserver_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SecretSetRequest) String() string {
+func (x *MediaChunksResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SecretSetRequest) ProtoMessage() {}
+func (*MediaChunksResponse) ProtoMessage() {}
 
-func (x *SecretSetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_keeperserver_proto_msgTypes[13]
+func (x *MediaChunksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -850,33 +1077,74 @@ func (x *SecretSetRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SecretSetRequest.ProtoReflect.Descriptor instead.
-func (*SecretSetRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use MediaChunksResponse.ProtoReflect.Descriptor instead.
+func (*MediaChunksResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *SecretSetRequest) GetSecretType() SecretType {
+func (x *MediaChunksResponse) GetMissing() []string {
 	if x != nil {
-		return x.SecretType
+		return x.Missing
 	}
-	return SecretType_CREDENTIALS
+	return nil
 }
 
-func (x *SecretSetRequest) GetName() string {
+type UploadMediaChunkRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hash string `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *UploadMediaChunkRequest) Reset() {
+	*x = UploadMediaChunkRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UploadMediaChunkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadMediaChunkRequest) ProtoMessage() {}
+
+func (x *UploadMediaChunkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadMediaChunkRequest.ProtoReflect.Descriptor instead.
+func (*UploadMediaChunkRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UploadMediaChunkRequest) GetHash() string {
 	if x != nil {
-		return x.Name
+		return x.Hash
 	}
 	return ""
 }
 
-func (x *SecretSetRequest) GetContent() []byte {
+func (x *UploadMediaChunkRequest) GetData() []byte {
 	if x != nil {
-		return x.Content
+		return x.Data
 	}
 	return nil
 }
 
-type SecretSetResponse struct {
+type UploadMediaChunkResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
@@ -884,23 +1152,23 @@ type SecretSetResponse struct {
 	Error string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
 }
 
-func (x *SecretSetResponse) Reset() {
-	*x = SecretSetResponse{}
+func (x *UploadMediaChunkResponse) Reset() {
+	*x = UploadMediaChunkResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_keeperserver_proto_msgTypes[14]
+		mi := &file_api_proto_keeperserver_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SecretSetResponse) String() string {
+func (x *UploadMediaChunkResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SecretSetResponse) ProtoMessage() {}
+func (*UploadMediaChunkResponse) ProtoMessage() {}
 
-func (x *SecretSetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_keeperserver_proto_msgTypes[14]
+func (x *UploadMediaChunkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -911,44 +1179,45 @@ func (x *SecretSetResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SecretSetResponse.ProtoReflect.Descriptor instead.
-func (*SecretSetResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use UploadMediaChunkResponse.ProtoReflect.Descriptor instead.
+func (*UploadMediaChunkResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *SecretSetResponse) GetError() string {
+func (x *UploadMediaChunkResponse) GetError() string {
 	if x != nil {
 		return x.Error
 	}
 	return ""
 }
 
-type SecretGetRequest struct {
+type CommitChunkedMediaRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SecretType SecretType `protobuf:"varint,1,opt,name=secret_type,json=secretType,proto3,enum=keeperservice.grpc.SecretType" json:"secret_type,omitempty"`
-	Name       string     `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Metadata *MediaSecretMetadata `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// hashes ordered chunks of media, all of them must be uploaded
+	Hashes []string `protobuf:"bytes,2,rep,name=hashes,proto3" json:"hashes,omitempty"`
 }
 
-func (x *SecretGetRequest) Reset() {
-	*x = SecretGetRequest{}
+func (x *CommitChunkedMediaRequest) Reset() {
+	*x = CommitChunkedMediaRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_keeperserver_proto_msgTypes[15]
+		mi := &file_api_proto_keeperserver_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SecretGetRequest) String() string {
+func (x *CommitChunkedMediaRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SecretGetRequest) ProtoMessage() {}
+func (*CommitChunkedMediaRequest) ProtoMessage() {}
 
-func (x *SecretGetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_keeperserver_proto_msgTypes[15]
+func (x *CommitChunkedMediaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -959,51 +1228,52 @@ func (x *SecretGetRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SecretGetRequest.ProtoReflect.Descriptor instead.
-func (*SecretGetRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use CommitChunkedMediaRequest.ProtoReflect.Descriptor instead.
+func (*CommitChunkedMediaRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *SecretGetRequest) GetSecretType() SecretType {
+func (x *CommitChunkedMediaRequest) GetMetadata() *MediaSecretMetadata {
 	if x != nil {
-		return x.SecretType
+		return x.Metadata
 	}
-	return SecretType_CREDENTIALS
+	return nil
 }
 
-func (x *SecretGetRequest) GetName() string {
+func (x *CommitChunkedMediaRequest) GetHashes() []string {
 	if x != nil {
-		return x.Name
+		return x.Hashes
 	}
-	return ""
+	return nil
 }
 
-type SecretGetResponse struct {
+type PresignedUploadRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Secret *Secret `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
-	Error  string  `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Metadata *MediaSecretMetadata `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// size count of bytes of media content
+	Size int64 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
 }
 
-func (x *SecretGetResponse) Reset() {
-	*x = SecretGetResponse{}
+func (x *PresignedUploadRequest) Reset() {
+	*x = PresignedUploadRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_keeperserver_proto_msgTypes[16]
+		mi := &file_api_proto_keeperserver_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SecretGetResponse) String() string {
+func (x *PresignedUploadRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SecretGetResponse) ProtoMessage() {}
+func (*PresignedUploadRequest) ProtoMessage() {}
 
-func (x *SecretGetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_keeperserver_proto_msgTypes[16]
+func (x *PresignedUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1014,52 +1284,54 @@ func (x *SecretGetResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SecretGetResponse.ProtoReflect.Descriptor instead.
-func (*SecretGetResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use PresignedUploadRequest.ProtoReflect.Descriptor instead.
+func (*PresignedUploadRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *SecretGetResponse) GetSecret() *Secret {
+func (x *PresignedUploadRequest) GetMetadata() *MediaSecretMetadata {
 	if x != nil {
-		return x.Secret
+		return x.Metadata
 	}
 	return nil
 }
 
-func (x *SecretGetResponse) GetError() string {
+func (x *PresignedUploadRequest) GetSize() int64 {
 	if x != nil {
-		return x.Error
+		return x.Size
 	}
-	return ""
+	return 0
 }
 
-type SecretUpdateRequest struct {
+type PresignedUploadResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SecretType SecretType `protobuf:"varint,1,opt,name=secret_type,json=secretType,proto3,enum=keeperservice.grpc.SecretType" json:"secret_type,omitempty"`
-	Name       string     `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Content    []byte     `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	// session_id upload session, that is committed by CommitMediaUpload or aborted by AbortMediaUpload
+	SessionId string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// part_urls URLs for HTTP PUT of content parts, part i contains content from i * part_size
+	PartUrls []string `protobuf:"bytes,2,rep,name=part_urls,json=partUrls,proto3" json:"part_urls,omitempty"`
+	PartSize int64    `protobuf:"varint,3,opt,name=part_size,json=partSize,proto3" json:"part_size,omitempty"`
 }
 
-func (x *SecretUpdateRequest) Reset() {
-	*x = SecretUpdateRequest{}
+func (x *PresignedUploadResponse) Reset() {
+	*x = PresignedUploadResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_keeperserver_proto_msgTypes[17]
+		mi := &file_api_proto_keeperserver_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SecretUpdateRequest) String() string {
+func (x *PresignedUploadResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SecretUpdateRequest) ProtoMessage() {}
+func (*PresignedUploadResponse) ProtoMessage() {}
 
-func (x *SecretUpdateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_keeperserver_proto_msgTypes[17]
+func (x *PresignedUploadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1070,57 +1342,59 @@ func (x *SecretUpdateRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SecretUpdateRequest.ProtoReflect.Descriptor instead.
-func (*SecretUpdateRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use PresignedUploadResponse.ProtoReflect.Descriptor instead.
+func (*PresignedUploadResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *SecretUpdateRequest) GetSecretType() SecretType {
+func (x *PresignedUploadResponse) GetSessionId() string {
 	if x != nil {
-		return x.SecretType
+		return x.SessionId
 	}
-	return SecretType_CREDENTIALS
+	return ""
 }
 
-func (x *SecretUpdateRequest) GetName() string {
+func (x *PresignedUploadResponse) GetPartUrls() []string {
 	if x != nil {
-		return x.Name
+		return x.PartUrls
 	}
-	return ""
+	return nil
 }
 
-func (x *SecretUpdateRequest) GetContent() []byte {
+func (x *PresignedUploadResponse) GetPartSize() int64 {
 	if x != nil {
-		return x.Content
+		return x.PartSize
 	}
-	return nil
+	return 0
 }
 
-type SecretUpdateResponse struct {
+type CommitMediaUploadRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Error string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	SessionId string `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// etags ETag headers of responses of part uploads in order of parts
+	Etags []string `protobuf:"bytes,2,rep,name=etags,proto3" json:"etags,omitempty"`
 }
 
-func (x *SecretUpdateResponse) Reset() {
-	*x = SecretUpdateResponse{}
+func (x *CommitMediaUploadRequest) Reset() {
+	*x = CommitMediaUploadRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_keeperserver_proto_msgTypes[18]
+		mi := &file_api_proto_keeperserver_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SecretUpdateResponse) String() string {
+func (x *CommitMediaUploadRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SecretUpdateResponse) ProtoMessage() {}
+func (*CommitMediaUploadRequest) ProtoMessage() {}
 
-func (x *SecretUpdateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_keeperserver_proto_msgTypes[18]
+func (x *CommitMediaUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1131,44 +1405,54 @@ func (x *SecretUpdateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SecretUpdateResponse.ProtoReflect.Descriptor instead.
-func (*SecretUpdateResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use CommitMediaUploadRequest.ProtoReflect.Descriptor instead.
+func (*CommitMediaUploadRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *SecretUpdateResponse) GetError() string {
+func (x *CommitMediaUploadRequest) GetSessionId() string {
 	if x != nil {
-		return x.Error
+		return x.SessionId
 	}
 	return ""
 }
 
-type SecretDeleteRequest struct {
+func (x *CommitMediaUploadRequest) GetEtags() []string {
+	if x != nil {
+		return x.Etags
+	}
+	return nil
+}
+
+type PresignedDownloadResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SecretType SecretType `protobuf:"varint,1,opt,name=secret_type,json=secretType,proto3,enum=keeperservice.grpc.SecretType" json:"secret_type,omitempty"`
-	SecretName string     `protobuf:"bytes,2,opt,name=secret_name,json=secretName,proto3" json:"secret_name,omitempty"`
+	// url URL for HTTP GET of media content
+	Url  string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Size int64  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	// sha256 checksum of media content, empty if service doesn't know it
+	Sha256 []byte `protobuf:"bytes,3,opt,name=sha256,proto3" json:"sha256,omitempty"`
 }
 
-func (x *SecretDeleteRequest) Reset() {
-	*x = SecretDeleteRequest{}
+func (x *PresignedDownloadResponse) Reset() {
+	*x = PresignedDownloadResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_keeperserver_proto_msgTypes[19]
+		mi := &file_api_proto_keeperserver_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SecretDeleteRequest) String() string {
+func (x *PresignedDownloadResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SecretDeleteRequest) ProtoMessage() {}
+func (*PresignedDownloadResponse) ProtoMessage() {}
 
-func (x *SecretDeleteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_keeperserver_proto_msgTypes[19]
+func (x *PresignedDownloadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1179,50 +1463,60 @@ func (x *SecretDeleteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SecretDeleteRequest.ProtoReflect.Descriptor instead.
-func (*SecretDeleteRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{19}
+// Deprecated: Use PresignedDownloadResponse.ProtoReflect.Descriptor instead.
+func (*PresignedDownloadResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *SecretDeleteRequest) GetSecretType() SecretType {
+func (x *PresignedDownloadResponse) GetUrl() string {
 	if x != nil {
-		return x.SecretType
+		return x.Url
 	}
-	return SecretType_CREDENTIALS
+	return ""
 }
 
-func (x *SecretDeleteRequest) GetSecretName() string {
+func (x *PresignedDownloadResponse) GetSize() int64 {
 	if x != nil {
-		return x.SecretName
+		return x.Size
 	}
-	return ""
+	return 0
 }
 
-type SecretDeleteResponse struct {
+func (x *PresignedDownloadResponse) GetSha256() []byte {
+	if x != nil {
+		return x.Sha256
+	}
+	return nil
+}
+
+// SecretTag free-form tag of secret
+type SecretTag struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Error string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	// tag blind index of tag
+	Tag          string `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	EncryptedTag []byte `protobuf:"bytes,2,opt,name=encrypted_tag,json=encryptedTag,proto3" json:"encrypted_tag,omitempty"`
 }
 
-func (x *SecretDeleteResponse) Reset() {
-	*x = SecretDeleteResponse{}
+func (x *SecretTag) Reset() {
+	*x = SecretTag{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_proto_keeperserver_proto_msgTypes[20]
+		mi := &file_api_proto_keeperserver_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SecretDeleteResponse) String() string {
+func (x *SecretTag) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SecretDeleteResponse) ProtoMessage() {}
+func (*SecretTag) ProtoMessage() {}
 
-func (x *SecretDeleteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_keeperserver_proto_msgTypes[20]
+func (x *SecretTag) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1233,107 +1527,2086 @@ func (x *SecretDeleteResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SecretDeleteResponse.ProtoReflect.Descriptor instead.
-func (*SecretDeleteResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use SecretTag.ProtoReflect.Descriptor instead.
+func (*SecretTag) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *SecretDeleteResponse) GetError() string {
+func (x *SecretTag) GetTag() string {
 	if x != nil {
-		return x.Error
+		return x.Tag
 	}
 	return ""
 }
 
-var File_api_proto_keeperserver_proto protoreflect.FileDescriptor
+func (x *SecretTag) GetEncryptedTag() []byte {
+	if x != nil {
+		return x.EncryptedTag
+	}
+	return nil
+}
 
-var file_api_proto_keeperserver_proto_rawDesc = []byte{
-	0x0a, 0x1c, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6b, 0x65, 0x65, 0x70,
-	0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x12,
-	0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72,
-	0x70, 0x63, 0x22, 0x27, 0x0a, 0x0b, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x3c, 0x0a, 0x0c, 0x50,
-	0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61,
-	0x6e, 0x73, 0x77, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x6e, 0x73,
-	0x77, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x4a, 0x0a, 0x16, 0x55, 0x73, 0x65,
-	0x72, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x05, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73,
-	0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73,
-	0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x45, 0x0a, 0x17, 0x55, 0x73, 0x65, 0x72, 0x43, 0x72, 0x65,
-	0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x47, 0x0a, 0x13,
-	0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64,
-	0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x76, 0x65, 0x72, 0x77,
-	0x72, 0x69, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x6f, 0x76, 0x65, 0x72,
-	0x77, 0x72, 0x69, 0x74, 0x65, 0x22, 0x23, 0x0a, 0x0b, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65,
-	0x63, 0x72, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x22, 0xa3, 0x01, 0x0a, 0x18, 0x55,
-	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x45, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64,
-	0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x6b, 0x65, 0x65, 0x70,
-	0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d,
-	0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61,
-	0x74, 0x61, 0x48, 0x00, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x35,
-	0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6b,
-	0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70,
-	0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x48, 0x00, 0x52,
-	0x04, 0x64, 0x61, 0x74, 0x61, 0x42, 0x09, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x22, 0x59, 0x0a, 0x19, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53,
-	0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a,
-	0x04, 0x75, 0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69,
-	0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x3d, 0x0a, 0x1a, 0x44,
-	0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72,
-	0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x63,
-	0x72, 0x65, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
-	0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x5e, 0x0a, 0x1b, 0x44, 0x6f,
-	0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65,
-	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x0a, 0x73, 0x65, 0x63,
-	0x72, 0x65, 0x74, 0x50, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e,
-	0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72,
-	0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x0a,
-	0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x50, 0x61, 0x72, 0x74, 0x22, 0xcd, 0x01, 0x0a, 0x06, 0x53,
-	0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x3f, 0x0a, 0x0b, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x5f,
-	0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x6b, 0x65, 0x65,
-	0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
-	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0a, 0x73, 0x65, 0x63, 0x72,
-	0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x72,
-	0x65, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65,
-	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x29, 0x0a, 0x10, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f,
-	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x0f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
-	0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x6a, 0x0a, 0x11, 0x53, 0x65,
-	0x63, 0x72, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x3f, 0x0a, 0x0b, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
-	0x54, 0x79, 0x70, 0x65, 0x52, 0x0a, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65,
-	0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x60, 0x0a, 0x12, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
-	0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x34, 0x0a, 0x07,
-	0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+type Secret struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SecretType SecretType `protobuf:"varint,1,opt,name=secret_type,json=secretType,proto3,enum=keeperservice.grpc.SecretType" json:"secret_type,omitempty"`
+	// name blind index of secret name (or plain name for secrets that are not migrated yet)
+	Name            string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CreateTimestamp int64  `protobuf:"varint,3,opt,name=create_timestamp,json=createTimestamp,proto3" json:"create_timestamp,omitempty"`
+	UpdateTimestamp int64  `protobuf:"varint,4,opt,name=update_timestamp,json=updateTimestamp,proto3" json:"update_timestamp,omitempty"`
+	Content         []byte `protobuf:"bytes,5,opt,name=content,proto3" json:"content,omitempty"`
+	// encrypted_name secret name encrypted by client, empty for not migrated secrets
+	EncryptedName   []byte `protobuf:"bytes,6,opt,name=encrypted_name,json=encryptedName,proto3" json:"encrypted_name,omitempty"`
+	ExpireTimestamp int64  `protobuf:"varint,7,opt,name=expire_timestamp,json=expireTimestamp,proto3" json:"expire_timestamp,omitempty"`
+	MaxReads        int32  `protobuf:"varint,8,opt,name=max_reads,json=maxReads,proto3" json:"max_reads,omitempty"`
+	Reads           int32  `protobuf:"varint,9,opt,name=reads,proto3" json:"reads,omitempty"`
+	// folder blind index of folder that contains secret
+	Folder string       `protobuf:"bytes,10,opt,name=folder,proto3" json:"folder,omitempty"`
+	Tags   []*SecretTag `protobuf:"bytes,11,rep,name=tags,proto3" json:"tags,omitempty"`
+	// size count of bytes of stored media content, 0 for plain secrets
+	Size int64 `protobuf:"varint,12,opt,name=size,proto3" json:"size,omitempty"`
+	// encrypted_media_info media info encrypted by client, empty for plain secrets and media uploaded without info
+	EncryptedMediaInfo []byte `protobuf:"bytes,13,opt,name=encrypted_media_info,json=encryptedMediaInfo,proto3" json:"encrypted_media_info,omitempty"`
+}
+
+func (x *Secret) Reset() {
+	*x = Secret{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Secret) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Secret) ProtoMessage() {}
+
+func (x *Secret) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Secret.ProtoReflect.Descriptor instead.
+func (*Secret) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *Secret) GetSecretType() SecretType {
+	if x != nil {
+		return x.SecretType
+	}
+	return SecretType_CREDENTIALS
+}
+
+func (x *Secret) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Secret) GetCreateTimestamp() int64 {
+	if x != nil {
+		return x.CreateTimestamp
+	}
+	return 0
+}
+
+func (x *Secret) GetUpdateTimestamp() int64 {
+	if x != nil {
+		return x.UpdateTimestamp
+	}
+	return 0
+}
+
+func (x *Secret) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *Secret) GetEncryptedName() []byte {
+	if x != nil {
+		return x.EncryptedName
+	}
+	return nil
+}
+
+func (x *Secret) GetExpireTimestamp() int64 {
+	if x != nil {
+		return x.ExpireTimestamp
+	}
+	return 0
+}
+
+func (x *Secret) GetMaxReads() int32 {
+	if x != nil {
+		return x.MaxReads
+	}
+	return 0
+}
+
+func (x *Secret) GetReads() int32 {
+	if x != nil {
+		return x.Reads
+	}
+	return 0
+}
+
+func (x *Secret) GetFolder() string {
+	if x != nil {
+		return x.Folder
+	}
+	return ""
+}
+
+func (x *Secret) GetTags() []*SecretTag {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Secret) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Secret) GetEncryptedMediaInfo() []byte {
+	if x != nil {
+		return x.EncryptedMediaInfo
+	}
+	return nil
+}
+
+type SecretListRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SecretType SecretType `protobuf:"varint,1,opt,name=secret_type,json=secretType,proto3,enum=keeperservice.grpc.SecretType" json:"secret_type,omitempty"`
+	Error      string     `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	// folder blind index of folder for list only its secrets, empty for secrets of all folders
+	Folder string `protobuf:"bytes,3,opt,name=folder,proto3" json:"folder,omitempty"`
+	// tag blind index of tag for list only marked secrets, empty for secrets with any tags
+	Tag string `protobuf:"bytes,4,opt,name=tag,proto3" json:"tag,omitempty"`
+	// page_size max count of secrets in response, 0 for default page size
+	PageSize int32 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// page_token next_page_token of previous response, empty for first page
+	PageToken  string          `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	Sort       SecretSortField `protobuf:"varint,7,opt,name=sort,proto3,enum=keeperservice.grpc.SecretSortField" json:"sort,omitempty"`
+	Descending bool            `protobuf:"varint,8,opt,name=descending,proto3" json:"descending,omitempty"`
+	// created_after, created_before, updated_after, updated_before unix time bounds (exclusive) of secret dates, 0 for unbounded
+	CreatedAfter  int64 `protobuf:"varint,9,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore int64 `protobuf:"varint,10,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	UpdatedAfter  int64 `protobuf:"varint,11,opt,name=updated_after,json=updatedAfter,proto3" json:"updated_after,omitempty"`
+	UpdatedBefore int64 `protobuf:"varint,12,opt,name=updated_before,json=updatedBefore,proto3" json:"updated_before,omitempty"`
+	// any_type lists secrets of all types, secret_type is ignored
+	AnyType bool `protobuf:"varint,13,opt,name=any_type,json=anyType,proto3" json:"any_type,omitempty"`
+}
+
+func (x *SecretListRequest) Reset() {
+	*x = SecretListRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretListRequest) ProtoMessage() {}
+
+func (x *SecretListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretListRequest.ProtoReflect.Descriptor instead.
+func (*SecretListRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SecretListRequest) GetSecretType() SecretType {
+	if x != nil {
+		return x.SecretType
+	}
+	return SecretType_CREDENTIALS
+}
+
+func (x *SecretListRequest) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *SecretListRequest) GetFolder() string {
+	if x != nil {
+		return x.Folder
+	}
+	return ""
+}
+
+func (x *SecretListRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *SecretListRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SecretListRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *SecretListRequest) GetSort() SecretSortField {
+	if x != nil {
+		return x.Sort
+	}
+	return SecretSortField_CREATED
+}
+
+func (x *SecretListRequest) GetDescending() bool {
+	if x != nil {
+		return x.Descending
+	}
+	return false
+}
+
+func (x *SecretListRequest) GetCreatedAfter() int64 {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return 0
+}
+
+func (x *SecretListRequest) GetCreatedBefore() int64 {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return 0
+}
+
+func (x *SecretListRequest) GetUpdatedAfter() int64 {
+	if x != nil {
+		return x.UpdatedAfter
+	}
+	return 0
+}
+
+func (x *SecretListRequest) GetUpdatedBefore() int64 {
+	if x != nil {
+		return x.UpdatedBefore
+	}
+	return 0
+}
+
+func (x *SecretListRequest) GetAnyType() bool {
+	if x != nil {
+		return x.AnyType
+	}
+	return false
+}
+
+type SecretListResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Secrets []*Secret `protobuf:"bytes,1,rep,name=secrets,proto3" json:"secrets,omitempty"`
+	Error   string    `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	// next_page_token token for request next page, empty for last page
+	NextPageToken string `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+}
+
+func (x *SecretListResponse) Reset() {
+	*x = SecretListResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretListResponse) ProtoMessage() {}
+
+func (x *SecretListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretListResponse.ProtoReflect.Descriptor instead.
+func (*SecretListResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SecretListResponse) GetSecrets() []*Secret {
+	if x != nil {
+		return x.Secrets
+	}
+	return nil
+}
+
+func (x *SecretListResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *SecretListResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type SecretSetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SecretType    SecretType `protobuf:"varint,1,opt,name=secret_type,json=secretType,proto3,enum=keeperservice.grpc.SecretType" json:"secret_type,omitempty"`
+	Name          string     `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Content       []byte     `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	EncryptedName []byte     `protobuf:"bytes,4,opt,name=encrypted_name,json=encryptedName,proto3" json:"encrypted_name,omitempty"`
+	// expire_timestamp unix time after that secret will be removed, 0 for endless secret
+	ExpireTimestamp int64 `protobuf:"varint,5,opt,name=expire_timestamp,json=expireTimestamp,proto3" json:"expire_timestamp,omitempty"`
+	// max_reads count of reads after that secret will be removed, 0 for unlimited reads
+	MaxReads int32 `protobuf:"varint,6,opt,name=max_reads,json=maxReads,proto3" json:"max_reads,omitempty"`
+	// folder blind index of folder that contains secret
+	Folder string `protobuf:"bytes,7,opt,name=folder,proto3" json:"folder,omitempty"`
+}
+
+func (x *SecretSetRequest) Reset() {
+	*x = SecretSetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretSetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretSetRequest) ProtoMessage() {}
+
+func (x *SecretSetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretSetRequest.ProtoReflect.Descriptor instead.
+func (*SecretSetRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *SecretSetRequest) GetSecretType() SecretType {
+	if x != nil {
+		return x.SecretType
+	}
+	return SecretType_CREDENTIALS
+}
+
+func (x *SecretSetRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SecretSetRequest) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *SecretSetRequest) GetEncryptedName() []byte {
+	if x != nil {
+		return x.EncryptedName
+	}
+	return nil
+}
+
+func (x *SecretSetRequest) GetExpireTimestamp() int64 {
+	if x != nil {
+		return x.ExpireTimestamp
+	}
+	return 0
+}
+
+func (x *SecretSetRequest) GetMaxReads() int32 {
+	if x != nil {
+		return x.MaxReads
+	}
+	return 0
+}
+
+func (x *SecretSetRequest) GetFolder() string {
+	if x != nil {
+		return x.Folder
+	}
+	return ""
+}
+
+type SecretSetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Error string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *SecretSetResponse) Reset() {
+	*x = SecretSetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretSetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretSetResponse) ProtoMessage() {}
+
+func (x *SecretSetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretSetResponse.ProtoReflect.Descriptor instead.
+func (*SecretSetResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SecretSetResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type SecretGetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SecretType SecretType `protobuf:"varint,1,opt,name=secret_type,json=secretType,proto3,enum=keeperservice.grpc.SecretType" json:"secret_type,omitempty"`
+	Name       string     `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *SecretGetRequest) Reset() {
+	*x = SecretGetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretGetRequest) ProtoMessage() {}
+
+func (x *SecretGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretGetRequest.ProtoReflect.Descriptor instead.
+func (*SecretGetRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SecretGetRequest) GetSecretType() SecretType {
+	if x != nil {
+		return x.SecretType
+	}
+	return SecretType_CREDENTIALS
+}
+
+func (x *SecretGetRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type SecretGetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Secret *Secret `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	Error  string  `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *SecretGetResponse) Reset() {
+	*x = SecretGetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretGetResponse) ProtoMessage() {}
+
+func (x *SecretGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretGetResponse.ProtoReflect.Descriptor instead.
+func (*SecretGetResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SecretGetResponse) GetSecret() *Secret {
+	if x != nil {
+		return x.Secret
+	}
+	return nil
+}
+
+func (x *SecretGetResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type SecretUpdateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SecretType SecretType `protobuf:"varint,1,opt,name=secret_type,json=secretType,proto3,enum=keeperservice.grpc.SecretType" json:"secret_type,omitempty"`
+	Name       string     `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Content    []byte     `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	// replace_limits replaces limits of secret by expire_timestamp and max_reads, reads of secret are counted again
+	ReplaceLimits bool `protobuf:"varint,4,opt,name=replace_limits,json=replaceLimits,proto3" json:"replace_limits,omitempty"`
+	// expire_timestamp unix time after that secret will be removed, 0 for endless secret
+	ExpireTimestamp int64 `protobuf:"varint,5,opt,name=expire_timestamp,json=expireTimestamp,proto3" json:"expire_timestamp,omitempty"`
+	// max_reads count of reads after that secret will be removed, 0 for unlimited reads
+	MaxReads int32 `protobuf:"varint,6,opt,name=max_reads,json=maxReads,proto3" json:"max_reads,omitempty"`
+}
+
+func (x *SecretUpdateRequest) Reset() {
+	*x = SecretUpdateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretUpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretUpdateRequest) ProtoMessage() {}
+
+func (x *SecretUpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretUpdateRequest.ProtoReflect.Descriptor instead.
+func (*SecretUpdateRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *SecretUpdateRequest) GetSecretType() SecretType {
+	if x != nil {
+		return x.SecretType
+	}
+	return SecretType_CREDENTIALS
+}
+
+func (x *SecretUpdateRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SecretUpdateRequest) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *SecretUpdateRequest) GetReplaceLimits() bool {
+	if x != nil {
+		return x.ReplaceLimits
+	}
+	return false
+}
+
+func (x *SecretUpdateRequest) GetExpireTimestamp() int64 {
+	if x != nil {
+		return x.ExpireTimestamp
+	}
+	return 0
+}
+
+func (x *SecretUpdateRequest) GetMaxReads() int32 {
+	if x != nil {
+		return x.MaxReads
+	}
+	return 0
+}
+
+type SecretUpdateResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Error string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *SecretUpdateResponse) Reset() {
+	*x = SecretUpdateResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretUpdateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretUpdateResponse) ProtoMessage() {}
+
+func (x *SecretUpdateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretUpdateResponse.ProtoReflect.Descriptor instead.
+func (*SecretUpdateResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *SecretUpdateResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type SecretDeleteRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SecretType SecretType `protobuf:"varint,1,opt,name=secret_type,json=secretType,proto3,enum=keeperservice.grpc.SecretType" json:"secret_type,omitempty"`
+	SecretName string     `protobuf:"bytes,2,opt,name=secret_name,json=secretName,proto3" json:"secret_name,omitempty"`
+}
+
+func (x *SecretDeleteRequest) Reset() {
+	*x = SecretDeleteRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretDeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretDeleteRequest) ProtoMessage() {}
+
+func (x *SecretDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretDeleteRequest.ProtoReflect.Descriptor instead.
+func (*SecretDeleteRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *SecretDeleteRequest) GetSecretType() SecretType {
+	if x != nil {
+		return x.SecretType
+	}
+	return SecretType_CREDENTIALS
+}
+
+func (x *SecretDeleteRequest) GetSecretName() string {
+	if x != nil {
+		return x.SecretName
+	}
+	return ""
+}
+
+type SecretDeleteResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Error string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *SecretDeleteResponse) Reset() {
+	*x = SecretDeleteResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretDeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretDeleteResponse) ProtoMessage() {}
+
+func (x *SecretDeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretDeleteResponse.ProtoReflect.Descriptor instead.
+func (*SecretDeleteResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *SecretDeleteResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// SecretMigrateNameRequest replaces plain name of secret by blind index and encrypted name
+type SecretMigrateNameRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SecretType    SecretType `protobuf:"varint,1,opt,name=secret_type,json=secretType,proto3,enum=keeperservice.grpc.SecretType" json:"secret_type,omitempty"`
+	Name          string     `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	NameIndex     string     `protobuf:"bytes,3,opt,name=name_index,json=nameIndex,proto3" json:"name_index,omitempty"`
+	EncryptedName []byte     `protobuf:"bytes,4,opt,name=encrypted_name,json=encryptedName,proto3" json:"encrypted_name,omitempty"`
+	Folder        string     `protobuf:"bytes,5,opt,name=folder,proto3" json:"folder,omitempty"`
+}
+
+func (x *SecretMigrateNameRequest) Reset() {
+	*x = SecretMigrateNameRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretMigrateNameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretMigrateNameRequest) ProtoMessage() {}
+
+func (x *SecretMigrateNameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretMigrateNameRequest.ProtoReflect.Descriptor instead.
+func (*SecretMigrateNameRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *SecretMigrateNameRequest) GetSecretType() SecretType {
+	if x != nil {
+		return x.SecretType
+	}
+	return SecretType_CREDENTIALS
+}
+
+func (x *SecretMigrateNameRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SecretMigrateNameRequest) GetNameIndex() string {
+	if x != nil {
+		return x.NameIndex
+	}
+	return ""
+}
+
+func (x *SecretMigrateNameRequest) GetEncryptedName() []byte {
+	if x != nil {
+		return x.EncryptedName
+	}
+	return nil
+}
+
+func (x *SecretMigrateNameRequest) GetFolder() string {
+	if x != nil {
+		return x.Folder
+	}
+	return ""
+}
+
+type SecretMigrateNameResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Error string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *SecretMigrateNameResponse) Reset() {
+	*x = SecretMigrateNameResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretMigrateNameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretMigrateNameResponse) ProtoMessage() {}
+
+func (x *SecretMigrateNameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretMigrateNameResponse.ProtoReflect.Descriptor instead.
+func (*SecretMigrateNameResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SecretMigrateNameResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// SecretRenameRequest changes name, folder and type of secret without changing its content.
+// Type can be changed only between plain types (credentials, card, text)
+type SecretRenameRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SecretType       SecretType `protobuf:"varint,1,opt,name=secret_type,json=secretType,proto3,enum=keeperservice.grpc.SecretType" json:"secret_type,omitempty"`
+	Name             string     `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	NewName          string     `protobuf:"bytes,3,opt,name=new_name,json=newName,proto3" json:"new_name,omitempty"`
+	NewEncryptedName []byte     `protobuf:"bytes,4,opt,name=new_encrypted_name,json=newEncryptedName,proto3" json:"new_encrypted_name,omitempty"`
+	NewFolder        string     `protobuf:"bytes,5,opt,name=new_folder,json=newFolder,proto3" json:"new_folder,omitempty"`
+	// new_secret_type new type of secret, not set for keep type
+	NewSecretType *SecretType `protobuf:"varint,6,opt,name=new_secret_type,json=newSecretType,proto3,enum=keeperservice.grpc.SecretType,oneof" json:"new_secret_type,omitempty"`
+}
+
+func (x *SecretRenameRequest) Reset() {
+	*x = SecretRenameRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretRenameRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretRenameRequest) ProtoMessage() {}
+
+func (x *SecretRenameRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretRenameRequest.ProtoReflect.Descriptor instead.
+func (*SecretRenameRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *SecretRenameRequest) GetSecretType() SecretType {
+	if x != nil {
+		return x.SecretType
+	}
+	return SecretType_CREDENTIALS
+}
+
+func (x *SecretRenameRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SecretRenameRequest) GetNewName() string {
+	if x != nil {
+		return x.NewName
+	}
+	return ""
+}
+
+func (x *SecretRenameRequest) GetNewEncryptedName() []byte {
+	if x != nil {
+		return x.NewEncryptedName
+	}
+	return nil
+}
+
+func (x *SecretRenameRequest) GetNewFolder() string {
+	if x != nil {
+		return x.NewFolder
+	}
+	return ""
+}
+
+func (x *SecretRenameRequest) GetNewSecretType() SecretType {
+	if x != nil && x.NewSecretType != nil {
+		return *x.NewSecretType
+	}
+	return SecretType_CREDENTIALS
+}
+
+type SecretRenameResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Error string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *SecretRenameResponse) Reset() {
+	*x = SecretRenameResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretRenameResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretRenameResponse) ProtoMessage() {}
+
+func (x *SecretRenameResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretRenameResponse.ProtoReflect.Descriptor instead.
+func (*SecretRenameResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *SecretRenameResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// SecretSetTagsRequest replaces all tags of secret
+type SecretSetTagsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SecretType SecretType   `protobuf:"varint,1,opt,name=secret_type,json=secretType,proto3,enum=keeperservice.grpc.SecretType" json:"secret_type,omitempty"`
+	Name       string       `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Tags       []*SecretTag `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
+}
+
+func (x *SecretSetTagsRequest) Reset() {
+	*x = SecretSetTagsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretSetTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretSetTagsRequest) ProtoMessage() {}
+
+func (x *SecretSetTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretSetTagsRequest.ProtoReflect.Descriptor instead.
+func (*SecretSetTagsRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *SecretSetTagsRequest) GetSecretType() SecretType {
+	if x != nil {
+		return x.SecretType
+	}
+	return SecretType_CREDENTIALS
+}
+
+func (x *SecretSetTagsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SecretSetTagsRequest) GetTags() []*SecretTag {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type SecretSetTagsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Error string `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *SecretSetTagsResponse) Reset() {
+	*x = SecretSetTagsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretSetTagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretSetTagsResponse) ProtoMessage() {}
+
+func (x *SecretSetTagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretSetTagsResponse.ProtoReflect.Descriptor instead.
+func (*SecretSetTagsResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *SecretSetTagsResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// BatchItemResult result of one item of batch operation. Batch operations are all-or-nothing:
+// if any item fails, other items are not applied and have ABORTED code
+type BatchItemResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SecretType SecretType `protobuf:"varint,1,opt,name=secret_type,json=secretType,proto3,enum=keeperservice.grpc.SecretType" json:"secret_type,omitempty"`
+	Name       string     `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// code grpc status code of item operation
+	Code  uint32 `protobuf:"varint,3,opt,name=code,proto3" json:"code,omitempty"`
+	Error string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *BatchItemResult) Reset() {
+	*x = BatchItemResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchItemResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchItemResult) ProtoMessage() {}
+
+func (x *BatchItemResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchItemResult.ProtoReflect.Descriptor instead.
+func (*BatchItemResult) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *BatchItemResult) GetSecretType() SecretType {
+	if x != nil {
+		return x.SecretType
+	}
+	return SecretType_CREDENTIALS
+}
+
+func (x *BatchItemResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BatchItemResult) GetCode() uint32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *BatchItemResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BatchSetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*SecretSetRequest `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *BatchSetRequest) Reset() {
+	*x = BatchSetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[42]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchSetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchSetRequest) ProtoMessage() {}
+
+func (x *BatchSetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[42]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchSetRequest.ProtoReflect.Descriptor instead.
+func (*BatchSetRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *BatchSetRequest) GetItems() []*SecretSetRequest {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type BatchSetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results []*BatchItemResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	// committed true if all items are applied
+	Committed bool   `protobuf:"varint,2,opt,name=committed,proto3" json:"committed,omitempty"`
+	Error     string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *BatchSetResponse) Reset() {
+	*x = BatchSetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[43]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchSetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchSetResponse) ProtoMessage() {}
+
+func (x *BatchSetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[43]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchSetResponse.ProtoReflect.Descriptor instead.
+func (*BatchSetResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *BatchSetResponse) GetResults() []*BatchItemResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *BatchSetResponse) GetCommitted() bool {
+	if x != nil {
+		return x.Committed
+	}
+	return false
+}
+
+func (x *BatchSetResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BatchGetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*SecretGetRequest `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *BatchGetRequest) Reset() {
+	*x = BatchGetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetRequest) ProtoMessage() {}
+
+func (x *BatchGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *BatchGetRequest) GetItems() []*SecretGetRequest {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type BatchGetResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Result *BatchItemResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	// secret found secret, empty if batch is not committed
+	Secret *Secret `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+}
+
+func (x *BatchGetResult) Reset() {
+	*x = BatchGetResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchGetResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetResult) ProtoMessage() {}
+
+func (x *BatchGetResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetResult.ProtoReflect.Descriptor instead.
+func (*BatchGetResult) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *BatchGetResult) GetResult() *BatchItemResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *BatchGetResult) GetSecret() *Secret {
+	if x != nil {
+		return x.Secret
+	}
+	return nil
+}
+
+type BatchGetResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results   []*BatchGetResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Committed bool              `protobuf:"varint,2,opt,name=committed,proto3" json:"committed,omitempty"`
+	Error     string            `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *BatchGetResponse) Reset() {
+	*x = BatchGetResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetResponse) ProtoMessage() {}
+
+func (x *BatchGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *BatchGetResponse) GetResults() []*BatchGetResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *BatchGetResponse) GetCommitted() bool {
+	if x != nil {
+		return x.Committed
+	}
+	return false
+}
+
+func (x *BatchGetResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BatchDeleteRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*SecretDeleteRequest `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *BatchDeleteRequest) Reset() {
+	*x = BatchDeleteRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchDeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDeleteRequest) ProtoMessage() {}
+
+func (x *BatchDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDeleteRequest.ProtoReflect.Descriptor instead.
+func (*BatchDeleteRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *BatchDeleteRequest) GetItems() []*SecretDeleteRequest {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type BatchDeleteResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results   []*BatchItemResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Committed bool               `protobuf:"varint,2,opt,name=committed,proto3" json:"committed,omitempty"`
+	Error     string             `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *BatchDeleteResponse) Reset() {
+	*x = BatchDeleteResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchDeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDeleteResponse) ProtoMessage() {}
+
+func (x *BatchDeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDeleteResponse.ProtoReflect.Descriptor instead.
+func (*BatchDeleteResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *BatchDeleteResponse) GetResults() []*BatchItemResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *BatchDeleteResponse) GetCommitted() bool {
+	if x != nil {
+		return x.Committed
+	}
+	return false
+}
+
+func (x *BatchDeleteResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type UsageRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *UsageRequest) Reset() {
+	*x = UsageRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageRequest) ProtoMessage() {}
+
+func (x *UsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageRequest.ProtoReflect.Descriptor instead.
+func (*UsageRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{49}
+}
+
+type UsageResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MediaCount int32 `protobuf:"varint,1,opt,name=media_count,json=mediaCount,proto3" json:"media_count,omitempty"`
+	PlainCount int32 `protobuf:"varint,2,opt,name=plain_count,json=plainCount,proto3" json:"plain_count,omitempty"`
+	// media_bytes size of stored media content, chunks shared by media of user are counted once
+	MediaBytes int64 `protobuf:"varint,3,opt,name=media_bytes,json=mediaBytes,proto3" json:"media_bytes,omitempty"`
+	PlainBytes int64 `protobuf:"varint,4,opt,name=plain_bytes,json=plainBytes,proto3" json:"plain_bytes,omitempty"`
+	// max_bytes limit of media_bytes and plain_bytes sum, 0 for unlimited size
+	MaxBytes int64 `protobuf:"varint,5,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	// max_media limit of media_count, 0 for unlimited count
+	MaxMedia int32 `protobuf:"varint,6,opt,name=max_media,json=maxMedia,proto3" json:"max_media,omitempty"`
+	// max_secrets limit of plain_count, 0 for unlimited count
+	MaxSecrets int32 `protobuf:"varint,7,opt,name=max_secrets,json=maxSecrets,proto3" json:"max_secrets,omitempty"`
+}
+
+func (x *UsageResponse) Reset() {
+	*x = UsageResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_proto_keeperserver_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageResponse) ProtoMessage() {}
+
+func (x *UsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_keeperserver_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageResponse.ProtoReflect.Descriptor instead.
+func (*UsageResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_keeperserver_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *UsageResponse) GetMediaCount() int32 {
+	if x != nil {
+		return x.MediaCount
+	}
+	return 0
+}
+
+func (x *UsageResponse) GetPlainCount() int32 {
+	if x != nil {
+		return x.PlainCount
+	}
+	return 0
+}
+
+func (x *UsageResponse) GetMediaBytes() int64 {
+	if x != nil {
+		return x.MediaBytes
+	}
+	return 0
+}
+
+func (x *UsageResponse) GetPlainBytes() int64 {
+	if x != nil {
+		return x.PlainBytes
+	}
+	return 0
+}
+
+func (x *UsageResponse) GetMaxBytes() int64 {
+	if x != nil {
+		return x.MaxBytes
+	}
+	return 0
+}
+
+func (x *UsageResponse) GetMaxMedia() int32 {
+	if x != nil {
+		return x.MaxMedia
+	}
+	return 0
+}
+
+func (x *UsageResponse) GetMaxSecrets() int32 {
+	if x != nil {
+		return x.MaxSecrets
+	}
+	return 0
+}
+
+var File_api_proto_keeperserver_proto protoreflect.FileDescriptor
+
+var file_api_proto_keeperserver_proto_rawDesc = []byte{
+	0x0a, 0x1c, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6b, 0x65, 0x65, 0x70,
+	0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x12,
 	0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72,
-	0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x07, 0x73, 0x65, 0x63, 0x72, 0x65,
-	0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x81, 0x01, 0x0a, 0x10, 0x53, 0x65, 0x63,
-	0x72, 0x65, 0x74, 0x53, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a,
+	0x70, 0x63, 0x22, 0x27, 0x0a, 0x0b, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x3c, 0x0a, 0x0c, 0x50,
+	0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61,
+	0x6e, 0x73, 0x77, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x6e, 0x73,
+	0x77, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x4a, 0x0a, 0x16, 0x55, 0x73, 0x65,
+	0x72, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0x45, 0x0a, 0x17, 0x55, 0x73, 0x65, 0x72, 0x43, 0x72, 0x65,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0xf5, 0x01, 0x0a,
+	0x13, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x76, 0x65, 0x72,
+	0x77, 0x72, 0x69, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x6f, 0x76, 0x65,
+	0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70,
+	0x74, 0x65, 0x64, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d,
+	0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x29, 0x0a,
+	0x10, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x78, 0x5f,
+	0x72, 0x65, 0x61, 0x64, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6d, 0x61, 0x78,
+	0x52, 0x65, 0x61, 0x64, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x25, 0x0a,
+	0x0e, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64,
+	0x49, 0x6e, 0x66, 0x6f, 0x22, 0x23, 0x0a, 0x0b, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x22, 0x4a, 0x0a, 0x11, 0x4d, 0x65, 0x64,
+	0x69, 0x61, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x16, 0x0a,
+	0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f,
+	0x66, 0x66, 0x73, 0x65, 0x74, 0x22, 0x2c, 0x0a, 0x12, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x55, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x54, 0x72, 0x61, 0x69, 0x6c, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x73,
+	0x68, 0x61, 0x32, 0x35, 0x36, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x68, 0x61,
+	0x32, 0x35, 0x36, 0x22, 0xa8, 0x02, 0x0a, 0x18, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65,
+	0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x45, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x27, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x48, 0x00, 0x52, 0x08, 0x6d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x35, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61,
+	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x48, 0x00, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x3f,
+	0x0a, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25,
+	0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52,
+	0x65, 0x73, 0x75, 0x6d, 0x65, 0x48, 0x00, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x12,
+	0x42, 0x0a, 0x07, 0x74, 0x72, 0x61, 0x69, 0x6c, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x26, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x55, 0x70, 0x6c, 0x6f, 0x61,
+	0x64, 0x54, 0x72, 0x61, 0x69, 0x6c, 0x65, 0x72, 0x48, 0x00, 0x52, 0x07, 0x74, 0x72, 0x61, 0x69,
+	0x6c, 0x65, 0x72, 0x42, 0x09, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x59,
+	0x0a, 0x19, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75,
+	0x75, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x75, 0x69, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x6d, 0x0a, 0x1a, 0x44, 0x6f, 0x77,
+	0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x73, 0x65,
+	0x63, 0x72, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73,
+	0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x22, 0x90, 0x01, 0x0a, 0x1b, 0x44, 0x6f, 0x77,
+	0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x0a, 0x73, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x50, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6b,
+	0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x0a, 0x73,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x50, 0x61, 0x72, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x68, 0x75,
+	0x6e, 0x6b, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x63, 0x68, 0x75, 0x6e,
+	0x6b, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x22, 0x3a, 0x0a, 0x19, 0x4d,
+	0x65, 0x64, 0x69, 0x61, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x53, 0x0a, 0x1a, 0x4d, 0x65, 0x64, 0x69, 0x61,
+	0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x22, 0x2c, 0x0a, 0x12,
+	0x4d, 0x65, 0x64, 0x69, 0x61, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x22, 0x2f, 0x0a, 0x13, 0x4d, 0x65,
+	0x64, 0x69, 0x61, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x07, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x22, 0x41, 0x0a, 0x17, 0x55,
+	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61,
+	0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x30,
+	0x0a, 0x18, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x43, 0x68, 0x75,
+	0x6e, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x22, 0x78, 0x0a, 0x19, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x65,
+	0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x43, 0x0a,
+	0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x27, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x06, 0x68, 0x61, 0x73, 0x68, 0x65, 0x73, 0x22, 0x71, 0x0a, 0x16, 0x50, 0x72,
+	0x65, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x43, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69,
+	0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x52,
+	0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x22, 0x72, 0x0a,
+	0x17, 0x50, 0x72, 0x65, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x72, 0x74, 0x5f,
+	0x75, 0x72, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x72, 0x74,
+	0x55, 0x72, 0x6c, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x72, 0x74, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x70, 0x61, 0x72, 0x74, 0x53, 0x69, 0x7a,
+	0x65, 0x22, 0x4f, 0x0a, 0x18, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x4d, 0x65, 0x64, 0x69, 0x61,
+	0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a,
+	0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05,
+	0x65, 0x74, 0x61, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x65, 0x74, 0x61,
+	0x67, 0x73, 0x22, 0x59, 0x0a, 0x19, 0x50, 0x72, 0x65, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x44,
+	0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72,
+	0x6c, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x22, 0x42, 0x0a,
+	0x09, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x61, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61,
+	0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74, 0x61, 0x67, 0x12, 0x23, 0x0a, 0x0d,
+	0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x5f, 0x74, 0x61, 0x67, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0c, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x54, 0x61,
+	0x67, 0x22, 0xe3, 0x03, 0x0a, 0x06, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x3f, 0x0a, 0x0b,
+	0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x1e, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x0a, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x29, 0x0a, 0x10, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x29, 0x0a, 0x10,
+	0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
+	0x74, 0x12, 0x25, 0x0a, 0x0e, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x5f, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x65, 0x6e, 0x63, 0x72, 0x79,
+	0x70, 0x74, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x65, 0x78, 0x70, 0x69,
+	0x72, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x73,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6d, 0x61, 0x78, 0x52, 0x65, 0x61, 0x64, 0x73,
+	0x12, 0x14, 0x0a, 0x05, 0x72, 0x65, 0x61, 0x64, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x72, 0x65, 0x61, 0x64, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x31,
+	0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6b,
+	0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x61, 0x67, 0x52, 0x04, 0x74, 0x61, 0x67,
+	0x73, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x30, 0x0a, 0x14, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74,
+	0x65, 0x64, 0x5f, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x12, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x4d, 0x65,
+	0x64, 0x69, 0x61, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0xdc, 0x03, 0x0a, 0x11, 0x53, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a,
 	0x0b, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69,
 	0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x79,
-	0x70, 0x65, 0x52, 0x0a, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12,
-	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x29, 0x0a, 0x11,
+	0x70, 0x65, 0x52, 0x0a, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x10, 0x0a, 0x03,
+	0x74, 0x61, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74, 0x61, 0x67, 0x12, 0x1b,
+	0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70,
+	0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x37, 0x0a, 0x04, 0x73, 0x6f,
+	0x72, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x23, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65,
+	0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65,
+	0x63, 0x72, 0x65, 0x74, 0x53, 0x6f, 0x72, 0x74, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x04, 0x73,
+	0x6f, 0x72, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x64, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x64, 0x65, 0x73, 0x63, 0x65, 0x6e, 0x64,
+	0x69, 0x6e, 0x67, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61,
+	0x66, 0x74, 0x65, 0x72, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x41, 0x66, 0x74, 0x65, 0x72, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x5f, 0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0d, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12,
+	0x23, 0x0a, 0x0d, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41,
+	0x66, 0x74, 0x65, 0x72, 0x12, 0x25, 0x0a, 0x0e, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f,
+	0x62, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x75, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x64, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x61,
+	0x6e, 0x79, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x61,
+	0x6e, 0x79, 0x54, 0x79, 0x70, 0x65, 0x22, 0x88, 0x01, 0x0a, 0x12, 0x53, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x34, 0x0a,
+	0x07, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a,
+	0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x07, 0x73, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x78,
+	0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65,
+	0x6e, 0x22, 0x88, 0x02, 0x0a, 0x10, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x53, 0x65, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x0b, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x6b, 0x65,
+	0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63,
+	0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0a, 0x73, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x6f,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74,
+	0x65, 0x64, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x65,
+	0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x29, 0x0a, 0x10,
+	0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x78, 0x5f, 0x72,
+	0x65, 0x61, 0x64, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6d, 0x61, 0x78, 0x52,
+	0x65, 0x61, 0x64, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x22, 0x29, 0x0a, 0x11,
 	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x53, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
 	0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
 	0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x67, 0x0a, 0x10, 0x53, 0x65, 0x63, 0x72, 0x65,
@@ -1349,7 +3622,7 @@ var file_api_proto_keeperserver_proto_rawDesc = []byte{
 	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65,
 	0x74, 0x52, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72,
 	0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22,
-	0x84, 0x01, 0x0a, 0x13, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0xf3, 0x01, 0x0a, 0x13, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
 	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x0b, 0x73, 0x65, 0x63, 0x72, 0x65,
 	0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x6b,
 	0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70,
@@ -1357,90 +3630,332 @@ var file_api_proto_keeperserver_proto_rawDesc = []byte{
 	0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
 	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07,
 	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63,
-	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x2c, 0x0a, 0x14, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
-	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14,
-	0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65,
-	0x72, 0x72, 0x6f, 0x72, 0x22, 0x77, 0x0a, 0x13, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x44, 0x65,
-	0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x0b, 0x73,
-	0x65, 0x63, 0x72, 0x65, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
-	0x32, 0x1e, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65,
-	0x52, 0x0a, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x0b,
-	0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0a, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x2c, 0x0a,
-	0x14, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x2a, 0x43, 0x0a, 0x0a, 0x53,
-	0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x52, 0x45,
-	0x44, 0x45, 0x4e, 0x54, 0x49, 0x41, 0x4c, 0x53, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x52,
-	0x45, 0x44, 0x49, 0x54, 0x5f, 0x43, 0x41, 0x52, 0x44, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x54,
-	0x45, 0x58, 0x54, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x4d, 0x45, 0x44, 0x49, 0x41, 0x10, 0x03,
-	0x32, 0xe6, 0x07, 0x0a, 0x0d, 0x4b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x12, 0x49, 0x0a, 0x04, 0x50, 0x69, 0x6e, 0x67, 0x12, 0x1f, 0x2e, 0x6b, 0x65, 0x65,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63,
+	0x65, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d,
+	0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x73, 0x12, 0x29, 0x0a,
+	0x10, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x78, 0x5f,
+	0x72, 0x65, 0x61, 0x64, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6d, 0x61, 0x78,
+	0x52, 0x65, 0x61, 0x64, 0x73, 0x22, 0x2c, 0x0a, 0x14, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x22, 0x77, 0x0a, 0x13, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x0b, 0x73, 0x65,
+	0x63, 0x72, 0x65, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x1e, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x0a, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x73,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x2c, 0x0a, 0x14,
+	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0xcd, 0x01, 0x0a, 0x18, 0x53,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x4e, 0x61, 0x6d, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x0b, 0x73, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x6b,
+	0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70,
+	0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0a, 0x73, 0x65,
+	0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a,
+	0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x25, 0x0a, 0x0e, 0x65,
+	0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x0d, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x22, 0x31, 0x0a, 0x19, 0x53, 0x65,
+	0x63, 0x72, 0x65, 0x74, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0xb3, 0x02,
+	0x0a, 0x13, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x0b, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x5f,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x6b, 0x65, 0x65,
 	0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
-	0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x6b, 0x65,
-	0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63,
-	0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x63, 0x0a,
-	0x08, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x12, 0x2a, 0x2e, 0x6b, 0x65, 0x65, 0x70,
-	0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55,
-	0x73, 0x65, 0x72, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x43,
-	0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x60, 0x0a, 0x05, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x12, 0x2a, 0x2e, 0x6b, 0x65,
+	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0a, 0x73, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6e, 0x65,
+	0x77, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6e, 0x65,
+	0x77, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x12, 0x6e, 0x65, 0x77, 0x5f, 0x65, 0x6e, 0x63,
+	0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x10, 0x6e, 0x65, 0x77, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65, 0x64, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x65, 0x77, 0x5f, 0x66, 0x6f, 0x6c, 0x64, 0x65,
+	0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x65, 0x77, 0x46, 0x6f, 0x6c, 0x64,
+	0x65, 0x72, 0x12, 0x4b, 0x0a, 0x0f, 0x6e, 0x65, 0x77, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x6b, 0x65,
 	0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63,
-	0x2e, 0x55, 0x73, 0x65, 0x72, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72,
-	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x73, 0x65,
-	0x72, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x72, 0x0a, 0x11, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65,
-	0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x2c, 0x2e, 0x6b, 0x65, 0x65, 0x70,
-	0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55,
-	0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72,
-	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x6c,
-	0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01, 0x12, 0x78, 0x0a, 0x13, 0x44, 0x6f, 0x77, 0x6e,
-	0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12,
-	0x2e, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
-	0x67, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64,
-	0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x2f, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
-	0x67, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64,
-	0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x30, 0x01, 0x12, 0x5b, 0x0a, 0x0a, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74,
-	0x12, 0x25, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72,
-	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63,
-	0x72, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x58, 0x0a, 0x09, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x53, 0x65, 0x74, 0x12, 0x24, 0x2e, 0x6b,
-	0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70,
-	0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x53, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x25, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x53, 0x65,
-	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x58, 0x0a, 0x09, 0x53, 0x65, 0x63,
-	0x72, 0x65, 0x74, 0x47, 0x65, 0x74, 0x12, 0x24, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73,
+	0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x48, 0x00, 0x52, 0x0d, 0x6e,
+	0x65, 0x77, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x88, 0x01, 0x01, 0x42,
+	0x12, 0x0a, 0x10, 0x5f, 0x6e, 0x65, 0x77, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x5f, 0x74,
+	0x79, 0x70, 0x65, 0x22, 0x2c, 0x0a, 0x14, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x6e,
+	0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x22, 0x9e, 0x01, 0x0a, 0x14, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x53, 0x65, 0x74, 0x54,
+	0x61, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x0b, 0x73, 0x65,
+	0x63, 0x72, 0x65, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x1e, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x0a, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x31, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e,
+	0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x61, 0x67, 0x52, 0x04, 0x74, 0x61,
+	0x67, 0x73, 0x22, 0x2d, 0x0a, 0x15, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x53, 0x65, 0x74, 0x54,
+	0x61, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x22, 0x90, 0x01, 0x0a, 0x0f, 0x42, 0x61, 0x74, 0x63, 0x68, 0x49, 0x74, 0x65, 0x6d, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x3f, 0x0a, 0x0b, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x5f,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x6b, 0x65, 0x65,
+	0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0a, 0x73, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f,
+	0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x22, 0x4d, 0x0a, 0x0f, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3a, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73,
 	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72,
-	0x65, 0x74, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x6b,
+	0x65, 0x74, 0x53, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x05, 0x69, 0x74,
+	0x65, 0x6d, 0x73, 0x22, 0x85, 0x01, 0x0a, 0x10, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3d, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x6b, 0x65, 0x65, 0x70,
+	0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07,
+	0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x69,
+	0x74, 0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x6d,
+	0x69, 0x74, 0x74, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x4d, 0x0a, 0x0f, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3a,
+	0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e,
+	0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x81, 0x01, 0x0a, 0x0e, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x3b, 0x0a,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e,
+	0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x32, 0x0a, 0x06, 0x73, 0x65,
+	0x63, 0x72, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6b, 0x65, 0x65,
+	0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x22, 0x84,
+	0x01, 0x0a, 0x10, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x3c, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47,
+	0x65, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x12,
+	0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x53, 0x0a, 0x12, 0x42, 0x61, 0x74, 0x63, 0x68, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3d, 0x0a, 0x05, 0x69,
+	0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x6b, 0x65, 0x65,
+	0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x22, 0x88, 0x01, 0x0a, 0x13, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x3d, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x49, 0x74,
+	0x65, 0x6d, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x74, 0x65, 0x64, 0x12,
+	0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x0e, 0x0a, 0x0c, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xee, 0x01, 0x0a, 0x0d, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x65, 0x64, 0x69, 0x61,
+	0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6d, 0x65,
+	0x64, 0x69, 0x61, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x6c, 0x61, 0x69,
+	0x6e, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x70,
+	0x6c, 0x61, 0x69, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x65, 0x64,
+	0x69, 0x61, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a,
+	0x6d, 0x65, 0x64, 0x69, 0x61, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x6c,
+	0x61, 0x69, 0x6e, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x0a, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6d,
+	0x61, 0x78, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08,
+	0x6d, 0x61, 0x78, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x78, 0x5f,
+	0x6d, 0x65, 0x64, 0x69, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6d, 0x61, 0x78,
+	0x4d, 0x65, 0x64, 0x69, 0x61, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x53,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x2a, 0x43, 0x0a, 0x0a, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x52, 0x45, 0x44, 0x45, 0x4e, 0x54, 0x49,
+	0x41, 0x4c, 0x53, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x52, 0x45, 0x44, 0x49, 0x54, 0x5f,
+	0x43, 0x41, 0x52, 0x44, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x45, 0x58, 0x54, 0x10, 0x02,
+	0x12, 0x09, 0x0a, 0x05, 0x4d, 0x45, 0x44, 0x49, 0x41, 0x10, 0x03, 0x2a, 0x2b, 0x0a, 0x0f, 0x53,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x53, 0x6f, 0x72, 0x74, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x0b,
+	0x0a, 0x07, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x55,
+	0x50, 0x44, 0x41, 0x54, 0x45, 0x44, 0x10, 0x01, 0x32, 0x89, 0x15, 0x0a, 0x0d, 0x4b, 0x65, 0x65,
+	0x70, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x49, 0x0a, 0x04, 0x50, 0x69,
+	0x6e, 0x67, 0x12, 0x1f, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x63, 0x0a, 0x08, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65,
+	0x72, 0x12, 0x2a, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x43, 0x72, 0x65, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e,
+	0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61,
+	0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x60, 0x0a, 0x05, 0x4c, 0x6f,
+	0x67, 0x69, 0x6e, 0x12, 0x2a, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x43, 0x72, 0x65,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x2b, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x72, 0x0a, 0x11,
+	0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x12, 0x2c, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64,
+	0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x2d, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61,
+	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x28, 0x01,
+	0x12, 0x78, 0x0a, 0x13, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69,
+	0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x2e, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x6f, 0x77,
+	0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2f, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x6f, 0x77,
+	0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x6b, 0x0a, 0x10, 0x53, 0x74,
+	0x61, 0x72, 0x74, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x27,
+	0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x1a, 0x2e, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64,
+	0x69, 0x61, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x76, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x4d, 0x65,
+	0x64, 0x69, 0x61, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x2d, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x55, 0x70, 0x6c, 0x6f, 0x61,
+	0x64, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x2e, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64,
+	0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x71, 0x0a, 0x10, 0x41, 0x62, 0x6f, 0x72, 0x74, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x55, 0x70, 0x6c,
+	0x6f, 0x61, 0x64, 0x12, 0x2d, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x55, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x2e, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x55, 0x70, 0x6c,
+	0x6f, 0x61, 0x64, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x68, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67,
+	0x4d, 0x65, 0x64, 0x69, 0x61, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x12, 0x26, 0x2e, 0x6b, 0x65,
+	0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63,
+	0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x43, 0x68,
+	0x75, 0x6e, 0x6b, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6d, 0x0a, 0x10,
+	0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x43, 0x68, 0x75, 0x6e, 0x6b,
+	0x12, 0x2b, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69,
+	0x61, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2c, 0x2e,
+	0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x43, 0x68,
+	0x75, 0x6e, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x72, 0x0a, 0x12, 0x43,
+	0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x65, 0x64, 0x4d, 0x65, 0x64, 0x69,
+	0x61, 0x12, 0x2d, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x43, 0x68, 0x75,
+	0x6e, 0x6b, 0x65, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x2d, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69,
+	0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x74, 0x0a, 0x19, 0x53, 0x74, 0x61, 0x72, 0x74, 0x50, 0x72, 0x65, 0x73, 0x69, 0x67, 0x6e, 0x65,
+	0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x2a, 0x2e, 0x6b,
 	0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70,
-	0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x61, 0x0a, 0x0c, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x55, 0x70, 0x64,
-	0x61, 0x74, 0x65, 0x12, 0x27, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x55,
-	0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x6b,
+	0x63, 0x2e, 0x50, 0x72, 0x65, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x55, 0x70, 0x6c, 0x6f, 0x61,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65,
+	0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72,
+	0x65, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x70, 0x0a, 0x11, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x4d,
+	0x65, 0x64, 0x69, 0x61, 0x55, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x2c, 0x2e, 0x6b, 0x65, 0x65,
+	0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x55, 0x70, 0x6c, 0x6f, 0x61,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65,
+	0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70,
+	0x6c, 0x6f, 0x61, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x7a, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x50, 0x72,
+	0x65, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x44, 0x6f, 0x77, 0x6e,
+	0x6c, 0x6f, 0x61, 0x64, 0x12, 0x2e, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f,
+	0x61, 0x64, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x65, 0x73, 0x69, 0x67,
+	0x6e, 0x65, 0x64, 0x44, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x5b, 0x0a, 0x0a, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4c, 0x69, 0x73,
+	0x74, 0x12, 0x25, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4c, 0x69, 0x73,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65,
+	0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65,
+	0x63, 0x72, 0x65, 0x74, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x58, 0x0a, 0x09, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x53, 0x65, 0x74, 0x12, 0x24, 0x2e,
+	0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x53, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x53,
+	0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x58, 0x0a, 0x09, 0x53, 0x65,
+	0x63, 0x72, 0x65, 0x74, 0x47, 0x65, 0x74, 0x12, 0x24, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e,
+	0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x61, 0x0a, 0x0c, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x12, 0x27, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e,
+	0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x61, 0x0a, 0x0c, 0x53, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x27, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x28, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x70, 0x0a, 0x11, 0x53, 0x65,
+	0x63, 0x72, 0x65, 0x74, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x2c, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4d, 0x69, 0x67, 0x72, 0x61,
+	0x74, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e,
+	0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x65,
+	0x4e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x61, 0x0a, 0x0c,
+	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x27, 0x2e, 0x6b,
 	0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70,
-	0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x61, 0x0a, 0x0c, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
-	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x27, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72,
-	0x65, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x28, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
-	0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x44, 0x65, 0x6c, 0x65, 0x74,
-	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x29, 0x5a, 0x27, 0x67, 0x69, 0x74,
-	0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6e, 0x65, 0x73, 0x73, 0x61, 0x69, 0x31, 0x2f,
-	0x67, 0x6f, 0x70, 0x68, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x52, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x64, 0x0a, 0x0d, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x53, 0x65, 0x74, 0x54, 0x61, 0x67, 0x73,
+	0x12, 0x28, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x53, 0x65, 0x74, 0x54,
+	0x61, 0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x29, 0x2e, 0x6b, 0x65, 0x65,
+	0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x53, 0x65, 0x74, 0x54, 0x61, 0x67, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x55, 0x0a, 0x08, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65,
+	0x74, 0x12, 0x23, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x53, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x55, 0x0a, 0x08,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x12, 0x23, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65,
+	0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e,
+	0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x47, 0x65, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x5e, 0x0a, 0x0b, 0x42, 0x61, 0x74, 0x63, 0x68, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x12, 0x26, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x6b, 0x65, 0x65,
+	0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x4f, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12,
+	0x20, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x21, 0x2e, 0x6b, 0x65, 0x65, 0x70, 0x65, 0x72, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x42, 0x29, 0x5a, 0x27, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x6e, 0x65, 0x73, 0x73, 0x61, 0x69, 0x31, 0x2f, 0x67, 0x6f, 0x70, 0x68, 0x6b,
+	0x65, 0x65, 0x70, 0x65, 0x72, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1455,69 +3970,152 @@ func file_api_proto_keeperserver_proto_rawDescGZIP() []byte {
 	return file_api_proto_keeperserver_proto_rawDescData
 }
 
-var file_api_proto_keeperserver_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_api_proto_keeperserver_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_api_proto_keeperserver_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_api_proto_keeperserver_proto_msgTypes = make([]protoimpl.MessageInfo, 51)
 var file_api_proto_keeperserver_proto_goTypes = []interface{}{
 	(SecretType)(0),                     // 0: keeperservice.grpc.SecretType
-	(*PingRequest)(nil),                 // 1: keeperservice.grpc.PingRequest
-	(*PingResponse)(nil),                // 2: keeperservice.grpc.PingResponse
-	(*UserCredentialsRequest)(nil),      // 3: keeperservice.grpc.UserCredentialsRequest
-	(*UserCredentialsResponse)(nil),     // 4: keeperservice.grpc.UserCredentialsResponse
-	(*MediaSecretMetadata)(nil),         // 5: keeperservice.grpc.MediaSecretMetadata
-	(*MediaSecret)(nil),                 // 6: keeperservice.grpc.MediaSecret
-	(*UploadMediaSecretRequest)(nil),    // 7: keeperservice.grpc.UploadMediaSecretRequest
-	(*UploadMediaSecretResponse)(nil),   // 8: keeperservice.grpc.UploadMediaSecretResponse
-	(*DownloadMediaSecretRequest)(nil),  // 9: keeperservice.grpc.DownloadMediaSecretRequest
-	(*DownloadMediaSecretResponse)(nil), // 10: keeperservice.grpc.DownloadMediaSecretResponse
-	(*Secret)(nil),                      // 11: keeperservice.grpc.Secret
-	(*SecretListRequest)(nil),           // 12: keeperservice.grpc.SecretListRequest
-	(*SecretListResponse)(nil),          // 13: keeperservice.grpc.SecretListResponse
-	(*SecretSetRequest)(nil),            // 14: keeperservice.grpc.SecretSetRequest
-	(*SecretSetResponse)(nil),           // 15: keeperservice.grpc.SecretSetResponse
-	(*SecretGetRequest)(nil),            // 16: keeperservice.grpc.SecretGetRequest
-	(*SecretGetResponse)(nil),           // 17: keeperservice.grpc.SecretGetResponse
-	(*SecretUpdateRequest)(nil),         // 18: keeperservice.grpc.SecretUpdateRequest
-	(*SecretUpdateResponse)(nil),        // 19: keeperservice.grpc.SecretUpdateResponse
-	(*SecretDeleteRequest)(nil),         // 20: keeperservice.grpc.SecretDeleteRequest
-	(*SecretDeleteResponse)(nil),        // 21: keeperservice.grpc.SecretDeleteResponse
+	(SecretSortField)(0),                // 1: keeperservice.grpc.SecretSortField
+	(*PingRequest)(nil),                 // 2: keeperservice.grpc.PingRequest
+	(*PingResponse)(nil),                // 3: keeperservice.grpc.PingResponse
+	(*UserCredentialsRequest)(nil),      // 4: keeperservice.grpc.UserCredentialsRequest
+	(*UserCredentialsResponse)(nil),     // 5: keeperservice.grpc.UserCredentialsResponse
+	(*MediaSecretMetadata)(nil),         // 6: keeperservice.grpc.MediaSecretMetadata
+	(*MediaSecret)(nil),                 // 7: keeperservice.grpc.MediaSecret
+	(*MediaUploadResume)(nil),           // 8: keeperservice.grpc.MediaUploadResume
+	(*MediaUploadTrailer)(nil),          // 9: keeperservice.grpc.MediaUploadTrailer
+	(*UploadMediaSecretRequest)(nil),    // 10: keeperservice.grpc.UploadMediaSecretRequest
+	(*UploadMediaSecretResponse)(nil),   // 11: keeperservice.grpc.UploadMediaSecretResponse
+	(*DownloadMediaSecretRequest)(nil),  // 12: keeperservice.grpc.DownloadMediaSecretRequest
+	(*DownloadMediaSecretResponse)(nil), // 13: keeperservice.grpc.DownloadMediaSecretResponse
+	(*MediaUploadSessionRequest)(nil),   // 14: keeperservice.grpc.MediaUploadSessionRequest
+	(*MediaUploadSessionResponse)(nil),  // 15: keeperservice.grpc.MediaUploadSessionResponse
+	(*MediaChunksRequest)(nil),          // 16: keeperservice.grpc.MediaChunksRequest
+	(*MediaChunksResponse)(nil),         // 17: keeperservice.grpc.MediaChunksResponse
+	(*UploadMediaChunkRequest)(nil),     // 18: keeperservice.grpc.UploadMediaChunkRequest
+	(*UploadMediaChunkResponse)(nil),    // 19: keeperservice.grpc.UploadMediaChunkResponse
+	(*CommitChunkedMediaRequest)(nil),   // 20: keeperservice.grpc.CommitChunkedMediaRequest
+	(*PresignedUploadRequest)(nil),      // 21: keeperservice.grpc.PresignedUploadRequest
+	(*PresignedUploadResponse)(nil),     // 22: keeperservice.grpc.PresignedUploadResponse
+	(*CommitMediaUploadRequest)(nil),    // 23: keeperservice.grpc.CommitMediaUploadRequest
+	(*PresignedDownloadResponse)(nil),   // 24: keeperservice.grpc.PresignedDownloadResponse
+	(*SecretTag)(nil),                   // 25: keeperservice.grpc.SecretTag
+	(*Secret)(nil),                      // 26: keeperservice.grpc.Secret
+	(*SecretListRequest)(nil),           // 27: keeperservice.grpc.SecretListRequest
+	(*SecretListResponse)(nil),          // 28: keeperservice.grpc.SecretListResponse
+	(*SecretSetRequest)(nil),            // 29: keeperservice.grpc.SecretSetRequest
+	(*SecretSetResponse)(nil),           // 30: keeperservice.grpc.SecretSetResponse
+	(*SecretGetRequest)(nil),            // 31: keeperservice.grpc.SecretGetRequest
+	(*SecretGetResponse)(nil),           // 32: keeperservice.grpc.SecretGetResponse
+	(*SecretUpdateRequest)(nil),         // 33: keeperservice.grpc.SecretUpdateRequest
+	(*SecretUpdateResponse)(nil),        // 34: keeperservice.grpc.SecretUpdateResponse
+	(*SecretDeleteRequest)(nil),         // 35: keeperservice.grpc.SecretDeleteRequest
+	(*SecretDeleteResponse)(nil),        // 36: keeperservice.grpc.SecretDeleteResponse
+	(*SecretMigrateNameRequest)(nil),    // 37: keeperservice.grpc.SecretMigrateNameRequest
+	(*SecretMigrateNameResponse)(nil),   // 38: keeperservice.grpc.SecretMigrateNameResponse
+	(*SecretRenameRequest)(nil),         // 39: keeperservice.grpc.SecretRenameRequest
+	(*SecretRenameResponse)(nil),        // 40: keeperservice.grpc.SecretRenameResponse
+	(*SecretSetTagsRequest)(nil),        // 41: keeperservice.grpc.SecretSetTagsRequest
+	(*SecretSetTagsResponse)(nil),       // 42: keeperservice.grpc.SecretSetTagsResponse
+	(*BatchItemResult)(nil),             // 43: keeperservice.grpc.BatchItemResult
+	(*BatchSetRequest)(nil),             // 44: keeperservice.grpc.BatchSetRequest
+	(*BatchSetResponse)(nil),            // 45: keeperservice.grpc.BatchSetResponse
+	(*BatchGetRequest)(nil),             // 46: keeperservice.grpc.BatchGetRequest
+	(*BatchGetResult)(nil),              // 47: keeperservice.grpc.BatchGetResult
+	(*BatchGetResponse)(nil),            // 48: keeperservice.grpc.BatchGetResponse
+	(*BatchDeleteRequest)(nil),          // 49: keeperservice.grpc.BatchDeleteRequest
+	(*BatchDeleteResponse)(nil),         // 50: keeperservice.grpc.BatchDeleteResponse
+	(*UsageRequest)(nil),                // 51: keeperservice.grpc.UsageRequest
+	(*UsageResponse)(nil),               // 52: keeperservice.grpc.UsageResponse
 }
 var file_api_proto_keeperserver_proto_depIdxs = []int32{
-	5,  // 0: keeperservice.grpc.UploadMediaSecretRequest.metadata:type_name -> keeperservice.grpc.MediaSecretMetadata
-	6,  // 1: keeperservice.grpc.UploadMediaSecretRequest.data:type_name -> keeperservice.grpc.MediaSecret
-	6,  // 2: keeperservice.grpc.DownloadMediaSecretResponse.secretPart:type_name -> keeperservice.grpc.MediaSecret
-	0,  // 3: keeperservice.grpc.Secret.secret_type:type_name -> keeperservice.grpc.SecretType
-	0,  // 4: keeperservice.grpc.SecretListRequest.secret_type:type_name -> keeperservice.grpc.SecretType
-	11, // 5: keeperservice.grpc.SecretListResponse.secrets:type_name -> keeperservice.grpc.Secret
-	0,  // 6: keeperservice.grpc.SecretSetRequest.secret_type:type_name -> keeperservice.grpc.SecretType
-	0,  // 7: keeperservice.grpc.SecretGetRequest.secret_type:type_name -> keeperservice.grpc.SecretType
-	11, // 8: keeperservice.grpc.SecretGetResponse.secret:type_name -> keeperservice.grpc.Secret
-	0,  // 9: keeperservice.grpc.SecretUpdateRequest.secret_type:type_name -> keeperservice.grpc.SecretType
-	0,  // 10: keeperservice.grpc.SecretDeleteRequest.secret_type:type_name -> keeperservice.grpc.SecretType
-	1,  // 11: keeperservice.grpc.KeeperService.Ping:input_type -> keeperservice.grpc.PingRequest
-	3,  // 12: keeperservice.grpc.KeeperService.Register:input_type -> keeperservice.grpc.UserCredentialsRequest
-	3,  // 13: keeperservice.grpc.KeeperService.Login:input_type -> keeperservice.grpc.UserCredentialsRequest
-	7,  // 14: keeperservice.grpc.KeeperService.UploadMediaSecret:input_type -> keeperservice.grpc.UploadMediaSecretRequest
-	9,  // 15: keeperservice.grpc.KeeperService.DownloadMediaSecret:input_type -> keeperservice.grpc.DownloadMediaSecretRequest
-	12, // 16: keeperservice.grpc.KeeperService.SecretList:input_type -> keeperservice.grpc.SecretListRequest
-	14, // 17: keeperservice.grpc.KeeperService.SecretSet:input_type -> keeperservice.grpc.SecretSetRequest
-	16, // 18: keeperservice.grpc.KeeperService.SecretGet:input_type -> keeperservice.grpc.SecretGetRequest
-	18, // 19: keeperservice.grpc.KeeperService.SecretUpdate:input_type -> keeperservice.grpc.SecretUpdateRequest
-	20, // 20: keeperservice.grpc.KeeperService.SecretDelete:input_type -> keeperservice.grpc.SecretDeleteRequest
-	2,  // 21: keeperservice.grpc.KeeperService.Ping:output_type -> keeperservice.grpc.PingResponse
-	4,  // 22: keeperservice.grpc.KeeperService.Register:output_type -> keeperservice.grpc.UserCredentialsResponse
-	4,  // 23: keeperservice.grpc.KeeperService.Login:output_type -> keeperservice.grpc.UserCredentialsResponse
-	8,  // 24: keeperservice.grpc.KeeperService.UploadMediaSecret:output_type -> keeperservice.grpc.UploadMediaSecretResponse
-	10, // 25: keeperservice.grpc.KeeperService.DownloadMediaSecret:output_type -> keeperservice.grpc.DownloadMediaSecretResponse
-	13, // 26: keeperservice.grpc.KeeperService.SecretList:output_type -> keeperservice.grpc.SecretListResponse
-	15, // 27: keeperservice.grpc.KeeperService.SecretSet:output_type -> keeperservice.grpc.SecretSetResponse
-	17, // 28: keeperservice.grpc.KeeperService.SecretGet:output_type -> keeperservice.grpc.SecretGetResponse
-	19, // 29: keeperservice.grpc.KeeperService.SecretUpdate:output_type -> keeperservice.grpc.SecretUpdateResponse
-	21, // 30: keeperservice.grpc.KeeperService.SecretDelete:output_type -> keeperservice.grpc.SecretDeleteResponse
-	21, // [21:31] is the sub-list for method output_type
-	11, // [11:21] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	6,  // 0: keeperservice.grpc.UploadMediaSecretRequest.metadata:type_name -> keeperservice.grpc.MediaSecretMetadata
+	7,  // 1: keeperservice.grpc.UploadMediaSecretRequest.data:type_name -> keeperservice.grpc.MediaSecret
+	8,  // 2: keeperservice.grpc.UploadMediaSecretRequest.resume:type_name -> keeperservice.grpc.MediaUploadResume
+	9,  // 3: keeperservice.grpc.UploadMediaSecretRequest.trailer:type_name -> keeperservice.grpc.MediaUploadTrailer
+	7,  // 4: keeperservice.grpc.DownloadMediaSecretResponse.secretPart:type_name -> keeperservice.grpc.MediaSecret
+	6,  // 5: keeperservice.grpc.CommitChunkedMediaRequest.metadata:type_name -> keeperservice.grpc.MediaSecretMetadata
+	6,  // 6: keeperservice.grpc.PresignedUploadRequest.metadata:type_name -> keeperservice.grpc.MediaSecretMetadata
+	0,  // 7: keeperservice.grpc.Secret.secret_type:type_name -> keeperservice.grpc.SecretType
+	25, // 8: keeperservice.grpc.Secret.tags:type_name -> keeperservice.grpc.SecretTag
+	0,  // 9: keeperservice.grpc.SecretListRequest.secret_type:type_name -> keeperservice.grpc.SecretType
+	1,  // 10: keeperservice.grpc.SecretListRequest.sort:type_name -> keeperservice.grpc.SecretSortField
+	26, // 11: keeperservice.grpc.SecretListResponse.secrets:type_name -> keeperservice.grpc.Secret
+	0,  // 12: keeperservice.grpc.SecretSetRequest.secret_type:type_name -> keeperservice.grpc.SecretType
+	0,  // 13: keeperservice.grpc.SecretGetRequest.secret_type:type_name -> keeperservice.grpc.SecretType
+	26, // 14: keeperservice.grpc.SecretGetResponse.secret:type_name -> keeperservice.grpc.Secret
+	0,  // 15: keeperservice.grpc.SecretUpdateRequest.secret_type:type_name -> keeperservice.grpc.SecretType
+	0,  // 16: keeperservice.grpc.SecretDeleteRequest.secret_type:type_name -> keeperservice.grpc.SecretType
+	0,  // 17: keeperservice.grpc.SecretMigrateNameRequest.secret_type:type_name -> keeperservice.grpc.SecretType
+	0,  // 18: keeperservice.grpc.SecretRenameRequest.secret_type:type_name -> keeperservice.grpc.SecretType
+	0,  // 19: keeperservice.grpc.SecretRenameRequest.new_secret_type:type_name -> keeperservice.grpc.SecretType
+	0,  // 20: keeperservice.grpc.SecretSetTagsRequest.secret_type:type_name -> keeperservice.grpc.SecretType
+	25, // 21: keeperservice.grpc.SecretSetTagsRequest.tags:type_name -> keeperservice.grpc.SecretTag
+	0,  // 22: keeperservice.grpc.BatchItemResult.secret_type:type_name -> keeperservice.grpc.SecretType
+	29, // 23: keeperservice.grpc.BatchSetRequest.items:type_name -> keeperservice.grpc.SecretSetRequest
+	43, // 24: keeperservice.grpc.BatchSetResponse.results:type_name -> keeperservice.grpc.BatchItemResult
+	31, // 25: keeperservice.grpc.BatchGetRequest.items:type_name -> keeperservice.grpc.SecretGetRequest
+	43, // 26: keeperservice.grpc.BatchGetResult.result:type_name -> keeperservice.grpc.BatchItemResult
+	26, // 27: keeperservice.grpc.BatchGetResult.secret:type_name -> keeperservice.grpc.Secret
+	47, // 28: keeperservice.grpc.BatchGetResponse.results:type_name -> keeperservice.grpc.BatchGetResult
+	35, // 29: keeperservice.grpc.BatchDeleteRequest.items:type_name -> keeperservice.grpc.SecretDeleteRequest
+	43, // 30: keeperservice.grpc.BatchDeleteResponse.results:type_name -> keeperservice.grpc.BatchItemResult
+	2,  // 31: keeperservice.grpc.KeeperService.Ping:input_type -> keeperservice.grpc.PingRequest
+	4,  // 32: keeperservice.grpc.KeeperService.Register:input_type -> keeperservice.grpc.UserCredentialsRequest
+	4,  // 33: keeperservice.grpc.KeeperService.Login:input_type -> keeperservice.grpc.UserCredentialsRequest
+	10, // 34: keeperservice.grpc.KeeperService.UploadMediaSecret:input_type -> keeperservice.grpc.UploadMediaSecretRequest
+	12, // 35: keeperservice.grpc.KeeperService.DownloadMediaSecret:input_type -> keeperservice.grpc.DownloadMediaSecretRequest
+	6,  // 36: keeperservice.grpc.KeeperService.StartMediaUpload:input_type -> keeperservice.grpc.MediaSecretMetadata
+	14, // 37: keeperservice.grpc.KeeperService.GetMediaUploadSession:input_type -> keeperservice.grpc.MediaUploadSessionRequest
+	14, // 38: keeperservice.grpc.KeeperService.AbortMediaUpload:input_type -> keeperservice.grpc.MediaUploadSessionRequest
+	16, // 39: keeperservice.grpc.KeeperService.GetMissingMediaChunks:input_type -> keeperservice.grpc.MediaChunksRequest
+	18, // 40: keeperservice.grpc.KeeperService.UploadMediaChunk:input_type -> keeperservice.grpc.UploadMediaChunkRequest
+	20, // 41: keeperservice.grpc.KeeperService.CommitChunkedMedia:input_type -> keeperservice.grpc.CommitChunkedMediaRequest
+	21, // 42: keeperservice.grpc.KeeperService.StartPresignedMediaUpload:input_type -> keeperservice.grpc.PresignedUploadRequest
+	23, // 43: keeperservice.grpc.KeeperService.CommitMediaUpload:input_type -> keeperservice.grpc.CommitMediaUploadRequest
+	12, // 44: keeperservice.grpc.KeeperService.GetPresignedMediaDownload:input_type -> keeperservice.grpc.DownloadMediaSecretRequest
+	27, // 45: keeperservice.grpc.KeeperService.SecretList:input_type -> keeperservice.grpc.SecretListRequest
+	29, // 46: keeperservice.grpc.KeeperService.SecretSet:input_type -> keeperservice.grpc.SecretSetRequest
+	31, // 47: keeperservice.grpc.KeeperService.SecretGet:input_type -> keeperservice.grpc.SecretGetRequest
+	33, // 48: keeperservice.grpc.KeeperService.SecretUpdate:input_type -> keeperservice.grpc.SecretUpdateRequest
+	35, // 49: keeperservice.grpc.KeeperService.SecretDelete:input_type -> keeperservice.grpc.SecretDeleteRequest
+	37, // 50: keeperservice.grpc.KeeperService.SecretMigrateName:input_type -> keeperservice.grpc.SecretMigrateNameRequest
+	39, // 51: keeperservice.grpc.KeeperService.SecretRename:input_type -> keeperservice.grpc.SecretRenameRequest
+	41, // 52: keeperservice.grpc.KeeperService.SecretSetTags:input_type -> keeperservice.grpc.SecretSetTagsRequest
+	44, // 53: keeperservice.grpc.KeeperService.BatchSet:input_type -> keeperservice.grpc.BatchSetRequest
+	46, // 54: keeperservice.grpc.KeeperService.BatchGet:input_type -> keeperservice.grpc.BatchGetRequest
+	49, // 55: keeperservice.grpc.KeeperService.BatchDelete:input_type -> keeperservice.grpc.BatchDeleteRequest
+	51, // 56: keeperservice.grpc.KeeperService.GetUsage:input_type -> keeperservice.grpc.UsageRequest
+	3,  // 57: keeperservice.grpc.KeeperService.Ping:output_type -> keeperservice.grpc.PingResponse
+	5,  // 58: keeperservice.grpc.KeeperService.Register:output_type -> keeperservice.grpc.UserCredentialsResponse
+	5,  // 59: keeperservice.grpc.KeeperService.Login:output_type -> keeperservice.grpc.UserCredentialsResponse
+	11, // 60: keeperservice.grpc.KeeperService.UploadMediaSecret:output_type -> keeperservice.grpc.UploadMediaSecretResponse
+	13, // 61: keeperservice.grpc.KeeperService.DownloadMediaSecret:output_type -> keeperservice.grpc.DownloadMediaSecretResponse
+	15, // 62: keeperservice.grpc.KeeperService.StartMediaUpload:output_type -> keeperservice.grpc.MediaUploadSessionResponse
+	15, // 63: keeperservice.grpc.KeeperService.GetMediaUploadSession:output_type -> keeperservice.grpc.MediaUploadSessionResponse
+	15, // 64: keeperservice.grpc.KeeperService.AbortMediaUpload:output_type -> keeperservice.grpc.MediaUploadSessionResponse
+	17, // 65: keeperservice.grpc.KeeperService.GetMissingMediaChunks:output_type -> keeperservice.grpc.MediaChunksResponse
+	19, // 66: keeperservice.grpc.KeeperService.UploadMediaChunk:output_type -> keeperservice.grpc.UploadMediaChunkResponse
+	11, // 67: keeperservice.grpc.KeeperService.CommitChunkedMedia:output_type -> keeperservice.grpc.UploadMediaSecretResponse
+	22, // 68: keeperservice.grpc.KeeperService.StartPresignedMediaUpload:output_type -> keeperservice.grpc.PresignedUploadResponse
+	11, // 69: keeperservice.grpc.KeeperService.CommitMediaUpload:output_type -> keeperservice.grpc.UploadMediaSecretResponse
+	24, // 70: keeperservice.grpc.KeeperService.GetPresignedMediaDownload:output_type -> keeperservice.grpc.PresignedDownloadResponse
+	28, // 71: keeperservice.grpc.KeeperService.SecretList:output_type -> keeperservice.grpc.SecretListResponse
+	30, // 72: keeperservice.grpc.KeeperService.SecretSet:output_type -> keeperservice.grpc.SecretSetResponse
+	32, // 73: keeperservice.grpc.KeeperService.SecretGet:output_type -> keeperservice.grpc.SecretGetResponse
+	34, // 74: keeperservice.grpc.KeeperService.SecretUpdate:output_type -> keeperservice.grpc.SecretUpdateResponse
+	36, // 75: keeperservice.grpc.KeeperService.SecretDelete:output_type -> keeperservice.grpc.SecretDeleteResponse
+	38, // 76: keeperservice.grpc.KeeperService.SecretMigrateName:output_type -> keeperservice.grpc.SecretMigrateNameResponse
+	40, // 77: keeperservice.grpc.KeeperService.SecretRename:output_type -> keeperservice.grpc.SecretRenameResponse
+	42, // 78: keeperservice.grpc.KeeperService.SecretSetTags:output_type -> keeperservice.grpc.SecretSetTagsResponse
+	45, // 79: keeperservice.grpc.KeeperService.BatchSet:output_type -> keeperservice.grpc.BatchSetResponse
+	48, // 80: keeperservice.grpc.KeeperService.BatchGet:output_type -> keeperservice.grpc.BatchGetResponse
+	50, // 81: keeperservice.grpc.KeeperService.BatchDelete:output_type -> keeperservice.grpc.BatchDeleteResponse
+	52, // 82: keeperservice.grpc.KeeperService.GetUsage:output_type -> keeperservice.grpc.UsageResponse
+	57, // [57:83] is the sub-list for method output_type
+	31, // [31:57] is the sub-list for method input_type
+	31, // [31:31] is the sub-list for extension type_name
+	31, // [31:31] is the sub-list for extension extendee
+	0,  // [0:31] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_keeperserver_proto_init() }
@@ -1538,8 +4136,116 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_keeperserver_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PingResponse); i {
+		file_api_proto_keeperserver_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PingResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserCredentialsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserCredentialsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MediaSecretMetadata); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MediaSecret); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MediaUploadResume); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MediaUploadTrailer); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UploadMediaSecretRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UploadMediaSecretResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DownloadMediaSecretRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1550,8 +4256,8 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_keeperserver_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UserCredentialsRequest); i {
+		file_api_proto_keeperserver_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DownloadMediaSecretResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1562,8 +4268,8 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_keeperserver_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UserCredentialsResponse); i {
+		file_api_proto_keeperserver_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MediaUploadSessionRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1574,8 +4280,8 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_keeperserver_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MediaSecretMetadata); i {
+		file_api_proto_keeperserver_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MediaUploadSessionResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1586,8 +4292,8 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_keeperserver_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MediaSecret); i {
+		file_api_proto_keeperserver_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MediaChunksRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1598,8 +4304,8 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_keeperserver_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UploadMediaSecretRequest); i {
+		file_api_proto_keeperserver_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MediaChunksResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1610,8 +4316,8 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_keeperserver_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UploadMediaSecretResponse); i {
+		file_api_proto_keeperserver_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UploadMediaChunkRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1622,8 +4328,8 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_keeperserver_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DownloadMediaSecretRequest); i {
+		file_api_proto_keeperserver_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UploadMediaChunkResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1634,8 +4340,8 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_keeperserver_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DownloadMediaSecretResponse); i {
+		file_api_proto_keeperserver_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommitChunkedMediaRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1646,7 +4352,67 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_keeperserver_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+		file_api_proto_keeperserver_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PresignedUploadRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PresignedUploadResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommitMediaUploadRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PresignedDownloadResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SecretTag); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Secret); i {
 			case 0:
 				return &v.state
@@ -1658,7 +4424,7 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_keeperserver_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+		file_api_proto_keeperserver_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SecretListRequest); i {
 			case 0:
 				return &v.state
@@ -1670,7 +4436,7 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_keeperserver_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+		file_api_proto_keeperserver_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SecretListResponse); i {
 			case 0:
 				return &v.state
@@ -1682,7 +4448,7 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_keeperserver_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+		file_api_proto_keeperserver_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SecretSetRequest); i {
 			case 0:
 				return &v.state
@@ -1694,7 +4460,7 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_keeperserver_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+		file_api_proto_keeperserver_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SecretSetResponse); i {
 			case 0:
 				return &v.state
@@ -1706,7 +4472,7 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_keeperserver_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+		file_api_proto_keeperserver_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SecretGetRequest); i {
 			case 0:
 				return &v.state
@@ -1718,7 +4484,7 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_keeperserver_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+		file_api_proto_keeperserver_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SecretGetResponse); i {
 			case 0:
 				return &v.state
@@ -1730,7 +4496,7 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_keeperserver_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+		file_api_proto_keeperserver_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SecretUpdateRequest); i {
 			case 0:
 				return &v.state
@@ -1742,7 +4508,7 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_keeperserver_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+		file_api_proto_keeperserver_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SecretUpdateResponse); i {
 			case 0:
 				return &v.state
@@ -1754,7 +4520,7 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_keeperserver_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+		file_api_proto_keeperserver_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SecretDeleteRequest); i {
 			case 0:
 				return &v.state
@@ -1766,7 +4532,7 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
-		file_api_proto_keeperserver_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+		file_api_proto_keeperserver_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*SecretDeleteResponse); i {
 			case 0:
 				return &v.state
@@ -1778,18 +4544,213 @@ func file_api_proto_keeperserver_proto_init() {
 				return nil
 			}
 		}
+		file_api_proto_keeperserver_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SecretMigrateNameRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SecretMigrateNameResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SecretRenameRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SecretRenameResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SecretSetTagsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SecretSetTagsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchItemResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchSetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchSetResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchGetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchGetResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchGetResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchDeleteRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchDeleteResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UsageRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_proto_keeperserver_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UsageResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
-	file_api_proto_keeperserver_proto_msgTypes[6].OneofWrappers = []interface{}{
+	file_api_proto_keeperserver_proto_msgTypes[8].OneofWrappers = []interface{}{
 		(*UploadMediaSecretRequest_Metadata)(nil),
 		(*UploadMediaSecretRequest_Data)(nil),
+		(*UploadMediaSecretRequest_Resume)(nil),
+		(*UploadMediaSecretRequest_Trailer)(nil),
 	}
+	file_api_proto_keeperserver_proto_msgTypes[37].OneofWrappers = []interface{}{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_api_proto_keeperserver_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   21,
+			NumEnums:      2,
+			NumMessages:   51,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,16 +19,32 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	KeeperService_Ping_FullMethodName                = "/keeperservice.grpc.KeeperService/Ping"
-	KeeperService_Register_FullMethodName            = "/keeperservice.grpc.KeeperService/Register"
-	KeeperService_Login_FullMethodName               = "/keeperservice.grpc.KeeperService/Login"
-	KeeperService_UploadMediaSecret_FullMethodName   = "/keeperservice.grpc.KeeperService/UploadMediaSecret"
-	KeeperService_DownloadMediaSecret_FullMethodName = "/keeperservice.grpc.KeeperService/DownloadMediaSecret"
-	KeeperService_SecretList_FullMethodName          = "/keeperservice.grpc.KeeperService/SecretList"
-	KeeperService_SecretSet_FullMethodName           = "/keeperservice.grpc.KeeperService/SecretSet"
-	KeeperService_SecretGet_FullMethodName           = "/keeperservice.grpc.KeeperService/SecretGet"
-	KeeperService_SecretUpdate_FullMethodName        = "/keeperservice.grpc.KeeperService/SecretUpdate"
-	KeeperService_SecretDelete_FullMethodName        = "/keeperservice.grpc.KeeperService/SecretDelete"
+	KeeperService_Ping_FullMethodName                      = "/keeperservice.grpc.KeeperService/Ping"
+	KeeperService_Register_FullMethodName                  = "/keeperservice.grpc.KeeperService/Register"
+	KeeperService_Login_FullMethodName                     = "/keeperservice.grpc.KeeperService/Login"
+	KeeperService_UploadMediaSecret_FullMethodName         = "/keeperservice.grpc.KeeperService/UploadMediaSecret"
+	KeeperService_DownloadMediaSecret_FullMethodName       = "/keeperservice.grpc.KeeperService/DownloadMediaSecret"
+	KeeperService_StartMediaUpload_FullMethodName          = "/keeperservice.grpc.KeeperService/StartMediaUpload"
+	KeeperService_GetMediaUploadSession_FullMethodName     = "/keeperservice.grpc.KeeperService/GetMediaUploadSession"
+	KeeperService_AbortMediaUpload_FullMethodName          = "/keeperservice.grpc.KeeperService/AbortMediaUpload"
+	KeeperService_GetMissingMediaChunks_FullMethodName     = "/keeperservice.grpc.KeeperService/GetMissingMediaChunks"
+	KeeperService_UploadMediaChunk_FullMethodName          = "/keeperservice.grpc.KeeperService/UploadMediaChunk"
+	KeeperService_CommitChunkedMedia_FullMethodName        = "/keeperservice.grpc.KeeperService/CommitChunkedMedia"
+	KeeperService_StartPresignedMediaUpload_FullMethodName = "/keeperservice.grpc.KeeperService/StartPresignedMediaUpload"
+	KeeperService_CommitMediaUpload_FullMethodName         = "/keeperservice.grpc.KeeperService/CommitMediaUpload"
+	KeeperService_GetPresignedMediaDownload_FullMethodName = "/keeperservice.grpc.KeeperService/GetPresignedMediaDownload"
+	KeeperService_SecretList_FullMethodName                = "/keeperservice.grpc.KeeperService/SecretList"
+	KeeperService_SecretSet_FullMethodName                 = "/keeperservice.grpc.KeeperService/SecretSet"
+	KeeperService_SecretGet_FullMethodName                 = "/keeperservice.grpc.KeeperService/SecretGet"
+	KeeperService_SecretUpdate_FullMethodName              = "/keeperservice.grpc.KeeperService/SecretUpdate"
+	KeeperService_SecretDelete_FullMethodName              = "/keeperservice.grpc.KeeperService/SecretDelete"
+	KeeperService_SecretMigrateName_FullMethodName         = "/keeperservice.grpc.KeeperService/SecretMigrateName"
+	KeeperService_SecretRename_FullMethodName              = "/keeperservice.grpc.KeeperService/SecretRename"
+	KeeperService_SecretSetTags_FullMethodName             = "/keeperservice.grpc.KeeperService/SecretSetTags"
+	KeeperService_BatchSet_FullMethodName                  = "/keeperservice.grpc.KeeperService/BatchSet"
+	KeeperService_BatchGet_FullMethodName                  = "/keeperservice.grpc.KeeperService/BatchGet"
+	KeeperService_BatchDelete_FullMethodName               = "/keeperservice.grpc.KeeperService/BatchDelete"
+	KeeperService_GetUsage_FullMethodName                  = "/keeperservice.grpc.KeeperService/GetUsage"
 )
 
 // KeeperServiceClient is the client API for KeeperService service.
@@ -40,11 +56,27 @@ type KeeperServiceClient interface {
 	Login(ctx context.Context, in *UserCredentialsRequest, opts ...grpc.CallOption) (*UserCredentialsResponse, error)
 	UploadMediaSecret(ctx context.Context, opts ...grpc.CallOption) (KeeperService_UploadMediaSecretClient, error)
 	DownloadMediaSecret(ctx context.Context, in *DownloadMediaSecretRequest, opts ...grpc.CallOption) (KeeperService_DownloadMediaSecretClient, error)
+	StartMediaUpload(ctx context.Context, in *MediaSecretMetadata, opts ...grpc.CallOption) (*MediaUploadSessionResponse, error)
+	GetMediaUploadSession(ctx context.Context, in *MediaUploadSessionRequest, opts ...grpc.CallOption) (*MediaUploadSessionResponse, error)
+	AbortMediaUpload(ctx context.Context, in *MediaUploadSessionRequest, opts ...grpc.CallOption) (*MediaUploadSessionResponse, error)
+	GetMissingMediaChunks(ctx context.Context, in *MediaChunksRequest, opts ...grpc.CallOption) (*MediaChunksResponse, error)
+	UploadMediaChunk(ctx context.Context, in *UploadMediaChunkRequest, opts ...grpc.CallOption) (*UploadMediaChunkResponse, error)
+	CommitChunkedMedia(ctx context.Context, in *CommitChunkedMediaRequest, opts ...grpc.CallOption) (*UploadMediaSecretResponse, error)
+	StartPresignedMediaUpload(ctx context.Context, in *PresignedUploadRequest, opts ...grpc.CallOption) (*PresignedUploadResponse, error)
+	CommitMediaUpload(ctx context.Context, in *CommitMediaUploadRequest, opts ...grpc.CallOption) (*UploadMediaSecretResponse, error)
+	GetPresignedMediaDownload(ctx context.Context, in *DownloadMediaSecretRequest, opts ...grpc.CallOption) (*PresignedDownloadResponse, error)
 	SecretList(ctx context.Context, in *SecretListRequest, opts ...grpc.CallOption) (*SecretListResponse, error)
 	SecretSet(ctx context.Context, in *SecretSetRequest, opts ...grpc.CallOption) (*SecretSetResponse, error)
 	SecretGet(ctx context.Context, in *SecretGetRequest, opts ...grpc.CallOption) (*SecretGetResponse, error)
 	SecretUpdate(ctx context.Context, in *SecretUpdateRequest, opts ...grpc.CallOption) (*SecretUpdateResponse, error)
 	SecretDelete(ctx context.Context, in *SecretDeleteRequest, opts ...grpc.CallOption) (*SecretDeleteResponse, error)
+	SecretMigrateName(ctx context.Context, in *SecretMigrateNameRequest, opts ...grpc.CallOption) (*SecretMigrateNameResponse, error)
+	SecretRename(ctx context.Context, in *SecretRenameRequest, opts ...grpc.CallOption) (*SecretRenameResponse, error)
+	SecretSetTags(ctx context.Context, in *SecretSetTagsRequest, opts ...grpc.CallOption) (*SecretSetTagsResponse, error)
+	BatchSet(ctx context.Context, in *BatchSetRequest, opts ...grpc.CallOption) (*BatchSetResponse, error)
+	BatchGet(ctx context.Context, in *BatchGetRequest, opts ...grpc.CallOption) (*BatchGetResponse, error)
+	BatchDelete(ctx context.Context, in *BatchDeleteRequest, opts ...grpc.CallOption) (*BatchDeleteResponse, error)
+	GetUsage(ctx context.Context, in *UsageRequest, opts ...grpc.CallOption) (*UsageResponse, error)
 }
 
 type keeperServiceClient struct {
@@ -148,6 +180,87 @@ func (x *keeperServiceDownloadMediaSecretClient) Recv() (*DownloadMediaSecretRes
 	return m, nil
 }
 
+func (c *keeperServiceClient) StartMediaUpload(ctx context.Context, in *MediaSecretMetadata, opts ...grpc.CallOption) (*MediaUploadSessionResponse, error) {
+	out := new(MediaUploadSessionResponse)
+	err := c.cc.Invoke(ctx, KeeperService_StartMediaUpload_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperServiceClient) GetMediaUploadSession(ctx context.Context, in *MediaUploadSessionRequest, opts ...grpc.CallOption) (*MediaUploadSessionResponse, error) {
+	out := new(MediaUploadSessionResponse)
+	err := c.cc.Invoke(ctx, KeeperService_GetMediaUploadSession_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperServiceClient) AbortMediaUpload(ctx context.Context, in *MediaUploadSessionRequest, opts ...grpc.CallOption) (*MediaUploadSessionResponse, error) {
+	out := new(MediaUploadSessionResponse)
+	err := c.cc.Invoke(ctx, KeeperService_AbortMediaUpload_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperServiceClient) GetMissingMediaChunks(ctx context.Context, in *MediaChunksRequest, opts ...grpc.CallOption) (*MediaChunksResponse, error) {
+	out := new(MediaChunksResponse)
+	err := c.cc.Invoke(ctx, KeeperService_GetMissingMediaChunks_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperServiceClient) UploadMediaChunk(ctx context.Context, in *UploadMediaChunkRequest, opts ...grpc.CallOption) (*UploadMediaChunkResponse, error) {
+	out := new(UploadMediaChunkResponse)
+	err := c.cc.Invoke(ctx, KeeperService_UploadMediaChunk_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperServiceClient) CommitChunkedMedia(ctx context.Context, in *CommitChunkedMediaRequest, opts ...grpc.CallOption) (*UploadMediaSecretResponse, error) {
+	out := new(UploadMediaSecretResponse)
+	err := c.cc.Invoke(ctx, KeeperService_CommitChunkedMedia_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperServiceClient) StartPresignedMediaUpload(ctx context.Context, in *PresignedUploadRequest, opts ...grpc.CallOption) (*PresignedUploadResponse, error) {
+	out := new(PresignedUploadResponse)
+	err := c.cc.Invoke(ctx, KeeperService_StartPresignedMediaUpload_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperServiceClient) CommitMediaUpload(ctx context.Context, in *CommitMediaUploadRequest, opts ...grpc.CallOption) (*UploadMediaSecretResponse, error) {
+	out := new(UploadMediaSecretResponse)
+	err := c.cc.Invoke(ctx, KeeperService_CommitMediaUpload_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperServiceClient) GetPresignedMediaDownload(ctx context.Context, in *DownloadMediaSecretRequest, opts ...grpc.CallOption) (*PresignedDownloadResponse, error) {
+	out := new(PresignedDownloadResponse)
+	err := c.cc.Invoke(ctx, KeeperService_GetPresignedMediaDownload_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *keeperServiceClient) SecretList(ctx context.Context, in *SecretListRequest, opts ...grpc.CallOption) (*SecretListResponse, error) {
 	out := new(SecretListResponse)
 	err := c.cc.Invoke(ctx, KeeperService_SecretList_FullMethodName, in, out, opts...)
@@ -193,6 +306,69 @@ func (c *keeperServiceClient) SecretDelete(ctx context.Context, in *SecretDelete
 	return out, nil
 }
 
+func (c *keeperServiceClient) SecretMigrateName(ctx context.Context, in *SecretMigrateNameRequest, opts ...grpc.CallOption) (*SecretMigrateNameResponse, error) {
+	out := new(SecretMigrateNameResponse)
+	err := c.cc.Invoke(ctx, KeeperService_SecretMigrateName_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperServiceClient) SecretRename(ctx context.Context, in *SecretRenameRequest, opts ...grpc.CallOption) (*SecretRenameResponse, error) {
+	out := new(SecretRenameResponse)
+	err := c.cc.Invoke(ctx, KeeperService_SecretRename_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperServiceClient) SecretSetTags(ctx context.Context, in *SecretSetTagsRequest, opts ...grpc.CallOption) (*SecretSetTagsResponse, error) {
+	out := new(SecretSetTagsResponse)
+	err := c.cc.Invoke(ctx, KeeperService_SecretSetTags_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperServiceClient) BatchSet(ctx context.Context, in *BatchSetRequest, opts ...grpc.CallOption) (*BatchSetResponse, error) {
+	out := new(BatchSetResponse)
+	err := c.cc.Invoke(ctx, KeeperService_BatchSet_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperServiceClient) BatchGet(ctx context.Context, in *BatchGetRequest, opts ...grpc.CallOption) (*BatchGetResponse, error) {
+	out := new(BatchGetResponse)
+	err := c.cc.Invoke(ctx, KeeperService_BatchGet_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperServiceClient) BatchDelete(ctx context.Context, in *BatchDeleteRequest, opts ...grpc.CallOption) (*BatchDeleteResponse, error) {
+	out := new(BatchDeleteResponse)
+	err := c.cc.Invoke(ctx, KeeperService_BatchDelete_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keeperServiceClient) GetUsage(ctx context.Context, in *UsageRequest, opts ...grpc.CallOption) (*UsageResponse, error) {
+	out := new(UsageResponse)
+	err := c.cc.Invoke(ctx, KeeperService_GetUsage_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // KeeperServiceServer is the server API for KeeperService service.
 // All implementations must embed UnimplementedKeeperServiceServer
 // for forward compatibility
@@ -202,11 +378,27 @@ type KeeperServiceServer interface {
 	Login(context.Context, *UserCredentialsRequest) (*UserCredentialsResponse, error)
 	UploadMediaSecret(KeeperService_UploadMediaSecretServer) error
 	DownloadMediaSecret(*DownloadMediaSecretRequest, KeeperService_DownloadMediaSecretServer) error
+	StartMediaUpload(context.Context, *MediaSecretMetadata) (*MediaUploadSessionResponse, error)
+	GetMediaUploadSession(context.Context, *MediaUploadSessionRequest) (*MediaUploadSessionResponse, error)
+	AbortMediaUpload(context.Context, *MediaUploadSessionRequest) (*MediaUploadSessionResponse, error)
+	GetMissingMediaChunks(context.Context, *MediaChunksRequest) (*MediaChunksResponse, error)
+	UploadMediaChunk(context.Context, *UploadMediaChunkRequest) (*UploadMediaChunkResponse, error)
+	CommitChunkedMedia(context.Context, *CommitChunkedMediaRequest) (*UploadMediaSecretResponse, error)
+	StartPresignedMediaUpload(context.Context, *PresignedUploadRequest) (*PresignedUploadResponse, error)
+	CommitMediaUpload(context.Context, *CommitMediaUploadRequest) (*UploadMediaSecretResponse, error)
+	GetPresignedMediaDownload(context.Context, *DownloadMediaSecretRequest) (*PresignedDownloadResponse, error)
 	SecretList(context.Context, *SecretListRequest) (*SecretListResponse, error)
 	SecretSet(context.Context, *SecretSetRequest) (*SecretSetResponse, error)
 	SecretGet(context.Context, *SecretGetRequest) (*SecretGetResponse, error)
 	SecretUpdate(context.Context, *SecretUpdateRequest) (*SecretUpdateResponse, error)
 	SecretDelete(context.Context, *SecretDeleteRequest) (*SecretDeleteResponse, error)
+	SecretMigrateName(context.Context, *SecretMigrateNameRequest) (*SecretMigrateNameResponse, error)
+	SecretRename(context.Context, *SecretRenameRequest) (*SecretRenameResponse, error)
+	SecretSetTags(context.Context, *SecretSetTagsRequest) (*SecretSetTagsResponse, error)
+	BatchSet(context.Context, *BatchSetRequest) (*BatchSetResponse, error)
+	BatchGet(context.Context, *BatchGetRequest) (*BatchGetResponse, error)
+	BatchDelete(context.Context, *BatchDeleteRequest) (*BatchDeleteResponse, error)
+	GetUsage(context.Context, *UsageRequest) (*UsageResponse, error)
 	mustEmbedUnimplementedKeeperServiceServer()
 }
 
@@ -229,6 +421,33 @@ func (UnimplementedKeeperServiceServer) UploadMediaSecret(KeeperService_UploadMe
 func (UnimplementedKeeperServiceServer) DownloadMediaSecret(*DownloadMediaSecretRequest, KeeperService_DownloadMediaSecretServer) error {
 	return status.Errorf(codes.Unimplemented, "method DownloadMediaSecret not implemented")
 }
+func (UnimplementedKeeperServiceServer) StartMediaUpload(context.Context, *MediaSecretMetadata) (*MediaUploadSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartMediaUpload not implemented")
+}
+func (UnimplementedKeeperServiceServer) GetMediaUploadSession(context.Context, *MediaUploadSessionRequest) (*MediaUploadSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMediaUploadSession not implemented")
+}
+func (UnimplementedKeeperServiceServer) AbortMediaUpload(context.Context, *MediaUploadSessionRequest) (*MediaUploadSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AbortMediaUpload not implemented")
+}
+func (UnimplementedKeeperServiceServer) GetMissingMediaChunks(context.Context, *MediaChunksRequest) (*MediaChunksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMissingMediaChunks not implemented")
+}
+func (UnimplementedKeeperServiceServer) UploadMediaChunk(context.Context, *UploadMediaChunkRequest) (*UploadMediaChunkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UploadMediaChunk not implemented")
+}
+func (UnimplementedKeeperServiceServer) CommitChunkedMedia(context.Context, *CommitChunkedMediaRequest) (*UploadMediaSecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitChunkedMedia not implemented")
+}
+func (UnimplementedKeeperServiceServer) StartPresignedMediaUpload(context.Context, *PresignedUploadRequest) (*PresignedUploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartPresignedMediaUpload not implemented")
+}
+func (UnimplementedKeeperServiceServer) CommitMediaUpload(context.Context, *CommitMediaUploadRequest) (*UploadMediaSecretResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitMediaUpload not implemented")
+}
+func (UnimplementedKeeperServiceServer) GetPresignedMediaDownload(context.Context, *DownloadMediaSecretRequest) (*PresignedDownloadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPresignedMediaDownload not implemented")
+}
 func (UnimplementedKeeperServiceServer) SecretList(context.Context, *SecretListRequest) (*SecretListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SecretList not implemented")
 }
@@ -244,6 +463,27 @@ func (UnimplementedKeeperServiceServer) SecretUpdate(context.Context, *SecretUpd
 func (UnimplementedKeeperServiceServer) SecretDelete(context.Context, *SecretDeleteRequest) (*SecretDeleteResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SecretDelete not implemented")
 }
+func (UnimplementedKeeperServiceServer) SecretMigrateName(context.Context, *SecretMigrateNameRequest) (*SecretMigrateNameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SecretMigrateName not implemented")
+}
+func (UnimplementedKeeperServiceServer) SecretRename(context.Context, *SecretRenameRequest) (*SecretRenameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SecretRename not implemented")
+}
+func (UnimplementedKeeperServiceServer) SecretSetTags(context.Context, *SecretSetTagsRequest) (*SecretSetTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SecretSetTags not implemented")
+}
+func (UnimplementedKeeperServiceServer) BatchSet(context.Context, *BatchSetRequest) (*BatchSetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchSet not implemented")
+}
+func (UnimplementedKeeperServiceServer) BatchGet(context.Context, *BatchGetRequest) (*BatchGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGet not implemented")
+}
+func (UnimplementedKeeperServiceServer) BatchDelete(context.Context, *BatchDeleteRequest) (*BatchDeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDelete not implemented")
+}
+func (UnimplementedKeeperServiceServer) GetUsage(context.Context, *UsageRequest) (*UsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsage not implemented")
+}
 func (UnimplementedKeeperServiceServer) mustEmbedUnimplementedKeeperServiceServer() {}
 
 // UnsafeKeeperServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -358,6 +598,168 @@ func (x *keeperServiceDownloadMediaSecretServer) Send(m *DownloadMediaSecretResp
 	return x.ServerStream.SendMsg(m)
 }
 
+func _KeeperService_StartMediaUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MediaSecretMetadata)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServiceServer).StartMediaUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeeperService_StartMediaUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServiceServer).StartMediaUpload(ctx, req.(*MediaSecretMetadata))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeeperService_GetMediaUploadSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MediaUploadSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServiceServer).GetMediaUploadSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeeperService_GetMediaUploadSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServiceServer).GetMediaUploadSession(ctx, req.(*MediaUploadSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeeperService_AbortMediaUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MediaUploadSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServiceServer).AbortMediaUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeeperService_AbortMediaUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServiceServer).AbortMediaUpload(ctx, req.(*MediaUploadSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeeperService_GetMissingMediaChunks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MediaChunksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServiceServer).GetMissingMediaChunks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeeperService_GetMissingMediaChunks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServiceServer).GetMissingMediaChunks(ctx, req.(*MediaChunksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeeperService_UploadMediaChunk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadMediaChunkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServiceServer).UploadMediaChunk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeeperService_UploadMediaChunk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServiceServer).UploadMediaChunk(ctx, req.(*UploadMediaChunkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeeperService_CommitChunkedMedia_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitChunkedMediaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServiceServer).CommitChunkedMedia(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeeperService_CommitChunkedMedia_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServiceServer).CommitChunkedMedia(ctx, req.(*CommitChunkedMediaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeeperService_StartPresignedMediaUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PresignedUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServiceServer).StartPresignedMediaUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeeperService_StartPresignedMediaUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServiceServer).StartPresignedMediaUpload(ctx, req.(*PresignedUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeeperService_CommitMediaUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitMediaUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServiceServer).CommitMediaUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeeperService_CommitMediaUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServiceServer).CommitMediaUpload(ctx, req.(*CommitMediaUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeeperService_GetPresignedMediaDownload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DownloadMediaSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServiceServer).GetPresignedMediaDownload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeeperService_GetPresignedMediaDownload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServiceServer).GetPresignedMediaDownload(ctx, req.(*DownloadMediaSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _KeeperService_SecretList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SecretListRequest)
 	if err := dec(in); err != nil {
@@ -448,6 +850,132 @@ func _KeeperService_SecretDelete_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _KeeperService_SecretMigrateName_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SecretMigrateNameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServiceServer).SecretMigrateName(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeeperService_SecretMigrateName_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServiceServer).SecretMigrateName(ctx, req.(*SecretMigrateNameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeeperService_SecretRename_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SecretRenameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServiceServer).SecretRename(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeeperService_SecretRename_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServiceServer).SecretRename(ctx, req.(*SecretRenameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeeperService_SecretSetTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SecretSetTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServiceServer).SecretSetTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeeperService_SecretSetTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServiceServer).SecretSetTags(ctx, req.(*SecretSetTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeeperService_BatchSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServiceServer).BatchSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeeperService_BatchSet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServiceServer).BatchSet(ctx, req.(*BatchSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeeperService_BatchGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServiceServer).BatchGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeeperService_BatchGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServiceServer).BatchGet(ctx, req.(*BatchGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeeperService_BatchDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServiceServer).BatchDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeeperService_BatchDelete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServiceServer).BatchDelete(ctx, req.(*BatchDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeeperService_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeeperServiceServer).GetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeeperService_GetUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeeperServiceServer).GetUsage(ctx, req.(*UsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // KeeperService_ServiceDesc is the grpc.ServiceDesc for KeeperService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -467,6 +995,42 @@ var KeeperService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Login",
 			Handler:    _KeeperService_Login_Handler,
 		},
+		{
+			MethodName: "StartMediaUpload",
+			Handler:    _KeeperService_StartMediaUpload_Handler,
+		},
+		{
+			MethodName: "GetMediaUploadSession",
+			Handler:    _KeeperService_GetMediaUploadSession_Handler,
+		},
+		{
+			MethodName: "AbortMediaUpload",
+			Handler:    _KeeperService_AbortMediaUpload_Handler,
+		},
+		{
+			MethodName: "GetMissingMediaChunks",
+			Handler:    _KeeperService_GetMissingMediaChunks_Handler,
+		},
+		{
+			MethodName: "UploadMediaChunk",
+			Handler:    _KeeperService_UploadMediaChunk_Handler,
+		},
+		{
+			MethodName: "CommitChunkedMedia",
+			Handler:    _KeeperService_CommitChunkedMedia_Handler,
+		},
+		{
+			MethodName: "StartPresignedMediaUpload",
+			Handler:    _KeeperService_StartPresignedMediaUpload_Handler,
+		},
+		{
+			MethodName: "CommitMediaUpload",
+			Handler:    _KeeperService_CommitMediaUpload_Handler,
+		},
+		{
+			MethodName: "GetPresignedMediaDownload",
+			Handler:    _KeeperService_GetPresignedMediaDownload_Handler,
+		},
 		{
 			MethodName: "SecretList",
 			Handler:    _KeeperService_SecretList_Handler,
@@ -487,6 +1051,34 @@ var KeeperService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SecretDelete",
 			Handler:    _KeeperService_SecretDelete_Handler,
 		},
+		{
+			MethodName: "SecretMigrateName",
+			Handler:    _KeeperService_SecretMigrateName_Handler,
+		},
+		{
+			MethodName: "SecretRename",
+			Handler:    _KeeperService_SecretRename_Handler,
+		},
+		{
+			MethodName: "SecretSetTags",
+			Handler:    _KeeperService_SecretSetTags_Handler,
+		},
+		{
+			MethodName: "BatchSet",
+			Handler:    _KeeperService_BatchSet_Handler,
+		},
+		{
+			MethodName: "BatchGet",
+			Handler:    _KeeperService_BatchGet_Handler,
+		},
+		{
+			MethodName: "BatchDelete",
+			Handler:    _KeeperService_BatchDelete_Handler,
+		},
+		{
+			MethodName: "GetUsage",
+			Handler:    _KeeperService_GetUsage_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
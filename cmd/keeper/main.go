@@ -1,12 +1,10 @@
 package main
 
 import (
-	"fmt"
 	"github.com/nessai1/gophkeeper/internal/keeper"
+	"os"
 )
 
 func main() {
-	if err := keeper.Listen(); err != nil {
-		fmt.Printf("\033[31mKeeper was crashed: %s\033[0m", err.Error())
-	}
+	os.Exit(keeper.Listen())
 }
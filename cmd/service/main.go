@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"github.com/nessai1/gophkeeper/internal/service"
+	"os"
 )
 
 var (
@@ -14,5 +15,36 @@ var (
 func main() {
 	fmt.Println(Commit)
 	fmt.Println(BuildTime)
+
+	// "service fsck [-delete] [-grace 24h]" checks consistency of media storage instead of run
+	if len(os.Args) > 1 && os.Args[1] == "fsck" {
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+		service.RunFsck()
+
+		return
+	}
+
+	// "service rotate-kek" rewraps data keys of encryption at rest by current key-encryption key
+	if len(os.Args) > 1 && os.Args[1] == "rotate-kek" {
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+		service.RunRotateKEK()
+
+		return
+	}
+
+	// "service backup <archive>" writes archive of whole service, "service restore [-verify] <archive>"
+	// restores it into empty deployment
+	if len(os.Args) > 1 && (os.Args[1] == "backup" || os.Args[1] == "restore") {
+		command := os.Args[1]
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+		if command == "backup" {
+			service.RunBackup()
+		} else {
+			service.RunRestore()
+		}
+
+		return
+	}
+
 	service.Run()
 }
@@ -19,6 +19,14 @@ type Config struct {
 
 	// Path to certificate if server has TLS connection
 	Certificate string `json:"certificate"`
+
+	// DirectMedia transfer media with media storage by presigned URLs, if service allows it
+	DirectMedia bool `json:"direct_media"`
+
+	// Command one-shot command given by arguments of application, empty for interactive mode
+	Command []string `json:"-"`
+	// PasswordFD file descriptor to read password of saved session in one-shot mode, -1 if it isn't set
+	PasswordFD int `json:"-"`
 }
 
 func fetchConfig() (Config, error) {
@@ -27,6 +35,7 @@ func fetchConfig() (Config, error) {
 	workDir := flag.String("d", "./keeperData", "Path to keeper data, like log files, user content etc.")
 	isDevMode := flag.Bool("dev", false, "Enable dev mode of application for complicated logs")
 	serverAddr := flag.String("s", "", "Address of keeper server")
+	passwordFD := flag.Int("password-fd", -1, "File descriptor to read password of saved session for one-shot command")
 
 	flag.Parse()
 
@@ -39,6 +48,8 @@ func fetchConfig() (Config, error) {
 		WorkDir:    *workDir,
 		Mode:       mode,
 		ServerAddr: *serverAddr,
+		Command:    flag.Args(),
+		PasswordFD: *passwordFD,
 	}
 
 	fileCfg, err := readFileConfig(*configPath)
@@ -62,6 +73,8 @@ func fetchConfig() (Config, error) {
 		cfg.Certificate = fileCfg.Certificate
 	}
 
+	cfg.DirectMedia = fileCfg.DirectMedia
+
 	return cfg, nil
 }
 
@@ -0,0 +1,73 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	pb "github.com/nessai1/gophkeeper/api/proto"
+	"github.com/nessai1/gophkeeper/internal/keeper/secret"
+	"google.golang.org/grpc/codes"
+	"strings"
+)
+
+// setBatchSize count of secrets created by one batch request, service rejects bigger batches
+const setBatchSize = 1000
+
+func (c *GRPCServiceConnector) SetSecrets(ctx context.Context, secrets []NewSecret) (int, error) {
+	created := 0
+	for start := 0; start < len(secrets); start += setBatchSize {
+		batch := secrets[start:min(start+setBatchSize, len(secrets))]
+		if err := c.setBatch(ctx, batch); err != nil {
+			return created, err
+		}
+
+		created += len(batch)
+	}
+
+	return created, nil
+}
+
+func (c *GRPCServiceConnector) setBatch(ctx context.Context, batch []NewSecret) error {
+	items := make([]*pb.SecretSetRequest, len(batch))
+	for i, v := range batch {
+		translatedType, err := translateSecretTypeTypeToGRPCType(v.SecretType)
+		if err != nil {
+			return fmt.Errorf("cannot set secret %s: %w", v.Name, err)
+		}
+
+		encryptedName, err := c.encryptName(v.Name)
+		if err != nil {
+			return fmt.Errorf("cannot encrypt secret name: %w", err)
+		}
+
+		expireTimestamp, maxReads := translateLimitsToGRPC(v.Limits)
+		items[i] = &pb.SecretSetRequest{
+			SecretType:      translatedType,
+			Name:            c.nameIndex(v.Name),
+			EncryptedName:   encryptedName,
+			Folder:          c.folderIndex(secret.FolderOf(v.Name)),
+			Content:         v.Data,
+			ExpireTimestamp: expireTimestamp,
+			MaxReads:        maxReads,
+		}
+	}
+
+	// batch isn't repeated, because batch committed before failure of response would fail on repeat by taken names
+	res, err := c.client.BatchSet(ctx, &pb.BatchSetRequest{Items: items})
+	if err != nil {
+		return fmt.Errorf("cannot set batch of secrets: %w", err)
+	}
+
+	if res.Committed {
+		return nil
+	}
+
+	// results follow order of items, names of results are blind indexes, so names are taken from batch
+	failures := make([]string, 0)
+	for i, v := range res.Results {
+		if codes.Code(v.Code) != codes.OK && codes.Code(v.Code) != codes.Aborted && i < len(batch) {
+			failures = append(failures, fmt.Sprintf("%s: %s", batch[i].Name, v.Error))
+		}
+	}
+
+	return fmt.Errorf("batch of secrets isn't committed: %s", strings.Join(failures, "; "))
+}
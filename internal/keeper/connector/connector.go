@@ -7,6 +7,36 @@ import (
 	"os"
 )
 
+// UploadStats sizes of uploaded media in bytes
+type UploadStats struct {
+	UUID string
+	// Size size of encrypted media content
+	Size int64
+	// UploadedSize size of chunks sent to service, others were already stored
+	UploadedSize int64
+}
+
+// Usage storage consumption of user and quota of service, zero limits mean no limit
+type Usage struct {
+	MediaCount int
+	PlainCount int
+	// MediaBytes size of encrypted media content stored by service
+	MediaBytes int64
+	PlainBytes int64
+
+	MaxBytes   int64
+	MaxMedia   int
+	MaxSecrets int
+}
+
+// NewSecret plain secret created by batch, data is encrypted by caller
+type NewSecret struct {
+	Name       string
+	SecretType secret.SecretType
+	Data       []byte
+	Limits     secret.Limits
+}
+
 type ServiceConnector interface {
 	Ping(ctx context.Context) (answer string, error error)
 
@@ -14,13 +44,40 @@ type ServiceConnector interface {
 	Login(ctx context.Context, login string, password string) (token string, err error)
 
 	SetAuthToken(token string)
+	// SetSecretKey sets key for encrypt secret names and build their blind indexes
+	SetSecretKey(key [32]byte)
 
-	UploadMedia(ctx context.Context, name string, reader io.Reader, replace bool) (string, error)
-	DownloadMedia(ctx context.Context, name string, dest string) (*os.File, error)
+	// UploadMedia uploads media content by one object, info of media is encrypted and stored with media, nil for media without info
+	UploadMedia(ctx context.Context, name string, reader io.Reader, replace bool, limits secret.Limits, info *secret.MediaInfo) (string, error)
+	// UploadChunkedMedia splits media to content-defined chunks, encrypts every chunk deterministically
+	// and uploads only chunks that service hasn't stored yet
+	UploadChunkedMedia(ctx context.Context, name string, reader io.Reader, replace bool, limits secret.Limits, info *secret.MediaInfo) (UploadStats, error)
+	// DownloadMedia saves media content to dest, content of chunked media is decrypted by connector
+	// and returned with true chunked flag, content of other media stays encrypted
+	DownloadMedia(ctx context.Context, name string, dest string) (file *os.File, chunked bool, err error)
 
-	ListSecret(ctx context.Context, secretType secret.SecretType) ([]secret.Secret, error)
-	SetSecret(ctx context.Context, name string, secretType secret.SecretType, data []byte) error
+	ListSecret(ctx context.Context, secretType secret.SecretType, filter secret.Filter) ([]secret.Secret, error)
+	// ListAllSecrets returns secrets of all types matched by filter
+	ListAllSecrets(ctx context.Context, filter secret.Filter) ([]secret.Secret, error)
+	SetSecret(ctx context.Context, name string, secretType secret.SecretType, data []byte, limits secret.Limits) error
+	// SetSecrets creates plain secrets by batches, every batch is created all-or-nothing.
+	// Returns count of created secrets, it's count of secrets of committed batches
+	SetSecrets(ctx context.Context, secrets []NewSecret) (int, error)
 	UpdateSecret(ctx context.Context, name string, secretType secret.SecretType, data []byte) error
+	// ReplaceSecret replaces data and limits of existing secret, reads of secret are counted again
+	ReplaceSecret(ctx context.Context, name string, secretType secret.SecretType, data []byte, limits secret.Limits) error
 	RemoveSecret(ctx context.Context, name string, secretType secret.SecretType) error
 	GetSecret(ctx context.Context, name string, secretType secret.SecretType) ([]byte, error)
+	// RenameSecret changes path-like name and type of secret without reupload its content, folder of secret changes by new name.
+	// Type can be changed only between plain types
+	RenameSecret(ctx context.Context, name string, newName string, secretType secret.SecretType, newSecretType secret.SecretType) error
+	// SetSecretTags replaces all tags of secret
+	SetSecretTags(ctx context.Context, name string, secretType secret.SecretType, tags []string) error
+
+	// GetUsage returns storage consumption of user and its limits
+	GetUsage(ctx context.Context) (Usage, error)
+
+	// MigrateSecretNames encrypts names of secrets that were saved with plain names and places secrets saved before folders
+	// to their folders, returns count of migrated secrets
+	MigrateSecretNames(ctx context.Context, secretType secret.SecretType) (int, error)
 }
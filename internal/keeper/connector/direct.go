@@ -0,0 +1,234 @@
+package connector
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	pb "github.com/nessai1/gophkeeper/api/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"net"
+	"net/http"
+)
+
+// errDirectUnavailable returns by direct transfer if media can't be transferred directly with media storage,
+// then media is transferred through service
+var errDirectUnavailable = errors.New("direct media transfer is unavailable")
+
+// errTransientHTTP wraps errors of requests to media storage that can be repeated
+var errTransientHTTP = errors.New("transient failure of media storage")
+
+// SetDirectMedia enables direct transfers of media content with media storage by presigned URLs given by service.
+// Media is transferred through service if service doesn't allow direct transfers
+func (c *GRPCServiceConnector) SetDirectMedia(enabled bool) {
+	c.directMedia = enabled
+}
+
+// uploadMediaDirect uploads content of reader by presigned URLs of parts and commits upload on service.
+// Reader must be io.Seeker to know size of content
+func (c *GRPCServiceConnector) uploadMediaDirect(ctx context.Context, md *pb.MediaSecretMetadata, reader io.Reader) (string, error) {
+	size, err := contentSize(reader)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errDirectUnavailable, err)
+	}
+
+	var upload *pb.PresignedUploadResponse
+	err = withRetry(ctx, func() error {
+		upload, err = c.client.StartPresignedMediaUpload(ctx, &pb.PresignedUploadRequest{Metadata: md, Size: size})
+
+		return err
+	})
+	if status.Code(err) == codes.Unimplemented {
+		return "", fmt.Errorf("%w: %w", errDirectUnavailable, err)
+	} else if err != nil {
+		return "", fmt.Errorf("cannot start presigned media upload: %w", err)
+	}
+
+	etags := make([]string, len(upload.PartUrls))
+	part := make([]byte, upload.PartSize)
+	for i, url := range upload.PartUrls {
+		n, err := io.ReadFull(reader, part)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			c.abortMediaUpload(upload.SessionId)
+
+			return "", fmt.Errorf("cannot read media for upload: %w", err)
+		}
+
+		etags[i], err = c.putMediaPart(ctx, url, part[:n])
+		if err != nil {
+			c.abortMediaUpload(upload.SessionId)
+
+			return "", fmt.Errorf("cannot upload part %d of media: %w", i+1, err)
+		}
+	}
+
+	// commit isn't repeated, because repeat of committed upload fails by removed session
+	res, err := c.client.CommitMediaUpload(ctx, &pb.CommitMediaUploadRequest{SessionId: upload.SessionId, Etags: etags})
+	if err != nil {
+		c.abortMediaUpload(upload.SessionId)
+
+		return "", fmt.Errorf("cannot commit presigned media upload: %w", err)
+	}
+
+	return res.Uuid, nil
+}
+
+// contentSize returns count of bytes left in seekable reader
+func contentSize(reader io.Reader) (int64, error) {
+	seeker, ok := reader.(io.Seeker)
+	if !ok {
+		return 0, errors.New("size of media reader is unknown")
+	}
+
+	current, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, fmt.Errorf("cannot get position of media reader: %w", err)
+	}
+
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, fmt.Errorf("cannot get size of media reader: %w", err)
+	}
+
+	_, err = seeker.Seek(current, io.SeekStart)
+	if err != nil {
+		return 0, fmt.Errorf("cannot seek media reader back: %w", err)
+	}
+
+	return end - current, nil
+}
+
+// putMediaPart uploads part to presigned URL and returns ETag of part, transient failures are repeated
+func (c *GRPCServiceConnector) putMediaPart(ctx context.Context, url string, part []byte) (string, error) {
+	for attempt := 0; ; attempt++ {
+		etag, err := putPart(ctx, url, part)
+		if err == nil {
+			return etag, nil
+		}
+
+		if !errors.Is(err, errTransientHTTP) || attempt == retryAttempts-1 || waitRetry(ctx, attempt) != nil {
+			return "", err
+		}
+	}
+}
+
+func putPart(ctx context.Context, url string, part []byte) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(part))
+	if err != nil {
+		return "", fmt.Errorf("cannot build request of part upload: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", wrapHTTPError(err)
+	}
+	defer resp.Body.Close()
+
+	if err = checkHTTPStatus(resp, http.StatusOK); err != nil {
+		return "", err
+	}
+
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		return "", errors.New("media storage returned no ETag of uploaded part")
+	}
+
+	return etag, nil
+}
+
+// downloadMediaDirect downloads media by presigned URL from received offset of download, transient failures are
+// continued from received content. Chunked media and media with limits are unavailable for direct download
+func (c *GRPCServiceConnector) downloadMediaDirect(ctx context.Context, name string, download *mediaDownload) error {
+	var res *pb.PresignedDownloadResponse
+	var err error
+	err = withRetry(ctx, func() error {
+		res, err = c.client.GetPresignedMediaDownload(ctx, &pb.DownloadMediaSecretRequest{SecretName: c.nameIndex(name)})
+
+		return err
+	})
+	if code := status.Code(err); code == codes.Unimplemented || code == codes.FailedPrecondition {
+		return fmt.Errorf("%w: %w", errDirectUnavailable, err)
+	} else if err != nil {
+		return fmt.Errorf("cannot get presigned media download: %w", err)
+	}
+
+	if len(res.Sha256) != 0 {
+		download.checksum = res.Sha256
+	}
+	for attempt := 0; ; attempt++ {
+		err = getMedia(ctx, res.Url, download)
+		if err == nil {
+			return nil
+		}
+
+		if !errors.Is(err, errTransientHTTP) || attempt == retryAttempts-1 || waitRetry(ctx, attempt) != nil {
+			return err
+		}
+	}
+}
+
+// getMedia writes content of media from received offset of download to file
+func getMedia(ctx context.Context, url string, download *mediaDownload) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("cannot build request of media download: %w", err)
+	}
+
+	if download.received > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", download.received))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return wrapHTTPError(err)
+	}
+	defer resp.Body.Close()
+
+	if download.received > 0 && resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		// whole content is received before failure
+		return nil
+	}
+
+	expected := http.StatusOK
+	if download.received > 0 {
+		expected = http.StatusPartialContent
+	}
+
+	if err = checkHTTPStatus(resp, expected); err != nil {
+		return err
+	}
+
+	written, err := io.Copy(io.MultiWriter(download.file, download.hash), resp.Body)
+	download.received += written
+	if err != nil {
+		return fmt.Errorf("%w: cannot receive media content: %w", errTransientHTTP, err)
+	}
+
+	return nil
+}
+
+// checkHTTPStatus returns error if response of media storage has other status, server errors are transient
+func checkHTTPStatus(resp *http.Response, expected int) error {
+	if resp.StatusCode == expected {
+		return nil
+	}
+
+	err := fmt.Errorf("media storage responded with status %s", resp.Status)
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("%w: %w", errTransientHTTP, err)
+	}
+
+	return err
+}
+
+// wrapHTTPError marks network errors of request as transient
+func wrapHTTPError(err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: %w", errTransientHTTP, err)
+	}
+
+	return fmt.Errorf("cannot send request to media storage: %w", err)
+}
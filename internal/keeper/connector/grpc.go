@@ -1,14 +1,21 @@
 package connector
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
 	"fmt"
 	pb "github.com/nessai1/gophkeeper/api/proto"
+	"github.com/nessai1/gophkeeper/internal/keeper/encrypt"
 	"github.com/nessai1/gophkeeper/internal/keeper/secret"
+	"github.com/nessai1/gophkeeper/pkg/cdc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"hash"
 	"io"
 	"os"
 	"time"
@@ -16,24 +23,110 @@ import (
 
 type GRPCServiceConnector struct {
 	authToken string
+	secretKey [32]byte
 
 	connection *grpc.ClientConn
 	client     pb.KeeperServiceClient
+
+	// directMedia media is transferred with media storage by presigned URLs if service allows it
+	directMedia bool
 }
 
 const uploadBlockSize = 256 * 524288 // ~0.5mb
 
-func (c *GRPCServiceConnector) UploadMedia(ctx context.Context, name string, reader io.Reader, replace bool) (string, error) {
+// ErrChecksumMismatch downloaded media differs from media uploaded to service
+var ErrChecksumMismatch = errors.New("checksum of downloaded media mismatches")
+
+// listPageSize count of secrets requested by one page of list
+const listPageSize = 500
+
+// UploadMedia uploads media through upload session of service. After network blip upload is continued
+// from offset committed by service, reader must be io.Seeker to repeat data that service hasn't received
+func (c *GRPCServiceConnector) UploadMedia(ctx context.Context, name string, reader io.Reader, replace bool, limits secret.Limits, info *secret.MediaInfo) (string, error) {
+	md, err := c.buildMediaMetadata(name, replace, limits, info)
+	if err != nil {
+		return "", err
+	}
+
+	if c.directMedia {
+		mediaUUID, err := c.uploadMediaDirect(ctx, md, reader)
+		if !errors.Is(err, errDirectUnavailable) {
+			return mediaUUID, err
+		}
+	}
+
+	// start isn't repeated: repeat after lost response starts second session, and the first one is left until reaper
+	session, err := c.client.StartMediaUpload(ctx, md)
+	if err != nil {
+		return "", fmt.Errorf("cannot start media upload session: %w", err)
+	}
+
+	// read count of bytes read from reader and written to hash, offset count of bytes committed by service
+	var read, offset int64
+	contentHash := sha256.New()
+	for attempt := 0; ; attempt++ {
+		mediaUUID, err := c.sendMedia(ctx, session.SessionId, offset, reader, contentHash, &read)
+		if err == nil {
+			return mediaUUID, nil
+		}
+
+		if !isTransient(err) || attempt == retryAttempts-1 || waitRetry(ctx, attempt) != nil {
+			c.abortMediaUpload(session.SessionId)
+
+			return "", err
+		}
+
+		var state *pb.MediaUploadSessionResponse
+		err = withRetry(ctx, func() error {
+			state, err = c.client.GetMediaUploadSession(ctx, &pb.MediaUploadSessionRequest{SessionId: session.SessionId})
+
+			return err
+		})
+		if err != nil {
+			return "", fmt.Errorf("cannot get media upload session for resume: %w", err)
+		}
+
+		if state.Offset != read {
+			seeker, ok := reader.(io.Seeker)
+			if !ok {
+				c.abortMediaUpload(session.SessionId)
+
+				return "", fmt.Errorf("cannot resume media upload from offset %d: media reader can't seek", state.Offset)
+			}
+
+			// hash can't be rolled back, so it's computed again by committed content
+			contentHash.Reset()
+			_, err = seeker.Seek(-read, io.SeekCurrent)
+			if err == nil {
+				_, err = io.CopyN(contentHash, reader, state.Offset)
+			}
+
+			if err != nil {
+				c.abortMediaUpload(session.SessionId)
+
+				return "", fmt.Errorf("cannot seek media to resume upload: %w", err)
+			}
+
+			read = state.Offset
+		}
+
+		offset = state.Offset
+	}
+}
+
+// sendMedia sends media from reader to upload session starting with offset and trailer with checksum of contentHash,
+// read is increased by count of bytes read from reader
+func (c *GRPCServiceConnector) sendMedia(ctx context.Context, sessionID string, offset int64, reader io.Reader, contentHash hash.Hash, read *int64) (string, error) {
 	stream, err := c.client.UploadMediaSecret(ctx)
 	if err != nil {
 		return "", fmt.Errorf("cannot open stream for upload media sercret: %w", err)
 	}
 
-	md := pb.MediaSecretMetadata{Name: name, Overwrite: replace}
-
-	err = stream.Send(&pb.UploadMediaSecretRequest{Request: &pb.UploadMediaSecretRequest_Metadata{Metadata: &md}})
+	err = stream.Send(&pb.UploadMediaSecretRequest{Request: &pb.UploadMediaSecretRequest_Resume{
+		Resume: &pb.MediaUploadResume{SessionId: sessionID, Offset: offset},
+	}})
 	if err != nil {
-		return "", fmt.Errorf("cannot send metadata in media secret upload stream: %w", err)
+		return "", c.closeUploadStream(stream, fmt.Errorf("cannot send session in media secret upload stream: %w", err))
 	}
 
 	var b []byte
@@ -48,16 +141,26 @@ func (c *GRPCServiceConnector) UploadMedia(ctx context.Context, name string, rea
 			return "", fmt.Errorf("cannot read media for upload: %w", err)
 		}
 
+		*read += int64(n)
 		if n != uploadBlockSize {
 			b = b[:n]
 		}
 
+		contentHash.Write(b)
+
 		err = stream.Send(&pb.UploadMediaSecretRequest{Request: &pb.UploadMediaSecretRequest_Data{Data: &pb.MediaSecret{Chunk: b}}})
 		if err != nil {
-			return "", fmt.Errorf("cannot send media chunk: %w", err)
+			return "", c.closeUploadStream(stream, fmt.Errorf("cannot send media chunk: %w", err))
 		}
 	}
 
+	err = stream.Send(&pb.UploadMediaSecretRequest{Request: &pb.UploadMediaSecretRequest_Trailer{
+		Trailer: &pb.MediaUploadTrailer{Sha256: contentHash.Sum(nil)},
+	}})
+	if err != nil {
+		return "", c.closeUploadStream(stream, fmt.Errorf("cannot send trailer of media: %w", err))
+	}
+
 	res, err := stream.CloseAndRecv()
 	if err != nil {
 		return "", fmt.Errorf("cannot close stream for upload media secret: %w", err)
@@ -66,6 +169,166 @@ func (c *GRPCServiceConnector) UploadMedia(ctx context.Context, name string, rea
 	return res.Uuid, nil
 }
 
+// closeUploadStream returns error of upload stream that failed on send. Send returns io.EOF when stream
+// is closed by service, then the real error is given by CloseAndRecv
+func (c *GRPCServiceConnector) closeUploadStream(stream pb.KeeperService_UploadMediaSecretClient, err error) error {
+	if !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	_, recvErr := stream.CloseAndRecv()
+	if recvErr != nil {
+		return fmt.Errorf("media secret upload stream closed by service: %w", recvErr)
+	}
+
+	return err
+}
+
+// abortMediaUpload aborts upload session on failure, session is reaped by service if abort fails too
+func (c *GRPCServiceConnector) abortMediaUpload(sessionID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), retryBaseDelay*4)
+	defer cancel()
+
+	_, _ = c.client.AbortMediaUpload(ctx, &pb.MediaUploadSessionRequest{SessionId: sessionID})
+}
+
+// chunksBatchSize count of chunks checked on service by one request, encrypted chunks of batch are kept in memory
+const chunksBatchSize = 16
+
+func (c *GRPCServiceConnector) UploadChunkedMedia(ctx context.Context, name string, reader io.Reader, replace bool, limits secret.Limits, info *secret.MediaInfo) (UploadStats, error) {
+	chunker, err := cdc.NewChunker(reader, cdc.DefaultConfig)
+	if err != nil {
+		return UploadStats{}, fmt.Errorf("cannot create chunker of media: %w", err)
+	}
+
+	stats := UploadStats{}
+	hashes := make([]string, 0)
+	batch := make([][]byte, 0, chunksBatchSize)
+	for {
+		chunk, err := chunker.Next()
+		if err != nil && err != io.EOF {
+			return UploadStats{}, fmt.Errorf("cannot read media chunk: %w", err)
+		}
+
+		if chunk != nil {
+			encrypted, err := encrypt.EncryptChunk(chunk, c.secretKey)
+			if err != nil {
+				return UploadStats{}, fmt.Errorf("cannot encrypt media chunk: %w", err)
+			}
+
+			batch = append(batch, encrypted)
+			hashes = append(hashes, encrypt.BuildChunkHash(encrypted))
+			stats.Size += int64(len(encrypted))
+		}
+
+		if len(batch) == chunksBatchSize || err == io.EOF && len(batch) > 0 {
+			uploaded, err := c.uploadChunks(ctx, batch, hashes[len(hashes)-len(batch):])
+			if err != nil {
+				return UploadStats{}, err
+			}
+
+			stats.UploadedSize += uploaded
+			batch = batch[:0]
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	// service can't store media without chunks, so empty media is uploaded as single object
+	if len(hashes) == 0 {
+		stats.UUID, err = c.UploadMedia(ctx, name, reader, replace, limits, info)
+
+		return stats, err
+	}
+
+	md, err := c.buildMediaMetadata(name, replace, limits, info)
+	if err != nil {
+		return UploadStats{}, err
+	}
+
+	res, err := c.client.CommitChunkedMedia(ctx, &pb.CommitChunkedMediaRequest{Metadata: md, Hashes: hashes})
+	if err != nil {
+		return UploadStats{}, fmt.Errorf("cannot commit chunked media: %w", err)
+	}
+
+	stats.UUID = res.Uuid
+
+	return stats, nil
+}
+
+// uploadChunks uploads chunks that service hasn't, returns size of uploaded chunks
+func (c *GRPCServiceConnector) uploadChunks(ctx context.Context, chunks [][]byte, hashes []string) (int64, error) {
+	var res *pb.MediaChunksResponse
+	err := withRetry(ctx, func() (err error) {
+		res, err = c.client.GetMissingMediaChunks(ctx, &pb.MediaChunksRequest{Hashes: hashes})
+
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("cannot get missing chunks of media: %w", err)
+	}
+
+	missing := make(map[string]struct{}, len(res.Missing))
+	for _, v := range res.Missing {
+		missing[v] = struct{}{}
+	}
+
+	var uploaded int64
+	for i, v := range chunks {
+		if _, ok := missing[hashes[i]]; !ok {
+			continue
+		}
+
+		err = withRetry(ctx, func() error {
+			_, err := c.client.UploadMediaChunk(ctx, &pb.UploadMediaChunkRequest{Hash: hashes[i], Data: v})
+
+			return err
+		})
+		if err != nil {
+			return 0, fmt.Errorf("cannot upload chunk of media: %w", err)
+		}
+
+		uploaded += int64(len(v))
+		delete(missing, hashes[i])
+	}
+
+	return uploaded, nil
+}
+
+func (c *GRPCServiceConnector) buildMediaMetadata(name string, replace bool, limits secret.Limits, info *secret.MediaInfo) (*pb.MediaSecretMetadata, error) {
+	encryptedName, err := c.encryptName(name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encrypt media name: %w", err)
+	}
+
+	var encryptedInfo []byte
+	if info != nil {
+		rawInfo, err := json.Marshal(info)
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal media info: %w", err)
+		}
+
+		encryptedInfo, err = encrypt.EncryptAES256(rawInfo, c.secretKey)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encrypt media info: %w", err)
+		}
+	}
+
+	expireTimestamp, maxReads := translateLimitsToGRPC(limits)
+
+	return &pb.MediaSecretMetadata{
+		Name:            c.nameIndex(name),
+		EncryptedName:   encryptedName,
+		Folder:          c.folderIndex(secret.FolderOf(name)),
+		Overwrite:       replace,
+		ExpireTimestamp: expireTimestamp,
+		MaxReads:        maxReads,
+		EncryptedInfo:   encryptedInfo,
+	}, nil
+}
+
 func CreateGRPCConnector(serviceAddr, CertificateTLSFilePath string) (*GRPCServiceConnector, error) {
 	server := &GRPCServiceConnector{}
 
@@ -168,83 +431,289 @@ func (c *GRPCServiceConnector) Login(ctx context.Context, login, password string
 	return response.Token, nil
 }
 
+func (c *GRPCServiceConnector) GetUsage(ctx context.Context) (Usage, error) {
+	res, err := c.client.GetUsage(ctx, &pb.UsageRequest{})
+	if err != nil {
+		return Usage{}, fmt.Errorf("cannot get usage from service: %w", err)
+	}
+
+	return Usage{
+		MediaCount: int(res.MediaCount),
+		PlainCount: int(res.PlainCount),
+		MediaBytes: res.MediaBytes,
+		PlainBytes: res.PlainBytes,
+		MaxBytes:   res.MaxBytes,
+		MaxMedia:   int(res.MaxMedia),
+		MaxSecrets: int(res.MaxSecrets),
+	}, nil
+}
+
 func (c *GRPCServiceConnector) SetAuthToken(token string) {
 	c.authToken = token
 }
 
-func (c *GRPCServiceConnector) DownloadMedia(ctx context.Context, name string, dest string) (*os.File, error) {
-	stream, err := c.client.DownloadMediaSecret(ctx, &pb.DownloadMediaSecretRequest{SecretName: name})
+func (c *GRPCServiceConnector) SetSecretKey(key [32]byte) {
+	c.secretKey = key
+}
+
+// nameIndex returns blind index of secret name, that service uses for lookups
+func (c *GRPCServiceConnector) nameIndex(name string) string {
+	return encrypt.BuildNameIndex(name, c.secretKey)
+}
+
+// folderIndex returns blind index of folder, that service uses for filter secrets of folder
+func (c *GRPCServiceConnector) folderIndex(folder string) string {
+	return encrypt.BuildFolderIndex(secret.CleanFolder(folder), c.secretKey)
+}
+
+func (c *GRPCServiceConnector) encryptName(name string) ([]byte, error) {
+	return encrypt.EncryptAES256([]byte(name), c.secretKey)
+}
+
+// decryptName returns real name of service secret, secrets without encrypted name store it in plain
+func (c *GRPCServiceConnector) decryptName(s *pb.Secret) (string, error) {
+	if len(s.EncryptedName) == 0 {
+		return s.Name, nil
+	}
+
+	name, err := encrypt.DecryptAES256(s.EncryptedName, c.secretKey)
 	if err != nil {
-		return nil, fmt.Errorf("cannot start media download: %w", err)
+		return "", fmt.Errorf("cannot decrypt secret name: %w", err)
 	}
 
+	return string(name), nil
+}
+
+// DownloadMedia downloads media to dest file. After network blip download is continued from received offset.
+// Returns ErrChecksumMismatch if content differs from checksum given by service
+func (c *GRPCServiceConnector) DownloadMedia(ctx context.Context, name string, dest string) (*os.File, bool, error) {
 	f, err := os.Create(dest)
 	if err != nil {
-		return nil, fmt.Errorf("cannot create file to dest %s: %w", dest, err)
+		return nil, false, fmt.Errorf("cannot create file to dest %s: %w", dest, err)
+	}
+
+	download := mediaDownload{file: f, hash: sha256.New()}
+	direct := false
+	if c.directMedia {
+		err = c.downloadMediaDirect(ctx, name, &download)
+		if err != nil && !errors.Is(err, errDirectUnavailable) {
+			f.Close()
+
+			return nil, false, err
+		}
+
+		direct = err == nil
+	}
+
+	for attempt := 0; !direct; attempt++ {
+		err = c.receiveMedia(ctx, name, &download)
+		if err == nil {
+			break
+		}
+
+		if !isTransient(err) || attempt == retryAttempts-1 || waitRetry(ctx, attempt) != nil {
+			f.Close()
+
+			return nil, false, err
+		}
+	}
+
+	if download.checksum != nil && !bytes.Equal(download.checksum, download.hash.Sum(nil)) {
+		f.Close()
+		os.Remove(dest)
+
+		return nil, false, ErrChecksumMismatch
+	}
+
+	_, err = f.Seek(0, 0)
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot seek destination file: %w", err)
+	}
+
+	return f, download.chunked, nil
+}
+
+// mediaDownload state of media download kept between streams
+type mediaDownload struct {
+	file *os.File
+	// received count of raw bytes received from service, it's offset of download continue
+	received int64
+	// hash of raw content received from service
+	hash     hash.Hash
+	checksum []byte
+	chunked  bool
+}
+
+// receiveMedia writes media from received offset of download to file
+func (c *GRPCServiceConnector) receiveMedia(ctx context.Context, name string, download *mediaDownload) error {
+	stream, err := c.client.DownloadMediaSecret(ctx, &pb.DownloadMediaSecretRequest{SecretName: c.nameIndex(name), Offset: download.received})
+	if err != nil {
+		return fmt.Errorf("cannot start media download: %w", err)
 	}
 
 	for {
 		recv, err := stream.Recv()
 		if err == io.EOF {
-			break
+			return nil
 		}
 
 		if err != nil {
-			f.Close()
+			return fmt.Errorf("cannot receive package of media file: %w", err)
+		}
 
-			return nil, fmt.Errorf("cannot receive package of media file: %w", err)
+		if recv.Sha256 != nil {
+			download.checksum = recv.Sha256
 		}
 
-		_, err = f.Write(recv.GetSecretPart().Chunk)
-		if err != nil {
-			f.Close()
+		content := recv.GetSecretPart().Chunk
+		size := int64(len(content))
+		download.hash.Write(content)
+		if recv.Chunked {
+			download.chunked = true
+			content, err = encrypt.DecryptChunk(content, c.secretKey)
+			if err != nil {
+				return fmt.Errorf("cannot decrypt media chunk: %w", err)
+			}
+		}
 
-			return nil, fmt.Errorf("cannot write media chunk to file while download: %w", err)
+		_, err = download.file.Write(content)
+		if err != nil {
+			return fmt.Errorf("cannot write media chunk to file while download: %w", err)
 		}
+
+		download.received += size
 	}
+}
 
-	_, err = f.Seek(0, 0)
+// decryptMediaInfo decrypts media info given by service, nil for empty info
+func (c *GRPCServiceConnector) decryptMediaInfo(encryptedInfo []byte) (*secret.MediaInfo, error) {
+	if len(encryptedInfo) == 0 {
+		return nil, nil
+	}
+
+	rawInfo, err := encrypt.DecryptAES256(encryptedInfo, c.secretKey)
 	if err != nil {
-		return nil, fmt.Errorf("cannot seek destination file: %w", err)
+		return nil, fmt.Errorf("cannot decrypt media info: %w", err)
 	}
 
-	return f, nil
+	info := secret.MediaInfo{}
+	err = json.Unmarshal(rawInfo, &info)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal media info: %w", err)
+	}
+
+	return &info, nil
 }
 
-func (c *GRPCServiceConnector) ListSecret(ctx context.Context, secretType secret.SecretType) ([]secret.Secret, error) {
+func (c *GRPCServiceConnector) ListSecret(ctx context.Context, secretType secret.SecretType, filter secret.Filter) ([]secret.Secret, error) {
 	translatedType, err := translateSecretTypeTypeToGRPCType(secretType)
 	if err != nil {
 		return nil, fmt.Errorf("cannot list secrets: %w", err)
 	}
 
-	resp, err := c.client.SecretList(ctx, &pb.SecretListRequest{SecretType: translatedType})
+	return c.listSecrets(ctx, &pb.SecretListRequest{SecretType: translatedType}, filter)
+}
+
+func (c *GRPCServiceConnector) ListAllSecrets(ctx context.Context, filter secret.Filter) ([]secret.Secret, error) {
+	return c.listSecrets(ctx, &pb.SecretListRequest{AnyType: true}, filter)
+}
+
+// listSecrets requests all secrets matched by request with filter and decrypts them
+func (c *GRPCServiceConnector) listSecrets(ctx context.Context, req *pb.SecretListRequest, filter secret.Filter) ([]secret.Secret, error) {
+	if filter.Folder != nil {
+		req.Folder = c.folderIndex(*filter.Folder)
+	}
+
+	if filter.Tag != "" {
+		req.Tag = encrypt.BuildTagIndex(filter.Tag, c.secretKey)
+	}
+
+	req.Descending = filter.Descending
+	if filter.Sort == secret.SortUpdated {
+		req.Sort = pb.SecretSortField_UPDATED
+	} else {
+		req.Sort = pb.SecretSortField_CREATED
+	}
+
+	limit := filter.Limit
+	if filter.ByClient() {
+		// names are encrypted, so secrets are filtered and sorted by names after all of them are listed and decrypted
+		limit = 0
+	}
+
+	listedSecrets, err := c.listAllSecrets(ctx, req, limit)
 	if err != nil {
 		return nil, fmt.Errorf("error while list secrets from external service: %w", err)
 	}
 
-	secrets := make([]secret.Secret, len(resp.Secrets))
-	for i, v := range resp.Secrets {
+	secrets := make([]secret.Secret, len(listedSecrets))
+	for i, v := range listedSecrets {
+		name, err := c.decryptName(v)
+		if err != nil {
+			return nil, fmt.Errorf("cannot list secrets: %w", err)
+		}
+
+		tags := make([]string, len(v.Tags))
+		for j, t := range v.Tags {
+			tag, err := encrypt.DecryptAES256(t.EncryptedTag, c.secretKey)
+			if err != nil {
+				return nil, fmt.Errorf("cannot decrypt secret tag: %w", err)
+			}
+
+			tags[j] = string(tag)
+		}
+
+		secretType, err := translateGRPCTypeToSecretType(v.SecretType)
+		if err != nil {
+			return nil, fmt.Errorf("cannot list secrets: %w", err)
+		}
+
+		mediaInfo, err := c.decryptMediaInfo(v.EncryptedMediaInfo)
+		if err != nil {
+			return nil, fmt.Errorf("cannot list secrets: %w", err)
+		}
+
 		secrets[i] = secret.Secret{
 			SecretType: secretType,
-			Name:       v.Name,
+			Name:       name,
 			Created:    time.Unix(v.CreateTimestamp, 0),
 			Updated:    time.Unix(v.UpdateTimestamp, 0),
+			Limits:     translateGRPCLimits(v.ExpireTimestamp, v.MaxReads),
+			Reads:      int(v.Reads),
+			Folder:     secret.FolderOf(name),
+			Tags:       tags,
+			Size:       v.Size,
+			Media:      mediaInfo,
 		}
 	}
 
+	if filter.ByClient() {
+		return filter.ApplyByClient(secrets), nil
+	}
+
 	return secrets, nil
 }
 
-func (c *GRPCServiceConnector) SetSecret(ctx context.Context, name string, secretType secret.SecretType, data []byte) error {
+func (c *GRPCServiceConnector) SetSecret(ctx context.Context, name string, secretType secret.SecretType, data []byte, limits secret.Limits) error {
 	translatedType, err := translateSecretTypeTypeToGRPCType(secretType)
 	if err != nil {
 		return fmt.Errorf("cannot set secret: %w", err)
 	}
 
+	encryptedName, err := c.encryptName(name)
+	if err != nil {
+		return fmt.Errorf("cannot encrypt secret name: %w", err)
+	}
+
+	expireTimestamp, maxReads := translateLimitsToGRPC(limits)
 	_, err = c.client.SecretSet(ctx, &pb.SecretSetRequest{
-		SecretType: translatedType,
-		Name:       name,
-		Content:    data,
+		SecretType:      translatedType,
+		Name:            c.nameIndex(name),
+		EncryptedName:   encryptedName,
+		Folder:          c.folderIndex(secret.FolderOf(name)),
+		Content:         data,
+		ExpireTimestamp: expireTimestamp,
+		MaxReads:        maxReads,
 	})
 
 	if err != nil {
@@ -255,16 +724,31 @@ func (c *GRPCServiceConnector) SetSecret(ctx context.Context, name string, secre
 }
 
 func (c *GRPCServiceConnector) UpdateSecret(ctx context.Context, name string, secretType secret.SecretType, data []byte) error {
+	return c.updateSecret(ctx, &pb.SecretUpdateRequest{Name: name, Content: data}, secretType)
+}
+
+func (c *GRPCServiceConnector) ReplaceSecret(ctx context.Context, name string, secretType secret.SecretType, data []byte, limits secret.Limits) error {
+	expireTimestamp, maxReads := translateLimitsToGRPC(limits)
+
+	return c.updateSecret(ctx, &pb.SecretUpdateRequest{
+		Name:            name,
+		Content:         data,
+		ReplaceLimits:   true,
+		ExpireTimestamp: expireTimestamp,
+		MaxReads:        maxReads,
+	}, secretType)
+}
+
+// updateSecret sends update request with plain name of secret replaced by its blind index
+func (c *GRPCServiceConnector) updateSecret(ctx context.Context, req *pb.SecretUpdateRequest, secretType secret.SecretType) error {
 	translatedType, err := translateSecretTypeTypeToGRPCType(secretType)
 	if err != nil {
 		return fmt.Errorf("cannot update secret: %w", err)
 	}
 
-	_, err = c.client.SecretUpdate(ctx, &pb.SecretUpdateRequest{
-		SecretType: translatedType,
-		Name:       name,
-		Content:    data,
-	})
+	req.SecretType = translatedType
+	req.Name = c.nameIndex(req.Name)
+	_, err = c.client.SecretUpdate(ctx, req)
 
 	if err != nil {
 		return fmt.Errorf("cannot update secret: %w", err)
@@ -280,7 +764,7 @@ func (c *GRPCServiceConnector) RemoveSecret(ctx context.Context, name string, se
 
 	_, err = c.client.SecretDelete(ctx, &pb.SecretDeleteRequest{
 		SecretType: translatedType,
-		SecretName: name,
+		SecretName: c.nameIndex(name),
 	})
 	if err != nil {
 		return fmt.Errorf("cannot remove secret: %w", err)
@@ -297,7 +781,7 @@ func (c *GRPCServiceConnector) GetSecret(ctx context.Context, name string, secre
 
 	resp, err := c.client.SecretGet(ctx, &pb.SecretGetRequest{
 		SecretType: translatedType,
-		Name:       name,
+		Name:       c.nameIndex(name),
 	})
 
 	if err != nil {
@@ -307,6 +791,179 @@ func (c *GRPCServiceConnector) GetSecret(ctx context.Context, name string, secre
 	return resp.Secret.Content, nil
 }
 
+func (c *GRPCServiceConnector) MigrateSecretNames(ctx context.Context, secretType secret.SecretType) (int, error) {
+	translatedType, err := translateSecretTypeTypeToGRPCType(secretType)
+	if err != nil {
+		return 0, fmt.Errorf("cannot migrate secret names: %w", err)
+	}
+
+	// list sorted by creation time, because names are changed while migration
+	listedSecrets, err := c.listAllSecrets(ctx, &pb.SecretListRequest{SecretType: translatedType, Sort: pb.SecretSortField_CREATED}, 0)
+	if err != nil {
+		return 0, fmt.Errorf("error while list secrets from external service: %w", err)
+	}
+
+	migrated := 0
+	for _, v := range listedSecrets {
+		if len(v.EncryptedName) != 0 && v.Folder != "" {
+			continue
+		}
+
+		if len(v.EncryptedName) != 0 {
+			name, err := c.decryptName(v)
+			if err != nil {
+				return migrated, fmt.Errorf("cannot migrate secret folder: %w", err)
+			}
+
+			_, err = c.client.SecretRename(ctx, &pb.SecretRenameRequest{
+				SecretType:       translatedType,
+				Name:             v.Name,
+				NewName:          v.Name,
+				NewEncryptedName: v.EncryptedName,
+				NewFolder:        c.folderIndex(secret.FolderOf(name)),
+			})
+			if err != nil {
+				return migrated, fmt.Errorf("cannot migrate secret folder: %w", err)
+			}
+
+			migrated++
+
+			continue
+		}
+
+		encryptedName, err := c.encryptName(v.Name)
+		if err != nil {
+			return migrated, fmt.Errorf("cannot encrypt secret name: %w", err)
+		}
+
+		_, err = c.client.SecretMigrateName(ctx, &pb.SecretMigrateNameRequest{
+			SecretType:    translatedType,
+			Name:          v.Name,
+			NameIndex:     c.nameIndex(v.Name),
+			EncryptedName: encryptedName,
+			Folder:        c.folderIndex(secret.FolderOf(v.Name)),
+		})
+		if err != nil {
+			return migrated, fmt.Errorf("cannot migrate secret name: %w", err)
+		}
+
+		migrated++
+	}
+
+	return migrated, nil
+}
+
+// listAllSecrets requests pages of secrets list until last page or limit of secrets, 0 for all pages
+func (c *GRPCServiceConnector) listAllSecrets(ctx context.Context, req *pb.SecretListRequest, limit int) ([]*pb.Secret, error) {
+	req.PageSize = listPageSize
+
+	var secrets []*pb.Secret
+	for {
+		if limit > 0 && limit-len(secrets) < listPageSize {
+			req.PageSize = int32(limit - len(secrets))
+		}
+
+		resp, err := c.client.SecretList(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		secrets = append(secrets, resp.Secrets...)
+		if resp.NextPageToken == "" || limit > 0 && len(secrets) >= limit {
+			return secrets, nil
+		}
+
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+func (c *GRPCServiceConnector) RenameSecret(ctx context.Context, name string, newName string, secretType secret.SecretType, newSecretType secret.SecretType) error {
+	translatedType, err := translateSecretTypeTypeToGRPCType(secretType)
+	if err != nil {
+		return fmt.Errorf("cannot rename secret: %w", err)
+	}
+
+	var translatedNewType *pb.SecretType
+	if newSecretType != secretType {
+		newType, err := translateSecretTypeTypeToGRPCType(newSecretType)
+		if err != nil {
+			return fmt.Errorf("cannot rename secret: %w", err)
+		}
+
+		translatedNewType = &newType
+	}
+
+	encryptedName, err := c.encryptName(newName)
+	if err != nil {
+		return fmt.Errorf("cannot encrypt new secret name: %w", err)
+	}
+
+	_, err = c.client.SecretRename(ctx, &pb.SecretRenameRequest{
+		SecretType:       translatedType,
+		Name:             c.nameIndex(name),
+		NewName:          c.nameIndex(newName),
+		NewEncryptedName: encryptedName,
+		NewFolder:        c.folderIndex(secret.FolderOf(newName)),
+		NewSecretType:    translatedNewType,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot rename secret: %w", err)
+	}
+
+	return nil
+}
+
+func (c *GRPCServiceConnector) SetSecretTags(ctx context.Context, name string, secretType secret.SecretType, tags []string) error {
+	translatedType, err := translateSecretTypeTypeToGRPCType(secretType)
+	if err != nil {
+		return fmt.Errorf("cannot set secret tags: %w", err)
+	}
+
+	encryptedTags := make([]*pb.SecretTag, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, v := range tags {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+
+		encryptedTag, err := encrypt.EncryptAES256([]byte(v), c.secretKey)
+		if err != nil {
+			return fmt.Errorf("cannot encrypt secret tag: %w", err)
+		}
+
+		encryptedTags = append(encryptedTags, &pb.SecretTag{Tag: encrypt.BuildTagIndex(v, c.secretKey), EncryptedTag: encryptedTag})
+	}
+
+	_, err = c.client.SecretSetTags(ctx, &pb.SecretSetTagsRequest{
+		SecretType: translatedType,
+		Name:       c.nameIndex(name),
+		Tags:       encryptedTags,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot set secret tags: %w", err)
+	}
+
+	return nil
+}
+
+func translateLimitsToGRPC(limits secret.Limits) (expireTimestamp int64, maxReads int32) {
+	if !limits.ExpiresAt.IsZero() {
+		expireTimestamp = limits.ExpiresAt.Unix()
+	}
+
+	return expireTimestamp, int32(limits.MaxReads)
+}
+
+func translateGRPCLimits(expireTimestamp int64, maxReads int32) secret.Limits {
+	limits := secret.Limits{MaxReads: int(maxReads)}
+	if expireTimestamp != 0 {
+		limits.ExpiresAt = time.Unix(expireTimestamp, 0)
+	}
+
+	return limits
+}
+
 func translateSecretTypeTypeToGRPCType(keeperSecret secret.SecretType) (pb.SecretType, error) {
 	switch keeperSecret {
 	case secret.SecretTypeCredentials:
@@ -321,3 +978,18 @@ func translateSecretTypeTypeToGRPCType(keeperSecret secret.SecretType) (pb.Secre
 		return 0, fmt.Errorf("undefined secret type translated")
 	}
 }
+
+func translateGRPCTypeToSecretType(grpcType pb.SecretType) (secret.SecretType, error) {
+	switch grpcType {
+	case pb.SecretType_CREDENTIALS:
+		return secret.SecretTypeCredentials, nil
+	case pb.SecretType_CREDIT_CARD:
+		return secret.SecretTypeCard, nil
+	case pb.SecretType_TEXT:
+		return secret.SecretTypeText, nil
+	case pb.SecretType_MEDIA:
+		return secret.SecretTypeMedia, nil
+	default:
+		return 0, fmt.Errorf("undefined secret type translated")
+	}
+}
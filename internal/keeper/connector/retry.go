@@ -0,0 +1,49 @@
+package connector
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// retryAttempts count of attempts to repeat request after transient failure
+const retryAttempts = 5
+
+// retryBaseDelay delay before first repeat of request, doubles with every next attempt
+const retryBaseDelay = 500 * time.Millisecond
+
+// isTransient reports whether request failed by network blip or busy service and can be repeated
+func isTransient(err error) bool {
+	code := status.Code(err)
+
+	return code == codes.Unavailable || code == codes.Aborted
+}
+
+// waitRetry waits before repeat of request by attempt number (starts from 0)
+func waitRetry(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(retryBaseDelay << attempt)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// withRetry calls fn until it succeeds, fails with not transient error or attempts are over
+func withRetry(ctx context.Context, fn func() error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = fn()
+		if err == nil || !isTransient(err) || attempt == retryAttempts-1 {
+			return err
+		}
+
+		if waitErr := waitRetry(ctx, attempt); waitErr != nil {
+			return err
+		}
+	}
+}
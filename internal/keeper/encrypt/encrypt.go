@@ -3,15 +3,51 @@ package encrypt
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"math/rand"
+)
+
+// Labels separate keys of blind indexes from each other and from key of secrets content
+const (
+	nameIndexLabel   = "keeper-name-index"
+	folderIndexLabel = "keeper-folder-index"
+	tagIndexLabel    = "keeper-tag-index"
+
+	chunkKeyLabel   = "keeper-chunk-key"
+	chunkNonceLabel = "keeper-chunk-nonce"
 )
 
 func BuildAESKey(login string, password string) [32]byte {
 	return sha256.Sum256([]byte(login + password))
 }
 
+// BuildNameIndex builds keyed HMAC blind index of secret name.
+// Service stores and looks up secrets by this index, so it never knows real names
+func BuildNameIndex(name string, passphrase [32]byte) string {
+	return buildBlindIndex(name, passphrase, nameIndexLabel)
+}
+
+// BuildFolderIndex builds blind index of folder path, service filters secrets of folder by it
+func BuildFolderIndex(folder string, passphrase [32]byte) string {
+	return buildBlindIndex(folder, passphrase, folderIndexLabel)
+}
+
+// BuildTagIndex builds blind index of secret tag, service filters tagged secrets by it
+func BuildTagIndex(tag string, passphrase [32]byte) string {
+	return buildBlindIndex(tag, passphrase, tagIndexLabel)
+}
+
+func buildBlindIndex(value string, passphrase [32]byte, label string) string {
+	indexKey := sha256.Sum256(append(passphrase[:], label...))
+	mac := hmac.New(sha256.New, indexKey[:])
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 // EncryptAES256 encrypt data by AES256 algorithm
 func EncryptAES256(data []byte, passphrase [32]byte) ([]byte, error) {
 	c, err := aes.NewCipher(passphrase[:])
@@ -59,3 +95,72 @@ func DecryptAES256(ciphertext []byte, passphrase [32]byte) ([]byte, error) {
 
 	return plaintext, nil
 }
+
+// EncryptChunk deterministically encrypts chunk of media by AES256-GCM with synthetic nonce: nonce is keyed HMAC
+// of chunk content, so equal chunks of one user give equal ciphertexts and service can deduplicate them.
+// Nonce repeats only for equal content, so service learns only that chunks are equal
+func EncryptChunk(chunk []byte, passphrase [32]byte) ([]byte, error) {
+	gcm, err := buildChunkGCM(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := buildChunkNonce(chunk, passphrase)[:gcm.NonceSize()]
+
+	return gcm.Seal(nonce, nonce, chunk, nil), nil
+}
+
+// DecryptChunk decrypts chunk encrypted by EncryptChunk and checks that nonce matches content
+func DecryptChunk(ciphertext []byte, passphrase [32]byte) ([]byte, error) {
+	gcm, err := buildChunkGCM(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, fmt.Errorf("encrypted chunk is shorter than nonce")
+	}
+
+	nonce, sealed := ciphertext[:nonceSize], ciphertext[nonceSize:]
+	chunk, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open gcm while decrypt chunk: %w", err)
+	}
+
+	if !hmac.Equal(nonce, buildChunkNonce(chunk, passphrase)[:nonceSize]) {
+		return nil, fmt.Errorf("nonce of chunk doesn't match its content")
+	}
+
+	return chunk, nil
+}
+
+// BuildChunkHash returns hash of encrypted chunk, that identifies chunk on service
+func BuildChunkHash(ciphertext []byte) string {
+	hash := sha256.Sum256(ciphertext)
+
+	return hex.EncodeToString(hash[:])
+}
+
+func buildChunkGCM(passphrase [32]byte) (cipher.AEAD, error) {
+	key := sha256.Sum256(append(passphrase[:], chunkKeyLabel...))
+	c, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("cannot create cipher for chunk: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(c)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create GCM for chunk: %w", err)
+	}
+
+	return gcm, nil
+}
+
+func buildChunkNonce(chunk []byte, passphrase [32]byte) []byte {
+	nonceKey := sha256.Sum256(append(passphrase[:], chunkNonceLabel...))
+	mac := hmac.New(sha256.New, nonceKey[:])
+	mac.Write(chunk)
+
+	return mac.Sum(nil)
+}
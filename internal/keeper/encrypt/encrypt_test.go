@@ -100,3 +100,49 @@ func TestEncryptDecryptAES256(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildNameIndex(t *testing.T) {
+	key := BuildAESKey("user1", "upass1")
+
+	index := BuildNameIndex("prod-db-root", key)
+	assert.Len(t, index, 64)
+	assert.NotContains(t, index, "prod-db-root")
+	assert.Equal(t, index, BuildNameIndex("prod-db-root", key))
+	assert.NotEqual(t, index, BuildNameIndex("prod-db-root2", key))
+	assert.NotEqual(t, index, BuildNameIndex("prod-db-root", BuildAESKey("user2", "upass2")))
+	assert.NotEqual(t, index, BuildFolderIndex("prod-db-root", key))
+	assert.NotEqual(t, index, BuildTagIndex("prod-db-root", key))
+	assert.NotEqual(t, BuildFolderIndex("prod-db-root", key), BuildTagIndex("prod-db-root", key))
+}
+
+func TestEncryptDecryptChunk(t *testing.T) {
+	key := BuildAESKey("user1", "upass1")
+	otherKey := BuildAESKey("user2", "upass2")
+	chunk := []byte("some chunk of media file")
+
+	first, err := EncryptChunk(chunk, key)
+	require.NoError(t, err)
+
+	second, err := EncryptChunk(chunk, key)
+	require.NoError(t, err)
+	assert.Equal(t, first, second, "equal chunks of user must have equal ciphertext")
+	assert.Equal(t, BuildChunkHash(first), BuildChunkHash(second))
+
+	other, err := EncryptChunk(chunk, otherKey)
+	require.NoError(t, err)
+	assert.NotEqual(t, first, other, "equal chunks of other users must have other ciphertext")
+
+	changed, err := EncryptChunk([]byte("some chunk of media filE"), key)
+	require.NoError(t, err)
+	assert.NotEqual(t, first[:12], changed[:12], "nonce must depend on content")
+
+	decrypted, err := DecryptChunk(first, key)
+	require.NoError(t, err)
+	assert.Equal(t, chunk, decrypted)
+
+	_, err = DecryptChunk(first, otherKey)
+	assert.Error(t, err)
+
+	_, err = DecryptChunk(first[:4], key)
+	assert.Error(t, err)
+}
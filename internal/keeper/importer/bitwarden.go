@@ -0,0 +1,156 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/keeper/secret"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Types of Bitwarden items
+const (
+	bitwardenLogin      = 1
+	bitwardenSecureNote = 2
+	bitwardenCard       = 3
+	bitwardenIdentity   = 4
+)
+
+type bitwardenExport struct {
+	Encrypted         bool `json:"encrypted"`
+	PasswordProtected bool `json:"passwordProtected"`
+	Folders           []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"folders"`
+	Items []bitwardenItem `json:"items"`
+}
+
+type bitwardenItem struct {
+	Type     int    `json:"type"`
+	Name     string `json:"name"`
+	Notes    string `json:"notes"`
+	FolderID string `json:"folderId"`
+	Login    *struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		URIs     []struct {
+			URI string `json:"uri"`
+		} `json:"uris"`
+	} `json:"login"`
+	Card *struct {
+		CardholderName string `json:"cardholderName"`
+		Number         string `json:"number"`
+		ExpMonth       string `json:"expMonth"`
+		ExpYear        string `json:"expYear"`
+		Code           string `json:"code"`
+	} `json:"card"`
+	Identity map[string]*string `json:"identity"`
+	Fields   []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"fields"`
+}
+
+// ParseBitwarden parses unencrypted JSON export of Bitwarden. Logins are mapped to credentials, cards to cards,
+// secure notes and identities to texts. Folders of Bitwarden are separated by '/', so they are kept as folders
+func ParseBitwarden(r io.Reader) ([]Entry, error) {
+	var export bitwardenExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("cannot decode Bitwarden export: %w", err)
+	}
+
+	if export.Encrypted || export.PasswordProtected {
+		return nil, fmt.Errorf("encrypted Bitwarden export isn't supported, export vault to unencrypted JSON")
+	}
+
+	folders := make(map[string][]string, len(export.Folders))
+	for _, v := range export.Folders {
+		folders[v.ID] = strings.Split(v.Name, secret.FolderSeparator)
+	}
+
+	entries := make([]Entry, 0, len(export.Items))
+	for _, v := range export.Items {
+		name := buildName(folders[v.FolderID], v.Name)
+		notes := withFields(v.Notes, v)
+
+		switch {
+		case v.Type == bitwardenLogin && v.Login != nil:
+			credentials := Credentials{Login: v.Login.Username, Password: v.Login.Password, Notes: notes}
+			if len(v.Login.URIs) > 0 {
+				credentials.URL = v.Login.URIs[0].URI
+			}
+
+			if entry, ok := newLoginEntry(name, nil, credentials); ok {
+				entries = append(entries, entry)
+			}
+		case v.Type == bitwardenCard && v.Card != nil:
+			entries = append(entries, Entry{Type: secret.SecretTypeCard, Name: name, Card: Card{
+				Number:  v.Card.Number,
+				Holder:  v.Card.CardholderName,
+				CVV:     v.Card.Code,
+				Expires: formatExpires(v.Card.ExpMonth, v.Card.ExpYear),
+			}})
+		case v.Type == bitwardenIdentity && v.Identity != nil:
+			entries = append(entries, Entry{Type: secret.SecretTypeText, Name: name, Text: joinLines(formatIdentity(v.Identity), notes)})
+		default:
+			if notes != "" {
+				entries = append(entries, Entry{Type: secret.SecretTypeText, Name: name, Text: notes})
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// withFields appends custom fields of item to its notes
+func withFields(notes string, item bitwardenItem) string {
+	lines := make([]string, 0, len(item.Fields))
+	for _, v := range item.Fields {
+		lines = append(lines, v.Name+": "+v.Value)
+	}
+
+	return joinLines(notes, strings.Join(lines, "\n"))
+}
+
+func formatIdentity(identity map[string]*string) string {
+	keys := make([]string, 0, len(identity))
+	for k, v := range identity {
+		if v != nil && *v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, len(keys))
+	for i, k := range keys {
+		lines[i] = k + ": " + *identity[k]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func formatExpires(month string, year string) string {
+	if month == "" || year == "" {
+		return month + year
+	}
+
+	if len(month) == 1 {
+		month = "0" + month
+	}
+
+	return month + "/" + year
+}
+
+// joinLines joins not empty parts by new lines
+func joinLines(parts ...string) string {
+	lines := make([]string, 0, len(parts))
+	for _, v := range parts {
+		if v = strings.TrimSpace(v); v != "" {
+			lines = append(lines, v)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,139 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// csvColumns indexes of columns found in header of CSV export, -1 for missing columns
+type csvColumns struct {
+	title    int
+	url      int
+	login    int
+	password int
+	notes    int
+	tags     int
+	folder   int
+}
+
+// ParseBrowserCSV parses passwords exported by Chrome (name,url,username,password,note) or
+// Firefox (url,username,password,...). Entries are named by name column or host of URL
+func ParseBrowserCSV(r io.Reader) ([]Entry, error) {
+	return parseCSV(r, map[string][]string{
+		"title":    {"name"},
+		"url":      {"url", "origin"},
+		"login":    {"username"},
+		"password": {"password"},
+		"notes":    {"note", "notes"},
+	})
+}
+
+// Parse1PasswordCSV parses CSV export of 1Password with columns like Title,Url,Username,Password,Notes,Tags
+func Parse1PasswordCSV(r io.Reader) ([]Entry, error) {
+	return parseCSV(r, map[string][]string{
+		"title":    {"title", "name"},
+		"url":      {"url", "website", "login_uri"},
+		"login":    {"username", "login_username"},
+		"password": {"password", "login_password"},
+		"notes":    {"notes", "notesplain", "note"},
+		"tags":     {"tags"},
+		"folder":   {"vault", "folder"},
+	})
+}
+
+func parseCSV(r io.Reader, aliases map[string][]string) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("CSV export is empty")
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot read header of CSV export: %w", err)
+	}
+
+	columns := findColumns(header, aliases)
+	if columns.password == -1 {
+		return nil, fmt.Errorf("CSV export has no password column")
+	}
+
+	var entries []Entry
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("cannot read CSV export: %w", err)
+		}
+
+		credentials := Credentials{
+			Login:    column(row, columns.login),
+			Password: column(row, columns.password),
+			URL:      column(row, columns.url),
+			Notes:    column(row, columns.notes),
+		}
+
+		title := column(row, columns.title)
+		if title == "" {
+			title = hostOf(credentials.URL)
+		}
+
+		var folders []string
+		if folder := column(row, columns.folder); folder != "" {
+			folders = []string{folder}
+		}
+
+		entry, ok := newLoginEntry(buildName(folders, title), splitTags(column(row, columns.tags)), credentials)
+		if ok {
+			entries = append(entries, entry)
+		}
+	}
+}
+
+func findColumns(header []string, aliases map[string][]string) csvColumns {
+	find := func(field string) int {
+		for i, v := range header {
+			// exports of some tools start with byte order mark
+			name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(v, "\ufeff")))
+			for _, alias := range aliases[field] {
+				if name == alias {
+					return i
+				}
+			}
+		}
+
+		return -1
+	}
+
+	return csvColumns{
+		title:    find("title"),
+		url:      find("url"),
+		login:    find("login"),
+		password: find("password"),
+		notes:    find("notes"),
+		tags:     find("tags"),
+		folder:   find("folder"),
+	}
+}
+
+func column(row []string, index int) string {
+	if index < 0 || index >= len(row) {
+		return ""
+	}
+
+	return strings.TrimSpace(row[index])
+}
+
+// hostOf returns host of URL to name entries without title, raw value for values that aren't URL
+func hostOf(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return rawURL
+	}
+
+	return parsed.Hostname()
+}
@@ -0,0 +1,223 @@
+// Package importer parses exports of other password managers and maps their entries onto secrets of keeper
+package importer
+
+import (
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/keeper/secret"
+	"github.com/nessai1/gophkeeper/internal/keeper/vault"
+	"io"
+	"strings"
+)
+
+// Formats of supported exports
+const (
+	FormatKeePass   = "keepass"
+	FormatBitwarden = "bitwarden"
+	Format1Password = "1password"
+	FormatChrome    = "chrome"
+	FormatFirefox   = "firefox"
+)
+
+// untitledName name of entries without title
+const untitledName = "untitled"
+
+// Entry secret parsed from export, only fields of its type are filled
+type Entry struct {
+	Type secret.SecretType
+	// Name path-like name of secret, folders of source manager are kept as folders
+	Name string
+	Tags []string
+
+	Credentials Credentials
+	Card        Card
+	Text        string
+}
+
+type Credentials struct {
+	Login    string
+	Password string
+	URL      string
+	Notes    string
+}
+
+type Card struct {
+	Number string
+	Holder string
+	CVV    string
+	// Expires expires date in format MM/YYYY
+	Expires string
+}
+
+// Options of parse, password and key file are used only by KeePass database
+type Options struct {
+	Password string
+	// KeyFile content of KeePass key file, nil for database without key file
+	KeyFile []byte
+}
+
+// IsFormat reports whether format is supported
+func IsFormat(format string) bool {
+	return format == FormatKeePass || format == FormatBitwarden || format == Format1Password || format == FormatChrome || format == FormatFirefox
+}
+
+// Parse reads export of format
+func Parse(format string, r io.Reader, options Options) ([]Entry, error) {
+	switch format {
+	case FormatKeePass:
+		return ParseKeePass(r, options.Password, options.KeyFile)
+	case FormatBitwarden:
+		return ParseBitwarden(r)
+	case Format1Password:
+		return Parse1PasswordCSV(r)
+	case FormatChrome, FormatFirefox:
+		return ParseBrowserCSV(r)
+	default:
+		return nil, fmt.Errorf("unsupported format of import: %s", format)
+	}
+}
+
+// Status of entry in plan of import
+type Status int
+
+const (
+	// StatusNew entry is uploaded with its name
+	StatusNew Status = iota
+	// StatusRenamed entry is uploaded with new name, because its name is taken by other entry or existing secret
+	StatusRenamed
+	// StatusDuplicate entry repeats previous entry of export and isn't uploaded
+	StatusDuplicate
+	// StatusExists name of entry is taken by existing secret, entry isn't uploaded
+	StatusExists
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusRenamed:
+		return "renamed"
+	case StatusDuplicate:
+		return "duplicate"
+	case StatusExists:
+		return "exists"
+	default:
+		return "new"
+	}
+}
+
+// Item entry of plan with name of uploaded secret
+type Item struct {
+	Entry  Entry
+	Name   string
+	Status Status
+}
+
+// Plan deduplicates entries and resolves their names. Entries equal to previous entries of export are duplicates,
+// other entries with taken names are renamed. Entries with names of existing secrets are renamed
+// if rename is true, otherwise they are skipped
+func Plan(entries []Entry, exists func(secretType secret.SecretType, name string) bool, rename bool) []Item {
+	type entryKey struct {
+		secretType secret.SecretType
+		name       string
+	}
+
+	// planned names of uploaded secrets, seen entries by their original names
+	planned := make(map[entryKey]struct{}, len(entries))
+	seen := make(map[entryKey][]Entry, len(entries))
+	taken := func(secretType secret.SecretType) func(name string) bool {
+		return func(name string) bool {
+			_, ok := planned[entryKey{secretType: secretType, name: name}]
+
+			return ok || exists(secretType, name)
+		}
+	}
+
+	items := make([]Item, len(entries))
+	for i, v := range entries {
+		items[i] = Item{Entry: v, Name: v.Name, Status: StatusNew}
+
+		original := entryKey{secretType: v.Type, name: v.Name}
+		if isDuplicate(seen[original], v) {
+			items[i].Status = StatusDuplicate
+
+			continue
+		}
+		seen[original] = append(seen[original], v)
+
+		if exists(v.Type, v.Name) && !rename {
+			items[i].Status = StatusExists
+
+			continue
+		}
+
+		if taken(v.Type)(v.Name) {
+			items[i].Name = vault.UniqueName(v.Name, taken(v.Type))
+			items[i].Status = StatusRenamed
+		}
+
+		planned[entryKey{secretType: v.Type, name: items[i].Name}] = struct{}{}
+	}
+
+	return items
+}
+
+func isDuplicate(seen []Entry, entry Entry) bool {
+	for _, v := range seen {
+		if sameContent(v, entry) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sameContent(a, b Entry) bool {
+	return a.Type == b.Type && a.Credentials == b.Credentials && a.Card == b.Card && a.Text == b.Text &&
+		strings.Join(a.Tags, "\n") == strings.Join(b.Tags, "\n")
+}
+
+// buildName builds path-like name of secret from folders and title of entry,
+// separators inside folders and title are replaced, so they don't create new folders
+func buildName(folders []string, title string) string {
+	parts := make([]string, 0, len(folders)+1)
+	for _, v := range folders {
+		if v = cleanNamePart(v); v != "" {
+			parts = append(parts, v)
+		}
+	}
+
+	title = cleanNamePart(title)
+	if title == "" {
+		title = untitledName
+	}
+
+	return strings.Join(append(parts, title), secret.FolderSeparator)
+}
+
+func cleanNamePart(part string) string {
+	return strings.TrimSpace(strings.ReplaceAll(part, secret.FolderSeparator, "-"))
+}
+
+// splitTags splits tags separated by commas or semicolons
+func splitTags(raw string) []string {
+	var tags []string
+	for _, v := range strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || r == ';' }) {
+		if v = strings.TrimSpace(v); v != "" {
+			tags = append(tags, v)
+		}
+	}
+
+	return tags
+}
+
+// newLoginEntry maps login of source manager to credentials, logins without login and password are kept as text
+func newLoginEntry(name string, tags []string, credentials Credentials) (Entry, bool) {
+	if credentials.Login == "" && credentials.Password == "" {
+		text := strings.TrimSpace(strings.Join([]string{credentials.URL, credentials.Notes}, "\n"))
+		if text == "" {
+			return Entry{}, false
+		}
+
+		return Entry{Type: secret.SecretTypeText, Name: name, Tags: tags, Text: text}, true
+	}
+
+	return Entry{Type: secret.SecretTypeCredentials, Name: name, Tags: tags, Credentials: credentials}, true
+}
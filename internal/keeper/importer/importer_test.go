@@ -0,0 +1,159 @@
+package importer
+
+import (
+	"github.com/nessai1/gophkeeper/internal/keeper/secret"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestParseBrowserCSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		export   string
+		expected []Entry
+	}{
+		{
+			name:   "Chrome",
+			export: "\ufeffname,url,username,password,note\ngithub,https://github.com/login,octocat,hunter2,\n,https://mail.example.com/,me,secret,work mail\n",
+			expected: []Entry{
+				{Type: secret.SecretTypeCredentials, Name: "github", Credentials: Credentials{Login: "octocat", Password: "hunter2", URL: "https://github.com/login"}},
+				{Type: secret.SecretTypeCredentials, Name: "mail.example.com", Credentials: Credentials{Login: "me", Password: "secret", URL: "https://mail.example.com/", Notes: "work mail"}},
+			},
+		},
+		{
+			name:   "Firefox",
+			export: "\"url\",\"username\",\"password\",\"httpRealm\",\"formActionOrigin\",\"guid\"\n\"https://example.com:8080\",\"admin\",\"qwerty\",,\"\",\"{1}\"\n\"https://empty.example.com\",\"\",\"\",,\"\",\"{2}\"\n",
+			expected: []Entry{
+				{Type: secret.SecretTypeCredentials, Name: "example.com", Credentials: Credentials{Login: "admin", Password: "qwerty", URL: "https://example.com:8080"}},
+				{Type: secret.SecretTypeText, Name: "empty.example.com", Text: "https://empty.example.com"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := ParseBrowserCSV(strings.NewReader(tt.export))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, entries)
+		})
+	}
+
+	_, err := ParseBrowserCSV(strings.NewReader("name,url,username\n"))
+	assert.Error(t, err)
+}
+
+func TestParse1PasswordCSV(t *testing.T) {
+	export := "Title,Url,Username,Password,Notes,Tags,Vault\n" +
+		"AWS / root,https://aws.amazon.com,root@example.com,pa$$,MFA on phone,\"cloud,work\",Work\n"
+
+	entries, err := Parse1PasswordCSV(strings.NewReader(export))
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{
+			Type: secret.SecretTypeCredentials,
+			Name: "Work/AWS - root",
+			Tags: []string{"cloud", "work"},
+			Credentials: Credentials{
+				Login:    "root@example.com",
+				Password: "pa$$",
+				URL:      "https://aws.amazon.com",
+				Notes:    "MFA on phone",
+			},
+		},
+	}, entries)
+}
+
+func TestParseBitwarden(t *testing.T) {
+	export := `{
+	"encrypted": false,
+	"folders": [{"id": "f1", "name": "Personal/Bank"}],
+	"items": [
+		{
+			"type": 1, "name": "github", "notes": "main account", "folderId": null,
+			"login": {"username": "octocat", "password": "hunter2", "uris": [{"uri": "https://github.com"}]},
+			"fields": [{"name": "PIN", "value": "1234"}]
+		},
+		{"type": 2, "name": "wifi", "notes": "password is on router", "folderId": "f1", "secureNote": {"type": 0}},
+		{"type": 2, "name": "empty", "notes": null},
+		{
+			"type": 3, "name": "visa", "folderId": "f1",
+			"card": {"cardholderName": "JOHN DOE", "number": "4111111111111111", "expMonth": "3", "expYear": "2030", "code": "123"}
+		},
+		{"type": 4, "name": "passport", "identity": {"firstName": "John", "lastName": "Doe", "email": null}}
+	]
+}`
+
+	entries, err := ParseBitwarden(strings.NewReader(export))
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{
+			Type: secret.SecretTypeCredentials,
+			Name: "github",
+			Credentials: Credentials{
+				Login:    "octocat",
+				Password: "hunter2",
+				URL:      "https://github.com",
+				Notes:    "main account\nPIN: 1234",
+			},
+		},
+		{Type: secret.SecretTypeText, Name: "Personal/Bank/wifi", Text: "password is on router"},
+		{Type: secret.SecretTypeCard, Name: "Personal/Bank/visa", Card: Card{Number: "4111111111111111", Holder: "JOHN DOE", CVV: "123", Expires: "03/2030"}},
+		{Type: secret.SecretTypeText, Name: "passport", Text: "firstName: John\nlastName: Doe"},
+	}, entries)
+
+	_, err = ParseBitwarden(strings.NewReader(`{"encrypted": true, "items": []}`))
+	assert.Error(t, err)
+}
+
+func TestPlan(t *testing.T) {
+	github := Entry{Type: secret.SecretTypeCredentials, Name: "github", Credentials: Credentials{Login: "octocat", Password: "hunter2"}}
+	otherGithub := Entry{Type: secret.SecretTypeCredentials, Name: "github", Credentials: Credentials{Login: "work", Password: "qwerty"}}
+	githubNote := Entry{Type: secret.SecretTypeText, Name: "github", Text: "recovery codes"}
+	mail := Entry{Type: secret.SecretTypeCredentials, Name: "mail", Credentials: Credentials{Login: "me", Password: "secret"}}
+
+	entries := []Entry{github, otherGithub, github, githubNote, mail}
+	exists := func(secretType secret.SecretType, name string) bool {
+		return secretType == secret.SecretTypeCredentials && name == "mail"
+	}
+
+	tests := []struct {
+		name     string
+		rename   bool
+		expected []Item
+	}{
+		{
+			name: "Skip existing",
+			expected: []Item{
+				{Entry: github, Name: "github", Status: StatusNew},
+				{Entry: otherGithub, Name: "github-2", Status: StatusRenamed},
+				{Entry: github, Name: "github", Status: StatusDuplicate},
+				{Entry: githubNote, Name: "github", Status: StatusNew},
+				{Entry: mail, Name: "mail", Status: StatusExists},
+			},
+		},
+		{
+			name:   "Rename existing",
+			rename: true,
+			expected: []Item{
+				{Entry: github, Name: "github", Status: StatusNew},
+				{Entry: otherGithub, Name: "github-2", Status: StatusRenamed},
+				{Entry: github, Name: "github", Status: StatusDuplicate},
+				{Entry: githubNote, Name: "github", Status: StatusNew},
+				{Entry: mail, Name: "mail-2", Status: StatusRenamed},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Plan(entries, exists, tt.rename))
+		})
+	}
+}
+
+func TestBuildName(t *testing.T) {
+	assert.Equal(t, "Work/Infra-DB/untitled", buildName([]string{"Work", "", "Infra/DB"}, " "))
+	assert.Equal(t, "github", buildName(nil, "github"))
+}
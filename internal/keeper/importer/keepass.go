@@ -0,0 +1,683 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/nessai1/gophkeeper/pkg/argon2d"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20"
+	"golang.org/x/crypto/twofish"
+	"io"
+	"strings"
+)
+
+// ErrWrongKey returns if KeePass database can't be opened by password and key file
+var ErrWrongKey = errors.New("wrong password or key file of KeePass database")
+
+const (
+	kdbxSignature1 = 0x9AA2D903
+	kdbxSignature2 = 0xB54BFB67
+	kdbxMajorV4    = 4
+)
+
+// Fields of outer header of KDBX 4
+const (
+	kdbxEndOfHeader   = 0
+	kdbxCipherID      = 2
+	kdbxCompression   = 3
+	kdbxMasterSeed    = 4
+	kdbxEncryptionIV  = 7
+	kdbxKDFParameters = 11
+)
+
+// Fields of inner header of KDBX 4
+const (
+	kdbxInnerEnd       = 0
+	kdbxInnerStreamID  = 1
+	kdbxInnerStreamKey = 2
+)
+
+// kdbxMaxFieldSize max size of header field or payload block, it protects from allocations by damaged sizes
+const kdbxMaxFieldSize = 64 << 20
+
+// kdbxInnerChaCha20 id of ChaCha20 stream that protects values of KDBX 4 database
+const kdbxInnerChaCha20 = 3
+
+// UUIDs of ciphers and key derivation functions
+var (
+	kdbxCipherAES     = mustDecodeHex("31c1f2e6bf714350be5805216afc5aff")
+	kdbxCipherChaCha  = mustDecodeHex("d6038a2b8b6f4cb5a524339a31dbb59a")
+	kdbxCipherTwofish = mustDecodeHex("ad68f29f576f4bb9a36ad47af965346c")
+
+	kdbxKDFAES      = mustDecodeHex("c9d9f39a628a4460bf740d08c18a4fea")
+	kdbxKDFArgon2d  = mustDecodeHex("ef636ddf8c29444b91f7a9a403e30a0c")
+	kdbxKDFArgon2id = mustDecodeHex("9e298b1956db4773b23dfc3ec6f0a1e6")
+)
+
+// Types of values of KDBX variant dictionary
+const (
+	variantEnd    = 0x00
+	variantUInt32 = 0x04
+	variantUInt64 = 0x05
+)
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+// kdbxHeader fields of outer header used by decryption
+type kdbxHeader struct {
+	cipherID    []byte
+	compressed  bool
+	masterSeed  []byte
+	iv          []byte
+	kdf         map[string]variantValue
+	raw         []byte
+	sha256      []byte
+	hmacSHA256  []byte
+	hmacBaseKey []byte
+}
+
+type variantValue struct {
+	kind  byte
+	value []byte
+}
+
+// ParseKeePass parses KeePass database of KDBX 4 format. Groups are kept as folders, entries of recycle bin
+// and history of entries are skipped. Entries with login or password are mapped to credentials, other entries to texts
+func ParseKeePass(r io.Reader, password string, keyFile []byte) ([]Entry, error) {
+	br := bufio.NewReader(r)
+	header, err := readKDBXHeader(br)
+	if err != nil {
+		return nil, err
+	}
+
+	compositeKey, err := buildCompositeKey(password, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	transformedKey, err := transformKey(compositeKey, header.kdf)
+	if err != nil {
+		return nil, err
+	}
+
+	seeded := append(bytes.Clone(header.masterSeed), transformedKey...)
+	masterKey := sha256.Sum256(seeded)
+	hmacBaseKey := sha512.Sum512(append(seeded, 1))
+	header.hmacBaseKey = hmacBaseKey[:]
+
+	if !hmac.Equal(headerHMAC(header.hmacBaseKey, header.raw), header.hmacSHA256) {
+		return nil, ErrWrongKey
+	}
+
+	encrypted, err := readHMACBlocks(br, header.hmacBaseKey)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := decryptPayload(header, masterKey[:], encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	if header.compressed {
+		gz, err := gzip.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("cannot decompress KeePass database: %w", err)
+		}
+
+		if payload, err = io.ReadAll(gz); err != nil {
+			return nil, fmt.Errorf("cannot decompress KeePass database: %w", err)
+		}
+	}
+
+	return parseKDBXPayload(payload)
+}
+
+func readKDBXHeader(r io.Reader) (kdbxHeader, error) {
+	raw := bytes.Buffer{}
+	tr := io.TeeReader(r, &raw)
+
+	signature := make([]byte, 12)
+	if _, err := io.ReadFull(tr, signature); err != nil {
+		return kdbxHeader{}, fmt.Errorf("cannot read signature of KeePass database: %w", err)
+	}
+
+	if binary.LittleEndian.Uint32(signature[0:4]) != kdbxSignature1 || binary.LittleEndian.Uint32(signature[4:8]) != kdbxSignature2 {
+		return kdbxHeader{}, fmt.Errorf("file isn't KeePass database")
+	}
+
+	if major := binary.LittleEndian.Uint16(signature[10:12]); major != kdbxMajorV4 {
+		return kdbxHeader{}, fmt.Errorf("KeePass database of version %d isn't supported, save it in KDBX 4 format", major)
+	}
+
+	header := kdbxHeader{}
+	for {
+		fieldHeader := make([]byte, 5)
+		if _, err := io.ReadFull(tr, fieldHeader); err != nil {
+			return kdbxHeader{}, fmt.Errorf("cannot read header of KeePass database: %w", err)
+		}
+
+		size := binary.LittleEndian.Uint32(fieldHeader[1:])
+		if size > kdbxMaxFieldSize {
+			return kdbxHeader{}, fmt.Errorf("header of KeePass database is damaged")
+		}
+
+		data := make([]byte, size)
+		if _, err := io.ReadFull(tr, data); err != nil {
+			return kdbxHeader{}, fmt.Errorf("cannot read header of KeePass database: %w", err)
+		}
+
+		switch fieldHeader[0] {
+		case kdbxCipherID:
+			header.cipherID = data
+		case kdbxCompression:
+			header.compressed = len(data) == 4 && binary.LittleEndian.Uint32(data) == 1
+		case kdbxMasterSeed:
+			header.masterSeed = data
+		case kdbxEncryptionIV:
+			header.iv = data
+		case kdbxKDFParameters:
+			kdf, err := parseVariantDictionary(data)
+			if err != nil {
+				return kdbxHeader{}, fmt.Errorf("cannot parse key derivation parameters: %w", err)
+			}
+			header.kdf = kdf
+		}
+
+		if fieldHeader[0] == kdbxEndOfHeader {
+			break
+		}
+	}
+
+	header.raw = bytes.Clone(raw.Bytes())
+
+	checks := make([]byte, 64)
+	if _, err := io.ReadFull(r, checks); err != nil {
+		return kdbxHeader{}, fmt.Errorf("cannot read checksum of KeePass header: %w", err)
+	}
+	header.sha256, header.hmacSHA256 = checks[:32], checks[32:]
+
+	if sum := sha256.Sum256(header.raw); !bytes.Equal(sum[:], header.sha256) {
+		return kdbxHeader{}, fmt.Errorf("header of KeePass database is damaged")
+	}
+
+	if len(header.masterSeed) != 32 || header.kdf == nil || header.cipherID == nil {
+		return kdbxHeader{}, fmt.Errorf("header of KeePass database misses required fields")
+	}
+
+	return header, nil
+}
+
+func parseVariantDictionary(data []byte) (map[string]variantValue, error) {
+	if len(data) < 2 || data[1] != 0x01 {
+		return nil, fmt.Errorf("unsupported version of variant dictionary")
+	}
+	data = data[2:]
+
+	values := make(map[string]variantValue)
+	for {
+		if len(data) < 1 {
+			return nil, fmt.Errorf("variant dictionary is truncated")
+		}
+
+		kind := data[0]
+		if kind == variantEnd {
+			return values, nil
+		}
+
+		key, rest, err := readSized(data[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		value, rest, err := readSized(rest)
+		if err != nil {
+			return nil, err
+		}
+
+		values[string(key)] = variantValue{kind: kind, value: value}
+		data = rest
+	}
+}
+
+// readSized reads value prefixed by its int32 size
+func readSized(data []byte) ([]byte, []byte, error) {
+	if len(data) < 4 {
+		return nil, nil, fmt.Errorf("variant dictionary is truncated")
+	}
+
+	size := int(binary.LittleEndian.Uint32(data))
+	if size < 0 || len(data) < 4+size {
+		return nil, nil, fmt.Errorf("variant dictionary is truncated")
+	}
+
+	return data[4 : 4+size], data[4+size:], nil
+}
+
+func (v variantValue) uint64() (uint64, error) {
+	switch {
+	case v.kind == variantUInt64 && len(v.value) == 8:
+		return binary.LittleEndian.Uint64(v.value), nil
+	case v.kind == variantUInt32 && len(v.value) == 4:
+		return uint64(binary.LittleEndian.Uint32(v.value)), nil
+	default:
+		return 0, fmt.Errorf("unexpected type of numeric parameter")
+	}
+}
+
+// buildCompositeKey builds composite key of password and key file, password is skipped for key file without password
+func buildCompositeKey(password string, keyFile []byte) ([]byte, error) {
+	composite := sha256.New()
+	if password != "" || keyFile == nil {
+		passwordHash := sha256.Sum256([]byte(password))
+		composite.Write(passwordHash[:])
+	}
+
+	if keyFile != nil {
+		key, err := parseKeyFile(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		composite.Write(key)
+	}
+
+	return composite.Sum(nil), nil
+}
+
+type keyFileXML struct {
+	Meta struct {
+		Version string `xml:"Version"`
+	} `xml:"Meta"`
+	Key struct {
+		Data string `xml:"Data"`
+	} `xml:"Key"`
+}
+
+// parseKeyFile returns key of KeePass key file: XML key file, 32 raw bytes, 64 hex digits or hash of any other file
+func parseKeyFile(content []byte) ([]byte, error) {
+	var keyXML keyFileXML
+	if err := xml.Unmarshal(content, &keyXML); err == nil && keyXML.Key.Data != "" {
+		data := strings.Join(strings.Fields(keyXML.Key.Data), "")
+		if strings.HasPrefix(keyXML.Meta.Version, "2.") {
+			key, err := hex.DecodeString(data)
+			if err != nil {
+				return nil, fmt.Errorf("cannot decode key of key file: %w", err)
+			}
+
+			return key, nil
+		}
+
+		key, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decode key of key file: %w", err)
+		}
+
+		return key, nil
+	}
+
+	if len(content) == 32 {
+		return content, nil
+	}
+
+	if len(content) == 64 {
+		if key, err := hex.DecodeString(string(content)); err == nil {
+			return key, nil
+		}
+	}
+
+	sum := sha256.Sum256(content)
+
+	return sum[:], nil
+}
+
+func transformKey(compositeKey []byte, kdf map[string]variantValue) ([]byte, error) {
+	uuid := kdf["$UUID"].value
+	salt := kdf["S"].value
+	switch {
+	case bytes.Equal(uuid, kdbxKDFAES):
+		rounds, err := kdf["R"].uint64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid rounds of AES-KDF: %w", err)
+		}
+
+		return transformKeyAES(compositeKey, salt, rounds)
+	case bytes.Equal(uuid, kdbxKDFArgon2d), bytes.Equal(uuid, kdbxKDFArgon2id):
+		iterations, err := kdf["I"].uint64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid iterations of Argon2: %w", err)
+		}
+
+		memory, err := kdf["M"].uint64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid memory of Argon2: %w", err)
+		}
+
+		parallelism, err := kdf["P"].uint64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid parallelism of Argon2: %w", err)
+		}
+
+		if version, err := kdf["V"].uint64(); err != nil || version != argon2d.Version {
+			return nil, fmt.Errorf("unsupported version of Argon2, save database by newer KeePass")
+		}
+
+		if len(kdf["K"].value) > 0 || len(kdf["A"].value) > 0 {
+			return nil, fmt.Errorf("Argon2 with secret key or associated data isn't supported")
+		}
+
+		if iterations == 0 || iterations > 1<<32-1 || parallelism == 0 || parallelism > 255 || memory/1024 > 1<<32-1 {
+			return nil, fmt.Errorf("invalid parameters of Argon2")
+		}
+
+		if bytes.Equal(uuid, kdbxKDFArgon2d) {
+			return argon2d.Key(compositeKey, salt, uint32(iterations), uint32(memory/1024), uint8(parallelism), 32), nil
+		}
+
+		return argon2.IDKey(compositeKey, salt, uint32(iterations), uint32(memory/1024), uint8(parallelism), 32), nil
+	default:
+		return nil, fmt.Errorf("unsupported key derivation function of KeePass database")
+	}
+}
+
+// transformKeyAES encrypts both halves of composite key by AES-ECB with seed for rounds
+func transformKeyAES(compositeKey []byte, seed []byte, rounds uint64) ([]byte, error) {
+	block, err := aes.NewCipher(seed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid seed of AES-KDF: %w", err)
+	}
+
+	key := bytes.Clone(compositeKey)
+	for i := uint64(0); i < rounds; i++ {
+		block.Encrypt(key[:16], key[:16])
+		block.Encrypt(key[16:], key[16:])
+	}
+
+	sum := sha256.Sum256(key)
+
+	return sum[:], nil
+}
+
+// blockHMACKey builds key of HMAC of block by its index
+func blockHMACKey(baseKey []byte, index uint64) []byte {
+	key := sha512.Sum512(append(binary.LittleEndian.AppendUint64(nil, index), baseKey...))
+
+	return key[:]
+}
+
+// headerHMAC computes HMAC of header by key of block with max index
+func headerHMAC(baseKey []byte, header []byte) []byte {
+	mac := hmac.New(sha256.New, blockHMACKey(baseKey, ^uint64(0)))
+	mac.Write(header)
+
+	return mac.Sum(nil)
+}
+
+// blockHMAC computes HMAC of index, size and data of block
+func blockHMAC(baseKey []byte, index uint64, data []byte) []byte {
+	mac := hmac.New(sha256.New, blockHMACKey(baseKey, index))
+	mac.Write(binary.LittleEndian.AppendUint64(nil, index))
+	mac.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(data))))
+	mac.Write(data)
+
+	return mac.Sum(nil)
+}
+
+// readHMACBlocks reads and verifies blocks of encrypted payload
+func readHMACBlocks(r io.Reader, hmacBaseKey []byte) ([]byte, error) {
+	payload := bytes.Buffer{}
+	for index := uint64(0); ; index++ {
+		blockHeader := make([]byte, 36)
+		if _, err := io.ReadFull(r, blockHeader); err != nil {
+			return nil, fmt.Errorf("KeePass database is truncated: %w", err)
+		}
+
+		size := binary.LittleEndian.Uint32(blockHeader[32:])
+		if size > kdbxMaxFieldSize {
+			return nil, fmt.Errorf("block %d of KeePass database is damaged", index)
+		}
+
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, fmt.Errorf("KeePass database is truncated: %w", err)
+		}
+
+		if !hmac.Equal(blockHMAC(hmacBaseKey, index, data), blockHeader[:32]) {
+			return nil, fmt.Errorf("block %d of KeePass database is damaged", index)
+		}
+
+		if len(data) == 0 {
+			return payload.Bytes(), nil
+		}
+		payload.Write(data)
+	}
+}
+
+func decryptPayload(header kdbxHeader, masterKey []byte, encrypted []byte) ([]byte, error) {
+	switch {
+	case bytes.Equal(header.cipherID, kdbxCipherChaCha):
+		stream, err := chacha20.NewUnauthenticatedCipher(masterKey, header.iv)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create ChaCha20 cipher: %w", err)
+		}
+		stream.XORKeyStream(encrypted, encrypted)
+
+		return encrypted, nil
+	case bytes.Equal(header.cipherID, kdbxCipherAES):
+		block, err := aes.NewCipher(masterKey)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create AES cipher: %w", err)
+		}
+
+		return decryptCBC(block, header.iv, encrypted)
+	case bytes.Equal(header.cipherID, kdbxCipherTwofish):
+		block, err := twofish.NewCipher(masterKey)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create Twofish cipher: %w", err)
+		}
+
+		return decryptCBC(block, header.iv, encrypted)
+	default:
+		return nil, fmt.Errorf("unsupported cipher of KeePass database")
+	}
+}
+
+func decryptCBC(block cipher.Block, iv []byte, encrypted []byte) ([]byte, error) {
+	if len(iv) != block.BlockSize() || len(encrypted) == 0 || len(encrypted)%block.BlockSize() != 0 {
+		return nil, fmt.Errorf("invalid size of encrypted KeePass database")
+	}
+
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(encrypted, encrypted)
+
+	padding := int(encrypted[len(encrypted)-1])
+	if padding == 0 || padding > block.BlockSize() {
+		return nil, fmt.Errorf("invalid padding of KeePass database")
+	}
+
+	return encrypted[:len(encrypted)-padding], nil
+}
+
+// parseKDBXPayload reads inner header and XML document of decrypted payload
+func parseKDBXPayload(payload []byte) ([]Entry, error) {
+	var streamID uint32
+	var streamKey []byte
+	for {
+		if len(payload) < 5 {
+			return nil, fmt.Errorf("inner header of KeePass database is truncated")
+		}
+
+		id := payload[0]
+		size := int(binary.LittleEndian.Uint32(payload[1:5]))
+		if size < 0 || len(payload) < 5+size {
+			return nil, fmt.Errorf("inner header of KeePass database is truncated")
+		}
+
+		data := payload[5 : 5+size]
+		payload = payload[5+size:]
+
+		switch id {
+		case kdbxInnerStreamID:
+			if len(data) == 4 {
+				streamID = binary.LittleEndian.Uint32(data)
+			}
+		case kdbxInnerStreamKey:
+			streamKey = data
+		}
+
+		if id == kdbxInnerEnd {
+			break
+		}
+	}
+
+	if streamID != kdbxInnerChaCha20 {
+		return nil, fmt.Errorf("unsupported stream of protected values of KeePass database")
+	}
+
+	keyHash := sha512.Sum512(streamKey)
+	stream, err := chacha20.NewUnauthenticatedCipher(keyHash[:32], keyHash[32:44])
+	if err != nil {
+		return nil, fmt.Errorf("cannot create stream of protected values: %w", err)
+	}
+
+	var document xmlNode
+	if err = xml.Unmarshal(payload, &document); err != nil {
+		return nil, fmt.Errorf("cannot parse XML of KeePass database: %w", err)
+	}
+
+	if err = document.unprotect(stream); err != nil {
+		return nil, err
+	}
+
+	recycleBin := ""
+	if meta := document.child("Meta"); meta != nil && meta.text("RecycleBinEnabled") == "True" {
+		recycleBin = meta.text("RecycleBinUUID")
+	}
+
+	root := document.child("Root")
+	if root == nil || root.child("Group") == nil {
+		return nil, fmt.Errorf("KeePass database has no root group")
+	}
+
+	var entries []Entry
+	collectKeePassEntries(root.child("Group"), nil, recycleBin, &entries)
+
+	return entries, nil
+}
+
+// xmlNode element of XML document, children are kept in order of document
+type xmlNode struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Content string     `xml:",chardata"`
+	Nodes   []xmlNode  `xml:",any"`
+}
+
+// unprotect decrypts protected values, they are encrypted by one stream in order of document
+func (n *xmlNode) unprotect(stream *chacha20.Cipher) error {
+	if n.XMLName.Local == "Value" {
+		for _, attr := range n.Attrs {
+			if attr.Name.Local != "Protected" || attr.Value != "True" {
+				continue
+			}
+
+			value, err := base64.StdEncoding.DecodeString(n.Content)
+			if err != nil {
+				return fmt.Errorf("cannot decode protected value of KeePass database: %w", err)
+			}
+
+			stream.XORKeyStream(value, value)
+			n.Content = string(value)
+		}
+	}
+
+	for i := range n.Nodes {
+		if err := n.Nodes[i].unprotect(stream); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (n *xmlNode) child(name string) *xmlNode {
+	for i := range n.Nodes {
+		if n.Nodes[i].XMLName.Local == name {
+			return &n.Nodes[i]
+		}
+	}
+
+	return nil
+}
+
+func (n *xmlNode) text(name string) string {
+	if child := n.child(name); child != nil {
+		return child.Content
+	}
+
+	return ""
+}
+
+// collectKeePassEntries collects entries of group and its subgroups, name of root group isn't used as folder
+func collectKeePassEntries(group *xmlNode, folders []string, recycleBin string, entries *[]Entry) {
+	for i := range group.Nodes {
+		node := &group.Nodes[i]
+		switch node.XMLName.Local {
+		case "Group":
+			if recycleBin != "" && node.text("UUID") == recycleBin {
+				continue
+			}
+
+			collectKeePassEntries(node, append(folders[:len(folders):len(folders)], node.text("Name")), recycleBin, entries)
+		case "Entry":
+			if entry, ok := newKeePassEntry(node, folders); ok {
+				*entries = append(*entries, entry)
+			}
+		}
+	}
+}
+
+func newKeePassEntry(node *xmlNode, folders []string) (Entry, bool) {
+	values := make(map[string]string)
+	var custom []string
+	for i := range node.Nodes {
+		field := &node.Nodes[i]
+		if field.XMLName.Local != "String" {
+			continue
+		}
+
+		key, value := field.text("Key"), field.text("Value")
+		switch key {
+		case "Title", "UserName", "Password", "URL", "Notes":
+			values[key] = value
+		default:
+			if value != "" {
+				custom = append(custom, key+": "+value)
+			}
+		}
+	}
+
+	return newLoginEntry(buildName(folders, values["Title"]), splitTags(node.text("Tags")), Credentials{
+		Login:    values["UserName"],
+		Password: values["Password"],
+		URL:      values["URL"],
+		Notes:    joinLines(values["Notes"], strings.Join(custom, "\n")),
+	})
+}
@@ -0,0 +1,313 @@
+package importer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/keeper/secret"
+	"github.com/nessai1/gophkeeper/pkg/argon2d"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20"
+	"testing"
+)
+
+// kdbxOptions settings of database written by test
+type kdbxOptions struct {
+	password string
+	keyFile  []byte
+	kdf      []byte
+	cipher   []byte
+	compress bool
+}
+
+// keePassDocument XML of test database, protect encrypts value by stream of protected values
+func keePassDocument(protect func(value string) string) string {
+	return fmt.Sprintf(`<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<KeePassFile>
+	<Meta>
+		<DatabaseName>Team</DatabaseName>
+		<RecycleBinEnabled>True</RecycleBinEnabled>
+		<RecycleBinUUID>cmVjeWNsZWJpbg==</RecycleBinUUID>
+	</Meta>
+	<Root>
+		<Group>
+			<UUID>cm9vdA==</UUID>
+			<Name>Database</Name>
+			<Entry>
+				<Tags>dev;vcs</Tags>
+				<String><Key>Title</Key><Value>github</Value></String>
+				<String><Key>UserName</Key><Value>octocat</Value></String>
+				<String><Key>Password</Key><Value Protected="True">%s</Value></String>
+				<String><Key>URL</Key><Value>https://github.com</Value></String>
+				<String><Key>Recovery</Key><Value Protected="True">%s</Value></String>
+				<History>
+					<Entry>
+						<String><Key>Title</Key><Value>github</Value></String>
+						<String><Key>Password</Key><Value Protected="True">%s</Value></String>
+					</Entry>
+				</History>
+			</Entry>
+			<Group>
+				<UUID>d29yaw==</UUID>
+				<Name>Work/Infra</Name>
+				<Entry>
+					<String><Key>Title</Key><Value>db</Value></String>
+					<String><Key>UserName</Key><Value>root</Value></String>
+					<String><Key>Password</Key><Value Protected="True">%s</Value></String>
+				</Entry>
+				<Entry>
+					<String><Key>Title</Key><Value>wifi</Value></String>
+					<String><Key>Notes</Key><Value>office network</Value></String>
+				</Entry>
+			</Group>
+			<Group>
+				<UUID>cmVjeWNsZWJpbg==</UUID>
+				<Name>Recycle Bin</Name>
+				<Entry>
+					<String><Key>Title</Key><Value>removed</Value></String>
+					<String><Key>Password</Key><Value Protected="True">%s</Value></String>
+				</Entry>
+			</Group>
+		</Group>
+	</Root>
+</KeePassFile>`, protect("hunter2"), protect("codes 1234"), protect("old password"), protect("toor"), protect("removed password"))
+}
+
+// writeKDBX writes KDBX 4 database with document of keePassDocument
+func writeKDBX(t *testing.T, options kdbxOptions) []byte {
+	masterSeed := randomBytes(t, 32)
+	salt := randomBytes(t, 32)
+
+	iv := randomBytes(t, 16)
+	if bytes.Equal(options.cipher, kdbxCipherChaCha) {
+		iv = randomBytes(t, 12)
+	}
+
+	composite := sha256.New()
+	if options.password != "" || options.keyFile == nil {
+		passwordHash := sha256.Sum256([]byte(options.password))
+		composite.Write(passwordHash[:])
+	}
+	if options.keyFile != nil {
+		composite.Write(options.keyFile)
+	}
+	compositeKey := composite.Sum(nil)
+
+	kdf := []byte{0x00, 0x01}
+	kdf = appendVariant(kdf, variantByteArray, "$UUID", options.kdf)
+	kdf = appendVariant(kdf, variantByteArray, "S", salt)
+
+	var transformedKey []byte
+	if bytes.Equal(options.kdf, kdbxKDFAES) {
+		kdf = appendVariant(kdf, variantUInt64, "R", binary.LittleEndian.AppendUint64(nil, 10))
+
+		block, err := aes.NewCipher(salt)
+		require.NoError(t, err)
+		key := bytes.Clone(compositeKey)
+		for i := 0; i < 10; i++ {
+			block.Encrypt(key[:16], key[:16])
+			block.Encrypt(key[16:], key[16:])
+		}
+		sum := sha256.Sum256(key)
+		transformedKey = sum[:]
+	} else {
+		kdf = appendVariant(kdf, variantUInt64, "I", binary.LittleEndian.AppendUint64(nil, 2))
+		kdf = appendVariant(kdf, variantUInt64, "M", binary.LittleEndian.AppendUint64(nil, 64*1024))
+		kdf = appendVariant(kdf, variantUInt32, "P", binary.LittleEndian.AppendUint32(nil, 2))
+		kdf = appendVariant(kdf, variantUInt32, "V", binary.LittleEndian.AppendUint32(nil, 0x13))
+
+		if bytes.Equal(options.kdf, kdbxKDFArgon2d) {
+			transformedKey = argon2d.Key(compositeKey, salt, 2, 64, 2, 32)
+		} else {
+			transformedKey = argon2.IDKey(compositeKey, salt, 2, 64, 2, 32)
+		}
+	}
+	kdf = append(kdf, variantEnd)
+
+	compression := uint32(0)
+	if options.compress {
+		compression = 1
+	}
+
+	header := binary.LittleEndian.AppendUint32(nil, kdbxSignature1)
+	header = binary.LittleEndian.AppendUint32(header, kdbxSignature2)
+	header = binary.LittleEndian.AppendUint32(header, 0x00040000)
+	header = appendField(header, kdbxCipherID, options.cipher)
+	header = appendField(header, kdbxCompression, binary.LittleEndian.AppendUint32(nil, compression))
+	header = appendField(header, kdbxMasterSeed, masterSeed)
+	header = appendField(header, kdbxEncryptionIV, iv)
+	header = appendField(header, kdbxKDFParameters, kdf)
+	header = appendField(header, kdbxEndOfHeader, []byte("\r\n\r\n"))
+
+	seeded := append(bytes.Clone(masterSeed), transformedKey...)
+	masterKey := sha256.Sum256(seeded)
+	hmacBaseKey := sha512.Sum512(append(seeded, 1))
+
+	streamKey := randomBytes(t, 64)
+	streamHash := sha512.Sum512(streamKey)
+	stream, err := chacha20.NewUnauthenticatedCipher(streamHash[:32], streamHash[32:44])
+	require.NoError(t, err)
+
+	document := keePassDocument(func(value string) string {
+		protected := []byte(value)
+		stream.XORKeyStream(protected, protected)
+
+		return base64.StdEncoding.EncodeToString(protected)
+	})
+
+	payload := appendField(nil, kdbxInnerStreamID, binary.LittleEndian.AppendUint32(nil, kdbxInnerChaCha20))
+	payload = appendField(payload, kdbxInnerStreamKey, streamKey)
+	payload = appendField(payload, kdbxInnerEnd, nil)
+	payload = append(payload, document...)
+
+	if options.compress {
+		compressed := bytes.Buffer{}
+		gz := gzip.NewWriter(&compressed)
+		_, err = gz.Write(payload)
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
+		payload = compressed.Bytes()
+	}
+
+	if bytes.Equal(options.cipher, kdbxCipherChaCha) {
+		c, err := chacha20.NewUnauthenticatedCipher(masterKey[:], iv)
+		require.NoError(t, err)
+		c.XORKeyStream(payload, payload)
+	} else {
+		block, err := aes.NewCipher(masterKey[:])
+		require.NoError(t, err)
+		padding := aes.BlockSize - len(payload)%aes.BlockSize
+		payload = append(payload, bytes.Repeat([]byte{byte(padding)}, padding)...)
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(payload, payload)
+	}
+
+	hmacKey := func(index uint64) []byte {
+		key := sha512.Sum512(append(binary.LittleEndian.AppendUint64(nil, index), hmacBaseKey[:]...))
+
+		return key[:]
+	}
+
+	database := bytes.Clone(header)
+	headerHash := sha256.Sum256(header)
+	database = append(database, headerHash[:]...)
+	headerMAC := hmac.New(sha256.New, hmacKey(^uint64(0)))
+	headerMAC.Write(header)
+	database = append(database, headerMAC.Sum(nil)...)
+
+	// payload is split to several blocks, the last block is empty
+	blocks := [][]byte{payload[:len(payload)/2], payload[len(payload)/2:], nil}
+	for i, v := range blocks {
+		mac := hmac.New(sha256.New, hmacKey(uint64(i)))
+		mac.Write(binary.LittleEndian.AppendUint64(nil, uint64(i)))
+		mac.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(v))))
+		mac.Write(v)
+
+		database = append(database, mac.Sum(nil)...)
+		database = binary.LittleEndian.AppendUint32(database, uint32(len(v)))
+		database = append(database, v...)
+	}
+
+	return database
+}
+
+const (
+	variantByteArray = 0x42
+)
+
+func appendVariant(dict []byte, kind byte, key string, value []byte) []byte {
+	dict = append(dict, kind)
+	dict = binary.LittleEndian.AppendUint32(dict, uint32(len(key)))
+	dict = append(dict, key...)
+	dict = binary.LittleEndian.AppendUint32(dict, uint32(len(value)))
+
+	return append(dict, value...)
+}
+
+func appendField(header []byte, id byte, data []byte) []byte {
+	header = append(header, id)
+	header = binary.LittleEndian.AppendUint32(header, uint32(len(data)))
+
+	return append(header, data...)
+}
+
+func randomBytes(t *testing.T, size int) []byte {
+	b := make([]byte, size)
+	_, err := rand.Read(b)
+	require.NoError(t, err)
+
+	return b
+}
+
+func TestParseKeePass(t *testing.T) {
+	keyFile := randomBytes(t, 32)
+	tests := []struct {
+		name    string
+		options kdbxOptions
+	}{
+		{name: "Argon2d and ChaCha20", options: kdbxOptions{password: "master", kdf: kdbxKDFArgon2d, cipher: kdbxCipherChaCha, compress: true}},
+		{name: "Argon2id and AES", options: kdbxOptions{password: "master", kdf: kdbxKDFArgon2id, cipher: kdbxCipherAES}},
+		{name: "AES-KDF with key file", options: kdbxOptions{password: "master", keyFile: keyFile, kdf: kdbxKDFAES, cipher: kdbxCipherAES, compress: true}},
+	}
+
+	expected := []Entry{
+		{
+			Type: secret.SecretTypeCredentials,
+			Name: "github",
+			Tags: []string{"dev", "vcs"},
+			Credentials: Credentials{
+				Login:    "octocat",
+				Password: "hunter2",
+				URL:      "https://github.com",
+				Notes:    "Recovery: codes 1234",
+			},
+		},
+		{
+			Type:        secret.SecretTypeCredentials,
+			Name:        "Work-Infra/db",
+			Credentials: Credentials{Login: "root", Password: "toor"},
+		},
+		{
+			Type: secret.SecretTypeText,
+			Name: "Work-Infra/wifi",
+			Text: "office network",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database := writeKDBX(t, tt.options)
+
+			entries, err := ParseKeePass(bytes.NewReader(database), tt.options.password, tt.options.keyFile)
+			require.NoError(t, err)
+			assert.Equal(t, expected, entries)
+
+			_, err = ParseKeePass(bytes.NewReader(database), "wrong", tt.options.keyFile)
+			assert.ErrorIs(t, err, ErrWrongKey)
+		})
+	}
+}
+
+func TestParseKeePass_Damaged(t *testing.T) {
+	database := writeKDBX(t, kdbxOptions{password: "master", kdf: kdbxKDFAES, cipher: kdbxCipherAES})
+
+	modified := bytes.Clone(database)
+	modified[len(modified)-50] ^= 1
+	_, err := ParseKeePass(bytes.NewReader(modified), "master", nil)
+	assert.ErrorContains(t, err, "damaged")
+
+	_, err = ParseKeePass(bytes.NewReader(database[:len(database)-10]), "master", nil)
+	assert.ErrorContains(t, err, "truncated")
+
+	_, err = ParseKeePass(bytes.NewReader([]byte("name,url,username,password")), "master", nil)
+	assert.Error(t, err)
+}
@@ -15,24 +15,36 @@ import (
 	"github.com/nessai1/gophkeeper/pkg/command"
 )
 
-func Listen() error {
-	printGreetMessage(applicationInfo{Version: "0.0.1", BuildDate: time.Now()})
-
+// Listen executes command given by arguments of application or starts interactive mode if command isn't given,
+// returns exit code of application
+func Listen() int {
 	cfg, err := fetchConfig()
 	if err != nil {
-		return fmt.Errorf("cannot start listen application: %w", err)
+		fmt.Fprintf(os.Stderr, "\033[31mKeeper was crashed: cannot fetch config: %s\033[0m\n", err.Error())
+
+		return ExitFailure
 	}
 
+	if len(cfg.Command) > 0 {
+		return executeCommand(cfg)
+	}
+
+	printGreetMessage(applicationInfo{Version: "0.0.1", BuildDate: time.Now()})
+
 	app, err := NewApplication(cfg)
 	if err != nil {
-		return fmt.Errorf("cannot start listen application: %w", err)
+		fmt.Printf("\033[31mKeeper was crashed: cannot start listen application: %s\033[0m", err.Error())
+
+		return ExitFailure
 	}
 
 	if err = app.Run(); err != nil {
-		return fmt.Errorf("error while run application: %w", err)
+		fmt.Printf("\033[31mKeeper was crashed: error while run application: %s\033[0m", err.Error())
+
+		return ExitFailure
 	}
 
-	return nil
+	return ExitOK
 }
 
 type Application struct {
@@ -72,6 +84,8 @@ func NewApplication(config Config) (*Application, error) {
 		return nil, fmt.Errorf("cannot connect to the external service: %w", err)
 	}
 
+	gRPCConnector.SetDirectMedia(config.DirectMedia)
+
 	return &Application{
 		config:    config,
 		logger:    loggerInstance,
@@ -81,21 +95,21 @@ func NewApplication(config Config) (*Application, error) {
 
 func createKeeperDataDir(dir string) error {
 	err := os.Mkdir(dir, 0777)
-	if err != nil && !errors.As(err, &os.ErrExist) {
+	if err != nil && !errors.Is(err, os.ErrExist) {
 		return fmt.Errorf("cannot credate data dir: %w", err)
 	}
 
 	logsPath := filepath.Join(dir, "logs")
 
 	err = os.Mkdir(logsPath, 0777)
-	if err != nil && !errors.As(err, &os.ErrExist) {
+	if err != nil && !errors.Is(err, os.ErrExist) {
 		return fmt.Errorf("cannot create logs dir: %w", err)
 	}
 
 	mediaPath := filepath.Join(dir, "media")
 
 	err = os.Mkdir(mediaPath, 0777)
-	if err != nil && !errors.As(err, &os.ErrExist) {
+	if err != nil && !errors.Is(err, os.ErrExist) {
 		return fmt.Errorf("cannot create media dir: %w", err)
 	}
 
@@ -175,8 +189,10 @@ func (a *Application) SetSession(s *session.Session) {
 
 	if s != nil {
 		a.connector.SetAuthToken(s.AuthToken)
+		a.connector.SetSecretKey(s.SecretKey)
 	} else {
 		a.connector.SetAuthToken("")
+		a.connector.SetSecretKey([32]byte{})
 	}
 }
 
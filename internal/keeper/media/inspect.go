@@ -0,0 +1,67 @@
+package media
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/keeper/secret"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// sniffSize count of first bytes of content used to detect its type
+const sniffSize = 512
+
+// defaultContentType type detected for content that has no known signature
+const defaultContentType = "application/octet-stream"
+
+// Inspect captures info of media file: size, content type and checksum of content and absolute path of file.
+// Content type is sniffed by content, extension of file is used if content has no known signature.
+// File is read from start and seeked back to start after inspect
+func Inspect(file *os.File) (secret.MediaInfo, error) {
+	_, err := file.Seek(0, io.SeekStart)
+	if err != nil {
+		return secret.MediaInfo{}, fmt.Errorf("cannot seek media file to the start: %w", err)
+	}
+
+	head := make([]byte, sniffSize)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return secret.MediaInfo{}, fmt.Errorf("cannot read head of media file: %w", err)
+	}
+	head = head[:n]
+
+	hash := sha256.New()
+	hash.Write(head)
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return secret.MediaInfo{}, fmt.Errorf("cannot read media file: %w", err)
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return secret.MediaInfo{}, fmt.Errorf("cannot seek media file to the start: %w", err)
+	}
+
+	contentType := http.DetectContentType(head)
+	if contentType == defaultContentType {
+		if byExtension := mime.TypeByExtension(filepath.Ext(file.Name())); byExtension != "" {
+			contentType = byExtension
+		}
+	}
+
+	originalPath, err := filepath.Abs(file.Name())
+	if err != nil {
+		originalPath = file.Name()
+	}
+
+	return secret.MediaInfo{
+		Size:         size + int64(n),
+		ContentType:  contentType,
+		Checksum:     hex.EncodeToString(hash.Sum(nil)),
+		OriginalPath: originalPath,
+	}, nil
+}
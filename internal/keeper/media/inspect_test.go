@@ -0,0 +1,53 @@
+package media
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInspect(t *testing.T) {
+	png := append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), make([]byte, 1024)...)
+
+	tests := []struct {
+		name        string
+		filename    string
+		content     []byte
+		contentType string
+	}{
+		{name: "Sniffed by content", filename: "image.bin", content: png, contentType: "image/png"},
+		{name: "Plain text", filename: "notes", content: []byte("some notes"), contentType: "text/plain; charset=utf-8"},
+		{name: "By extension", filename: "archive.zip", content: []byte{0x00, 0x01, 0x02}, contentType: "application/zip"},
+		{name: "Unknown", filename: "data", content: []byte{0x00, 0x01, 0x02}, contentType: "application/octet-stream"},
+		{name: "Empty", filename: "empty", content: nil, contentType: "text/plain; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.filename)
+			require.NoError(t, os.WriteFile(path, tt.content, 0600))
+
+			file, err := os.Open(path)
+			require.NoError(t, err)
+			defer file.Close()
+
+			info, err := Inspect(file)
+			require.NoError(t, err)
+
+			checksum := sha256.Sum256(tt.content)
+			assert.Equal(t, int64(len(tt.content)), info.Size)
+			assert.Equal(t, tt.contentType, info.ContentType)
+			assert.Equal(t, hex.EncodeToString(checksum[:]), info.Checksum)
+			assert.Equal(t, path, info.OriginalPath)
+
+			content, err := io.ReadAll(file)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.content), len(content), "file must be seeked to the start")
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package keeper
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/keeper/performer"
+	"github.com/nessai1/gophkeeper/internal/keeper/session"
+	"github.com/nessai1/gophkeeper/pkg/command"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"os"
+	"strings"
+)
+
+// Exit codes of application
+const (
+	ExitOK = 0
+	// ExitFailure command or application failed
+	ExitFailure = 1
+	// ExitUsage command isn't found
+	ExitUsage = 2
+	// ExitUnauthorized saved session isn't unlocked or service rejects authorization
+	ExitUnauthorized = 3
+	// ExitNotFound secret or account of command isn't found by service
+	ExitNotFound = 4
+)
+
+// PasswordEnv environment variable with password of saved session for one-shot command
+const PasswordEnv = "KEEPER_PASSWORD"
+
+// executeCommand runs one-shot command of config without greeting and REPL. Results of command are written to stdout,
+// prompts and errors to stderr, so inputs of prompts can be piped to stdin line by line
+func executeCommand(cfg Config) int {
+	command.SetPromptOutput(os.Stderr)
+
+	p, ok := performer.AvailablePerformers[cfg.Command[0]]
+	if !ok {
+		printCommandError(fmt.Errorf("command '%s' not found", cfg.Command[0]))
+
+		return ExitUsage
+	}
+
+	app, err := NewApplication(cfg)
+	if err != nil {
+		printCommandError(fmt.Errorf("cannot start application: %w", err))
+
+		return ExitFailure
+	}
+
+	return app.Execute(p, cfg.Command)
+}
+
+// Execute performs one command with saved session, session is unlocked by password from environment or file descriptor.
+// Session is saved after command, so login and logout of one-shot commands are kept
+func (a *Application) Execute(p performer.Performer, args []string) int {
+	unlocked, err := a.unlockSession()
+	if err != nil {
+		a.logger.Error("Cannot unlock saved session", zap.Error(err))
+		printCommandError(err)
+
+		return ExitUnauthorized
+	}
+
+	a.logger.Info("One-shot command was started", zap.String("command", p.GetName()), zap.Bool("with_session", unlocked))
+
+	_, err = p.Execute(a.connector, a, a.logger, args, a.config.WorkDir)
+
+	if a.GetSession() != nil {
+		if saveErr := session.SaveLocalSession(a.config.WorkDir, *a.GetSession()); saveErr != nil {
+			a.logger.Error("Error while save local session", zap.Error(saveErr))
+		}
+	} else if unlocked {
+		if removeErr := session.RemoveLocalSession(a.config.WorkDir); removeErr != nil {
+			a.logger.Error("Error while remove local session", zap.Error(removeErr))
+		}
+	}
+
+	if err != nil {
+		printCommandError(err)
+
+		return exitCode(err)
+	}
+
+	return ExitOK
+}
+
+// unlockSession sets saved session if password is given, command runs without session if password or saved session is missing
+func (a *Application) unlockSession() (bool, error) {
+	password, err := readSessionPassword(a.config.PasswordFD)
+	if err != nil || password == "" {
+		return false, err
+	}
+
+	s, err := session.UnlockLocalSession(a.config.WorkDir, password)
+	if errors.Is(err, session.ErrNoLocalSession) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("cannot unlock saved session: %w", err)
+	}
+
+	a.SetSession(s)
+
+	return true, nil
+}
+
+// readSessionPassword reads password from file descriptor if it's set, otherwise from environment.
+// Password of stdin is read as first line, so next lines are still available for prompts
+func readSessionPassword(fd int) (string, error) {
+	if fd < 0 {
+		return os.Getenv(PasswordEnv), nil
+	}
+
+	if fd == 0 {
+		password, err := command.AskSecret("Enter password")
+		if err != nil {
+			return "", fmt.Errorf("cannot read password from stdin: %w", err)
+		}
+
+		return password, nil
+	}
+
+	file := os.NewFile(uintptr(fd), "password")
+	if file == nil {
+		return "", fmt.Errorf("invalid password file descriptor %d", fd)
+	}
+	defer file.Close()
+
+	password, err := bufio.NewReader(file).ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && password != "") {
+		return "", fmt.Errorf("cannot read password from file descriptor %d: %w", fd, err)
+	}
+
+	return strings.TrimRight(password, "\r\n"), nil
+}
+
+// exitCode maps error of command to exit code
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, performer.ErrNotAuthorized), status.Code(err) == codes.Unauthenticated:
+		return ExitUnauthorized
+	case status.Code(err) == codes.NotFound:
+		return ExitNotFound
+	default:
+		return ExitFailure
+	}
+}
+
+func printCommandError(err error) {
+	fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+}
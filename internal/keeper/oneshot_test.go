@@ -0,0 +1,38 @@
+package keeper
+
+import (
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/keeper/performer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"os"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, ExitUnauthorized, exitCode(fmt.Errorf("for working with secrets %w", performer.ErrNotAuthorized)))
+	assert.Equal(t, ExitUnauthorized, exitCode(fmt.Errorf("cannot get secret: %w", status.Error(codes.Unauthenticated, "expired token"))))
+	assert.Equal(t, ExitNotFound, exitCode(fmt.Errorf("cannot get secret: %w", status.Error(codes.NotFound, "secret not found"))))
+	assert.Equal(t, ExitFailure, exitCode(fmt.Errorf("cannot decrypt secret")))
+}
+
+func TestReadSessionPassword(t *testing.T) {
+	t.Setenv(PasswordEnv, "from env")
+
+	password, err := readSessionPassword(-1)
+	require.NoError(t, err)
+	assert.Equal(t, "from env", password)
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+
+	_, err = w.WriteString("from fd\r\nignored line\n")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	password, err = readSessionPassword(int(r.Fd()))
+	require.NoError(t, err)
+	assert.Equal(t, "from fd", password)
+}
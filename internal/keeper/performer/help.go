@@ -22,7 +22,13 @@ func (p Help) GetDescription() string {
 }
 
 func (p Help) GetDetailDescription() string {
-	return "Get common help information and list of available commands\nIf has argument [command] - returns detail description about concrete command"
+	return `Get common help information and list of available commands
+If has argument [command] - returns detail description about concrete command
+
+Any command can be run without interactive mode, like 'keeper secret credentials get github --field password':
+	-- Saved session is unlocked by password from KEEPER_PASSWORD or from file descriptor set by flag -password-fd
+	-- Prompts of command are written to stderr and read from stdin line by line
+	-- Exit codes: 0 - success, 1 - command failed, 2 - command not found, 3 - not authorized, 4 - not found by service`
 }
 
 func (p Help) Execute(_ connector.ServiceConnector, _ Sessional, _ *zap.Logger, args []string, _ string) (requireExit bool, err error) {
@@ -0,0 +1,324 @@
+package performer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/chrusty/go-tableprinter"
+	"github.com/nessai1/gophkeeper/internal/keeper/connector"
+	"github.com/nessai1/gophkeeper/internal/keeper/encrypt"
+	"github.com/nessai1/gophkeeper/internal/keeper/importer"
+	"github.com/nessai1/gophkeeper/internal/keeper/secret"
+	"github.com/nessai1/gophkeeper/internal/keeper/session"
+	"github.com/nessai1/gophkeeper/pkg/command"
+	"go.uber.org/zap"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type Import struct {
+}
+
+func (p Import) GetName() string {
+	return "import"
+}
+
+func (p Import) GetStruct() string {
+	return "import [keepass|bitwarden|1password|chrome|firefox] [file] [?--key-file path] [?--folder name] [?--conflict skip|rename] [?--yes]"
+}
+
+func (p Import) GetDescription() string {
+	return "Import secrets from export of other password manager"
+}
+
+func (p Import) GetDetailDescription() string {
+	return `Import secrets from export of other password manager
+
+Supported formats:
+
+- keepass - KeePass database in KDBX 4 format, master password is asked before import
+	-- Flag --key-file sets key file of database
+- bitwarden - unencrypted JSON export of Bitwarden
+- 1password - CSV export of 1Password
+- chrome, firefox - CSV export of passwords saved by browser
+
+Logins are imported as credentials, cards as cards, notes and identities as texts.
+Folders of password manager are kept as folders, tags are imported too.
+
+Before upload the plan of import is printed and asked for confirmation:
+	-- Entries that repeat previous entries of export are imported once
+	-- Cards with CVV that isn't kept exactly, like CVV with leading zero, are marked in the plan
+	-- Flag --conflict sets strategy for names of existing secrets:
+		skip (default) - keep existing secret and skip imported
+		rename - import secret with number added to name, like 'github-2'
+	-- Flag --folder places all imported secrets into folder, like '--folder keepass'
+	-- Flag --yes uploads secrets without confirmation
+`
+}
+
+func (p Import) Execute(conn connector.ServiceConnector, sessional Sessional, logger *zap.Logger, args []string, _ string) (requireExit bool, err error) {
+	if sessional.GetSession() == nil {
+		return false, fmt.Errorf("for import %w", ErrNotAuthorized)
+	}
+
+	if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+		return false, fmt.Errorf("mismatch arguments count for import: requires format and file")
+	}
+
+	if !importer.IsFormat(args[1]) {
+		return false, fmt.Errorf("invalid format of import: %s", args[1])
+	}
+
+	options, err := parseImportFlags(args[3:])
+	if err != nil {
+		return false, fmt.Errorf("got invalid import flags: %w", err)
+	}
+
+	entries, err := readImport(args[1], args[2], options)
+	if err != nil {
+		logger.Error("Cannot read export for import", zap.Error(err))
+
+		return false, fmt.Errorf("cannot read export: %w", err)
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("No secrets found in export\n")
+
+		return false, nil
+	}
+
+	return false, importEntries(context.TODO(), conn, *sessional.GetSession(), logger, entries, options)
+}
+
+type importOptions struct {
+	keyFile  string
+	folder   string
+	conflict string
+	yes      bool
+}
+
+// parseImportFlags parses flags of import like '--conflict rename'
+func parseImportFlags(args []string) (importOptions, error) {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	options := importOptions{}
+	fs.StringVar(&options.keyFile, "key-file", "", "key file of KeePass database")
+	fs.StringVar(&options.folder, "folder", "", "folder of imported secrets")
+	fs.StringVar(&options.conflict, "conflict", VaultConflictSkip, "strategy for names of existing secrets")
+	fs.BoolVar(&options.yes, "yes", false, "upload without confirmation")
+
+	if err := fs.Parse(args); err != nil {
+		return importOptions{}, fmt.Errorf("cannot parse flags: %w", err)
+	}
+
+	if fs.NArg() != 0 {
+		return importOptions{}, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	if options.conflict != VaultConflictSkip && options.conflict != VaultConflictRename {
+		return importOptions{}, fmt.Errorf("invalid conflict strategy: %s", options.conflict)
+	}
+
+	options.folder = secret.CleanFolder(options.folder)
+
+	return options, nil
+}
+
+// readImport parses export file, entries are placed into folder of options
+func readImport(format string, path string, options importOptions) ([]importer.Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open export: %w", err)
+	}
+	defer file.Close()
+
+	parseOptions := importer.Options{}
+	if format == importer.FormatKeePass {
+		parseOptions.Password, err = command.AskSecret("Enter KeePass master password")
+		if err != nil {
+			return nil, fmt.Errorf("cannot read master password: %w", err)
+		}
+
+		if options.keyFile != "" {
+			parseOptions.KeyFile, err = os.ReadFile(options.keyFile)
+			if err != nil {
+				return nil, fmt.Errorf("cannot read key file: %w", err)
+			}
+		}
+	}
+
+	entries, err := importer.Parse(format, file, parseOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.folder != "" {
+		for i := range entries {
+			entries[i].Name = options.folder + secret.FolderSeparator + entries[i].Name
+		}
+	}
+
+	return entries, nil
+}
+
+type printableImportItem struct {
+	Type   string
+	Name   string
+	Action string
+}
+
+// importEntries prints plan of import, asks confirmation and uploads planned secrets
+func importEntries(ctx context.Context, conn connector.ServiceConnector, s session.Session, logger *zap.Logger, entries []importer.Entry, options importOptions) error {
+	existing, err := conn.ListAllSecrets(ctx, secret.Filter{})
+	if err != nil {
+		logger.Error("Cannot list secrets for import", zap.Error(err))
+
+		return fmt.Errorf("cannot list secrets: %w", err)
+	}
+
+	taken := make(map[secret.SecretType]map[string]bool)
+	for _, v := range existing {
+		markTaken(taken, v.SecretType, v.Name)
+	}
+
+	items := importer.Plan(entries, func(secretType secret.SecretType, name string) bool {
+		return taken[secretType][name]
+	}, options.conflict == VaultConflictRename)
+
+	uploaded := make([]importer.Item, 0, len(items))
+	printable := make([]printableImportItem, len(items))
+	skipped, lossy := 0, 0
+	for i, v := range items {
+		printable[i] = printableImportItem{Type: formatSecretType(v.Entry.Type), Name: v.Name, Action: v.Status.String()}
+		if v.Status == importer.StatusRenamed {
+			printable[i].Action = "renamed from " + v.Entry.Name
+		}
+
+		if v.Status != importer.StatusNew && v.Status != importer.StatusRenamed {
+			skipped++
+
+			continue
+		}
+
+		uploaded = append(uploaded, v)
+		if _, ok := importCVV(v.Entry); !ok {
+			printable[i].Action += ", CVV isn't kept exactly"
+			lossy++
+		}
+	}
+
+	tableprinter.SetBorder(true)
+	tableprinter.Print(printable)
+	fmt.Printf("Secrets to upload: %d, skipped duplicates and existing secrets: %d\n", len(uploaded), skipped)
+	if lossy > 0 {
+		fmt.Printf("\033[33mCVV of %d cards isn't kept exactly, because CVV of card secret is stored as number\033[0m\n", lossy)
+	}
+
+	if len(uploaded) == 0 {
+		return nil
+	}
+
+	if !options.yes {
+		answer, err := command.AskText("Upload secrets? (y/n)")
+		if err != nil {
+			return fmt.Errorf("cannot read confirmation: %w", err)
+		}
+
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			fmt.Printf("Import canceled\n")
+
+			return nil
+		}
+	}
+
+	return uploadImport(ctx, conn, s, logger, uploaded)
+}
+
+// uploadImport encrypts and uploads items by batches, tags are set after secrets are created
+func uploadImport(ctx context.Context, conn connector.ServiceConnector, s session.Session, logger *zap.Logger, items []importer.Item) error {
+	secrets := make([]connector.NewSecret, len(items))
+	for i, v := range items {
+		data, err := encodeImportEntry(v.Entry)
+		if err != nil {
+			return fmt.Errorf("cannot encode %s %s: %w", formatSecretType(v.Entry.Type), v.Name, err)
+		}
+
+		encrypted, err := encrypt.EncryptAES256(data, s.SecretKey)
+		if err != nil {
+			return fmt.Errorf("cannot encrypt %s %s: %w", formatSecretType(v.Entry.Type), v.Name, err)
+		}
+
+		secrets[i] = connector.NewSecret{Name: v.Name, SecretType: v.Entry.Type, Data: encrypted}
+	}
+
+	created, err := conn.SetSecrets(ctx, secrets)
+	if err != nil {
+		logger.Error("Cannot upload imported secrets", zap.Error(err), zap.Int("created", created))
+
+		return fmt.Errorf("cannot upload secrets (uploaded %d): %w", created, err)
+	}
+
+	var tagErrs []error
+	for _, v := range items {
+		if len(v.Entry.Tags) == 0 {
+			continue
+		}
+
+		if err = conn.SetSecretTags(ctx, v.Name, v.Entry.Type, v.Entry.Tags); err != nil {
+			tagErrs = append(tagErrs, fmt.Errorf("%s %s: %w", formatSecretType(v.Entry.Type), v.Name, err))
+		}
+	}
+
+	if len(tagErrs) > 0 {
+		logger.Error("Cannot set tags of imported secrets", zap.Error(errors.Join(tagErrs...)))
+		fmt.Printf("\033[33mTags of %d secrets aren't imported\033[0m\n", len(tagErrs))
+	}
+
+	fmt.Printf("\033[32mImported %d secrets!\033[0m\n", created)
+
+	return nil
+}
+
+// encodeImportEntry encodes entry to plain data of its secret type
+func encodeImportEntry(entry importer.Entry) ([]byte, error) {
+	switch entry.Type {
+	case secret.SecretTypeCard:
+		cvv, _ := importCVV(entry)
+
+		return json.Marshal(secretCard{
+			Number:     entry.Card.Number,
+			CardHolder: entry.Card.Holder,
+			CVV:        cvv,
+			Expires:    entry.Card.Expires,
+		})
+	case secret.SecretTypeText:
+		return json.Marshal(secretText{Text: entry.Text})
+	default:
+		return json.Marshal(secretCredentials{
+			Login:    entry.Credentials.Login,
+			Password: entry.Credentials.Password,
+			URL:      entry.Credentials.URL,
+			Notes:    entry.Credentials.Notes,
+		})
+	}
+}
+
+// importCVV returns CVV of card entry as number stored by card secret. Returns false if number doesn't keep CVV exactly:
+// leading zeros are lost and not numeric CVV is dropped
+func importCVV(entry importer.Entry) (int, bool) {
+	if entry.Type != secret.SecretTypeCard || entry.Card.CVV == "" {
+		return 0, true
+	}
+
+	cvv, err := strconv.Atoi(entry.Card.CVV)
+	if err != nil || cvv < 0 {
+		return 0, false
+	}
+
+	return cvv, strconv.Itoa(cvv) == entry.Card.CVV
+}
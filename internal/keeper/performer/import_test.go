@@ -0,0 +1,30 @@
+package performer
+
+import (
+	"github.com/nessai1/gophkeeper/internal/keeper/importer"
+	"github.com/nessai1/gophkeeper/internal/keeper/secret"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestImportCVV(t *testing.T) {
+	tests := []struct {
+		cvv      string
+		expected int
+		exact    bool
+	}{
+		{cvv: "123", expected: 123, exact: true},
+		{cvv: "", expected: 0, exact: true},
+		{cvv: "012", expected: 12, exact: false},
+		{cvv: "12a", expected: 0, exact: false},
+		{cvv: "-12", expected: 0, exact: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cvv, func(t *testing.T) {
+			cvv, exact := importCVV(importer.Entry{Type: secret.SecretTypeCard, Card: importer.Card{CVV: tt.cvv}})
+			assert.Equal(t, tt.expected, cvv)
+			assert.Equal(t, tt.exact, exact)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package performer
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/chrusty/go-tableprinter"
+	"github.com/nessai1/gophkeeper/internal/keeper/connector"
+	"github.com/nessai1/gophkeeper/internal/keeper/secret"
+	"go.uber.org/zap"
+	"io"
+	"strings"
+)
+
+type Ls struct {
+}
+
+func (p Ls) GetName() string {
+	return "ls"
+}
+
+func (p Ls) GetStruct() string {
+	return "ls [?folder] [?--tag tag] [?--sort created|updated|name] [?--desc] [?--limit count] [?--prefix prefix]"
+}
+
+func (p Ls) GetDescription() string {
+	return "List secrets of all types placed in folder"
+}
+
+func (p Ls) GetDetailDescription() string {
+	return `List secrets of all types placed directly in folder, without folder lists root folder
+
+Flag --tag shows only secrets marked by tag
+Flag --sort sets order of secrets: created (default), updated or name, flag --desc shows latest secrets first or names in reverse order
+Flag --limit shows only first secrets of list, like '--limit 20'
+Flag --prefix shows only secrets which names start with prefix, like '--prefix work/db'
+`
+}
+
+type printableTypedSecret struct {
+	Type        string
+	Name        string
+	Update_time string // snake case used for table formatter
+	Tags        string
+}
+
+func (p Ls) Execute(conn connector.ServiceConnector, sessional Sessional, logger *zap.Logger, args []string, _ string) (requireExit bool, err error) {
+	if sessional.GetSession() == nil {
+		return false, fmt.Errorf("for list secrets %w", ErrNotAuthorized)
+	}
+
+	filter, err := parseLsArgs(args[1:])
+	if err != nil {
+		return false, fmt.Errorf("got invalid ls arguments: %w", err)
+	}
+
+	secrets, err := conn.ListAllSecrets(context.TODO(), filter)
+	if err != nil {
+		logger.Error("Cannot list secrets of folder", zap.Error(err))
+
+		return false, fmt.Errorf("cannot list secrets of folder: %w", err)
+	}
+
+	if len(secrets) == 0 {
+		fmt.Printf("No secrets found\n")
+
+		return false, nil
+	}
+
+	printTypedSecrets(secrets)
+
+	return false, nil
+}
+
+// parseLsArgs parses folder and flags of ls like 'work --tag=prod', folder may be given before or after flags
+func parseLsArgs(args []string) (secret.Filter, error) {
+	fs := flag.NewFlagSet("ls", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	tag := fs.String("tag", "", "tag of secrets")
+
+	var order listOrder
+	order.register(fs)
+
+	folder := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		folder = args[0]
+		args = args[1:]
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return secret.Filter{}, fmt.Errorf("cannot parse flags: %w", err)
+	}
+
+	rest := fs.Args()
+	if folder == "" && len(rest) > 0 {
+		folder, rest = rest[0], rest[1:]
+	}
+
+	if len(rest) != 0 {
+		return secret.Filter{}, fmt.Errorf("unexpected arguments: %s", strings.Join(rest, " "))
+	}
+
+	cleanFolder := secret.CleanFolder(folder)
+	filter := secret.Filter{Folder: &cleanFolder, Tag: *tag}
+	if err := order.apply(&filter); err != nil {
+		return secret.Filter{}, err
+	}
+
+	return filter, nil
+}
+
+// printTypedSecrets prints secrets of different types with type column
+func printTypedSecrets(secrets []secret.Secret) {
+	printable := make([]printableTypedSecret, len(secrets))
+	for i, v := range secrets {
+		printable[i] = printableTypedSecret{
+			Type:        formatSecretType(v.SecretType),
+			Name:        v.Name,
+			Update_time: v.Updated.String(),
+			Tags:        formatTags(v),
+		}
+	}
+
+	tableprinter.SetBorder(true)
+	tableprinter.Print(printable)
+}
@@ -0,0 +1,51 @@
+package performer
+
+import (
+	"context"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/keeper/connector"
+	"github.com/nessai1/gophkeeper/internal/keeper/secret"
+	"go.uber.org/zap"
+)
+
+type Migrate struct {
+}
+
+func (p Migrate) GetName() string {
+	return "migrate"
+}
+
+func (p Migrate) GetStruct() string {
+	return "migrate"
+}
+
+func (p Migrate) GetDescription() string {
+	return "encrypt names of secrets saved by previous keeper versions"
+}
+
+func (p Migrate) GetDetailDescription() string {
+	return "Encrypt names of secrets that were saved by previous keeper versions.\nOld secrets store names in plain on service, after migration service knows only encrypted names and their blind indexes"
+}
+
+func (p Migrate) Execute(conn connector.ServiceConnector, sessional Sessional, logger *zap.Logger, _ []string, _ string) (requireExit bool, err error) {
+	if sessional.GetSession() == nil {
+		return false, fmt.Errorf("for migrate secrets %w", ErrNotAuthorized)
+	}
+
+	secretTypes := []secret.SecretType{secret.SecretTypeCredentials, secret.SecretTypeCard, secret.SecretTypeText, secret.SecretTypeMedia}
+
+	total := 0
+	for _, secretType := range secretTypes {
+		migrated, err := conn.MigrateSecretNames(context.TODO(), secretType)
+		total += migrated
+		if err != nil {
+			logger.Error("Cannot migrate secret names", zap.Error(err), zap.Int("secret_type", int(secretType)))
+
+			return false, fmt.Errorf("error while migrate secret names (migrated %d): %w", total, err)
+		}
+	}
+
+	fmt.Printf("\033[32mSuccessful migrated %d secrets!\033[0m\n", total)
+
+	return false, nil
+}
@@ -1,11 +1,15 @@
 package performer
 
 import (
+	"errors"
 	"github.com/nessai1/gophkeeper/internal/keeper/connector"
 	"github.com/nessai1/gophkeeper/internal/keeper/session"
 	"go.uber.org/zap"
 )
 
+// ErrNotAuthorized returns by performers that require session of user
+var ErrNotAuthorized = errors.New("you need to be authorized")
+
 type Sessional interface {
 	SetSession(session *session.Session)
 	GetSession() *session.Session
@@ -35,4 +39,10 @@ var AvailablePerformers = map[string]Performer{
 	Login.GetName(Login{}):       Login{},
 	Logout.GetName(Logout{}):     Logout{},
 	Secret.GetName(Secret{}):     Secret{},
+	Migrate.GetName(Migrate{}):   Migrate{},
+	Ls.GetName(Ls{}):             Ls{},
+	Search.GetName(Search{}):     Search{},
+	Usage.GetName(Usage{}):       Usage{},
+	Vault.GetName(Vault{}):       Vault{},
+	Import.GetName(Import{}):     Import{},
 }
@@ -0,0 +1,100 @@
+package performer
+
+import (
+	"context"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/keeper/connector"
+	"github.com/nessai1/gophkeeper/internal/keeper/secret"
+	"github.com/nessai1/gophkeeper/pkg/fuzzy"
+	"go.uber.org/zap"
+	"sort"
+	"strings"
+)
+
+type Search struct {
+}
+
+func (p Search) GetName() string {
+	return "search"
+}
+
+func (p Search) GetStruct() string {
+	return "search [query]"
+}
+
+func (p Search) GetDescription() string {
+	return "Search secrets of all types by name"
+}
+
+func (p Search) GetDetailDescription() string {
+	return `Search secrets of all types by name or tags.
+
+Names of secrets are encrypted on service, so keeper loads list of all secrets and fuzzy matches them locally:
+letters of query must be found in name in the same order, like 'ghb' matches 'work/github'.
+Best matches are shown first
+`
+}
+
+type rankedSecret struct {
+	secret secret.Secret
+	score  int
+}
+
+func (p Search) Execute(conn connector.ServiceConnector, sessional Sessional, logger *zap.Logger, args []string, _ string) (requireExit bool, err error) {
+	if sessional.GetSession() == nil {
+		return false, fmt.Errorf("for search secrets %w", ErrNotAuthorized)
+	}
+
+	if len(args) < 2 || strings.TrimSpace(strings.Join(args[1:], " ")) == "" {
+		return false, fmt.Errorf("search query can't be empty")
+	}
+
+	query := strings.TrimSpace(strings.Join(args[1:], " "))
+
+	secrets, err := conn.ListAllSecrets(context.TODO(), secret.Filter{})
+	if err != nil {
+		logger.Error("Cannot list secrets for search", zap.Error(err))
+
+		return false, fmt.Errorf("cannot list secrets for search: %w", err)
+	}
+
+	found := rankSecrets(query, secrets)
+	if len(found) == 0 {
+		fmt.Printf("No secrets found by query '%s'\n", query)
+
+		return false, nil
+	}
+
+	printTypedSecrets(found)
+
+	return false, nil
+}
+
+// rankSecrets returns secrets matched by query, ordered by match score. Tags matches rank lower than name matches
+func rankSecrets(query string, secrets []secret.Secret) []secret.Secret {
+	ranked := make([]rankedSecret, 0)
+	for _, v := range secrets {
+		score, matched := fuzzy.Score(query, v.Name)
+		for _, tag := range v.Tags {
+			tagScore, tagMatched := fuzzy.Score(query, tag)
+			if tagMatched && (!matched || tagScore/2 > score) {
+				score, matched = tagScore/2, true
+			}
+		}
+
+		if matched {
+			ranked = append(ranked, rankedSecret{secret: v, score: score})
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].score > ranked[j].score
+	})
+
+	found := make([]secret.Secret, len(ranked))
+	for i, v := range ranked {
+		found[i] = v.secret
+	}
+
+	return found
+}
@@ -2,12 +2,19 @@ package performer
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"github.com/chrusty/go-tableprinter"
 	"github.com/nessai1/gophkeeper/internal/keeper/connector"
 	"github.com/nessai1/gophkeeper/internal/keeper/secret"
 	"go.uber.org/zap"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
 const (
@@ -23,8 +30,18 @@ const (
 	SecretActionUpdate = "update"
 	SecretActionDelete = "delete"
 	SecretActionList   = "list"
+	SecretActionTag    = "tag"
+	SecretActionMove   = "mv"
+	SecretActionRename = "rename"
 )
 
+// secretFields fields of secrets that can be printed by get with --field
+var secretFields = map[string][]string{
+	SecretTypeCredentials: {"login", "password", "url", "notes"},
+	SecretTypeCard:        {"number", "card_holder", "cvv", "expires"},
+	SecretTypeText:        {"text"},
+}
+
 type Secret struct {
 }
 
@@ -33,7 +50,7 @@ func (p Secret) GetName() string {
 }
 
 func (p Secret) GetStruct() string {
-	return "secret [type] [action] [?name]"
+	return "secret [type] [action] [?name] [?--expires duration] [?--max-reads count] [?--description text] [?tags...] [?folder] [?new name] [?--type type]"
 }
 
 func (p Secret) GetDescription() string {
@@ -57,22 +74,45 @@ Available actions:
 	-- For card it ask card-colder&cvv&number&date and save it by name
 	-- For text it ask text and save it by name
 	-- For media it find file with path == name and upload it on server
+	-- Flag --expires (like 24h or 30m) sets lifetime of secret, after that secret will be removed
+	-- Flag --max-reads sets count of reads (get), after that secret will be removed
+	-- Flag --description sets description of media, it's encrypted and shown by list
 
 - get - get data of secrets
 	-- For credentials/card/text it show information about secret by name
+	-- Flag --field prints only value of field, like '--field password' (credentials: login, password, url, notes;
+	   card: number, card_holder, cvv, expires; text: text)
 	-- For media it load file from server and save in media dir
 
 - remove - remove secret by name
 
 - update - update secret by name: remove old secret and start 'set' procedure again
 
-- list - get list of secrets
+- list - get list of secrets, 'secret list' without type lists secrets of all types
+	-- Flag --folder shows only secrets placed directly in folder, use '/' for root folder
+	-- Flag --tag shows only secrets marked by tag
+	-- Flag --sort sets order of secrets: created (default), updated or name, flag --desc shows latest secrets first or names in reverse order
+	-- Flag --limit shows only first secrets of list, like '--limit 20'
+	-- Flag --prefix shows only secrets which names start with prefix, like '--prefix work/db'
+
+- tag - replace tags of secret by name with list of tags after name, without tags removes all tags of secret
+
+- mv - move secret by name to folder, use '/' for root folder
+
+- rename - change name of secret, like 'secret text rename notes work/notes', content of secret is not reuploaded
+	-- Flag --type changes type of secret between credentials, card and text, like '--type text', content is kept as is
+
+Names of secrets can contain folders separated by '/', like 'work/db/prod-root', for media folder can be assigned by mv after upload
 `
 }
 
 func (p Secret) Execute(conn connector.ServiceConnector, sessional Sessional, logger *zap.Logger, args []string, workDir string) (requireExit bool, err error) {
 	if sessional.GetSession() == nil {
-		return false, fmt.Errorf("for working with secrets you need to be authorized")
+		return false, fmt.Errorf("for working with secrets %w", ErrNotAuthorized)
+	}
+
+	if len(args) >= 2 && args[1] == SecretActionList {
+		return false, listAllSecrets(conn, logger, args[2:])
 	}
 
 	err = validateArguments(args)
@@ -93,13 +133,78 @@ func (p Secret) Execute(conn connector.ServiceConnector, sessional Sessional, lo
 		secretName = args[3]
 	}
 
+	var (
+		limits      secret.Limits
+		description string
+	)
+	if secretAction == SecretActionSet && len(args) > 4 {
+		limits, description, err = parseSecretSetFlags(args[4:])
+		if err != nil {
+			return false, fmt.Errorf("got invalid secret limits: %w", err)
+		}
+
+		if description != "" && secretType != SecretTypeMedia {
+			return false, fmt.Errorf("description can be set only for media")
+		}
+	}
+
+	var field string
+	if secretAction == SecretActionGet && len(args) > 4 {
+		field, err = parseSecretGetFlags(args[4:])
+		if err != nil {
+			return false, fmt.Errorf("got invalid secret get flags: %w", err)
+		}
+
+		// field is validated before secret is read, because reads of secret can be limited
+		if !slices.Contains(secretFields[secretType], field) {
+			return false, fmt.Errorf("unknown field %s of %s, available fields: %s", field, secretType, strings.Join(secretFields[secretType], ", "))
+		}
+	}
+
+	var filter secret.Filter
+	if secretAction == SecretActionList {
+		filter, err = parseSecretFilter(args[3:])
+		if err != nil {
+			return false, fmt.Errorf("got invalid list filter: %w", err)
+		}
+	}
+
+	ctx := context.TODO()
+	switch secretAction {
+	case SecretActionTag:
+		return false, setSecretTags(ctx, conn, secretName, secretType, args[4:])
+	case SecretActionMove:
+		if len(args) != 5 {
+			return false, fmt.Errorf("mismatch arguments count for move secret: requires name and folder")
+		}
+
+		newName := secret.BaseName(secretName)
+		if folder := secret.CleanFolder(args[4]); folder != "" {
+			newName = folder + secret.FolderSeparator + newName
+		}
+
+		return false, renameSecret(ctx, conn, logger, secretName, newName, secretType, secretType, workDir)
+	case SecretActionRename:
+		if len(args) < 5 || strings.TrimSpace(args[4]) == "" || strings.HasPrefix(args[4], "-") {
+			return false, fmt.Errorf("mismatch arguments count for rename secret: requires old and new names")
+		}
+
+		newSecretType, err := parseSecretRenameFlags(secretType, args[5:])
+		if err != nil {
+			return false, fmt.Errorf("got invalid rename flags: %w", err)
+		}
+
+		return false, renameSecret(ctx, conn, logger, secretName, strings.Trim(args[4], secret.FolderSeparator), secretType, newSecretType, workDir)
+	}
+
 	var performer secretPerformer
 	if secretType == SecretTypeMedia {
 		performer = &secretMediaPerformer{
-			conn:    conn,
-			session: *sessional.GetSession(),
-			logger:  logger,
-			workDir: workDir,
+			conn:        conn,
+			session:     *sessional.GetSession(),
+			logger:      logger,
+			workDir:     workDir,
+			description: description,
 		}
 	} else if secretType == SecretTypeCredentials {
 		performer = &secretCredentialsPerformer{
@@ -123,15 +228,14 @@ func (p Secret) Execute(conn connector.ServiceConnector, sessional Sessional, lo
 		return false, fmt.Errorf("invalid secret type: %s", secretType)
 	}
 
-	ctx := context.TODO()
 	err = nil
 	switch secretAction {
 	case SecretActionSet:
-		err = performer.Set(ctx, secretName)
+		err = performer.Set(ctx, secretName, limits)
 	case SecretActionGet:
-		err = performer.Get(ctx, secretName)
+		err = performer.Get(ctx, secretName, field)
 	case SecretActionList:
-		err = performer.List(ctx)
+		err = performer.List(ctx, filter)
 	case SecretActionUpdate:
 		err = performer.Update(ctx, secretName)
 	case SecretActionDelete:
@@ -148,7 +252,11 @@ func (p Secret) Execute(conn connector.ServiceConnector, sessional Sessional, lo
 }
 
 func validateArguments(args []string) error {
-	if len(args) > 4 {
+	if len(args) < 3 {
+		return fmt.Errorf("mismatch arguments count: requires type and action")
+	}
+
+	if len(args) > 4 && args[2] != SecretActionSet && args[2] != SecretActionGet && args[2] != SecretActionList && args[2] != SecretActionTag && args[2] != SecretActionMove && args[2] != SecretActionRename {
 		return fmt.Errorf("too many arguments: %d", len(args)-1)
 	}
 
@@ -164,13 +272,266 @@ func validateArguments(args []string) error {
 		return nil
 	}
 
-	if strings.TrimSpace(args[3]) == "" && args[1] != SecretActionList {
+	if strings.TrimSpace(args[3]) == "" && args[2] != SecretActionList {
 		return fmt.Errorf("secret name can't be empty")
 	}
 
 	return nil
 }
 
+// parseSecretSetFlags parses flags of secret set like '--expires 24h --max-reads 1 --description "holiday photo"',
+// returns limits of secret and description of media
+func parseSecretSetFlags(args []string) (secret.Limits, string, error) {
+	fs := flag.NewFlagSet("secret", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	expires := fs.Duration("expires", 0, "lifetime of secret")
+	maxReads := fs.Int("max-reads", 0, "count of reads before secret removed")
+	description := fs.String("description", "", "description of media")
+
+	if err := fs.Parse(args); err != nil {
+		return secret.Limits{}, "", fmt.Errorf("cannot parse flags: %w", err)
+	}
+
+	if fs.NArg() != 0 {
+		return secret.Limits{}, "", fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	if *expires < 0 {
+		return secret.Limits{}, "", fmt.Errorf("expires duration can't be negative")
+	}
+
+	if *maxReads < 0 {
+		return secret.Limits{}, "", fmt.Errorf("max reads can't be negative")
+	}
+
+	limits := secret.Limits{MaxReads: *maxReads}
+	if *expires > 0 {
+		limits.ExpiresAt = time.Now().Add(*expires)
+	}
+
+	return limits, strings.TrimSpace(*description), nil
+}
+
+// parseSecretGetFlags parses flags of secret get like '--field password', returns name of printed field
+func parseSecretGetFlags(args []string) (string, error) {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	field := fs.String("field", "", "field of secret printed without formatting")
+
+	if err := fs.Parse(args); err != nil {
+		return "", fmt.Errorf("cannot parse flags: %w", err)
+	}
+
+	if fs.NArg() != 0 {
+		return "", fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	return strings.TrimSpace(*field), nil
+}
+
+// printSecretField prints value of field without formatting, so it can be used by scripts
+func printSecretField(field string, fields map[string]string) {
+	fmt.Println(fields[field])
+}
+
+// listOrder flags of secrets list order, shared by list commands
+type listOrder struct {
+	sort       string
+	descending bool
+	limit      int
+	prefix     string
+}
+
+func (o *listOrder) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.sort, "sort", "created", "order of secrets: created, updated or name")
+	fs.BoolVar(&o.descending, "desc", false, "list latest secrets first, or names in reverse order")
+	fs.IntVar(&o.limit, "limit", 0, "max count of secrets")
+	fs.StringVar(&o.prefix, "prefix", "", "prefix of secret names")
+}
+
+// apply sets order of list to filter, names are sorted and matched by prefix on client, other orders by service
+func (o *listOrder) apply(filter *secret.Filter) error {
+	switch o.sort {
+	case "created":
+		filter.Sort = secret.SortCreated
+	case "updated":
+		filter.Sort = secret.SortUpdated
+	case "name":
+		filter.Sort = secret.SortName
+	default:
+		return fmt.Errorf("invalid sort: %s, secrets can be sorted by created, updated or name", o.sort)
+	}
+
+	if o.limit < 0 {
+		return fmt.Errorf("limit can't be negative")
+	}
+
+	filter.Descending = o.descending
+	filter.Limit = o.limit
+	filter.NamePrefix = o.prefix
+
+	return nil
+}
+
+// parseSecretFilter parses flags of secrets list like '--folder work/db --tag prod --sort updated'
+func parseSecretFilter(args []string) (secret.Filter, error) {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	folder := fs.String("folder", "", "folder of secrets")
+	tag := fs.String("tag", "", "tag of secrets")
+
+	var order listOrder
+	order.register(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return secret.Filter{}, fmt.Errorf("cannot parse flags: %w", err)
+	}
+
+	if fs.NArg() != 0 {
+		return secret.Filter{}, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	filter := secret.Filter{Tag: *tag}
+	if err := order.apply(&filter); err != nil {
+		return secret.Filter{}, err
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "folder" {
+			cleanFolder := secret.CleanFolder(*folder)
+			filter.Folder = &cleanFolder
+		}
+	})
+
+	return filter, nil
+}
+
+// listAllSecrets prints secrets of all types matched by filter flags
+func listAllSecrets(conn connector.ServiceConnector, logger *zap.Logger, flags []string) error {
+	filter, err := parseSecretFilter(flags)
+	if err != nil {
+		return fmt.Errorf("got invalid list filter: %w", err)
+	}
+
+	secrets, err := conn.ListAllSecrets(context.TODO(), filter)
+	if err != nil {
+		logger.Error("Cannot list secrets of all types", zap.Error(err))
+
+		return fmt.Errorf("cannot list secrets: %w", err)
+	}
+
+	if len(secrets) == 0 {
+		fmt.Printf("No secrets found\n")
+
+		return nil
+	}
+
+	printTypedSecrets(secrets)
+
+	return nil
+}
+
+func setSecretTags(ctx context.Context, conn connector.ServiceConnector, name string, secretType string, tags []string) error {
+	for _, v := range tags {
+		if strings.TrimSpace(v) == "" {
+			return fmt.Errorf("tag can't be empty")
+		}
+	}
+
+	err := conn.SetSecretTags(ctx, name, translateSecretType(secretType), tags)
+	if err != nil {
+		return fmt.Errorf("cannot set tags of secret %s: %w", name, err)
+	}
+
+	fmt.Printf("\033[32mTags of secret %s successfuly updated!\033[0m\n", name)
+
+	return nil
+}
+
+// parseSecretRenameFlags parses flags of secret rename like '--type text', returns new type of secret
+func parseSecretRenameFlags(secretType string, args []string) (string, error) {
+	fs := flag.NewFlagSet("rename", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	newSecretType := fs.String("type", secretType, "new type of secret")
+
+	if err := fs.Parse(args); err != nil {
+		return "", fmt.Errorf("cannot parse flags: %w", err)
+	}
+
+	if fs.NArg() != 0 {
+		return "", fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	if err := validateType(*newSecretType); err != nil {
+		return "", err
+	}
+
+	if *newSecretType != secretType && (*newSecretType == SecretTypeMedia || secretType == SecretTypeMedia) {
+		return "", fmt.Errorf("type can be changed only between credentials, card and text")
+	}
+
+	return *newSecretType, nil
+}
+
+// renameSecret changes name and type of secret on service, loaded copy of media is renamed too
+func renameSecret(ctx context.Context, conn connector.ServiceConnector, logger *zap.Logger, name string, newName string, secretType string, newSecretType string, workDir string) error {
+	err := conn.RenameSecret(ctx, name, newName, translateSecretType(secretType), translateSecretType(newSecretType))
+	if err != nil {
+		return fmt.Errorf("cannot rename secret %s: %w", name, err)
+	}
+
+	if secretType == SecretTypeMedia {
+		oldPath := filepath.Join(workDir, "media", filepath.FromSlash(name))
+		newPath := filepath.Join(workDir, "media", filepath.FromSlash(newName))
+		if _, err := os.Stat(oldPath); err == nil {
+			err = os.MkdirAll(filepath.Dir(newPath), 0755)
+			if err == nil {
+				err = os.Rename(oldPath, newPath)
+			}
+
+			if err != nil {
+				logger.Error("Cannot rename loaded media file", zap.Error(err), zap.String("filename", name))
+			}
+		}
+	}
+
+	fmt.Printf("\033[32mSecret %s successfuly renamed to %s!\033[0m\n", name, newName)
+
+	return nil
+}
+
+// translateSecretType translates validated secret type argument to secret type
+func translateSecretType(secretType string) secret.SecretType {
+	switch secretType {
+	case SecretTypeCard:
+		return secret.SecretTypeCard
+	case SecretTypeText:
+		return secret.SecretTypeText
+	case SecretTypeMedia:
+		return secret.SecretTypeMedia
+	default:
+		return secret.SecretTypeCredentials
+	}
+}
+
+// formatSecretType translates secret type to name of type used in arguments
+func formatSecretType(secretType secret.SecretType) string {
+	switch secretType {
+	case secret.SecretTypeCard:
+		return SecretTypeCard
+	case secret.SecretTypeText:
+		return SecretTypeText
+	case secret.SecretTypeMedia:
+		return SecretTypeMedia
+	default:
+		return SecretTypeCredentials
+	}
+}
+
 func validateType(secretType string) error {
 	if secretType != SecretTypeCredentials && secretType != SecretTypeCard && secretType != SecretTypeText && secretType != SecretTypeMedia {
 		return fmt.Errorf("invalid secret type assigned: %s", secretType)
@@ -180,7 +541,7 @@ func validateType(secretType string) error {
 }
 
 func validateAction(secretAction string) error {
-	if secretAction != SecretActionSet && secretAction != SecretActionGet && secretAction != SecretActionUpdate && secretAction != SecretActionDelete && secretAction != SecretActionList {
+	if secretAction != SecretActionSet && secretAction != SecretActionGet && secretAction != SecretActionUpdate && secretAction != SecretActionDelete && secretAction != SecretActionList && secretAction != SecretActionTag && secretAction != SecretActionMove && secretAction != SecretActionRename {
 		return fmt.Errorf("invalid secret action assigned: %s", secretAction)
 	}
 
@@ -188,17 +549,21 @@ func validateAction(secretAction string) error {
 }
 
 type secretPerformer interface {
-	Set(ctx context.Context, name string) error
-	Get(ctx context.Context, name string) error
+	Set(ctx context.Context, name string, limits secret.Limits) error
+	// Get prints secret by name, only value of field is printed if field isn't empty
+	Get(ctx context.Context, name string, field string) error
 	Update(ctx context.Context, name string) error
 	Delete(ctx context.Context, name string) error
-	List(ctx context.Context) error
+	List(ctx context.Context, filter secret.Filter) error
 }
 
 type printableSecret struct {
 	Name        string
 	Create_time string // snake case used for table formatter
 	Update_time string
+	Expire_time string
+	Reads_left  string
+	Tags        string
 }
 
 func printSecrets(secrets []printableSecret) {
@@ -213,8 +578,35 @@ func printPlainSecrets(secrets []secret.Secret) {
 			Name:        v.Name,
 			Create_time: v.Created.String(),
 			Update_time: v.Updated.String(),
+			Expire_time: formatExpireTime(v),
+			Reads_left:  formatReadsLeft(v),
+			Tags:        formatTags(v),
 		}
 	}
 
 	printSecrets(printable)
 }
+
+func formatExpireTime(s secret.Secret) string {
+	if s.Limits.ExpiresAt.IsZero() {
+		return "-"
+	}
+
+	return s.Limits.ExpiresAt.String()
+}
+
+func formatTags(s secret.Secret) string {
+	if len(s.Tags) == 0 {
+		return "-"
+	}
+
+	return strings.Join(s.Tags, ", ")
+}
+
+func formatReadsLeft(s secret.Secret) string {
+	if s.Limits.MaxReads == 0 {
+		return "-"
+	}
+
+	return strconv.Itoa(s.Limits.MaxReads - s.Reads)
+}
@@ -79,7 +79,7 @@ func checkNumber(number string) error {
 	return nil
 }
 
-func (p *secretCardPerformer) Set(ctx context.Context, name string) error {
+func (p *secretCardPerformer) Set(ctx context.Context, name string, limits secret.Limits) error {
 	card, err := askCard()
 	if err != nil {
 		p.logger.Error("Cannot ask user card", zap.Error(err))
@@ -101,7 +101,7 @@ func (p *secretCardPerformer) Set(ctx context.Context, name string) error {
 		return fmt.Errorf("cannot encrypt card: %w", err)
 	}
 
-	err = p.conn.SetSecret(ctx, name, secret.SecretTypeCard, eCard)
+	err = p.conn.SetSecret(ctx, name, secret.SecretTypeCard, eCard, limits)
 	if err != nil {
 		p.logger.Error("Cannot set card to service", zap.Error(err))
 
@@ -113,7 +113,7 @@ func (p *secretCardPerformer) Set(ctx context.Context, name string) error {
 	return nil
 }
 
-func (p *secretCardPerformer) Get(ctx context.Context, name string) error {
+func (p *secretCardPerformer) Get(ctx context.Context, name string, field string) error {
 	s, err := p.conn.GetSecret(ctx, name, secret.SecretTypeCard)
 	if err != nil {
 		p.logger.Error("Cannot get card from service", zap.Error(err))
@@ -136,6 +136,17 @@ func (p *secretCardPerformer) Get(ctx context.Context, name string) error {
 		return fmt.Errorf("cannot unmarshal card: %w", err)
 	}
 
+	if field != "" {
+		printSecretField(field, map[string]string{
+			"number":      uSecret.Number,
+			"card_holder": uSecret.CardHolder,
+			"cvv":         strconv.Itoa(uSecret.CVV),
+			"expires":     uSecret.Expires,
+		})
+
+		return nil
+	}
+
 	fmt.Printf("---------\nCard %s\nNumber: %s\nCard Holder: %s\nCVV: %d\nExpires at: %s\n---------\n", name, uSecret.Number, uSecret.CardHolder, uSecret.CVV, uSecret.Expires)
 
 	return nil
@@ -188,8 +199,8 @@ func (p *secretCardPerformer) Delete(ctx context.Context, name string) error {
 	return nil
 }
 
-func (p *secretCardPerformer) List(ctx context.Context) error {
-	secrets, err := p.conn.ListSecret(ctx, secret.SecretTypeCard)
+func (p *secretCardPerformer) List(ctx context.Context, filter secret.Filter) error {
+	secrets, err := p.conn.ListSecret(ctx, secret.SecretTypeCard, filter)
 	if err != nil {
 		return fmt.Errorf("cannot get list of card: %w", err)
 	}
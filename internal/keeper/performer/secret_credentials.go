@@ -21,6 +21,9 @@ type secretCredentialsPerformer struct {
 type secretCredentials struct {
 	Login    string `json:"login"`
 	Password string `json:"password"`
+	// URL and Notes are filled by import from other password managers
+	URL   string `json:"url,omitempty"`
+	Notes string `json:"notes,omitempty"`
 }
 
 func askCredentials() (secretCredentials, error) {
@@ -40,7 +43,7 @@ func askCredentials() (secretCredentials, error) {
 	}, nil
 }
 
-func (p *secretCredentialsPerformer) Set(ctx context.Context, name string) error {
+func (p *secretCredentialsPerformer) Set(ctx context.Context, name string, limits secret.Limits) error {
 	creds, err := askCredentials()
 	if err != nil {
 		p.logger.Error("Cannot ask user credentials", zap.Error(err))
@@ -62,7 +65,7 @@ func (p *secretCredentialsPerformer) Set(ctx context.Context, name string) error
 		return fmt.Errorf("cannot encrypt credentials: %w", err)
 	}
 
-	err = p.conn.SetSecret(ctx, name, secret.SecretTypeCredentials, eCreds)
+	err = p.conn.SetSecret(ctx, name, secret.SecretTypeCredentials, eCreds, limits)
 	if err != nil {
 		p.logger.Error("Cannot set credentials to service", zap.Error(err))
 
@@ -74,7 +77,7 @@ func (p *secretCredentialsPerformer) Set(ctx context.Context, name string) error
 	return nil
 }
 
-func (p *secretCredentialsPerformer) Get(ctx context.Context, name string) error {
+func (p *secretCredentialsPerformer) Get(ctx context.Context, name string, field string) error {
 	s, err := p.conn.GetSecret(ctx, name, secret.SecretTypeCredentials)
 	if err != nil {
 		p.logger.Error("Cannot get credentials from service", zap.Error(err))
@@ -97,7 +100,25 @@ func (p *secretCredentialsPerformer) Get(ctx context.Context, name string) error
 		return fmt.Errorf("cannot unmarshal credentials: %w", err)
 	}
 
-	fmt.Printf("---------\nCredentials\nname: %s\nLogin: %s\tPassword: %s\n---------\n", name, uSecret.Login, uSecret.Password)
+	if field != "" {
+		printSecretField(field, map[string]string{
+			"login":    uSecret.Login,
+			"password": uSecret.Password,
+			"url":      uSecret.URL,
+			"notes":    uSecret.Notes,
+		})
+
+		return nil
+	}
+
+	fmt.Printf("---------\nCredentials\nname: %s\nLogin: %s\tPassword: %s\n", name, uSecret.Login, uSecret.Password)
+	if uSecret.URL != "" {
+		fmt.Printf("URL: %s\n", uSecret.URL)
+	}
+	if uSecret.Notes != "" {
+		fmt.Printf("Notes: %s\n", uSecret.Notes)
+	}
+	fmt.Printf("---------\n")
 
 	return nil
 }
@@ -149,8 +170,8 @@ func (p *secretCredentialsPerformer) Delete(ctx context.Context, name string) er
 	return nil
 }
 
-func (p *secretCredentialsPerformer) List(ctx context.Context) error {
-	secrets, err := p.conn.ListSecret(ctx, secret.SecretTypeCredentials)
+func (p *secretCredentialsPerformer) List(ctx context.Context, filter secret.Filter) error {
+	secrets, err := p.conn.ListSecret(ctx, secret.SecretTypeCredentials, filter)
 	if err != nil {
 		return fmt.Errorf("cannot get list of credentials: %w", err)
 	}
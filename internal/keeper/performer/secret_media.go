@@ -3,10 +3,12 @@ package performer
 import (
 	"context"
 	"fmt"
+	"github.com/chrusty/go-tableprinter"
 	"github.com/nessai1/gophkeeper/internal/keeper/connector"
 	"github.com/nessai1/gophkeeper/internal/keeper/media"
 	"github.com/nessai1/gophkeeper/internal/keeper/secret"
 	"github.com/nessai1/gophkeeper/internal/keeper/session"
+	"github.com/nessai1/gophkeeper/pkg/bytesize"
 	"go.uber.org/zap"
 	"io"
 	"os"
@@ -19,43 +21,38 @@ type secretMediaPerformer struct {
 	session session.Session
 	logger  *zap.Logger
 	workDir string
+	// description of media for set, empty for media without description
+	description string
 }
 
-// Set encrypt by AES file with path == name and save it on external service
-func (p *secretMediaPerformer) Set(ctx context.Context, name string) error {
-	return p.uploadFile(ctx, name, false)
+// Set encrypt by AES chunks of file with path == name and save it on external service
+func (p *secretMediaPerformer) Set(ctx context.Context, name string, limits secret.Limits) error {
+	return p.uploadFile(ctx, name, false, limits)
 }
 
-func (p *secretMediaPerformer) uploadFile(ctx context.Context, name string, replace bool) error {
+func (p *secretMediaPerformer) uploadFile(ctx context.Context, name string, replace bool, limits secret.Limits) error {
 	file, err := os.Open(name)
 	if err != nil {
 		return fmt.Errorf("cannot find file '%s': %w", name, err)
 	}
 
-	encryptedFile, err := media.EncryptFile(ctx, file, filepath.Join(os.TempDir(), filepath.Base(name)+"_"+time.Now().String()+".encrypted"), p.session.SecretKey)
-	if err != nil {
-		p.logger.Error("cannot encrypt media file", zap.Error(err), zap.String("filepath", name))
-
-		return fmt.Errorf("cannot encrypt file, see logs")
-	}
+	defer file.Close()
 
-	encryptedFileInfo, err := encryptedFile.Stat()
+	info, err := media.Inspect(file)
 	if err != nil {
-		p.logger.Error("cannot get encrypted file info", zap.Error(err), zap.String("filepath", encryptedFileInfo.Name()))
-
-		return fmt.Errorf("cannot get encrypt file, see logs")
+		return fmt.Errorf("cannot inspect file '%s': %w", name, err)
 	}
+	info.Description = p.description
 
-	encryptedFile.Seek(0, 0)
-	id, err := p.conn.UploadMedia(ctx, filepath.Base(name), encryptedFile, replace)
+	stats, err := p.conn.UploadChunkedMedia(ctx, filepath.Base(name), file, replace, limits, &info)
 	if err != nil {
 		p.logger.Error("Errror while upload new media", zap.String("filename", filepath.Base(name)), zap.Error(err))
 
 		return fmt.Errorf("cannot send media file to server: %w", err)
 	}
 
-	p.logger.Info("client set media secret", zap.String("login", p.session.Login), zap.String("filepath", name), zap.Int64("size", encryptedFileInfo.Size()))
-	fmt.Printf("Media file %s successful saved! Encrypted size: %d bytes\tUUID: %s\n", filepath.Base(name), encryptedFileInfo.Size(), id)
+	p.logger.Info("client set media secret", zap.String("login", p.session.Login), zap.String("filepath", name), zap.Int64("size", stats.Size), zap.Int64("uploaded_size", stats.UploadedSize))
+	fmt.Printf("Media file %s successful saved! Encrypted size: %d bytes, uploaded: %d bytes\tUUID: %s\n", filepath.Base(name), stats.Size, stats.UploadedSize, stats.UUID)
 
 	copyFile, err := os.Create(filepath.Join(p.workDir, "media", filepath.Base(name)))
 	if err != nil {
@@ -80,8 +77,8 @@ func (p *secretMediaPerformer) uploadFile(ctx context.Context, name string, repl
 	return nil
 }
 
-func (p *secretMediaPerformer) Get(ctx context.Context, name string) error {
-	f, err := p.conn.DownloadMedia(ctx, name, filepath.Join(os.TempDir(), filepath.Base(name)+"_"+time.Now().String()+".encrypted"))
+func (p *secretMediaPerformer) Get(ctx context.Context, name string, _ string) error {
+	f, chunked, err := p.conn.DownloadMedia(ctx, name, filepath.Join(os.TempDir(), filepath.Base(name)+"_"+time.Now().String()+".encrypted"))
 	if err != nil {
 		p.logger.Error("Cannot download media", zap.String("login", p.session.Login), zap.String("filename", name), zap.Error(err))
 
@@ -95,7 +92,19 @@ func (p *secretMediaPerformer) Get(ctx context.Context, name string) error {
 		}
 	}()
 
-	decryptedFile, err := media.DecryptFile(ctx, f, filepath.Join(p.workDir, "media", name), p.session.SecretKey)
+	err = os.MkdirAll(filepath.Join(p.workDir, "media", filepath.FromSlash(secret.FolderOf(name))), 0755)
+	if err != nil {
+		return fmt.Errorf("cannot create media folder: %w", err)
+	}
+
+	var decryptedFile *os.File
+	if chunked {
+		// chunked media is already decrypted by connector
+		decryptedFile, err = copyToFile(f, filepath.Join(p.workDir, "media", filepath.FromSlash(name)))
+	} else {
+		decryptedFile, err = media.DecryptFile(ctx, f, filepath.Join(p.workDir, "media", filepath.FromSlash(name)), p.session.SecretKey)
+	}
+
 	if err != nil {
 		p.logger.Error("Cannot decrypt downloaded media", zap.String("login", p.session.Login), zap.String("filename", name), zap.Error(err))
 
@@ -114,6 +123,23 @@ func (p *secretMediaPerformer) Get(ctx context.Context, name string) error {
 	return nil
 }
 
+// copyToFile copies content of reader to new file
+func copyToFile(reader io.Reader, destination string) (*os.File, error) {
+	output, err := os.OpenFile(destination, os.O_CREATE|os.O_RDWR|os.O_EXCL, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create file %s: %w", destination, err)
+	}
+
+	_, err = io.Copy(output, reader)
+	if err != nil {
+		output.Close()
+
+		return nil, fmt.Errorf("cannot copy content to file %s: %w", destination, err)
+	}
+
+	return output, nil
+}
+
 func (p *secretMediaPerformer) Update(ctx context.Context, name string) error {
 	if p.isFileLoaded(name) {
 		err := p.Delete(ctx, name)
@@ -122,7 +148,7 @@ func (p *secretMediaPerformer) Update(ctx context.Context, name string) error {
 		}
 	}
 
-	return p.uploadFile(ctx, name, true)
+	return p.uploadFile(ctx, name, true, secret.Limits{})
 }
 
 func (p *secretMediaPerformer) Delete(ctx context.Context, name string) error {
@@ -141,8 +167,8 @@ func (p *secretMediaPerformer) Delete(ctx context.Context, name string) error {
 	return nil
 }
 
-func (p *secretMediaPerformer) List(ctx context.Context) error {
-	secrets, err := p.conn.ListSecret(ctx, secret.SecretTypeMedia)
+func (p *secretMediaPerformer) List(ctx context.Context, filter secret.Filter) error {
+	secrets, err := p.conn.ListSecret(ctx, secret.SecretTypeMedia, filter)
 	if err != nil {
 		p.logger.Error("Cannot list media secrets", zap.Error(err))
 
@@ -155,7 +181,7 @@ func (p *secretMediaPerformer) List(ctx context.Context) error {
 		return nil
 	}
 
-	printable := make([]printableSecret, len(secrets))
+	printable := make([]printableMedia, len(secrets))
 	for i, v := range secrets {
 
 		name := v.Name
@@ -165,19 +191,78 @@ func (p *secretMediaPerformer) List(ctx context.Context) error {
 			name = fmt.Sprintf("\033[33m%s\033[0m", name)
 		}
 
-		printable[i] = printableSecret{
-			Name:        name,
-			Create_time: v.Created.String(),
-			Update_time: v.Updated.String(),
+		printable[i] = printableMedia{
+			Name:          name,
+			Size:          "-",
+			Stored_size:   formatSize(v.Size),
+			Content_type:  "-",
+			Checksum:      "-",
+			Original_path: "-",
+			Description:   "-",
+			Update_time:   v.Updated.String(),
+			Expire_time:   formatExpireTime(v),
+			Reads_left:    formatReadsLeft(v),
+			Tags:          formatTags(v),
+		}
+
+		if v.Media != nil {
+			printable[i].Size = bytesize.ByteSize(v.Media.Size).String()
+			printable[i].Content_type = v.Media.ContentType
+			printable[i].Checksum = formatChecksum(v.Media.Checksum)
+			printable[i].Original_path = orDash(v.Media.OriginalPath)
+			printable[i].Description = orDash(v.Media.Description)
 		}
 	}
 
 	fmt.Printf("\033[32mexists in media \t \033[33mstored only in service\033[0m\n")
-	printSecrets(printable)
+	tableprinter.SetBorder(true)
+	tableprinter.Print(printable)
 
 	return nil
 }
 
+type printableMedia struct {
+	Name          string
+	Size          string
+	Stored_size   string // snake case used for table formatter
+	Content_type  string
+	Checksum      string
+	Original_path string
+	Description   string
+	Update_time   string
+	Expire_time   string
+	Reads_left    string
+	Tags          string
+}
+
+// checksumPrintLength count of first hex digits of checksum printed in list, enough to compare with local file
+const checksumPrintLength = 12
+
+// formatSize formats size stored by service, media uploaded before media info has unknown zero size
+func formatSize(size int64) string {
+	if size == 0 {
+		return "-"
+	}
+
+	return bytesize.ByteSize(size).String()
+}
+
+func formatChecksum(checksum string) string {
+	if len(checksum) > checksumPrintLength {
+		return checksum[:checksumPrintLength]
+	}
+
+	return orDash(checksum)
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
+
 func (p *secretMediaPerformer) isFileLoaded(name string) bool {
 	_, err := os.Stat(filepath.Join(p.workDir, "media", name))
 	if err != nil {
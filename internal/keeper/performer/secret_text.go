@@ -33,7 +33,7 @@ func askText() (secretText, error) {
 	}, nil
 }
 
-func (p *secretTextPerformer) Set(ctx context.Context, name string) error {
+func (p *secretTextPerformer) Set(ctx context.Context, name string, limits secret.Limits) error {
 	text, err := askText()
 	if err != nil {
 		p.logger.Error("Cannot ask user text", zap.Error(err))
@@ -55,7 +55,7 @@ func (p *secretTextPerformer) Set(ctx context.Context, name string) error {
 		return fmt.Errorf("cannot encrypt text: %w", err)
 	}
 
-	err = p.conn.SetSecret(ctx, name, secret.SecretTypeText, eText)
+	err = p.conn.SetSecret(ctx, name, secret.SecretTypeText, eText, limits)
 	if err != nil {
 		p.logger.Error("Cannot set text to service", zap.Error(err))
 
@@ -67,7 +67,7 @@ func (p *secretTextPerformer) Set(ctx context.Context, name string) error {
 	return nil
 }
 
-func (p *secretTextPerformer) Get(ctx context.Context, name string) error {
+func (p *secretTextPerformer) Get(ctx context.Context, name string, field string) error {
 	s, err := p.conn.GetSecret(ctx, name, secret.SecretTypeText)
 	if err != nil {
 		p.logger.Error("Cannot get text from service", zap.Error(err))
@@ -90,6 +90,14 @@ func (p *secretTextPerformer) Get(ctx context.Context, name string) error {
 		return fmt.Errorf("cannot unmarshal text: %w", err)
 	}
 
+	if field != "" {
+		printSecretField(field, map[string]string{
+			"text": uSecret.Text,
+		})
+
+		return nil
+	}
+
 	fmt.Printf("---------\nText %s\n%s\n---------\n", name, uSecret.Text)
 
 	return nil
@@ -142,8 +150,8 @@ func (p *secretTextPerformer) Delete(ctx context.Context, name string) error {
 	return nil
 }
 
-func (p *secretTextPerformer) List(ctx context.Context) error {
-	secrets, err := p.conn.ListSecret(ctx, secret.SecretTypeText)
+func (p *secretTextPerformer) List(ctx context.Context, filter secret.Filter) error {
+	secrets, err := p.conn.ListSecret(ctx, secret.SecretTypeText, filter)
 	if err != nil {
 		return fmt.Errorf("cannot get list of text: %w", err)
 	}
@@ -0,0 +1,83 @@
+package performer
+
+import (
+	"context"
+	"fmt"
+	"github.com/chrusty/go-tableprinter"
+	"github.com/nessai1/gophkeeper/internal/keeper/connector"
+	"github.com/nessai1/gophkeeper/pkg/bytesize"
+	"go.uber.org/zap"
+	"strconv"
+	"time"
+)
+
+type Usage struct {
+}
+
+func (p Usage) GetName() string {
+	return "usage"
+}
+
+func (p Usage) GetStruct() string {
+	return "usage"
+}
+
+func (p Usage) GetDescription() string {
+	return "Show storage consumption and quota of user"
+}
+
+func (p Usage) GetDetailDescription() string {
+	return `Show count and size of stored media and plain secrets and limits of them set by service.
+
+Size of media is a size of encrypted content, parts shared by several media are counted once
+`
+}
+
+type printableUsage struct {
+	Resource string
+	Used     string
+	Limit    string
+}
+
+func (p Usage) Execute(conn connector.ServiceConnector, sessional Sessional, logger *zap.Logger, _ []string, _ string) (requireExit bool, err error) {
+	if sessional.GetSession() == nil {
+		return false, fmt.Errorf("for get usage %w", ErrNotAuthorized)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	usage, err := conn.GetUsage(ctx)
+	if err != nil {
+		logger.Error("Cannot get usage of user", zap.Error(err))
+
+		return false, fmt.Errorf("cannot get usage: %w", err)
+	}
+
+	tableprinter.SetBorder(true)
+	tableprinter.Print([]printableUsage{
+		{Resource: "media", Used: strconv.Itoa(usage.MediaCount), Limit: formatCountLimit(usage.MaxMedia)},
+		{Resource: "secrets", Used: strconv.Itoa(usage.PlainCount), Limit: formatCountLimit(usage.MaxSecrets)},
+		{Resource: "media size", Used: bytesize.ByteSize(usage.MediaBytes).String(), Limit: "-"},
+		{Resource: "secrets size", Used: bytesize.ByteSize(usage.PlainBytes).String(), Limit: "-"},
+		{Resource: "total size", Used: bytesize.ByteSize(usage.MediaBytes + usage.PlainBytes).String(), Limit: formatSizeLimit(usage.MaxBytes)},
+	})
+
+	return false, nil
+}
+
+func formatCountLimit(limit int) string {
+	if limit == 0 {
+		return "unlimited"
+	}
+
+	return strconv.Itoa(limit)
+}
+
+func formatSizeLimit(limit int64) string {
+	if limit == 0 {
+		return "unlimited"
+	}
+
+	return bytesize.ByteSize(limit).String()
+}
@@ -0,0 +1,430 @@
+package performer
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/keeper/connector"
+	"github.com/nessai1/gophkeeper/internal/keeper/encrypt"
+	"github.com/nessai1/gophkeeper/internal/keeper/media"
+	"github.com/nessai1/gophkeeper/internal/keeper/secret"
+	"github.com/nessai1/gophkeeper/internal/keeper/session"
+	"github.com/nessai1/gophkeeper/internal/keeper/vault"
+	"github.com/nessai1/gophkeeper/pkg/command"
+	"go.uber.org/zap"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	VaultActionExport = "export"
+	VaultActionImport = "import"
+)
+
+// Strategies of import for secrets with names that are already taken
+const (
+	VaultConflictRename    = "rename"
+	VaultConflictSkip      = "skip"
+	VaultConflictOverwrite = "overwrite"
+)
+
+type Vault struct {
+}
+
+func (p Vault) GetName() string {
+	return "vault"
+}
+
+func (p Vault) GetStruct() string {
+	return "vault [export|import] [file] [?--include-limited] [?--conflict rename|skip|overwrite]"
+}
+
+func (p Vault) GetDescription() string {
+	return "Export all secrets to offline archive protected by passphrase or import them from it"
+}
+
+func (p Vault) GetDetailDescription() string {
+	return `Export all secrets to offline archive protected by passphrase or import them from it
+
+Available actions:
+
+- export - fetch and decrypt secrets of all types including media and save them to new archive file
+	-- Archive is encrypted by separate export passphrase, it isn't bound to account and can be imported into any account
+	-- Secrets with limited reads are skipped, because every read is counted by service
+	-- Flag --include-limited exports secrets with limited reads too, one read of every such secret is spent.
+	   Secrets with the last read left are skipped anyway, because export would remove them
+
+- import - restore secrets of archive into current account
+	-- Names, folders, tags, lifetime and reads left of secrets are restored, expired secrets are skipped
+	-- Flag --conflict sets strategy for names that are already taken:
+		rename (default) - import secret with number added to name, like 'notes-2'
+		skip - keep existing secret and skip imported
+		overwrite - replace existing secret and its limits by imported
+`
+}
+
+func (p Vault) Execute(conn connector.ServiceConnector, sessional Sessional, logger *zap.Logger, args []string, _ string) (requireExit bool, err error) {
+	if sessional.GetSession() == nil {
+		return false, fmt.Errorf("for working with vault %w", ErrNotAuthorized)
+	}
+
+	if len(args) < 3 || strings.TrimSpace(args[2]) == "" {
+		return false, fmt.Errorf("mismatch arguments count for vault: requires action and file")
+	}
+
+	options, err := parseVaultFlags(args[1], args[3:])
+	if err != nil {
+		return false, fmt.Errorf("got invalid vault flags: %w", err)
+	}
+
+	ctx := context.TODO()
+	switch args[1] {
+	case VaultActionExport:
+		err = exportVault(ctx, conn, *sessional.GetSession(), logger, args[2], options)
+	case VaultActionImport:
+		err = importVault(ctx, conn, *sessional.GetSession(), logger, args[2], options)
+	default:
+		err = fmt.Errorf("invalid vault action: %s", args[1])
+	}
+
+	return false, err
+}
+
+type vaultOptions struct {
+	includeLimited bool
+	conflict       string
+}
+
+// parseVaultFlags parses flags of vault action like '--conflict skip'
+func parseVaultFlags(action string, args []string) (vaultOptions, error) {
+	fs := flag.NewFlagSet("vault", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	options := vaultOptions{}
+	if action == VaultActionExport {
+		fs.BoolVar(&options.includeLimited, "include-limited", false, "export secrets with limited reads")
+	} else {
+		fs.StringVar(&options.conflict, "conflict", VaultConflictRename, "strategy for taken names")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return vaultOptions{}, fmt.Errorf("cannot parse flags: %w", err)
+	}
+
+	if fs.NArg() != 0 {
+		return vaultOptions{}, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	if action == VaultActionImport && options.conflict != VaultConflictRename && options.conflict != VaultConflictSkip && options.conflict != VaultConflictOverwrite {
+		return vaultOptions{}, fmt.Errorf("invalid conflict strategy: %s", options.conflict)
+	}
+
+	return options, nil
+}
+
+// exportVault writes decrypted secrets of user to new archive, archive is removed if export fails
+func exportVault(ctx context.Context, conn connector.ServiceConnector, s session.Session, logger *zap.Logger, path string, options vaultOptions) error {
+	secrets, err := conn.ListAllSecrets(ctx, secret.Filter{})
+	if err != nil {
+		logger.Error("Cannot list secrets for export", zap.Error(err))
+
+		return fmt.Errorf("cannot list secrets: %w", err)
+	}
+
+	passphrase, err := askNewPassphrase()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("cannot create archive: %w", err)
+	}
+
+	exported, skipped, err := writeVault(ctx, conn, s, file, passphrase, secrets, options)
+	if err == nil {
+		err = file.Close()
+	}
+
+	if err != nil {
+		file.Close()
+		os.Remove(path)
+		logger.Error("Cannot export secrets", zap.Error(err))
+
+		return fmt.Errorf("cannot export secrets: %w", err)
+	}
+
+	reason := "has limited reads"
+	if options.includeLimited {
+		reason = "has the last read left"
+	}
+
+	for _, v := range skipped {
+		fmt.Printf("\033[33mSecret %s %s %s and skipped\033[0m\n", formatSecretType(v.SecretType), v.Name, reason)
+	}
+
+	fmt.Printf("\033[32mExported %d secrets to %s!\033[0m\n", exported, path)
+
+	return nil
+}
+
+// askNewPassphrase asks export passphrase with confirmation
+func askNewPassphrase() (string, error) {
+	passphrase, err := command.AskSecret("Enter export passphrase")
+	if err != nil {
+		return "", fmt.Errorf("cannot read passphrase: %w", err)
+	}
+
+	if strings.TrimSpace(passphrase) == "" {
+		return "", fmt.Errorf("export passphrase can't be empty")
+	}
+
+	confirm, err := command.AskSecret("Repeat export passphrase")
+	if err != nil {
+		return "", fmt.Errorf("cannot read passphrase: %w", err)
+	}
+
+	if confirm != passphrase {
+		return "", fmt.Errorf("passphrases don't match")
+	}
+
+	return passphrase, nil
+}
+
+// writeVault writes secrets to archive, returns count of exported secrets and secrets skipped by read limits
+func writeVault(ctx context.Context, conn connector.ServiceConnector, s session.Session, w io.Writer, passphrase string, secrets []secret.Secret, options vaultOptions) (int, []secret.Secret, error) {
+	archive, err := vault.NewWriter(w, passphrase)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	exported := 0
+	var skipped []secret.Secret
+	for _, v := range secrets {
+		// read of export is counted, so secret with the last read left would be removed by export
+		readsLeft := v.Limits.MaxReads - v.Reads - 1
+		if v.Limits.MaxReads > 0 && (!options.includeLimited || readsLeft < 1) {
+			skipped = append(skipped, v)
+
+			continue
+		}
+
+		record := vault.Record{
+			Type:    v.SecretType,
+			Name:    v.Name,
+			Tags:    v.Tags,
+			Created: v.Created,
+			Updated: v.Updated,
+			Media:   v.Media,
+		}
+
+		if v.Limits.MaxReads > 0 {
+			record.MaxReads = readsLeft
+		}
+
+		if !v.Limits.ExpiresAt.IsZero() {
+			expiresAt := v.Limits.ExpiresAt
+			record.ExpiresAt = &expiresAt
+		}
+
+		if v.SecretType == secret.SecretTypeMedia {
+			err = exportMedia(ctx, conn, s, archive, record)
+		} else {
+			err = exportPlainSecret(ctx, conn, s, archive, record)
+		}
+
+		if err != nil {
+			return exported, skipped, fmt.Errorf("cannot export %s %s: %w", formatSecretType(v.SecretType), v.Name, err)
+		}
+
+		exported++
+	}
+
+	return exported, skipped, archive.Close()
+}
+
+func exportPlainSecret(ctx context.Context, conn connector.ServiceConnector, s session.Session, archive *vault.Writer, record vault.Record) error {
+	data, err := conn.GetSecret(ctx, record.Name, record.Type)
+	if err != nil {
+		return fmt.Errorf("cannot get secret from service: %w", err)
+	}
+
+	record.Data, err = encrypt.DecryptAES256(data, s.SecretKey)
+	if err != nil {
+		return fmt.Errorf("cannot decrypt secret: %w", err)
+	}
+
+	return archive.AddSecret(record)
+}
+
+// exportMedia downloads and decrypts media by temporary files, size of content must be known before it's archived
+func exportMedia(ctx context.Context, conn connector.ServiceConnector, s session.Session, archive *vault.Writer, record vault.Record) error {
+	tempDir, err := os.MkdirTemp("", "keeper-vault-*")
+	if err != nil {
+		return fmt.Errorf("cannot create temporary dir: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	downloaded, chunked, err := conn.DownloadMedia(ctx, record.Name, filepath.Join(tempDir, "downloaded"))
+	if err != nil {
+		return fmt.Errorf("cannot download media: %w", err)
+	}
+	defer downloaded.Close()
+
+	content := downloaded
+	if !chunked {
+		// content of chunked media is already decrypted by connector
+		content, err = media.DecryptFile(ctx, downloaded, filepath.Join(tempDir, "decrypted"), s.SecretKey)
+		if err != nil {
+			return fmt.Errorf("cannot decrypt media: %w", err)
+		}
+		defer content.Close()
+
+		if _, err = content.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("cannot seek decrypted media: %w", err)
+		}
+	}
+
+	stat, err := content.Stat()
+	if err != nil {
+		return fmt.Errorf("cannot get size of media: %w", err)
+	}
+
+	return archive.AddMedia(record, stat.Size(), content)
+}
+
+// importReport counts of imported and skipped secrets
+type importReport struct {
+	imported int
+	expired  int
+	skipped  int
+}
+
+// importVault restores secrets of archive to account, secrets imported before failure stay in account
+func importVault(ctx context.Context, conn connector.ServiceConnector, s session.Session, logger *zap.Logger, path string, options vaultOptions) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("cannot open archive: %w", err)
+	}
+	defer file.Close()
+
+	passphrase, err := command.AskSecret("Enter export passphrase")
+	if err != nil {
+		return fmt.Errorf("cannot read passphrase: %w", err)
+	}
+
+	archive, err := vault.NewReader(file, passphrase)
+	if err != nil {
+		return fmt.Errorf("cannot read archive: %w", err)
+	}
+
+	existing, err := conn.ListAllSecrets(ctx, secret.Filter{})
+	if err != nil {
+		logger.Error("Cannot list secrets for import", zap.Error(err))
+
+		return fmt.Errorf("cannot list secrets: %w", err)
+	}
+
+	taken := make(map[secret.SecretType]map[string]bool)
+	for _, v := range existing {
+		markTaken(taken, v.SecretType, v.Name)
+	}
+
+	report := importReport{}
+	err = readVault(ctx, conn, s, archive, taken, options, &report)
+	if err != nil {
+		logger.Error("Cannot import secrets", zap.Error(err), zap.Int("imported", report.imported))
+
+		if errors.Is(err, vault.ErrWrongPassphrase) {
+			return fmt.Errorf("cannot import secrets: %w", err)
+		}
+
+		return fmt.Errorf("cannot import secrets (imported %d): %w", report.imported, err)
+	}
+
+	fmt.Printf("\033[32mImported %d secrets, skipped %d taken and %d expired secrets!\033[0m\n", report.imported, report.skipped, report.expired)
+
+	return nil
+}
+
+func markTaken(taken map[secret.SecretType]map[string]bool, secretType secret.SecretType, name string) {
+	if taken[secretType] == nil {
+		taken[secretType] = make(map[string]bool)
+	}
+
+	taken[secretType][name] = true
+}
+
+func readVault(ctx context.Context, conn connector.ServiceConnector, s session.Session, archive *vault.Reader, taken map[secret.SecretType]map[string]bool, options vaultOptions, report *importReport) error {
+	for {
+		record, content, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		limits := secret.Limits{MaxReads: record.MaxReads}
+		if record.ExpiresAt != nil {
+			if !record.ExpiresAt.After(time.Now()) {
+				report.expired++
+
+				continue
+			}
+
+			limits.ExpiresAt = *record.ExpiresAt
+		}
+
+		name := record.Name
+		overwrite := false
+		if taken[record.Type][name] {
+			switch options.conflict {
+			case VaultConflictSkip:
+				report.skipped++
+
+				continue
+			case VaultConflictOverwrite:
+				overwrite = true
+			default:
+				name = vault.UniqueName(name, func(name string) bool {
+					return taken[record.Type][name]
+				})
+				fmt.Printf("\033[33mName of %s %s is taken, secret is imported as %s\033[0m\n", formatSecretType(record.Type), record.Name, name)
+			}
+		}
+
+		if record.Type == secret.SecretTypeMedia {
+			_, err = conn.UploadChunkedMedia(ctx, name, content, overwrite, limits, record.Media)
+		} else {
+			err = importPlainSecret(ctx, conn, s, name, record, overwrite, limits)
+		}
+
+		if err == nil && len(record.Tags) > 0 {
+			err = conn.SetSecretTags(ctx, name, record.Type, record.Tags)
+		}
+
+		if err != nil {
+			return fmt.Errorf("cannot import %s %s: %w", formatSecretType(record.Type), record.Name, err)
+		}
+
+		markTaken(taken, record.Type, name)
+		report.imported++
+	}
+}
+
+func importPlainSecret(ctx context.Context, conn connector.ServiceConnector, s session.Session, name string, record vault.Record, overwrite bool, limits secret.Limits) error {
+	data, err := encrypt.EncryptAES256(record.Data, s.SecretKey)
+	if err != nil {
+		return fmt.Errorf("cannot encrypt secret: %w", err)
+	}
+
+	if overwrite {
+		return conn.ReplaceSecret(ctx, name, record.Type, data, limits)
+	}
+
+	return conn.SetSecret(ctx, name, record.Type, data, limits)
+}
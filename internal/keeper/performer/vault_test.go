@@ -0,0 +1,174 @@
+package performer
+
+import (
+	"bytes"
+	"context"
+	"github.com/nessai1/gophkeeper/internal/keeper/connector"
+	"github.com/nessai1/gophkeeper/internal/keeper/encrypt"
+	"github.com/nessai1/gophkeeper/internal/keeper/secret"
+	"github.com/nessai1/gophkeeper/internal/keeper/session"
+	"github.com/nessai1/gophkeeper/internal/keeper/vault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"testing"
+	"time"
+)
+
+type storedSecret struct {
+	data   []byte
+	limits secret.Limits
+	tags   []string
+}
+
+// secretsConnector keeps plain secrets by names, other methods of connector aren't implemented
+type secretsConnector struct {
+	connector.ServiceConnector
+
+	secrets map[string]*storedSecret
+}
+
+func (c *secretsConnector) SetSecret(_ context.Context, name string, _ secret.SecretType, data []byte, limits secret.Limits) error {
+	if _, ok := c.secrets[name]; ok {
+		return status.Error(codes.AlreadyExists, "secret with name already exists")
+	}
+
+	c.secrets[name] = &storedSecret{data: data, limits: limits}
+
+	return nil
+}
+
+func (c *secretsConnector) ReplaceSecret(_ context.Context, name string, _ secret.SecretType, data []byte, limits secret.Limits) error {
+	stored, ok := c.secrets[name]
+	if !ok {
+		return status.Error(codes.NotFound, "secret not found")
+	}
+
+	stored.data, stored.limits = data, limits
+
+	return nil
+}
+
+func (c *secretsConnector) GetSecret(_ context.Context, name string, _ secret.SecretType) ([]byte, error) {
+	stored, ok := c.secrets[name]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "secret not found")
+	}
+
+	return stored.data, nil
+}
+
+func (c *secretsConnector) SetSecretTags(_ context.Context, name string, _ secret.SecretType, tags []string) error {
+	stored, ok := c.secrets[name]
+	if !ok {
+		return status.Error(codes.NotFound, "secret not found")
+	}
+
+	stored.tags = tags
+
+	return nil
+}
+
+func TestReadVault_Conflicts(t *testing.T) {
+	ctx := context.Background()
+	s := session.Session{SecretKey: [32]byte{1}}
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	archive := bytes.Buffer{}
+	writer, err := vault.NewWriter(&archive, "passphrase")
+	require.NoError(t, err)
+	require.NoError(t, writer.AddSecret(vault.Record{
+		Type:      secret.SecretTypeText,
+		Name:      "notes",
+		Tags:      []string{"work"},
+		ExpiresAt: &expiresAt,
+		MaxReads:  2,
+		Data:      []byte(`{"text":"imported"}`),
+	}))
+	require.NoError(t, writer.Close())
+
+	imported := &storedSecret{data: []byte(`{"text":"imported"}`), limits: secret.Limits{ExpiresAt: expiresAt, MaxReads: 2}, tags: []string{"work"}}
+	existing := &storedSecret{data: []byte(`{"text":"existing"}`)}
+
+	tests := []struct {
+		conflict string
+		expected map[string]*storedSecret
+		report   importReport
+	}{
+		{conflict: VaultConflictRename, expected: map[string]*storedSecret{"notes": existing, "notes-2": imported}, report: importReport{imported: 1}},
+		{conflict: VaultConflictSkip, expected: map[string]*storedSecret{"notes": existing}, report: importReport{skipped: 1}},
+		{conflict: VaultConflictOverwrite, expected: map[string]*storedSecret{"notes": imported}, report: importReport{imported: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.conflict, func(t *testing.T) {
+			data, err := encrypt.EncryptAES256(existing.data, s.SecretKey)
+			require.NoError(t, err)
+			conn := &secretsConnector{secrets: map[string]*storedSecret{"notes": {data: data}}}
+			taken := map[secret.SecretType]map[string]bool{secret.SecretTypeText: {"notes": true}}
+
+			reader, err := vault.NewReader(bytes.NewReader(archive.Bytes()), "passphrase")
+			require.NoError(t, err)
+
+			report := importReport{}
+			require.NoError(t, readVault(ctx, conn, s, reader, taken, vaultOptions{conflict: tt.conflict}, &report))
+			assert.Equal(t, tt.report, report)
+
+			require.Len(t, conn.secrets, len(tt.expected))
+			for name, expected := range tt.expected {
+				stored, ok := conn.secrets[name]
+				require.True(t, ok, "secret %s must be stored", name)
+
+				data, err := encrypt.DecryptAES256(stored.data, s.SecretKey)
+				require.NoError(t, err)
+				assert.Equal(t, expected.data, data)
+				assert.Equal(t, expected.limits.MaxReads, stored.limits.MaxReads)
+				assert.True(t, expected.limits.ExpiresAt.Equal(stored.limits.ExpiresAt), "secret %s must expire at %s", name, expected.limits.ExpiresAt)
+				assert.Equal(t, expected.tags, stored.tags)
+			}
+		})
+	}
+}
+
+func TestWriteVault_ReadsLeft(t *testing.T) {
+	ctx := context.Background()
+	s := session.Session{SecretKey: [32]byte{1}}
+
+	conn := &secretsConnector{secrets: make(map[string]*storedSecret)}
+	for _, name := range []string{"endless", "limited", "last-read"} {
+		data, err := encrypt.EncryptAES256([]byte(`{"text":"`+name+`"}`), s.SecretKey)
+		require.NoError(t, err)
+		conn.secrets[name] = &storedSecret{data: data}
+	}
+
+	secrets := []secret.Secret{
+		{Name: "endless", SecretType: secret.SecretTypeText},
+		{Name: "limited", SecretType: secret.SecretTypeText, Limits: secret.Limits{MaxReads: 4}, Reads: 1},
+		{Name: "last-read", SecretType: secret.SecretTypeText, Limits: secret.Limits{MaxReads: 2}, Reads: 1},
+	}
+
+	archive := bytes.Buffer{}
+	exported, skipped, err := writeVault(ctx, conn, s, &archive, "passphrase", secrets, vaultOptions{includeLimited: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, exported)
+	require.Len(t, skipped, 1)
+	assert.Equal(t, "last-read", skipped[0].Name, "export must not spend the last read of secret")
+
+	reader, err := vault.NewReader(bytes.NewReader(archive.Bytes()), "passphrase")
+	require.NoError(t, err)
+
+	readsLeft := make(map[string]int)
+	for {
+		record, _, err := reader.Next()
+		if err != nil {
+			require.ErrorIs(t, err, io.EOF)
+
+			break
+		}
+
+		readsLeft[record.Name] = record.MaxReads
+	}
+	assert.Equal(t, map[string]int{"endless": 0, "limited": 2}, readsLeft, "reads left after read of export must be archived")
+}
@@ -1,6 +1,11 @@
 package secret
 
-import "time"
+import (
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
 
 type SecretType int
 
@@ -11,9 +16,126 @@ const (
 	SecretTypeMedia
 )
 
+// FolderSeparator separates folders in path-like secret names, like 'work/db/prod-root'
+const FolderSeparator = "/"
+
 type Secret struct {
 	SecretType SecretType
-	Name       string
-	Created    time.Time
-	Updated    time.Time
+	// Name full path-like name of secret
+	Name    string
+	Created time.Time
+	Updated time.Time
+
+	Limits Limits
+	// Reads count of secret reads, counts only for secrets with limits
+	Reads int
+
+	// Folder folder of secret computed by name, empty for root folder
+	Folder string
+	Tags   []string
+
+	// Size count of bytes stored by service, for media it's size of encrypted content, 0 for plain secrets
+	Size int64
+	// Media info of media given by client at upload, nil for plain secrets and media uploaded without info
+	Media *MediaInfo
+}
+
+// MediaInfo info of media captured by client at upload, service stores it encrypted
+type MediaInfo struct {
+	// Size size of plain media content
+	Size int64 `json:"size"`
+	// ContentType MIME type sniffed from media content
+	ContentType string `json:"content_type"`
+	// Checksum hex encoded SHA-256 of plain media content
+	Checksum string `json:"checksum"`
+	// OriginalPath absolute path of uploaded file
+	OriginalPath string `json:"original_path,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// Limits lifetime restrictions of secret, zero values mean no restrictions
+type Limits struct {
+	// ExpiresAt time after that secret will be removed
+	ExpiresAt time.Time
+	// MaxReads count of reads after that secret will be removed
+	MaxReads int
+}
+
+// Sort order of secrets list. Secrets are sorted by service, except names: service keeps them encrypted, so client sorts them
+type Sort uint8
+
+const (
+	// SortCreated lists secrets in order of creation
+	SortCreated Sort = iota
+	// SortUpdated lists secrets in order of last update
+	SortUpdated
+	// SortName lists secrets in order of names
+	SortName
+)
+
+// Filter conditions of secrets list, zero value lists all secrets of type
+type Filter struct {
+	// Folder lists only secrets placed directly in folder, nil for secrets of all folders
+	Folder *string
+	// Tag lists only secrets marked by tag, empty for secrets with any tags
+	Tag string
+	// NamePrefix lists only secrets which names start with prefix, empty for secrets with any names
+	NamePrefix string
+
+	Sort Sort
+	// Descending lists latest secrets first
+	Descending bool
+	// Limit max count of listed secrets, 0 for all secrets
+	Limit int
+}
+
+// ByClient reports that filter is applied by client to decrypted names, so service must list all secrets matched by other conditions
+func (f Filter) ByClient() bool {
+	return f.Sort == SortName || f.NamePrefix != ""
+}
+
+// ApplyByClient filters secrets by name prefix, sorts them by name if needed and cuts them to limit
+func (f Filter) ApplyByClient(secrets []Secret) []Secret {
+	filtered := make([]Secret, 0, len(secrets))
+	for _, v := range secrets {
+		if strings.HasPrefix(v.Name, f.NamePrefix) {
+			filtered = append(filtered, v)
+		}
+	}
+
+	if f.Sort == SortName {
+		sort.SliceStable(filtered, func(i, j int) bool {
+			if f.Descending {
+				return filtered[i].Name > filtered[j].Name
+			}
+
+			return filtered[i].Name < filtered[j].Name
+		})
+	}
+
+	if f.Limit > 0 && len(filtered) > f.Limit {
+		filtered = filtered[:f.Limit]
+	}
+
+	return filtered
+}
+
+// FolderOf returns folder of path-like secret name, empty string for root folder
+func FolderOf(name string) string {
+	return CleanFolder(path.Dir(strings.Trim(name, FolderSeparator)))
+}
+
+// BaseName returns name of secret without folder
+func BaseName(name string) string {
+	return path.Base(strings.Trim(name, FolderSeparator))
+}
+
+// CleanFolder normalizes folder path, empty string for root folder
+func CleanFolder(folder string) string {
+	folder = path.Clean(strings.Trim(folder, FolderSeparator))
+	if folder == "." || folder == FolderSeparator {
+		return ""
+	}
+
+	return strings.Trim(folder, FolderSeparator)
 }
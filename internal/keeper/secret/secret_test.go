@@ -0,0 +1,32 @@
+package secret
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFilter_ApplyByClient(t *testing.T) {
+	secrets := []Secret{{Name: "work/db"}, {Name: "home/wifi"}, {Name: "work/api"}, {Name: "work-old"}}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []string
+	}{
+		{name: "sort by name", filter: Filter{Sort: SortName}, expected: []string{"home/wifi", "work-old", "work/api", "work/db"}},
+		{name: "sort by name descending", filter: Filter{Sort: SortName, Descending: true, Limit: 2}, expected: []string{"work/db", "work/api"}},
+		{name: "prefix keeps order of service", filter: Filter{NamePrefix: "work/"}, expected: []string{"work/db", "work/api"}},
+		{name: "prefix with limit", filter: Filter{Sort: SortName, NamePrefix: "work", Limit: 2}, expected: []string{"work-old", "work/api"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := make([]string, 0)
+			for _, v := range tt.filter.ApplyByClient(secrets) {
+				names = append(names, v.Name)
+			}
+
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
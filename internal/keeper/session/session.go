@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/nessai1/gophkeeper/internal/keeper/encrypt"
 	"github.com/nessai1/gophkeeper/pkg/command"
@@ -46,10 +47,59 @@ type userData struct {
 	ServerToken  string `json:"server_token"`
 }
 
+// ErrNoLocalSession returns if there is no saved session in work dir
+var ErrNoLocalSession = errors.New("there is no saved session")
+
+// ErrWrongPassword returns if password doesn't match password of saved session
+var ErrWrongPassword = errors.New("incorrect password of saved session")
+
 func LoadLocalSession(workDir string) (*Session, error) {
-	file, err := os.Open(filepath.Join(workDir, userDataFilename))
+	ud, err := readUserData(workDir)
 	if err != nil {
-		return nil, fmt.Errorf("cannot open session file: %w", err)
+		return nil, err
+	}
+	defer os.Remove(filepath.Join(workDir, userDataFilename))
+
+	password, err := command.AskSecret(fmt.Sprintf("Enter %s password (or leave it blank for new session)", ud.Login))
+	if strings.TrimSpace(password) == "" {
+		return nil, nil
+	}
+
+	session, err := unlockUserData(ud, password)
+	if err != nil {
+		fmt.Printf("\033[31mIncorrect password! Session droped\033[0m\n")
+		return nil, fmt.Errorf("user enter incorrect password for existing session")
+	}
+
+	return session, nil
+}
+
+// UnlockLocalSession unlocks saved session by password without prompt, saved session is kept in work dir
+func UnlockLocalSession(workDir string, password string) (*Session, error) {
+	ud, err := readUserData(workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return unlockUserData(ud, password)
+}
+
+// RemoveLocalSession removes saved session from work dir
+func RemoveLocalSession(workDir string) error {
+	err := os.Remove(filepath.Join(workDir, userDataFilename))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("cannot remove user data file: %w", err)
+	}
+
+	return nil
+}
+
+func readUserData(workDir string) (userData, error) {
+	file, err := os.Open(filepath.Join(workDir, userDataFilename))
+	if errors.Is(err, os.ErrNotExist) {
+		return userData{}, ErrNoLocalSession
+	} else if err != nil {
+		return userData{}, fmt.Errorf("cannot open session file: %w", err)
 	}
 	defer file.Close()
 
@@ -57,27 +107,23 @@ func LoadLocalSession(workDir string) (*Session, error) {
 	n, err := b.ReadFrom(file)
 
 	if n == 0 {
-		return nil, fmt.Errorf("cannot open session file: is empty")
+		return userData{}, fmt.Errorf("cannot open session file: is empty")
 	} else if err != nil {
-		return nil, fmt.Errorf("cannot read session file: %w", err)
+		return userData{}, fmt.Errorf("cannot read session file: %w", err)
 	}
 
-	defer os.Remove(file.Name())
 	var ud userData
-
 	err = json.Unmarshal(b.Bytes(), &ud)
 	if err != nil {
-		return nil, fmt.Errorf("cannot unmarshal session file")
+		return userData{}, fmt.Errorf("cannot unmarshal session file")
 	}
 
-	password, err := command.AskSecret(fmt.Sprintf("Enter %s password (or leave it blank for new session)", ud.Login))
-	if strings.TrimSpace(password) == "" {
-		return nil, nil
-	}
+	return ud, nil
+}
 
+func unlockUserData(ud userData, password string) (*Session, error) {
 	if hashPassword(password) != ud.PasswordHash {
-		fmt.Printf("\033[31mIncorrect password! Session droped\033[0m\n")
-		return nil, fmt.Errorf("user enter incorrect password for existing session")
+		return nil, ErrWrongPassword
 	}
 
 	session := Session{
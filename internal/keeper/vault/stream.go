@@ -0,0 +1,239 @@
+package vault
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"io"
+)
+
+// headerMagic starts every vault archive, last byte is version of format
+const headerMagic = "\x00GKVAULT\x01"
+
+const (
+	saltSize = 16
+	// headerSize size of magic, parameters of key derivation and salt
+	headerSize = len(headerMagic) + 4 + 4 + 1 + saltSize
+	// segmentSize size of plain content sealed by one segment
+	segmentSize = 64 * 1024
+	tagSize     = 16
+)
+
+// ErrWrongPassphrase returns if the first segment of archive can't be opened by key of passphrase
+var ErrWrongPassphrase = errors.New("wrong passphrase of vault archive")
+
+// ErrDamagedArchive returns if archive isn't vault archive, some segment of it is modified or archive is truncated
+var ErrDamagedArchive = errors.New("vault archive is damaged")
+
+// kdfParams parameters of argon2id, they are stored in header of archive, so they can be raised for new archives
+type kdfParams struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+var defaultKDFParams = kdfParams{time: 3, memory: 64 * 1024, threads: 4}
+
+// maxKDFParams limits of parameters read from archive, so damaged or crafted header can't make key derivation
+// take too much memory or time. Memory is 1 GiB in KiB
+var maxKDFParams = kdfParams{time: 16, memory: 1024 * 1024, threads: 16}
+
+// header of archive, its raw bytes authenticate every segment
+type header struct {
+	params kdfParams
+	salt   []byte
+	raw    []byte
+}
+
+func newHeader(params kdfParams) (header, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return header{}, fmt.Errorf("cannot generate salt: %w", err)
+	}
+
+	raw := make([]byte, 0, headerSize)
+	raw = append(raw, headerMagic...)
+	raw = binary.BigEndian.AppendUint32(raw, params.time)
+	raw = binary.BigEndian.AppendUint32(raw, params.memory)
+	raw = append(raw, params.threads)
+	raw = append(raw, salt...)
+
+	return header{params: params, salt: salt, raw: raw}, nil
+}
+
+func readHeader(r io.Reader) (header, error) {
+	raw := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, raw); err != nil {
+		return header{}, fmt.Errorf("%w: cannot read header: %w", ErrDamagedArchive, err)
+	}
+
+	if string(raw[:len(headerMagic)]) != headerMagic {
+		return header{}, fmt.Errorf("%w: file isn't vault archive or has unsupported version", ErrDamagedArchive)
+	}
+
+	params := raw[len(headerMagic):]
+	h := header{
+		params: kdfParams{
+			time:    binary.BigEndian.Uint32(params[0:4]),
+			memory:  binary.BigEndian.Uint32(params[4:8]),
+			threads: params[8],
+		},
+		salt: raw[headerSize-saltSize:],
+		raw:  raw,
+	}
+
+	if h.params.time == 0 || h.params.memory == 0 || h.params.threads == 0 {
+		return header{}, fmt.Errorf("%w: invalid parameters of key derivation", ErrDamagedArchive)
+	}
+
+	if h.params.time > maxKDFParams.time || h.params.memory > maxKDFParams.memory || h.params.threads > maxKDFParams.threads {
+		return header{}, fmt.Errorf("%w: parameters of key derivation exceed limits", ErrDamagedArchive)
+	}
+
+	return h, nil
+}
+
+// aead derives key of archive from passphrase by argon2id
+func (h header) aead(passphrase string) (cipher.AEAD, error) {
+	key := argon2.IDKey([]byte(passphrase), h.salt, h.params.time, h.params.memory, h.params.threads, 32)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create cipher of archive: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create GCM of archive: %w", err)
+	}
+
+	return gcm, nil
+}
+
+// segmentNonce builds nonce of segment by its index, the last segment is marked, so truncation of archive is detected
+func segmentNonce(index uint64, final bool) []byte {
+	nonce := make([]byte, 12)
+	if final {
+		nonce[0] = 1
+	}
+	binary.BigEndian.PutUint64(nonce[4:], index)
+
+	return nonce
+}
+
+// encryptWriter seals content by segments, the last segment is sealed by Close, it's empty for empty content
+type encryptWriter struct {
+	w     io.Writer
+	aead  cipher.AEAD
+	aad   []byte
+	buf   []byte
+	index uint64
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		// full segment is sealed only when next content arrives, because the last segment is sealed with final nonce
+		if len(e.buf) == segmentSize {
+			if err := e.seal(false); err != nil {
+				return written, err
+			}
+		}
+
+		n := min(segmentSize-len(e.buf), len(p))
+		e.buf = append(e.buf, p[:n]...)
+		p = p[n:]
+		written += n
+	}
+
+	return written, nil
+}
+
+func (e *encryptWriter) Close() error {
+	return e.seal(true)
+}
+
+func (e *encryptWriter) seal(final bool) error {
+	sealed := e.aead.Seal(nil, segmentNonce(e.index, final), e.buf, e.aad)
+	if _, err := e.w.Write(sealed); err != nil {
+		return fmt.Errorf("cannot write segment of archive: %w", err)
+	}
+
+	e.index++
+	e.buf = e.buf[:0]
+
+	return nil
+}
+
+// decryptReader opens segments sealed by encryptWriter
+type decryptReader struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	aad     []byte
+	sealed  []byte
+	plain   []byte
+	index   uint64
+	pending []byte
+	final   bool
+}
+
+func newDecryptReader(r io.Reader, aead cipher.AEAD, aad []byte) *decryptReader {
+	return &decryptReader{
+		r:      bufio.NewReader(r),
+		aead:   aead,
+		aad:    aad,
+		sealed: make([]byte, segmentSize+tagSize),
+		plain:  make([]byte, 0, segmentSize),
+	}
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.pending) == 0 {
+		if d.final {
+			return 0, io.EOF
+		}
+
+		if err := d.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.pending)
+	d.pending = d.pending[n:]
+
+	return n, nil
+}
+
+// open reads and opens next segment, segment is final if nothing follows it
+func (d *decryptReader) open() error {
+	n, err := io.ReadFull(d.r, d.sealed)
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		d.final = true
+	} else if err != nil {
+		return fmt.Errorf("cannot read segment of archive: %w", err)
+	} else if _, err = d.r.Peek(1); errors.Is(err, io.EOF) {
+		d.final = true
+	} else if err != nil {
+		return fmt.Errorf("cannot read segment of archive: %w", err)
+	}
+
+	if n < tagSize {
+		return fmt.Errorf("%w: archive is truncated", ErrDamagedArchive)
+	}
+
+	plain, err := d.aead.Open(d.plain[:0], segmentNonce(d.index, d.final), d.sealed[:n], d.aad)
+	if err != nil && d.index == 0 {
+		return ErrWrongPassphrase
+	} else if err != nil {
+		return fmt.Errorf("%w: segment %d can't be opened", ErrDamagedArchive, d.index)
+	}
+
+	d.index++
+	d.pending = plain
+
+	return nil
+}
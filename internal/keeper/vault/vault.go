@@ -0,0 +1,253 @@
+// Package vault makes portable archive of decrypted user secrets. Archive is encrypted by key derived from separate
+// export passphrase, so it doesn't depend on account and can be imported into any account
+package vault
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/keeper/secret"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Prefixes of archive entries: every secret has record entry, content of media follows its record
+const (
+	recordsPrefix = "records/"
+	mediaPrefix   = "media/"
+)
+
+// Record secret stored by archive
+type Record struct {
+	Type secret.SecretType `json:"type"`
+	Name string            `json:"name"`
+	Tags []string          `json:"tags,omitempty"`
+
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+	// ExpiresAt lifetime of secret, nil for secrets without lifetime
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// MaxReads count of reads left after export, 0 for secrets with unlimited reads
+	MaxReads int `json:"max_reads,omitempty"`
+
+	// Data decrypted data of credentials, card or text, nil for media
+	Data json.RawMessage `json:"data,omitempty"`
+	// Media info of media, nil for plain secrets and media uploaded without info
+	Media *secret.MediaInfo `json:"media,omitempty"`
+}
+
+// Writer writes records to encrypted archive
+type Writer struct {
+	stream *encryptWriter
+	tw     *tar.Writer
+	count  int
+}
+
+// NewWriter writes header of archive to w and returns writer of its records
+func NewWriter(w io.Writer, passphrase string) (*Writer, error) {
+	return newWriter(w, passphrase, defaultKDFParams)
+}
+
+func newWriter(w io.Writer, passphrase string, params kdfParams) (*Writer, error) {
+	h, err := newHeader(params)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := h.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = w.Write(h.raw); err != nil {
+		return nil, fmt.Errorf("cannot write header of archive: %w", err)
+	}
+
+	stream := &encryptWriter{w: w, aead: aead, aad: h.raw, buf: make([]byte, 0, segmentSize)}
+
+	return &Writer{stream: stream, tw: tar.NewWriter(stream)}, nil
+}
+
+// AddSecret writes record of credentials, card or text
+func (w *Writer) AddSecret(record Record) error {
+	if record.Type == secret.SecretTypeMedia {
+		return fmt.Errorf("media %s must be added with its content", record.Name)
+	}
+
+	return w.addRecord(record)
+}
+
+// AddMedia writes record of media and its content of size
+func (w *Writer) AddMedia(record Record, size int64, content io.Reader) error {
+	if record.Type != secret.SecretTypeMedia {
+		return fmt.Errorf("secret %s isn't media", record.Name)
+	}
+
+	if err := w.addRecord(record); err != nil {
+		return err
+	}
+
+	if err := w.addEntry(mediaPrefix+strconv.Itoa(w.count), size, content); err != nil {
+		return fmt.Errorf("cannot write content of media %s: %w", record.Name, err)
+	}
+
+	return nil
+}
+
+// Close finishes archive, underlying writer isn't closed
+func (w *Writer) Close() error {
+	if err := w.tw.Close(); err != nil {
+		return fmt.Errorf("cannot close archive: %w", err)
+	}
+
+	return w.stream.Close()
+}
+
+func (w *Writer) addRecord(record Record) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("cannot marshal record of %s: %w", record.Name, err)
+	}
+
+	w.count++
+	if err = w.addEntry(recordsPrefix+strconv.Itoa(w.count)+".json", int64(len(raw)), bytes.NewReader(raw)); err != nil {
+		return fmt.Errorf("cannot write record of %s: %w", record.Name, err)
+	}
+
+	return nil
+}
+
+func (w *Writer) addEntry(name string, size int64, content io.Reader) error {
+	err := w.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     0600,
+		ModTime:  time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot write header of entry: %w", err)
+	}
+
+	n, err := io.Copy(w.tw, io.LimitReader(content, size))
+	if err != nil {
+		return fmt.Errorf("cannot write content of entry: %w", err)
+	}
+
+	if n != size {
+		return fmt.Errorf("entry has %d bytes, but %d bytes are written", size, n)
+	}
+
+	return nil
+}
+
+// Reader reads records of encrypted archive
+type Reader struct {
+	stream *decryptReader
+	tr     *tar.Reader
+}
+
+// NewReader reads header of archive and derives its key from passphrase. Wrong passphrase is detected
+// by the first Next, it returns ErrWrongPassphrase
+func NewReader(r io.Reader, passphrase string) (*Reader, error) {
+	h, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := h.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	stream := newDecryptReader(r, aead, h.raw)
+
+	return &Reader{stream: stream, tr: tar.NewReader(stream)}, nil
+}
+
+// Next returns next record of archive and reader of content for media, content must be read before next call.
+// Returns io.EOF after the last record
+func (r *Reader) Next() (Record, io.Reader, error) {
+	hdr, err := r.tr.Next()
+	if errors.Is(err, io.EOF) {
+		// rest of stream is read to check that archive isn't truncated after end of tar
+		if _, err = io.Copy(io.Discard, r.stream); err != nil {
+			return Record{}, nil, r.wrapError(err)
+		}
+
+		return Record{}, nil, io.EOF
+	} else if err != nil {
+		return Record{}, nil, r.wrapError(err)
+	}
+
+	index, ok := strings.CutPrefix(hdr.Name, recordsPrefix)
+	if !ok {
+		return Record{}, nil, fmt.Errorf("%w: unexpected entry %s", ErrDamagedArchive, hdr.Name)
+	}
+	index = strings.TrimSuffix(index, ".json")
+
+	var record Record
+	if err = json.NewDecoder(r.tr).Decode(&record); err != nil {
+		return Record{}, nil, r.wrapError(err)
+	}
+
+	if record.Type < secret.SecretTypeCredentials || record.Type > secret.SecretTypeMedia || strings.TrimSpace(record.Name) == "" {
+		return Record{}, nil, fmt.Errorf("%w: invalid record %s", ErrDamagedArchive, hdr.Name)
+	}
+
+	if record.Type != secret.SecretTypeMedia {
+		return record, nil, nil
+	}
+
+	hdr, err = r.tr.Next()
+	if err != nil {
+		return Record{}, nil, fmt.Errorf("cannot read content of media %s: %w", record.Name, r.wrapError(err))
+	}
+
+	if hdr.Name != mediaPrefix+index {
+		return Record{}, nil, fmt.Errorf("%w: media %s has no content", ErrDamagedArchive, record.Name)
+	}
+
+	return record, r.tr, nil
+}
+
+// wrapError keeps errors of decryption and marks others as damage of archive
+func (r *Reader) wrapError(err error) error {
+	if errors.Is(err, ErrWrongPassphrase) || errors.Is(err, ErrDamagedArchive) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", ErrDamagedArchive, err)
+}
+
+// UniqueName returns name if it isn't taken, otherwise adds number to base name before extension,
+// like 'work/photo-2.jpg'
+func UniqueName(name string, taken func(name string) bool) string {
+	if !taken(name) {
+		return name
+	}
+
+	folder := secret.FolderOf(name)
+	base := secret.BaseName(name)
+	ext := path.Ext(base)
+	if ext == base {
+		ext = ""
+	}
+	base = strings.TrimSuffix(base, ext)
+
+	for i := 2; ; i++ {
+		candidate := base + "-" + strconv.Itoa(i) + ext
+		if folder != "" {
+			candidate = folder + secret.FolderSeparator + candidate
+		}
+
+		if !taken(candidate) {
+			return candidate
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"github.com/nessai1/gophkeeper/internal/keeper/secret"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"testing"
+	"time"
+)
+
+// testKDFParams cheap parameters of key derivation for tests
+var testKDFParams = kdfParams{time: 1, memory: 64, threads: 1}
+
+type archivedRecord struct {
+	record  Record
+	content []byte
+}
+
+func writeArchive(t *testing.T, passphrase string, records []archivedRecord) []byte {
+	archive := bytes.Buffer{}
+	w, err := newWriter(&archive, passphrase, testKDFParams)
+	require.NoError(t, err)
+
+	for _, v := range records {
+		if v.record.Type == secret.SecretTypeMedia {
+			require.NoError(t, w.AddMedia(v.record, int64(len(v.content)), bytes.NewReader(v.content)))
+		} else {
+			require.NoError(t, w.AddSecret(v.record))
+		}
+	}
+	require.NoError(t, w.Close())
+
+	return archive.Bytes()
+}
+
+func readArchive(archive []byte, passphrase string) ([]archivedRecord, error) {
+	r, err := NewReader(bytes.NewReader(archive), passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []archivedRecord
+	for {
+		record, content, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		read := archivedRecord{record: record}
+		if content != nil {
+			if read.content, err = io.ReadAll(content); err != nil {
+				return nil, err
+			}
+		}
+		records = append(records, read)
+	}
+}
+
+func TestVault(t *testing.T) {
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	bigMedia := bytes.Repeat([]byte("media content "), segmentSize/5)
+	records := []archivedRecord{
+		{record: Record{Type: secret.SecretTypeCredentials, Name: "work/github", Tags: []string{"dev"}, Data: json.RawMessage(`{"login":"user","password":"pass"}`)}},
+		{record: Record{Type: secret.SecretTypeMedia, Name: "photo.jpg", ExpiresAt: &expires, Media: &secret.MediaInfo{Size: int64(len(bigMedia)), ContentType: "image/jpeg"}}, content: bigMedia},
+		{record: Record{Type: secret.SecretTypeMedia, Name: "empty.txt"}, content: []byte{}},
+		{record: Record{Type: secret.SecretTypeText, Name: "notes", Data: json.RawMessage(`{"text":"secret text"}`)}},
+	}
+
+	archive := writeArchive(t, "export passphrase", records)
+	assert.False(t, bytes.Contains(archive, []byte("secret text")), "content of archive must be encrypted")
+	assert.False(t, bytes.Contains(archive, []byte("work/github")), "names of secrets must be encrypted")
+
+	read, err := readArchive(archive, "export passphrase")
+	require.NoError(t, err)
+	require.Len(t, read, len(records))
+	for i, v := range records {
+		assert.Equal(t, v.record.Name, read[i].record.Name)
+		assert.Equal(t, v.record.Type, read[i].record.Type)
+		assert.Equal(t, v.record.Tags, read[i].record.Tags)
+		assert.JSONEq(t, string(orEmpty(v.record.Data)), string(orEmpty(read[i].record.Data)))
+		assert.Equal(t, v.record.Media, read[i].record.Media)
+		assert.Equal(t, v.content, read[i].content)
+	}
+	assert.True(t, expires.Equal(*read[1].record.ExpiresAt))
+
+	_, err = readArchive(archive, "other passphrase")
+	assert.ErrorIs(t, err, ErrWrongPassphrase)
+}
+
+func orEmpty(data json.RawMessage) json.RawMessage {
+	if data == nil {
+		return json.RawMessage("null")
+	}
+
+	return data
+}
+
+func TestVault_Damaged(t *testing.T) {
+	content := bytes.Repeat([]byte{7}, 3*segmentSize)
+	archive := writeArchive(t, "passphrase", []archivedRecord{
+		{record: Record{Type: secret.SecretTypeMedia, Name: "blob"}, content: content},
+	})
+
+	tests := []struct {
+		name    string
+		archive []byte
+		// damagedHeader archive must be rejected by header before key derivation
+		damagedHeader bool
+	}{
+		{name: "Truncated by segment", archive: archive[:headerSize+2*(segmentSize+tagSize)]},
+		{name: "Truncated inside segment", archive: archive[:len(archive)-10]},
+		{name: "Modified segment", archive: func() []byte {
+			modified := bytes.Clone(archive)
+			modified[headerSize+segmentSize+tagSize+100] ^= 1
+
+			return modified
+		}()},
+		{name: "Modified salt", archive: func() []byte {
+			modified := bytes.Clone(archive)
+			modified[headerSize-1] ^= 1
+
+			return modified
+		}()},
+		{name: "Too much memory of key derivation", archive: func() []byte {
+			modified := bytes.Clone(archive)
+			binary.BigEndian.PutUint32(modified[len(headerMagic)+4:], maxKDFParams.memory+1)
+
+			return modified
+		}(), damagedHeader: true},
+		{name: "Too many iterations of key derivation", archive: func() []byte {
+			modified := bytes.Clone(archive)
+			binary.BigEndian.PutUint32(modified[len(headerMagic):], 1<<30)
+
+			return modified
+		}(), damagedHeader: true},
+		{name: "Not vault archive", archive: []byte("plain file content of some other format")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.damagedHeader {
+				_, err := readHeader(bytes.NewReader(tt.archive))
+				assert.ErrorIs(t, err, ErrDamagedArchive)
+			}
+
+			_, err := readArchive(tt.archive, "passphrase")
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrDamagedArchive) || errors.Is(err, ErrWrongPassphrase), "unexpected error: %s", err)
+		})
+	}
+}
+
+func TestUniqueName(t *testing.T) {
+	taken := map[string]bool{"notes": true, "notes-2": true, "work/photo.jpg": true, ".env": true}
+	isTaken := func(name string) bool {
+		return taken[name]
+	}
+
+	assert.Equal(t, "free", UniqueName("free", isTaken))
+	assert.Equal(t, "notes-3", UniqueName("notes", isTaken))
+	assert.Equal(t, "work/photo-2.jpg", UniqueName("work/photo.jpg", isTaken))
+	assert.Equal(t, ".env-2", UniqueName(".env", isTaken))
+}
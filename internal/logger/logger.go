@@ -62,6 +62,6 @@ func translateApplicationLevelToLogLevel(level ApplicationLevel) (zapcore.Level,
 	case LevelDev:
 		return zapcore.DebugLevel, nil
 	default:
-		return 0, fmt.Errorf("cannot translate appLevel (%d) to logLevel: %w", level, ErrUndefinedAppLevel)
+		return 0, fmt.Errorf("cannot translate appLevel (%s) to logLevel: %w", level, ErrUndefinedAppLevel)
 	}
 }
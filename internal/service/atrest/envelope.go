@@ -0,0 +1,122 @@
+package atrest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// envelopeMagic prefix of encrypted plain secret data. Data without prefix is stored before encryption was enabled
+// and is read as is
+var envelopeMagic = []byte("\x00GKREST1")
+
+// envelopeHeaderSize size of envelope before encrypted data: magic, KEK identifier, wrapped data key and nonce
+const envelopeHeaderSize = 8 + kekIDSize + wrappedKeySize + 12
+
+// EnvelopeOverhead count of bytes that envelope adds to data: header and AES-GCM tag
+const EnvelopeOverhead = envelopeHeaderSize + 16
+
+// ErrInvalidEnvelope returns for encrypted data that can't be parsed
+var ErrInvalidEnvelope = errors.New("invalid envelope of encrypted data")
+
+// IsSealed reports that data is encrypted by Seal
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, envelopeMagic)
+}
+
+// Seal encrypts data by new data key, envelope contains data key wrapped by current key-encryption key.
+// Envelope is bound to aad, so it can't be opened with other aad, like data of other secret
+func (k *Keyring) Seal(data []byte, aad []byte) ([]byte, error) {
+	dataKey, kekID, wrapped, err := k.newDataKey()
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := make([]byte, envelopeHeaderSize, envelopeHeaderSize+len(data)+aead.Overhead())
+	copy(envelope, envelopeMagic)
+	copy(envelope[len(envelopeMagic):], kekID)
+	copy(envelope[len(envelopeMagic)+kekIDSize:], wrapped)
+
+	nonce := envelope[envelopeHeaderSize-aead.NonceSize():]
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("cannot generate nonce: %w", err)
+	}
+
+	return aead.Seal(envelope, nonce, data, aad), nil
+}
+
+// Open decrypts envelope made by Seal with same aad, data that isn't sealed is returned as is
+func (k *Keyring) Open(data []byte, aad []byte) ([]byte, error) {
+	if !IsSealed(data) {
+		return data, nil
+	}
+
+	if len(data) < envelopeHeaderSize {
+		return nil, ErrInvalidEnvelope
+	}
+
+	kekID, wrapped := parseEnvelope(data)
+	dataKey, err := k.UnwrapKey(kekID, wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := aead.Open(nil, data[envelopeHeaderSize-aead.NonceSize():envelopeHeaderSize], data[envelopeHeaderSize:], aad)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt data: %w", err)
+	}
+
+	return plain, nil
+}
+
+// Rewrap returns envelope with data key wrapped by current key-encryption key, encrypted data is not changed.
+// Data that isn't sealed is sealed with aad. Returns false if data is already wrapped by current key
+func (k *Keyring) Rewrap(data []byte, aad []byte) ([]byte, bool, error) {
+	if !IsSealed(data) {
+		sealed, err := k.Seal(data, aad)
+
+		return sealed, err == nil, err
+	}
+
+	if len(data) < envelopeHeaderSize {
+		return nil, false, ErrInvalidEnvelope
+	}
+
+	kekID, wrapped := parseEnvelope(data)
+	if kekID == k.current {
+		return data, false, nil
+	}
+
+	dataKey, err := k.UnwrapKey(kekID, wrapped)
+	if err != nil {
+		return nil, false, err
+	}
+
+	kekID, wrapped, err = k.WrapKey(dataKey)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rewrapped := bytes.Clone(data)
+	copy(rewrapped[len(envelopeMagic):], kekID)
+	copy(rewrapped[len(envelopeMagic)+kekIDSize:], wrapped)
+
+	return rewrapped, true, nil
+}
+
+func parseEnvelope(data []byte) (kekID string, wrapped []byte) {
+	header := data[len(envelopeMagic):]
+
+	return string(header[:kekIDSize]), header[kekIDSize : kekIDSize+wrappedKeySize]
+}
@@ -0,0 +1,177 @@
+// Package atrest encrypts plain secrets data and media objects before they reach storages. Every secret and object
+// is encrypted by its own data key (DEK), data keys are wrapped by key-encryption key (KEK) of service, so rotation
+// of KEK rewraps data keys without re-encryption of content
+package atrest
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/service/config"
+	"os"
+)
+
+// KeySize size of key-encryption keys and data keys, both are AES-256 keys
+const KeySize = 32
+
+// kekIDSize length of hex encoded identifier of key-encryption key
+const kekIDSize = 16
+
+// wrappedKeySize size of data key wrapped by AES-GCM: nonce, encrypted key and tag
+const wrappedKeySize = 12 + KeySize + 16
+
+// ErrUnknownKEK returns if data key is wrapped by key-encryption key that is not in keyring
+var ErrUnknownKEK = errors.New("unknown key-encryption key")
+
+// Keyring key-encryption keys of service: current key wraps new data keys, previous keys only unwrap data keys
+// wrapped before rotation
+type Keyring struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+func NewKeyring(current []byte, previous ...[]byte) (*Keyring, error) {
+	k := &Keyring{current: KEKID(current), keys: make(map[string]cipher.AEAD)}
+	for _, key := range append([][]byte{current}, previous...) {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key-encryption key: %w", err)
+		}
+
+		k.keys[KEKID(key)] = aead
+	}
+
+	return k, nil
+}
+
+// LoadKeyring loads key-encryption keys of config, key file is preferred if both key and key file are set
+func LoadKeyring(cfg config.EncryptionConfig) (*Keyring, error) {
+	current, err := loadKey(cfg.Key, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load current key: %w", err)
+	}
+
+	var previous [][]byte
+	for i, v := range cfg.PreviousKeys {
+		key, err := loadKey(v, "")
+		if err != nil {
+			return nil, fmt.Errorf("cannot load previous key %d: %w", i, err)
+		}
+
+		previous = append(previous, key)
+	}
+
+	for _, path := range cfg.PreviousKeyFiles {
+		key, err := loadKey("", path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load previous key %s: %w", path, err)
+		}
+
+		previous = append(previous, key)
+	}
+
+	return NewKeyring(current, previous...)
+}
+
+func loadKey(encoded string, path string) ([]byte, error) {
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read key file: %w", err)
+		}
+
+		if len(raw) == KeySize {
+			return raw, nil
+		}
+
+		encoded = string(bytes.TrimSpace(raw))
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("key must be encoded by base64: %w", err)
+	}
+
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must have %d bytes, got %d", KeySize, len(key))
+	}
+
+	return key, nil
+}
+
+// KEKID returns identifier of key-encryption key, identifier is stored with wrapped keys and doesn't reveal key
+func KEKID(key []byte) string {
+	sum := sha256.Sum256(append([]byte("gophkeeper kek id:"), key...))
+
+	return hex.EncodeToString(sum[:kekIDSize/2])
+}
+
+// CurrentID returns identifier of current key-encryption key
+func (k *Keyring) CurrentID() string {
+	return k.current
+}
+
+// WrapKey encrypts data key by current key-encryption key
+func (k *Keyring) WrapKey(dataKey []byte) (kekID string, wrapped []byte, err error) {
+	aead := k.keys[k.current]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", nil, fmt.Errorf("cannot generate nonce: %w", err)
+	}
+
+	return k.current, aead.Seal(nonce, nonce, dataKey, []byte(k.current)), nil
+}
+
+// UnwrapKey decrypts data key wrapped by key-encryption key with identifier kekID
+func (k *Keyring) UnwrapKey(kekID string, wrapped []byte) ([]byte, error) {
+	aead, ok := k.keys[kekID]
+	if !ok {
+		return nil, fmt.Errorf("data key is wrapped by key %s: %w", kekID, ErrUnknownKEK)
+	}
+
+	if len(wrapped) != wrappedKeySize {
+		return nil, fmt.Errorf("wrapped data key must have %d bytes, got %d", wrappedKeySize, len(wrapped))
+	}
+
+	nonceSize := aead.NonceSize()
+	dataKey, err := aead.Open(nil, wrapped[:nonceSize], wrapped[nonceSize:], []byte(kekID))
+	if err != nil {
+		return nil, fmt.Errorf("cannot unwrap data key: %w", err)
+	}
+
+	return dataKey, nil
+}
+
+// newDataKey generates data key and wraps it by current key-encryption key
+func (k *Keyring) newDataKey() (dataKey []byte, kekID string, wrapped []byte, err error) {
+	dataKey = make([]byte, KeySize)
+	if _, err = rand.Read(dataKey); err != nil {
+		return nil, "", nil, fmt.Errorf("cannot generate data key: %w", err)
+	}
+
+	kekID, wrapped, err = k.WrapKey(dataKey)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return dataKey, kekID, wrapped, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must have %d bytes, got %d", KeySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,112 @@
+package atrest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"github.com/nessai1/gophkeeper/internal/service/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestKeyring(t *testing.T, seed byte, previous ...byte) *Keyring {
+	var previousKeys [][]byte
+	for _, v := range previous {
+		previousKeys = append(previousKeys, bytes.Repeat([]byte{v}, KeySize))
+	}
+
+	keyring, err := NewKeyring(bytes.Repeat([]byte{seed}, KeySize), previousKeys...)
+	require.NoError(t, err)
+
+	return keyring
+}
+
+func TestKeyring_Seal(t *testing.T) {
+	keyring := newTestKeyring(t, 1)
+
+	sealed, err := keyring.Seal([]byte("secret data"), []byte("secret-uuid"))
+	require.NoError(t, err)
+	assert.True(t, IsSealed(sealed))
+	assert.NotContains(t, string(sealed), "secret data")
+	assert.Len(t, sealed, len("secret data")+EnvelopeOverhead)
+
+	data, err := keyring.Open(sealed, []byte("secret-uuid"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret data"), data)
+
+	_, err = keyring.Open(sealed, []byte("other-uuid"))
+	assert.Error(t, err, "data must not be decrypted as data of other secret")
+
+	data, err = keyring.Open([]byte("stored before encryption"), []byte("secret-uuid"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("stored before encryption"), data, "not sealed data must be read as is")
+
+	sealed[len(sealed)-1] ^= 1
+	_, err = keyring.Open(sealed, []byte("secret-uuid"))
+	assert.Error(t, err, "changed data must not be decrypted")
+
+	_, err = newTestKeyring(t, 2).Open(sealed, []byte("secret-uuid"))
+	assert.ErrorIs(t, err, ErrUnknownKEK)
+
+	_, err = keyring.Open(envelopeMagic, []byte("secret-uuid"))
+	assert.ErrorIs(t, err, ErrInvalidEnvelope)
+}
+
+func TestKeyring_Rewrap(t *testing.T) {
+	old := newTestKeyring(t, 1)
+	sealed, err := old.Seal([]byte("secret data"), []byte("secret-uuid"))
+	require.NoError(t, err)
+
+	rotated := newTestKeyring(t, 2, 1)
+	rewrapped, changed, err := rotated.Rewrap(sealed, []byte("secret-uuid"))
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, sealed[envelopeHeaderSize:], rewrapped[envelopeHeaderSize:], "encrypted data must not be changed by rewrap")
+
+	data, err := newTestKeyring(t, 2).Open(rewrapped, []byte("secret-uuid"))
+	require.NoError(t, err, "rewrapped data must be decrypted without previous key")
+	assert.Equal(t, []byte("secret data"), data)
+
+	_, changed, err = rotated.Rewrap(rewrapped, []byte("secret-uuid"))
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	encrypted, changed, err := rotated.Rewrap([]byte("stored before encryption"), []byte("secret-uuid"))
+	require.NoError(t, err)
+	assert.True(t, changed, "not sealed data must be sealed")
+	data, err = rotated.Open(encrypted, []byte("secret-uuid"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("stored before encryption"), data)
+}
+
+func TestLoadKeyring(t *testing.T) {
+	dir := t.TempDir()
+	current := bytes.Repeat([]byte{1}, KeySize)
+	previous := bytes.Repeat([]byte{2}, KeySize)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "raw.key"), current, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "base64.key"), []byte(base64.StdEncoding.EncodeToString(previous)+"\n"), 0600))
+
+	keyring, err := LoadKeyring(config.EncryptionConfig{
+		KeyFile:          filepath.Join(dir, "raw.key"),
+		PreviousKeyFiles: []string{filepath.Join(dir, "base64.key")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, KEKID(current), keyring.CurrentID())
+	assert.Len(t, keyring.keys, 2)
+
+	keyring, err = LoadKeyring(config.EncryptionConfig{
+		Key:          base64.StdEncoding.EncodeToString(previous),
+		PreviousKeys: []string{base64.StdEncoding.EncodeToString(current)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, KEKID(previous), keyring.CurrentID())
+
+	_, err = LoadKeyring(config.EncryptionConfig{Key: base64.StdEncoding.EncodeToString([]byte("short key"))})
+	assert.Error(t, err)
+
+	_, err = LoadKeyring(config.EncryptionConfig{KeyFile: filepath.Join(dir, "missing.key")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,329 @@
+package atrest
+
+import (
+	"context"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"io"
+)
+
+// segmentSize size of content encrypted by one segment, segments are encrypted separately,
+// so range of object is decrypted without read of whole object
+const segmentSize = 64 * 1024
+
+// segmentOverhead size of AES-GCM tag of segment
+const segmentOverhead = 16
+
+// ErrTruncatedObject returns if encrypted object ends before its final segment
+var ErrTruncatedObject = errors.New("encrypted media object is truncated")
+
+// KeyStore keeps data keys of media objects, it's implemented by plain storage
+type KeyStore interface {
+	GetMediaObjectKey(ctx context.Context, objectKey string) (*plainstorage.MediaObjectKey, error)
+	SetMediaObjectKey(ctx context.Context, key plainstorage.MediaObjectKey) error
+	RemoveMediaObjectKey(ctx context.Context, objectKey string) error
+}
+
+// MediaStorage encrypts objects of wrapped storage by segments. Data key of object is stored in key store,
+// object without data key is stored before encryption was enabled and is read as is.
+// Storage doesn't implement Presigner, because clients can't encrypt objects by data keys of service
+type MediaStorage struct {
+	storage mediastorage.MediaStorage
+	keys    KeyStore
+	keyring *Keyring
+}
+
+func NewMediaStorage(storage mediastorage.MediaStorage, keys KeyStore, keyring *Keyring) *MediaStorage {
+	return &MediaStorage{storage: storage, keys: keys, keyring: keyring}
+}
+
+func (s *MediaStorage) StartUpload(ctx context.Context, key string) (mediastorage.MultipartUpload, error) {
+	dataKey, kekID, wrapped, err := s.keyring.newDataKey()
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	upload, err := s.storage.StartUpload(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EncryptedUpload{
+		storage: s,
+		upload:  upload,
+		key:     plainstorage.MediaObjectKey{ObjectKey: key, KEKID: kekID, WrappedKey: wrapped},
+		aead:    aead,
+		buf:     make([]byte, 0, segmentSize),
+	}, nil
+}
+
+func (s *MediaStorage) StartDownload(ctx context.Context, key string) (io.ReadCloser, error) {
+	return s.StartDownloadRange(ctx, key, 0, 0)
+}
+
+func (s *MediaStorage) StartDownloadRange(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	if err := mediastorage.ValidateRange(offset, length); err != nil {
+		return nil, err
+	}
+
+	objectKey, err := s.keys.GetMediaObjectKey(ctx, key)
+	if errors.Is(err, plainstorage.ErrEntityNotFound) {
+		return s.storage.StartDownloadRange(ctx, key, offset, length)
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot get data key of object: %w", err)
+	}
+
+	dataKey, err := s.keyring.UnwrapKey(objectKey.KEKID, objectKey.WrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// range of content is read from range of whole segments
+	first := offset / segmentSize
+	var sealedLength int64
+	if length > 0 {
+		last := (offset + length - 1) / segmentSize
+		sealedLength = (last - first + 1) * (segmentSize + segmentOverhead)
+	}
+
+	source, err := s.storage.StartDownloadRange(ctx, key, first*(segmentSize+segmentOverhead), sealedLength)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := int64(-1)
+	if length > 0 {
+		remaining = length
+	}
+
+	return &decryptReader{
+		source:    source,
+		aead:      aead,
+		aad:       []byte(key),
+		index:     uint64(first),
+		skip:      int(offset % segmentSize),
+		remaining: remaining,
+		segment:   make([]byte, segmentSize+segmentOverhead),
+		plainBuf:  make([]byte, 0, segmentSize),
+	}, nil
+}
+
+// Delete removes object and its data key
+func (s *MediaStorage) Delete(ctx context.Context, key string) error {
+	err := s.storage.Delete(ctx, key)
+	if err != nil && !errors.Is(err, mediastorage.ErrObjectNotFound) {
+		return err
+	}
+
+	keyErr := s.keys.RemoveMediaObjectKey(ctx, key)
+	if keyErr != nil && !errors.Is(keyErr, plainstorage.ErrEntityNotFound) {
+		return fmt.Errorf("cannot remove data key of object: %w", keyErr)
+	}
+
+	return err
+}
+
+// List lists objects with sizes of their content, data key of every object is checked to know if object is encrypted
+func (s *MediaStorage) List(ctx context.Context, fn func(info mediastorage.ObjectInfo) error) error {
+	return s.storage.List(ctx, func(info mediastorage.ObjectInfo) error {
+		_, err := s.keys.GetMediaObjectKey(ctx, info.Key)
+		if err == nil {
+			info.Size = contentSize(info.Size)
+		} else if !errors.Is(err, plainstorage.ErrEntityNotFound) {
+			return fmt.Errorf("cannot get data key of object %s: %w", info.Key, err)
+		}
+
+		return fn(info)
+	})
+}
+
+// contentSize returns size of content of encrypted object by size of object
+func contentSize(size int64) int64 {
+	segments := max((size+segmentSize+segmentOverhead-1)/(segmentSize+segmentOverhead), 1)
+
+	return max(size-segments*segmentOverhead, 0)
+}
+
+// segmentNonce returns nonce of segment by its index, final segment has own nonce,
+// so object can't be truncated by segments boundary unnoticed
+func segmentNonce(index uint64, final bool) []byte {
+	nonce := make([]byte, 12)
+	if final {
+		nonce[0] = 1
+	}
+	binary.BigEndian.PutUint64(nonce[4:], index)
+
+	return nonce
+}
+
+// EncryptedUpload encrypts content of upload by segments, the last segment is kept until next content or
+// complete of upload, because the final segment is encrypted by own nonce
+type EncryptedUpload struct {
+	storage *MediaStorage
+	upload  mediastorage.MultipartUpload
+	key     plainstorage.MediaObjectKey
+	aead    cipher.AEAD
+
+	index uint64
+	buf   []byte
+}
+
+func (u *EncryptedUpload) Upload(ctx context.Context, content []byte) error {
+	var sealed []byte
+	for len(content) > 0 {
+		if len(u.buf) == segmentSize {
+			sealed = u.seal(sealed, false)
+		}
+
+		n := min(segmentSize-len(u.buf), len(content))
+		u.buf = append(u.buf, content[:n]...)
+		content = content[n:]
+	}
+
+	if len(sealed) == 0 {
+		return nil
+	}
+
+	return u.upload.Upload(ctx, sealed)
+}
+
+func (u *EncryptedUpload) seal(dst []byte, final bool) []byte {
+	dst = u.aead.Seal(dst, segmentNonce(u.index, final), u.buf, []byte(u.key.ObjectKey))
+	u.index++
+	u.buf = u.buf[:0]
+
+	return dst
+}
+
+// Complete stores data key of object before complete of upload, so completed object is always readable.
+// Data key is removed if upload can't be completed
+func (u *EncryptedUpload) Complete(ctx context.Context) error {
+	if err := u.upload.Upload(ctx, u.seal(nil, true)); err != nil {
+		return errors.Join(fmt.Errorf("cannot upload final segment: %w", err), u.upload.Abort(ctx))
+	}
+
+	if err := u.storage.keys.SetMediaObjectKey(ctx, u.key); err != nil {
+		return errors.Join(fmt.Errorf("cannot save data key of object: %w", err), u.upload.Abort(ctx))
+	}
+
+	if err := u.upload.Complete(ctx); err != nil {
+		return errors.Join(err, u.storage.keys.RemoveMediaObjectKey(ctx, u.key.ObjectKey))
+	}
+
+	return nil
+}
+
+func (u *EncryptedUpload) Abort(ctx context.Context) error {
+	u.buf = nil
+
+	return u.upload.Abort(ctx)
+}
+
+// decryptReader decrypts segments of object from source. Content before skip of first segment is dropped,
+// at most remaining bytes are read if remaining is not negative
+type decryptReader struct {
+	source io.ReadCloser
+	aead   cipher.AEAD
+	aad    []byte
+
+	index     uint64
+	skip      int
+	remaining int64
+
+	segment []byte
+	// plainBuf buffer of decrypted segment, segment isn't decrypted in place, because failed decryption
+	// by nonce of not final segment clears output
+	plainBuf []byte
+	plain    []byte
+	read     bool
+	final    bool
+	err      error
+}
+
+func (r *decryptReader) Read(p []byte) (int, error) {
+	if r.remaining == 0 {
+		return 0, io.EOF
+	}
+
+	for len(r.plain) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		r.err = r.next()
+	}
+
+	if r.remaining >= 0 && int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+
+	n := copy(p, r.plain)
+	r.plain = r.plain[n:]
+	if r.remaining >= 0 {
+		r.remaining -= int64(n)
+	}
+
+	return n, nil
+}
+
+// next decrypts next segment of source. Short segment must be the final segment, full segment is final
+// if it can't be decrypted by nonce of not final segment
+func (r *decryptReader) next() error {
+	if r.final {
+		return io.EOF
+	}
+
+	n, err := io.ReadFull(r.source, r.segment)
+	if errors.Is(err, io.EOF) {
+		// source of whole object must end by final segment, source of range can end before it.
+		// Source of object from its start can't be empty, because empty object has empty final segment
+		if r.remaining < 0 && (r.read || r.index == 0) {
+			return ErrTruncatedObject
+		}
+
+		return io.EOF
+	} else if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+
+	sealed := r.segment[:n]
+	var plain []byte
+	openErr := errors.New("short segment")
+	if n == len(r.segment) {
+		plain, openErr = r.aead.Open(r.plainBuf[:0], segmentNonce(r.index, false), sealed, r.aad)
+	}
+
+	if openErr != nil {
+		plain, openErr = r.aead.Open(r.plainBuf[:0], segmentNonce(r.index, true), sealed, r.aad)
+		r.final = openErr == nil
+	}
+
+	if openErr != nil {
+		return fmt.Errorf("cannot decrypt segment %d of object: %w", r.index, openErr)
+	}
+
+	r.index++
+	r.read = true
+	r.plain = plain[min(r.skip, len(plain)):]
+	r.skip = 0
+
+	return nil
+}
+
+func (r *decryptReader) Close() error {
+	return r.source.Close()
+}
@@ -0,0 +1,92 @@
+package atrest
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage/mediastoragetest"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"testing"
+)
+
+func TestMediaStorage(t *testing.T) {
+	mediastoragetest.TestMediaStorage(t, NewMediaStorage(mediastorage.NewMemoryStorage(), &plainstorage.MemoryStorage{}, newTestKeyring(t, 1)))
+}
+
+func TestMediaStorage_Encryption(t *testing.T) {
+	ctx := context.Background()
+	backend := mediastorage.NewMemoryStorage()
+	keys := &plainstorage.MemoryStorage{}
+	storage := NewMediaStorage(backend, keys, newTestKeyring(t, 1))
+
+	content := make([]byte, 3*segmentSize+100)
+	_, err := rand.Read(content)
+	require.NoError(t, err)
+
+	mediastoragetest.UploadObject(t, storage, "encrypted", content, 10000)
+	stored := mediastoragetest.DownloadObject(t, backend, "encrypted")
+	assert.Len(t, stored, len(content)+4*segmentOverhead)
+	assert.False(t, bytes.Contains(stored, content[:100]), "stored object must be encrypted")
+	assert.Equal(t, content, mediastoragetest.DownloadObject(t, storage, "encrypted"))
+
+	require.NoError(t, storage.List(ctx, func(info mediastorage.ObjectInfo) error {
+		assert.Equal(t, int64(len(content)), info.Size, "listed size must be size of content")
+
+		return nil
+	}))
+
+	t.Run("Range", func(t *testing.T) {
+		ranges := []struct{ offset, length int64 }{
+			{0, 10},
+			{segmentSize - 5, 10},
+			{segmentSize, segmentSize},
+			{100, 2 * segmentSize},
+			{3 * segmentSize, 0},
+			{3*segmentSize + 50, 1000},
+			{int64(len(content)), 0},
+			{int64(len(content)) + segmentSize, 10},
+		}
+
+		for _, r := range ranges {
+			reader, err := storage.StartDownloadRange(ctx, "encrypted", r.offset, r.length)
+			require.NoError(t, err)
+			got, err := io.ReadAll(reader)
+			require.NoError(t, reader.Close())
+			require.NoError(t, err, "range %d+%d", r.offset, r.length)
+
+			expected := content[min(r.offset, int64(len(content))):]
+			if r.length > 0 && r.length < int64(len(expected)) {
+				expected = expected[:r.length]
+			}
+			assert.Equal(t, expected, got, "range %d+%d", r.offset, r.length)
+		}
+	})
+
+	t.Run("Truncated object", func(t *testing.T) {
+		mediastoragetest.UploadObject(t, backend, "truncated", stored[:2*(segmentSize+segmentOverhead)], 10000)
+		key, err := keys.GetMediaObjectKey(ctx, "encrypted")
+		require.NoError(t, err)
+		key.ObjectKey = "truncated"
+		require.NoError(t, keys.SetMediaObjectKey(ctx, *key))
+
+		reader, err := storage.StartDownload(ctx, "truncated")
+		require.NoError(t, err)
+		_, err = io.ReadAll(reader)
+		assert.Error(t, err, "object truncated by segments boundary must not be read")
+	})
+
+	t.Run("Not encrypted object", func(t *testing.T) {
+		mediastoragetest.UploadObject(t, backend, "plain", []byte("stored before encryption"), 4)
+		assert.Equal(t, []byte("stored before encryption"), mediastoragetest.DownloadObject(t, storage, "plain"))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, storage.Delete(ctx, "encrypted"))
+		_, err := keys.GetMediaObjectKey(ctx, "encrypted")
+		assert.ErrorIs(t, err, plainstorage.ErrEntityNotFound, "data key must be removed with object")
+	})
+}
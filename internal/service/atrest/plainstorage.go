@@ -0,0 +1,151 @@
+package atrest
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+)
+
+// PlainStorage encrypts data of plain secrets stored by wrapped storage. Data stored before encryption was enabled
+// is read as is. GetPlainSecretsData and ReplacePlainSecretData work with stored data, they are used by rotation
+type PlainStorage struct {
+	plainstorage.PlainStorage
+
+	keyring *Keyring
+}
+
+func NewPlainStorage(storage plainstorage.PlainStorage, keyring *Keyring) *PlainStorage {
+	return &PlainStorage{PlainStorage: storage, keyring: keyring}
+}
+
+func (s *PlainStorage) InTransaction(ctx context.Context, transaction func(tx plainstorage.PlainStorage) error) error {
+	return s.PlainStorage.InTransaction(ctx, func(tx plainstorage.PlainStorage) error {
+		return transaction(NewPlainStorage(tx, s.keyring))
+	})
+}
+
+func (s *PlainStorage) InSnapshot(ctx context.Context, read func(snapshot plainstorage.PlainStorage) error) error {
+	return s.PlainStorage.InSnapshot(ctx, func(snapshot plainstorage.PlainStorage) error {
+		return read(NewPlainStorage(snapshot, s.keyring))
+	})
+}
+
+// AddPlainSecret creates secret by AddPlainSecrets, because data is sealed with UUID of secret before it's created
+func (s *PlainStorage) AddPlainSecret(ctx context.Context, userUUID string, name string, encryptedName []byte, dataType plainstorage.SecretType, data []byte) (*plainstorage.PlainSecret, error) {
+	created, err := s.AddPlainSecrets(ctx, userUUID, []plainstorage.NewPlainSecret{{Name: name, EncryptedName: encryptedName, Type: dataType, Data: data}})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created[0], nil
+}
+
+func (s *PlainStorage) AddPlainSecrets(ctx context.Context, userUUID string, secrets []plainstorage.NewPlainSecret) ([]plainstorage.PlainSecret, error) {
+	sealedSecrets := make([]plainstorage.NewPlainSecret, len(secrets))
+	for i, v := range secrets {
+		sealedSecrets[i] = v
+		if sealedSecrets[i].UUID == "" {
+			sealedSecrets[i].UUID = uuid.New().String()
+		}
+
+		sealed, err := s.seal(v.Data, sealedSecrets[i].UUID)
+		if err != nil {
+			return nil, err
+		}
+		sealedSecrets[i].Data = sealed
+	}
+
+	created, err := s.PlainStorage.AddPlainSecrets(ctx, userUUID, sealedSecrets)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range created {
+		created[i].Data = secrets[i].Data
+	}
+
+	return created, nil
+}
+
+// UpdatePlainSecretDataByName gets UUID of secret in transaction to seal data with it
+func (s *PlainStorage) UpdatePlainSecretDataByName(ctx context.Context, ownerUUID string, name string, dataType plainstorage.SecretType, data []byte) error {
+	return s.PlainStorage.InTransaction(ctx, func(tx plainstorage.PlainStorage) error {
+		secret, err := tx.GetUserSecretByName(ctx, ownerUUID, name, dataType)
+		if err != nil {
+			return err
+		}
+
+		sealed, err := s.seal(data, secret.Metadata.UUID)
+		if err != nil {
+			return err
+		}
+
+		return tx.UpdatePlainSecretDataByName(ctx, ownerUUID, name, dataType, sealed)
+	})
+}
+
+func (s *PlainStorage) GetUserSecretByName(ctx context.Context, userUUID string, secretName string, secretType plainstorage.SecretType) (*plainstorage.PlainSecret, error) {
+	secret, err := s.PlainStorage.GetUserSecretByName(ctx, userUUID, secretName, secretType)
+	if err != nil {
+		return nil, err
+	}
+
+	if secret.Data, err = s.keyring.Open(secret.Data, []byte(secret.Metadata.UUID)); err != nil {
+		return nil, fmt.Errorf("cannot decrypt data of secret %s: %w", secret.Metadata.UUID, err)
+	}
+
+	return secret, nil
+}
+
+func (s *PlainStorage) GetSecrets(ctx context.Context, afterUUID string, limit int) ([]plainstorage.PlainSecret, error) {
+	secrets, err := s.PlainStorage.GetSecrets(ctx, afterUUID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range secrets {
+		if secrets[i].Data, err = s.keyring.Open(secrets[i].Data, []byte(secrets[i].Metadata.UUID)); err != nil {
+			return nil, fmt.Errorf("cannot decrypt data of secret %s: %w", secrets[i].Metadata.UUID, err)
+		}
+	}
+
+	return secrets, nil
+}
+
+func (s *PlainStorage) RestoreSecret(ctx context.Context, secret plainstorage.PlainSecret) error {
+	sealed, err := s.seal(secret.Data, secret.Metadata.UUID)
+	if err != nil {
+		return err
+	}
+	secret.Data = sealed
+
+	return s.PlainStorage.RestoreSecret(ctx, secret)
+}
+
+// GetUserUsage counts size of plain data without envelopes. Data stored before encryption was enabled has no envelope,
+// so it's counted smaller until rotation encrypts it
+func (s *PlainStorage) GetUserUsage(ctx context.Context, userUUID string) (plainstorage.Usage, error) {
+	usage, err := s.PlainStorage.GetUserUsage(ctx, userUUID)
+	if err != nil {
+		return plainstorage.Usage{}, err
+	}
+
+	usage.PlainBytes = max(usage.PlainBytes-int64(usage.PlainCount)*EnvelopeOverhead, 0)
+
+	return usage, nil
+}
+
+// seal encrypts data bound to UUID of secret, nil data of media secrets stays nil
+func (s *PlainStorage) seal(data []byte, secretUUID string) ([]byte, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	sealed, err := s.keyring.Seal(data, []byte(secretUUID))
+	if err != nil {
+		return nil, fmt.Errorf("cannot encrypt data of secret: %w", err)
+	}
+
+	return sealed, nil
+}
@@ -0,0 +1,96 @@
+package atrest
+
+import (
+	"context"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage/mediastoragetest"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPlainStorage(t *testing.T) {
+	ctx := context.Background()
+	backend := &plainstorage.MemoryStorage{}
+	storage := NewPlainStorage(backend, newTestKeyring(t, 1))
+
+	user, err := storage.CreateUser(ctx, "user", "hash")
+	require.NoError(t, err)
+
+	secret, err := storage.AddPlainSecret(ctx, user.UUID, "text", nil, plainstorage.SecretTypeText, []byte("text data"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("text data"), secret.Data)
+
+	_, err = storage.AddSecretMetadata(ctx, user.UUID, "media-uuid", "media", nil, plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+
+	err = storage.InTransaction(ctx, func(tx plainstorage.PlainStorage) error {
+		created, err := tx.AddPlainSecrets(ctx, user.UUID, []plainstorage.NewPlainSecret{{Name: "card", Type: plainstorage.SecretTypeCard, Data: []byte("card data")}})
+		require.NoError(t, err)
+		assert.Equal(t, []byte("card data"), created[0].Data)
+
+		return tx.UpdatePlainSecretDataByName(ctx, user.UUID, "text", plainstorage.SecretTypeText, []byte("updated text"))
+	})
+	require.NoError(t, err)
+
+	stored, err := backend.GetPlainSecretsData(ctx, "", 10)
+	require.NoError(t, err)
+	require.Len(t, stored, 2, "media must have no plain data")
+	for _, v := range stored {
+		assert.True(t, IsSealed(v.Data), "data must be encrypted in storage")
+	}
+
+	secret, err = storage.GetUserSecretByName(ctx, user.UUID, "text", plainstorage.SecretTypeText)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("updated text"), secret.Data)
+
+	secret, err = storage.GetUserSecretByName(ctx, user.UUID, "card", plainstorage.SecretTypeCard)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("card data"), secret.Data)
+
+	usage, err := storage.GetUserUsage(ctx, user.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("updated text")+len("card data")), usage.PlainBytes, "usage must count size of data without envelopes")
+
+	// data is bound to its secret, so swapped data of secrets isn't decrypted
+	err = backend.ReplacePlainSecretData(ctx, stored[0].UUID, stored[0].Data, stored[1].Data)
+	require.NoError(t, err)
+	_, err = storage.GetSecrets(ctx, "", 10)
+	assert.Error(t, err)
+}
+
+func TestRotate(t *testing.T) {
+	ctx := context.Background()
+	backend := &plainstorage.MemoryStorage{}
+	objects := mediastorage.NewMemoryStorage()
+
+	user, err := backend.CreateUser(ctx, "user", "hash")
+	require.NoError(t, err)
+	_, err = backend.AddPlainSecret(ctx, user.UUID, "legacy", nil, plainstorage.SecretTypeText, []byte("stored before encryption"))
+	require.NoError(t, err)
+
+	old := newTestKeyring(t, 1)
+	_, err = NewPlainStorage(backend, old).AddPlainSecret(ctx, user.UUID, "text", nil, plainstorage.SecretTypeText, []byte("text data"))
+	require.NoError(t, err)
+	mediastoragetest.UploadObject(t, NewMediaStorage(objects, backend, old), "object", []byte("media content"), 4)
+
+	rotated := newTestKeyring(t, 2, 1)
+	report, err := Rotate(ctx, backend, rotated)
+	require.NoError(t, err)
+	assert.Equal(t, RotateReport{Secrets: 1, EncryptedSecrets: 1, Objects: 1}, report)
+
+	report, err = Rotate(ctx, backend, rotated)
+	require.NoError(t, err)
+	assert.Equal(t, RotateReport{}, report, "rotated storage must have nothing to rewrap")
+
+	// data must be read without old key after rotation
+	current := newTestKeyring(t, 2)
+	storage := NewPlainStorage(backend, current)
+	for name, data := range map[string]string{"legacy": "stored before encryption", "text": "text data"} {
+		secret, err := storage.GetUserSecretByName(ctx, user.UUID, name, plainstorage.SecretTypeText)
+		require.NoError(t, err)
+		assert.Equal(t, []byte(data), secret.Data)
+	}
+	assert.Equal(t, []byte("media content"), mediastoragetest.DownloadObject(t, NewMediaStorage(objects, backend, current), "object"))
+}
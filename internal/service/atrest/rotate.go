@@ -0,0 +1,124 @@
+package atrest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+)
+
+// rotatePageSize count of secrets or data keys rewrapped by one page
+const rotatePageSize = 500
+
+// RotateReport result of rotation of key-encryption key
+type RotateReport struct {
+	// Secrets count of plain secrets whose data key is rewrapped by current key
+	Secrets int
+	// EncryptedSecrets count of plain secrets stored before encryption was enabled, that are encrypted
+	EncryptedSecrets int
+	// Objects count of media objects whose data key is rewrapped by current key
+	Objects int
+	// Skipped count of secrets and data keys changed during rotation, they are written by service
+	// and wrapped by its current key
+	Skipped int
+}
+
+// Rotate rewraps data keys of all plain secrets and media objects by current key-encryption key and encrypts
+// plain secrets stored before encryption was enabled. Content of media objects is not read, so objects stored
+// before encryption was enabled stay unencrypted. Storage must be not decorated by PlainStorage, because rotation
+// works with stored data. Rotation can run with running service, if service uses same current key
+func Rotate(ctx context.Context, storage plainstorage.PlainStorage, keyring *Keyring) (RotateReport, error) {
+	report := RotateReport{}
+	if err := rotateSecrets(ctx, storage, keyring, &report); err != nil {
+		return report, err
+	}
+
+	if err := rotateObjectKeys(ctx, storage, keyring, &report); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+func rotateSecrets(ctx context.Context, storage plainstorage.PlainStorage, keyring *Keyring, report *RotateReport) error {
+	after := ""
+	for {
+		page, err := storage.GetPlainSecretsData(ctx, after, rotatePageSize)
+		if err != nil {
+			return fmt.Errorf("cannot get data of plain secrets: %w", err)
+		}
+
+		for _, v := range page {
+			rewrapped, changed, err := keyring.Rewrap(v.Data, []byte(v.UUID))
+			if err != nil {
+				return fmt.Errorf("cannot rewrap data key of secret %s: %w", v.UUID, err)
+			}
+
+			if !changed {
+				continue
+			}
+
+			err = storage.ReplacePlainSecretData(ctx, v.UUID, v.Data, rewrapped)
+			if errors.Is(err, plainstorage.ErrEntityNotFound) {
+				report.Skipped++
+
+				continue
+			} else if err != nil {
+				return fmt.Errorf("cannot replace data of secret %s: %w", v.UUID, err)
+			}
+
+			if IsSealed(v.Data) {
+				report.Secrets++
+			} else {
+				report.EncryptedSecrets++
+			}
+		}
+
+		if len(page) < rotatePageSize {
+			return nil
+		}
+		after = page[len(page)-1].UUID
+	}
+}
+
+func rotateObjectKeys(ctx context.Context, storage plainstorage.PlainStorage, keyring *Keyring, report *RotateReport) error {
+	after := ""
+	for {
+		page, err := storage.GetMediaObjectKeys(ctx, after, rotatePageSize)
+		if err != nil {
+			return fmt.Errorf("cannot get data keys of media objects: %w", err)
+		}
+
+		for _, v := range page {
+			if v.KEKID == keyring.CurrentID() {
+				continue
+			}
+
+			dataKey, err := keyring.UnwrapKey(v.KEKID, v.WrappedKey)
+			if err != nil {
+				return fmt.Errorf("cannot unwrap data key of object %s: %w", v.ObjectKey, err)
+			}
+
+			key := plainstorage.MediaObjectKey{ObjectKey: v.ObjectKey}
+			if key.KEKID, key.WrappedKey, err = keyring.WrapKey(dataKey); err != nil {
+				return err
+			}
+
+			err = storage.ReplaceMediaObjectKey(ctx, v, key)
+			if errors.Is(err, plainstorage.ErrEntityNotFound) {
+				report.Skipped++
+
+				continue
+			} else if err != nil {
+				return fmt.Errorf("cannot replace data key of object %s: %w", v.ObjectKey, err)
+			}
+
+			report.Objects++
+		}
+
+		if len(page) < rotatePageSize {
+			return nil
+		}
+		after = page[len(page)-1].ObjectKey
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/logger"
+	"github.com/nessai1/gophkeeper/internal/service/backup"
+	"github.com/nessai1/gophkeeper/internal/service/config"
+	"log"
+	"os"
+)
+
+// RunBackup writes archive of storages of config to file given by argument. Backup can run with running service:
+// plain storage is dumped from snapshot, objects removed during backup are listed as missing.
+// Data encrypted at rest is archived as stored, so archive is restored only with keys of config
+func RunBackup() {
+	c, err := config.FetchConfig()
+	if err != nil {
+		log.Fatalf("Cannot fetch config for service: %s", err.Error())
+	}
+
+	path := flag.Arg(0)
+	if path == "" {
+		log.Fatalf("Usage: service backup [-c config] <archive>")
+	}
+
+	l, err := logger.BuildLogger(logger.LevelDev, os.Stdout)
+	if err != nil {
+		log.Fatalf("Cannot build logger: %s", err.Error())
+	}
+
+	// storages are used only by command, so their background jobs stop on return
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ms, s, err := buildStoredDataStorages(ctx, c, l)
+	if err != nil {
+		log.Fatalf("Cannot build storages: %s", err.Error())
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		log.Fatalf("Cannot create archive: %s", err.Error())
+	}
+
+	manifest, err := backup.Backup(ctx, file, s, ms)
+	if err == nil {
+		err = file.Close()
+	}
+
+	if err != nil {
+		file.Close()
+		os.Remove(path)
+		log.Fatalf("Cannot backup service: %s", err.Error())
+	}
+
+	if c.Encryption != nil {
+		fmt.Printf("Archive keeps data encrypted at rest, keep encryption keys of config to restore it\n")
+	}
+
+	for _, key := range manifest.MissingObjects {
+		fmt.Printf("Missing object %s\n", key)
+	}
+
+	fmt.Printf("Backup %s: %d users, %d secrets, %d chunks, %d objects, %d missing objects\n",
+		path, manifest.Users, manifest.Secrets, manifest.Chunks, manifest.Objects, len(manifest.MissingObjects),
+	)
+}
+
+// RunRestore restores archive given by argument into empty storages of config. Service must be stopped during restore.
+// Data is restored as archived, so data encrypted at rest is read with keys of deployment that made archive
+func RunRestore() {
+	verifyOnly := flag.Bool("verify", false, "Only verify archive by checksums of manifest")
+
+	c, err := config.FetchConfig()
+	if err != nil {
+		log.Fatalf("Cannot fetch config for service: %s", err.Error())
+	}
+
+	path := flag.Arg(0)
+	if path == "" {
+		log.Fatalf("Usage: service restore [-c config] [-verify] <archive>")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Cannot open archive: %s", err.Error())
+	}
+	defer file.Close()
+
+	if *verifyOnly {
+		manifest, err := backup.Verify(file)
+		if err != nil {
+			log.Fatalf("Archive is invalid: %s", err.Error())
+		}
+
+		fmt.Printf("Archive of %s is valid: %d entries\n", manifest.Created.Format("2006-01-02 15:04:05"), len(manifest.Entries))
+
+		return
+	}
+
+	l, err := logger.BuildLogger(logger.LevelDev, os.Stdout)
+	if err != nil {
+		log.Fatalf("Cannot build logger: %s", err.Error())
+	}
+
+	// storages are used only by command, so their background jobs stop on return
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ms, s, err := buildStoredDataStorages(ctx, c, l)
+	if err != nil {
+		log.Fatalf("Cannot build storages: %s", err.Error())
+	}
+
+	manifest, err := backup.Restore(ctx, file, s, ms)
+	if err != nil {
+		log.Fatalf("Cannot restore service: %s", err.Error())
+	}
+
+	fmt.Printf("Restored %s: %d users, %d secrets, %d chunks, %d objects\n",
+		path, manifest.Users, manifest.Secrets, manifest.Chunks, manifest.Objects,
+	)
+}
@@ -0,0 +1,349 @@
+// Package backup makes archive of whole service: logical dump of plain storage and media objects with manifest
+// of checksums. Archive doesn't depend on storage backends, so it's restored into deployment with any backends.
+// Storages are archived as stored: data encrypted at rest stays encrypted and data keys of objects stay wrapped
+// by key-encryption keys, so storages must be not decorated by encryption at rest
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/service/chunkstorage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// FormatVersion version of archive format, restore rejects archives of newer versions.
+// Version 2 adds data keys of objects encrypted at rest
+const FormatVersion = 2
+
+// dumpPageSize count of users or secrets read from plain storage by one page
+const dumpPageSize = 500
+
+// Names of archive entries, entries follow in order of constants. Manifest is the last entry of archive
+const (
+	usersEntry       = "users.jsonl"
+	secretsEntry     = "secrets.jsonl"
+	chunksEntry      = "chunks.jsonl"
+	mediaChunksEntry = "media_chunks.jsonl"
+	objectKeysEntry  = "object_keys.jsonl"
+	objectsPrefix    = "objects/"
+	manifestEntry    = "manifest.json"
+)
+
+// Manifest describes content of archive
+type Manifest struct {
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+
+	Users   int `json:"users"`
+	Secrets int `json:"secrets"`
+	Chunks  int `json:"chunks"`
+	Objects int `json:"objects"`
+
+	// MissingObjects keys of objects of media and chunks that were missing in media storage during backup
+	MissingObjects []string `json:"missing_objects"`
+
+	// Entries all entries of archive except manifest
+	Entries []Entry `json:"entries"`
+}
+
+// Entry file of archive
+type Entry struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+	// SHA256 hex encoded checksum of entry content
+	SHA256 string `json:"sha256"`
+}
+
+// mediaChunks ordered chunks of chunked media
+type mediaChunks struct {
+	MediaUUID string   `json:"media_uuid"`
+	OwnerUUID string   `json:"owner_uuid"`
+	Hashes    []string `json:"hashes"`
+}
+
+// Backup writes archive of storages to w. Plain storage is dumped from one snapshot, after that objects of
+// dumped media and chunks are streamed from media storage. Objects removed before they are streamed are listed
+// as missing by manifest
+func Backup(ctx context.Context, w io.Writer, plain plainstorage.PlainStorage, media mediastorage.MediaStorage) (Manifest, error) {
+	archive := &archiveWriter{tw: tar.NewWriter(w)}
+	manifest := Manifest{Version: FormatVersion, Created: time.Now().UTC()}
+
+	var objectKeys []string
+	err := plain.InSnapshot(ctx, func(snapshot plainstorage.PlainStorage) error {
+		var err error
+		objectKeys, err = dumpPlainStorage(ctx, archive, snapshot, &manifest)
+
+		return err
+	})
+	if err != nil {
+		return manifest, fmt.Errorf("cannot dump plain storage: %w", err)
+	}
+
+	sizes := make(map[string]int64)
+	err = media.List(ctx, func(info mediastorage.ObjectInfo) error {
+		sizes[info.Key] = info.Size
+
+		return nil
+	})
+	if err != nil {
+		return manifest, fmt.Errorf("cannot list media storage: %w", err)
+	}
+
+	sort.Strings(objectKeys)
+	for _, key := range objectKeys {
+		if err = ctx.Err(); err != nil {
+			return manifest, err
+		}
+
+		size, ok := sizes[key]
+		if !ok {
+			manifest.MissingObjects = append(manifest.MissingObjects, key)
+
+			continue
+		}
+
+		reader, err := media.StartDownload(ctx, key)
+		if errors.Is(err, mediastorage.ErrObjectNotFound) {
+			manifest.MissingObjects = append(manifest.MissingObjects, key)
+
+			continue
+		} else if err != nil {
+			return manifest, fmt.Errorf("cannot download object %s: %w", key, err)
+		}
+
+		err = archive.add(objectsPrefix+key, size, reader)
+		reader.Close()
+		if err != nil {
+			return manifest, fmt.Errorf("cannot archive object %s: %w", key, err)
+		}
+
+		manifest.Objects++
+	}
+
+	manifest.Entries = archive.entries
+	raw, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return manifest, fmt.Errorf("cannot marshal manifest: %w", err)
+	}
+
+	if err = archive.add(manifestEntry, int64(len(raw)), bytes.NewReader(raw)); err != nil {
+		return manifest, fmt.Errorf("cannot archive manifest: %w", err)
+	}
+
+	if err = archive.tw.Close(); err != nil {
+		return manifest, fmt.Errorf("cannot close archive: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// dumpPlainStorage archives records of plain storage, returns keys of objects of dumped media and chunks
+func dumpPlainStorage(ctx context.Context, archive *archiveWriter, storage plainstorage.PlainStorage, manifest *Manifest) ([]string, error) {
+	err := archive.addRecords(usersEntry, func(enc *json.Encoder) error {
+		after := ""
+		for {
+			users, err := storage.GetUsers(ctx, after, dumpPageSize)
+			if err != nil {
+				return fmt.Errorf("cannot get users: %w", err)
+			}
+
+			for _, v := range users {
+				if err = enc.Encode(v); err != nil {
+					return err
+				}
+			}
+			manifest.Users += len(users)
+
+			if len(users) < dumpPageSize {
+				return nil
+			}
+			after = users[len(users)-1].UUID
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var media []plainstorage.SecretMetadata
+	err = archive.addRecords(secretsEntry, func(enc *json.Encoder) error {
+		after := ""
+		for {
+			secrets, err := storage.GetSecrets(ctx, after, dumpPageSize)
+			if err != nil {
+				return fmt.Errorf("cannot get secrets: %w", err)
+			}
+
+			for _, v := range secrets {
+				if err = enc.Encode(v); err != nil {
+					return err
+				}
+
+				if v.Metadata.Type == plainstorage.SecretTypeMedia {
+					media = append(media, v.Metadata)
+				}
+			}
+			manifest.Secrets += len(secrets)
+
+			if len(secrets) < dumpPageSize {
+				return nil
+			}
+			after = secrets[len(secrets)-1].Metadata.UUID
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var objectKeys []string
+	err = archive.addRecords(chunksEntry, func(enc *json.Encoder) error {
+		chunks, err := storage.GetAllMediaChunks(ctx)
+		if err != nil {
+			return fmt.Errorf("cannot get media chunks: %w", err)
+		}
+
+		for _, v := range chunks {
+			if err = enc.Encode(v); err != nil {
+				return err
+			}
+
+			objectKeys = append(objectKeys, chunkstorage.ObjectKey(v.OwnerUUID, v.Hash))
+		}
+		manifest.Chunks = len(chunks)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = archive.addRecords(mediaChunksEntry, func(enc *json.Encoder) error {
+		for _, v := range media {
+			chunks, err := storage.GetMediaChunks(ctx, v.UUID)
+			if err != nil {
+				return fmt.Errorf("cannot get chunks of media %s: %w", v.UUID, err)
+			}
+
+			// media without chunks is stored as single object
+			if len(chunks) == 0 {
+				objectKeys = append(objectKeys, v.UUID)
+
+				continue
+			}
+
+			record := mediaChunks{MediaUUID: v.UUID, OwnerUUID: v.UserUUID, Hashes: make([]string, len(chunks))}
+			for i, chunk := range chunks {
+				record.Hashes[i] = chunk.Hash
+			}
+
+			if err = enc.Encode(record); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = archive.addRecords(objectKeysEntry, func(enc *json.Encoder) error {
+		after := ""
+		for {
+			keys, err := storage.GetMediaObjectKeys(ctx, after, dumpPageSize)
+			if err != nil {
+				return fmt.Errorf("cannot get data keys of objects: %w", err)
+			}
+
+			for _, v := range keys {
+				if err = enc.Encode(v); err != nil {
+					return err
+				}
+			}
+
+			if len(keys) < dumpPageSize {
+				return nil
+			}
+			after = keys[len(keys)-1].ObjectKey
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objectKeys, nil
+}
+
+// archiveWriter writes entries of archive and collects their checksums
+type archiveWriter struct {
+	tw      *tar.Writer
+	entries []Entry
+}
+
+// add writes entry with content of size from reader
+func (a *archiveWriter) add(name string, size int64, reader io.Reader) error {
+	err := a.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     size,
+		Mode:     0600,
+		ModTime:  time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot write header of entry: %w", err)
+	}
+
+	hash := sha256.New()
+	n, err := io.Copy(a.tw, io.TeeReader(io.LimitReader(reader, size+1), hash))
+	if err != nil {
+		return fmt.Errorf("cannot write content of entry: %w", err)
+	}
+
+	if n != size {
+		return fmt.Errorf("entry has %d bytes, but %d bytes are written", size, n)
+	}
+
+	if name != manifestEntry {
+		a.entries = append(a.entries, Entry{Name: name, Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))})
+	}
+
+	return nil
+}
+
+// addRecords writes entry of JSON lines encoded by fill. Records are buffered by temporary file,
+// because size of entry must be known before its content
+func (a *archiveWriter) addRecords(name string, fill func(enc *json.Encoder) error) error {
+	file, err := os.CreateTemp("", "gophkeeper-backup-*")
+	if err != nil {
+		return fmt.Errorf("cannot create temporary file of %s: %w", name, err)
+	}
+	defer func() {
+		file.Close()
+		os.Remove(file.Name())
+	}()
+
+	if err = fill(json.NewEncoder(file)); err != nil {
+		return fmt.Errorf("cannot dump %s: %w", name, err)
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("cannot get size of %s: %w", name, err)
+	}
+
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("cannot rewind %s: %w", name, err)
+	}
+
+	return a.add(name, size, file)
+}
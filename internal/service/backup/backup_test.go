@@ -0,0 +1,149 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/google/uuid"
+	"github.com/nessai1/gophkeeper/internal/service/atrest"
+	"github.com/nessai1/gophkeeper/internal/service/chunkstorage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage/mediastoragetest"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func chunkHash(content []byte) string {
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func TestBackupRestore(t *testing.T) {
+	ctx := context.Background()
+	plain := &plainstorage.MemoryStorage{}
+	media := mediastorage.NewMemoryStorage()
+
+	// storages are encrypted at rest, archive keeps them as stored
+	keyring, err := atrest.NewKeyring(bytes.Repeat([]byte{1}, atrest.KeySize))
+	require.NoError(t, err)
+	sealedPlain := atrest.NewPlainStorage(plain, keyring)
+	sealedMedia := atrest.NewMediaStorage(media, plain, keyring)
+
+	user, err := plain.CreateUser(ctx, "user", "hash")
+	require.NoError(t, err)
+
+	secret, err := sealedPlain.AddPlainSecret(ctx, user.UUID, "text", []byte("encrypted name"), plainstorage.SecretTypeText, []byte("text data"))
+	require.NoError(t, err)
+	require.NoError(t, plain.SetSecretTags(ctx, secret.Metadata.UUID, []plainstorage.SecretTag{{Tag: "tag", EncryptedTag: []byte("encrypted tag")}}))
+
+	objectMedia := uuid.New().String()
+	_, err = plain.AddSecretMetadata(ctx, user.UUID, objectMedia, "object.bin", nil, plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+	mediastoragetest.UploadObject(t, sealedMedia, objectMedia, []byte("object content"), 4)
+
+	missingMedia := uuid.New().String()
+	_, err = plain.AddSecretMetadata(ctx, user.UUID, missingMedia, "missing.bin", nil, plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+
+	chunks := [][]byte{[]byte("first chunk"), []byte("second chunk"), []byte("unused chunk")}
+	for _, v := range chunks {
+		require.NoError(t, plain.AddMediaChunk(ctx, user.UUID, chunkHash(v), int64(len(v))))
+		mediastoragetest.UploadObject(t, media, chunkstorage.ObjectKey(user.UUID, chunkHash(v)), v, 4)
+	}
+
+	chunkedMedia := uuid.New().String()
+	_, err = plain.AddSecretMetadata(ctx, user.UUID, chunkedMedia, "chunked.bin", nil, plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+	hashes := []string{chunkHash(chunks[0]), chunkHash(chunks[1]), chunkHash(chunks[0])}
+	require.NoError(t, plain.SetMediaChunks(ctx, user.UUID, chunkedMedia, hashes))
+
+	mediastoragetest.UploadObject(t, media, "orphan", []byte("orphan content"), 4)
+
+	archive := bytes.Buffer{}
+	manifest, err := Backup(ctx, &archive, plain, media)
+	require.NoError(t, err)
+	assert.Equal(t, 1, manifest.Users)
+	assert.Equal(t, 4, manifest.Secrets)
+	assert.Equal(t, 3, manifest.Chunks)
+	assert.Equal(t, 4, manifest.Objects, "objects of media and chunks must be archived, orphans must not")
+	assert.Equal(t, []string{missingMedia}, manifest.MissingObjects)
+
+	verified, err := Verify(bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, manifest.Entries, verified.Entries)
+	assert.NotContains(t, archive.String(), "text data", "data encrypted at rest must be archived encrypted")
+	assert.NotContains(t, archive.String(), "object content", "objects encrypted at rest must be archived encrypted")
+
+	// archive is restored into other backends, data is read by keys of deployment that made archive
+	targetPlain := &plainstorage.MemoryStorage{}
+	targetMedia := mediastorage.NewMemoryStorage()
+	_, err = Restore(ctx, bytes.NewReader(archive.Bytes()), targetPlain, targetMedia)
+	require.NoError(t, err)
+
+	restoredPlain := atrest.NewPlainStorage(targetPlain, keyring)
+	restoredMedia := atrest.NewMediaStorage(targetMedia, targetPlain, keyring)
+
+	restoredUser, err := restoredPlain.GetUserByLogin(ctx, "user")
+	require.NoError(t, err)
+	assert.Equal(t, *user, *restoredUser)
+
+	restoredSecret, err := restoredPlain.GetUserSecretByName(ctx, user.UUID, "text", plainstorage.SecretTypeText)
+	require.NoError(t, err)
+	assert.Equal(t, secret.Metadata.UUID, restoredSecret.Metadata.UUID)
+	assert.Equal(t, []byte("text data"), restoredSecret.Data)
+	assert.Equal(t, []byte("encrypted name"), restoredSecret.Metadata.EncryptedName)
+	assert.Equal(t, []plainstorage.SecretTag{{Tag: "tag", EncryptedTag: []byte("encrypted tag")}}, restoredSecret.Metadata.Tags)
+
+	restoredChunks, err := restoredPlain.GetMediaChunks(ctx, chunkedMedia)
+	require.NoError(t, err)
+	require.Len(t, restoredChunks, 3)
+	for i, v := range restoredChunks {
+		assert.Equal(t, hashes[i], v.Hash)
+	}
+
+	allChunks, err := restoredPlain.GetAllMediaChunks(ctx)
+	require.NoError(t, err)
+	refs := make(map[string]int)
+	for _, v := range allChunks {
+		refs[v.Hash] = v.Refs
+	}
+	assert.Equal(t, map[string]int{chunkHash(chunks[0]): 2, chunkHash(chunks[1]): 1, chunkHash(chunks[2]): 0}, refs)
+
+	assert.Equal(t, []byte("object content"), mediastoragetest.DownloadObject(t, restoredMedia, objectMedia))
+	assert.Equal(t, chunks[1], mediastoragetest.DownloadObject(t, restoredMedia, chunkstorage.ObjectKey(user.UUID, chunkHash(chunks[1]))))
+	assert.Equal(t, mediastoragetest.DownloadObject(t, media, objectMedia), mediastoragetest.DownloadObject(t, targetMedia, objectMedia), "object must be restored as stored")
+
+	_, err = Restore(ctx, bytes.NewReader(archive.Bytes()), targetPlain, targetMedia)
+	assert.ErrorIs(t, err, ErrNotEmpty)
+}
+
+func TestVerify_Corrupted(t *testing.T) {
+	ctx := context.Background()
+	plain := &plainstorage.MemoryStorage{}
+	media := mediastorage.NewMemoryStorage()
+
+	user, err := plain.CreateUser(ctx, "user", "hash")
+	require.NoError(t, err)
+
+	mediaUUID := uuid.New().String()
+	_, err = plain.AddSecretMetadata(ctx, user.UUID, mediaUUID, "object.bin", nil, plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+	mediastoragetest.UploadObject(t, media, mediaUUID, []byte("object content"), 4)
+
+	archive := bytes.Buffer{}
+	_, err = Backup(ctx, &archive, plain, media)
+	require.NoError(t, err)
+
+	corrupted := bytes.Replace(archive.Bytes(), []byte("object content"), []byte("object CONTENT"), 1)
+	require.NotEqual(t, archive.Bytes(), corrupted)
+
+	_, err = Verify(bytes.NewReader(corrupted))
+	assert.ErrorIs(t, err, ErrInvalidArchive)
+
+	_, err = Restore(ctx, bytes.NewReader(corrupted), &plainstorage.MemoryStorage{}, mediastorage.NewMemoryStorage())
+	assert.ErrorIs(t, err, ErrInvalidArchive)
+}
@@ -0,0 +1,241 @@
+package backup
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"github.com/nessai1/gophkeeper/pkg/bytesize"
+	"io"
+	"strings"
+)
+
+// uploadPartSize size of parts of restored objects
+const uploadPartSize = 8 * int(bytesize.MB)
+
+// ErrNotEmpty returns by restore if storages of deployment already contain data
+var ErrNotEmpty = errors.New("storages of deployment are not empty")
+
+// ErrInvalidArchive returns if archive has no manifest or its entries don't match manifest
+var ErrInvalidArchive = errors.New("invalid backup archive")
+
+// errStopList stops listing of media storage after first object
+var errStopList = errors.New("stop list")
+
+// Verify reads whole archive and checks its entries by checksums of manifest
+func Verify(archive io.Reader) (Manifest, error) {
+	tr := tar.NewReader(archive)
+	found := make(map[string]Entry)
+	var manifest *Manifest
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return Manifest{}, fmt.Errorf("cannot read archive: %w", err)
+		}
+
+		if manifest != nil {
+			return Manifest{}, fmt.Errorf("%w: manifest must be the last entry", ErrInvalidArchive)
+		}
+
+		if header.Name == manifestEntry {
+			manifest = &Manifest{}
+			if err = json.NewDecoder(tr).Decode(manifest); err != nil {
+				return Manifest{}, fmt.Errorf("%w: cannot decode manifest: %w", ErrInvalidArchive, err)
+			}
+
+			continue
+		}
+
+		hash := sha256.New()
+		n, err := io.Copy(hash, tr)
+		if err != nil {
+			return Manifest{}, fmt.Errorf("cannot read entry %s: %w", header.Name, err)
+		}
+
+		found[header.Name] = Entry{Name: header.Name, Size: n, SHA256: hex.EncodeToString(hash.Sum(nil))}
+	}
+
+	if manifest == nil {
+		return Manifest{}, fmt.Errorf("%w: archive has no manifest", ErrInvalidArchive)
+	}
+
+	if manifest.Version < 1 || manifest.Version > FormatVersion {
+		return Manifest{}, fmt.Errorf("%w: unsupported version %d of archive", ErrInvalidArchive, manifest.Version)
+	}
+
+	if len(found) != len(manifest.Entries) {
+		return Manifest{}, fmt.Errorf("%w: archive has %d entries, manifest lists %d", ErrInvalidArchive, len(found), len(manifest.Entries))
+	}
+
+	for _, v := range manifest.Entries {
+		if found[v.Name] != v {
+			return Manifest{}, fmt.Errorf("%w: entry %s is missing or corrupted", ErrInvalidArchive, v.Name)
+		}
+	}
+
+	return *manifest, nil
+}
+
+// Restore verifies archive and restores it into empty storages. Plain storage is restored by one transaction,
+// after that objects are uploaded to media storage. Restore of objects isn't atomic, so storages must be
+// cleared before next try if restore of objects fails. Data is restored as archived, so data encrypted at rest
+// is read by keys of deployment that made archive
+func Restore(ctx context.Context, archive io.ReadSeeker, plain plainstorage.PlainStorage, media mediastorage.MediaStorage) (Manifest, error) {
+	manifest, err := Verify(archive)
+	if err != nil {
+		return manifest, err
+	}
+
+	if err = checkEmpty(ctx, plain, media); err != nil {
+		return manifest, err
+	}
+
+	if _, err = archive.Seek(0, io.SeekStart); err != nil {
+		return manifest, fmt.Errorf("cannot rewind archive: %w", err)
+	}
+
+	tr := tar.NewReader(archive)
+	var header *tar.Header
+	err = plain.InTransaction(ctx, func(tx plainstorage.PlainStorage) error {
+		var restoreErr error
+		header, restoreErr = restorePlainStorage(ctx, tr, tx)
+
+		return restoreErr
+	})
+	if err != nil {
+		return manifest, fmt.Errorf("cannot restore plain storage: %w", err)
+	}
+
+	for header.Name != manifestEntry {
+		key, ok := strings.CutPrefix(header.Name, objectsPrefix)
+		if !ok {
+			return manifest, fmt.Errorf("%w: unknown entry %s", ErrInvalidArchive, header.Name)
+		}
+
+		if err = restoreObject(ctx, media, key, tr); err != nil {
+			return manifest, fmt.Errorf("cannot restore object %s: %w", key, err)
+		}
+
+		if header, err = tr.Next(); err != nil {
+			return manifest, fmt.Errorf("cannot read archive: %w", err)
+		}
+	}
+
+	return manifest, nil
+}
+
+func checkEmpty(ctx context.Context, plain plainstorage.PlainStorage, media mediastorage.MediaStorage) error {
+	users, err := plain.GetUsers(ctx, "", 1)
+	if err != nil {
+		return fmt.Errorf("cannot get users: %w", err)
+	}
+
+	secrets, err := plain.GetSecrets(ctx, "", 1)
+	if err != nil {
+		return fmt.Errorf("cannot get secrets: %w", err)
+	}
+
+	if len(users) > 0 || len(secrets) > 0 {
+		return fmt.Errorf("%w: plain storage has users or secrets", ErrNotEmpty)
+	}
+
+	err = media.List(ctx, func(_ mediastorage.ObjectInfo) error {
+		return errStopList
+	})
+	if errors.Is(err, errStopList) {
+		return fmt.Errorf("%w: media storage has objects", ErrNotEmpty)
+	} else if err != nil {
+		return fmt.Errorf("cannot list media storage: %w", err)
+	}
+
+	return nil
+}
+
+// restorePlainStorage restores records of archive, returns header of the first entry after records
+func restorePlainStorage(ctx context.Context, tr *tar.Reader, storage plainstorage.PlainStorage) (*tar.Header, error) {
+	for {
+		header, err := tr.Next()
+		if err != nil {
+			return nil, fmt.Errorf("cannot read archive: %w", err)
+		}
+
+		switch header.Name {
+		case usersEntry:
+			err = decodeRecords(tr, func(user plainstorage.User) error {
+				return storage.RestoreUser(ctx, user)
+			})
+		case secretsEntry:
+			err = decodeRecords(tr, func(secret plainstorage.PlainSecret) error {
+				return storage.RestoreSecret(ctx, secret)
+			})
+		case chunksEntry:
+			err = decodeRecords(tr, func(chunk plainstorage.MediaChunk) error {
+				return storage.AddMediaChunk(ctx, chunk.OwnerUUID, chunk.Hash, chunk.Size)
+			})
+		case mediaChunksEntry:
+			// references of chunks are counted again by chunks lists of media
+			err = decodeRecords(tr, func(media mediaChunks) error {
+				return storage.SetMediaChunks(ctx, media.OwnerUUID, media.MediaUUID, media.Hashes)
+			})
+		case objectKeysEntry:
+			err = decodeRecords(tr, func(key plainstorage.MediaObjectKey) error {
+				return storage.SetMediaObjectKey(ctx, key)
+			})
+		default:
+			return header, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("cannot restore %s: %w", header.Name, err)
+		}
+	}
+}
+
+func decodeRecords[T any](r io.Reader, restore func(record T) error) error {
+	decoder := json.NewDecoder(r)
+	for {
+		var record T
+		err := decoder.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("cannot decode record: %w", err)
+		}
+
+		if err = restore(record); err != nil {
+			return err
+		}
+	}
+}
+
+func restoreObject(ctx context.Context, media mediastorage.MediaStorage, key string, content io.Reader) error {
+	upload, err := media.StartUpload(ctx, key)
+	if err != nil {
+		return fmt.Errorf("cannot start upload: %w", err)
+	}
+
+	part := make([]byte, uploadPartSize)
+	for {
+		n, err := io.ReadFull(content, part)
+		if n > 0 {
+			if uploadErr := upload.Upload(ctx, part[:n]); uploadErr != nil {
+				return errors.Join(fmt.Errorf("cannot upload part: %w", uploadErr), upload.Abort(ctx))
+			}
+		}
+
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		} else if err != nil {
+			return errors.Join(fmt.Errorf("cannot read object from archive: %w", err), upload.Abort(ctx))
+		}
+	}
+
+	return upload.Complete(ctx)
+}
@@ -0,0 +1,228 @@
+// Package chunkstorage keeps deduplicated chunks of chunked media on top of media storage
+package chunkstorage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"go.uber.org/zap"
+	"io"
+	"sync"
+)
+
+// lockStripes count of locks shared by chunks, chunk takes lock by first byte of its hash
+const lockStripes = 256
+
+var ErrInvalidHash = errors.New("invalid chunk hash")
+
+// ErrHashMismatch returns when content of uploaded chunk doesn't match its hash
+var ErrHashMismatch = errors.New("hash of chunk doesn't match its content")
+
+// ChunkStorage keeps encrypted chunks of media, every chunk of user is stored once as object of media storage.
+// References of chunks are counted by plain storage, object of chunk is removed after last media that uses it
+type ChunkStorage struct {
+	media mediastorage.MediaStorage
+	plain plainstorage.PlainStorage
+
+	// locks serialize put and release of chunks with same hash, so released chunk can't remove object of chunk uploaded again
+	locks [lockStripes]sync.Mutex
+
+	logger *zap.Logger
+}
+
+func NewStorage(media mediastorage.MediaStorage, plain plainstorage.PlainStorage, logger *zap.Logger) *ChunkStorage {
+	return &ChunkStorage{
+		media:  media,
+		plain:  plain,
+		logger: logger,
+	}
+}
+
+// ObjectKey returns key of chunk object in media storage, chunks are deduplicated only between media of one user
+func ObjectKey(userUUID string, hash string) string {
+	return "chunk-" + userUUID + "-" + hash
+}
+
+// ValidateHash checks that hash is hex encoded sha256
+func ValidateHash(hash string) error {
+	decoded, err := hex.DecodeString(hash)
+	if err != nil || len(decoded) != sha256.Size || hex.EncodeToString(decoded) != hash {
+		return fmt.Errorf("%w '%s'", ErrInvalidHash, hash)
+	}
+
+	return nil
+}
+
+func (s *ChunkStorage) lock(hash string) func() {
+	b, _ := hex.DecodeString(hash[:2])
+	mu := &s.locks[b[0]]
+	mu.Lock()
+
+	return mu.Unlock
+}
+
+// Missing returns hashes of chunks that user hasn't stored
+func (s *ChunkStorage) Missing(ctx context.Context, userUUID string, hashes []string) ([]string, error) {
+	for _, v := range hashes {
+		if err := ValidateHash(v); err != nil {
+			return nil, err
+		}
+	}
+
+	existing, err := s.plain.GetExistingMediaChunks(ctx, userUUID, hashes)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get existing chunks: %w", err)
+	}
+
+	found := make(map[string]struct{}, len(existing))
+	for _, v := range existing {
+		found[v] = struct{}{}
+	}
+
+	missing := make([]string, 0)
+	for _, v := range hashes {
+		if _, ok := found[v]; !ok {
+			missing = append(missing, v)
+			found[v] = struct{}{}
+		}
+	}
+
+	return missing, nil
+}
+
+// Put stores chunk of user without references, does nothing if user already has chunk.
+// Check is called by transaction that registers uploaded chunk, its error cancels registration and removes object of chunk
+func (s *ChunkStorage) Put(ctx context.Context, userUUID string, hash string, data []byte, check func(tx plainstorage.PlainStorage) error) error {
+	if err := ValidateHash(hash); err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != hash {
+		return ErrHashMismatch
+	}
+
+	unlock := s.lock(hash)
+	defer unlock()
+
+	existing, err := s.plain.GetExistingMediaChunks(ctx, userUUID, []string{hash})
+	if err != nil {
+		return fmt.Errorf("cannot check existing chunk: %w", err)
+	}
+
+	if len(existing) > 0 {
+		return nil
+	}
+
+	upload, err := s.media.StartUpload(ctx, ObjectKey(userUUID, hash))
+	if err != nil {
+		return fmt.Errorf("cannot start upload of chunk: %w", err)
+	}
+
+	err = upload.Upload(ctx, data)
+	if err == nil {
+		err = upload.Complete(ctx)
+	}
+
+	if err != nil {
+		return errors.Join(fmt.Errorf("cannot upload chunk: %w", err), upload.Abort(ctx))
+	}
+
+	err = s.plain.InTransaction(ctx, func(tx plainstorage.PlainStorage) error {
+		err := tx.AddMediaChunk(ctx, userUUID, hash, int64(len(data)))
+		if err != nil {
+			return fmt.Errorf("cannot register uploaded chunk: %w", err)
+		}
+
+		if check == nil {
+			return nil
+		}
+
+		return check(tx)
+	})
+	if err != nil {
+		deleteErr := s.media.Delete(ctx, ObjectKey(userUUID, hash))
+		if deleteErr != nil && !errors.Is(deleteErr, mediastorage.ErrObjectNotFound) {
+			return errors.Join(err, fmt.Errorf("cannot delete object of unregistered chunk: %w", deleteErr))
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// Open starts download of chunk content
+func (s *ChunkStorage) Open(ctx context.Context, userUUID string, hash string) (io.ReadCloser, error) {
+	rc, err := s.media.StartDownload(ctx, ObjectKey(userUUID, hash))
+	if err != nil {
+		return nil, fmt.Errorf("cannot start download of chunk %s: %w", hash, err)
+	}
+
+	return rc, nil
+}
+
+// Release removes objects of chunks that were left without references by plainstorage.PlainStorage.RemoveMediaChunks.
+// Chunks uploaded again after release keep their objects
+func (s *ChunkStorage) Release(ctx context.Context, userUUID string, hashes []string) error {
+	var errs []error
+	for _, v := range hashes {
+		errs = append(errs, s.release(ctx, userUUID, v))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *ChunkStorage) release(ctx context.Context, userUUID string, hash string) error {
+	if err := ValidateHash(hash); err != nil {
+		return err
+	}
+
+	unlock := s.lock(hash)
+	defer unlock()
+
+	existing, err := s.plain.GetExistingMediaChunks(ctx, userUUID, []string{hash})
+	if err != nil {
+		return fmt.Errorf("cannot check released chunk %s: %w", hash, err)
+	}
+
+	if len(existing) > 0 {
+		s.logger.Debug("Released chunk was uploaded again", zap.String("hash", hash))
+
+		return nil
+	}
+
+	err = s.media.Delete(ctx, ObjectKey(userUUID, hash))
+	if err != nil && !errors.Is(err, mediastorage.ErrObjectNotFound) {
+		return fmt.Errorf("cannot delete object of chunk %s: %w", hash, err)
+	}
+
+	return nil
+}
+
+// Collect removes chunk of user that is not used by any media, returns plainstorage.ErrEntityNotFound
+// if user has no chunk or chunk is used
+func (s *ChunkStorage) Collect(ctx context.Context, userUUID string, hash string) error {
+	if err := ValidateHash(hash); err != nil {
+		return err
+	}
+
+	unlock := s.lock(hash)
+	defer unlock()
+
+	err := s.plain.RemoveUnusedMediaChunk(ctx, userUUID, hash)
+	if err != nil {
+		return fmt.Errorf("cannot remove unused chunk %s: %w", hash, err)
+	}
+
+	err = s.media.Delete(ctx, ObjectKey(userUUID, hash))
+	if err != nil && !errors.Is(err, mediastorage.ErrObjectNotFound) {
+		return fmt.Errorf("cannot delete object of chunk %s: %w", hash, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package chunkstorage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/google/uuid"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage/mediastoragetest"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func testHash(data []byte) string {
+	hash := sha256.Sum256(data)
+
+	return hex.EncodeToString(hash[:])
+}
+
+func TestChunkStorage(t *testing.T) {
+	ctx := context.Background()
+	media := &mediastorage.MediaStorageLocal{StorageDir: t.TempDir()}
+	plain := &plainstorage.MemoryStorage{}
+	storage := NewStorage(media, plain, zap.NewNop())
+
+	userUUID := uuid.New().String()
+	chunk := []byte("some encrypted chunk")
+	hash := testHash(chunk)
+
+	assert.ErrorIs(t, storage.Put(ctx, userUUID, hash, []byte("other chunk"), nil), ErrHashMismatch)
+	assert.ErrorIs(t, storage.Put(ctx, userUUID, "ABC", chunk, nil), ErrInvalidHash)
+	assert.ErrorIs(t, storage.Put(ctx, userUUID, hex.EncodeToString(make([]byte, 32))[:62]+"ZZ", chunk, nil), ErrInvalidHash)
+
+	missing, err := storage.Missing(ctx, userUUID, []string{hash, hash})
+	require.NoError(t, err)
+	assert.Equal(t, []string{hash}, missing, "missing chunks must be returned once")
+
+	require.NoError(t, storage.Put(ctx, userUUID, hash, chunk, nil))
+	require.NoError(t, storage.Put(ctx, userUUID, hash, chunk, nil), "put of existing chunk must be ignored")
+
+	missing, err = storage.Missing(ctx, userUUID, []string{hash})
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+
+	rc, err := storage.Open(ctx, userUUID, hash)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	assert.Equal(t, chunk, mediastoragetest.DownloadObject(t, media, ObjectKey(userUUID, hash)))
+
+	// chunk is registered again, so release must keep its object
+	require.NoError(t, storage.Release(ctx, userUUID, []string{hash}))
+	assert.Equal(t, chunk, mediastoragetest.DownloadObject(t, media, ObjectKey(userUUID, hash)))
+
+	mediaUUID := uuid.New().String()
+	require.NoError(t, plain.SetMediaChunks(ctx, userUUID, mediaUUID, []string{hash}))
+	released, err := plain.RemoveMediaChunks(ctx, mediaUUID)
+	require.NoError(t, err)
+	require.NoError(t, storage.Release(ctx, userUUID, released))
+
+	_, err = storage.Open(ctx, userUUID, hash)
+	assert.ErrorIs(t, err, mediastorage.ErrObjectNotFound)
+
+	require.NoError(t, storage.Release(ctx, userUUID, released), "release of removed object must be ignored")
+}
+
+func TestChunkStorage_Collect(t *testing.T) {
+	ctx := context.Background()
+	media := &mediastorage.MediaStorageLocal{StorageDir: t.TempDir()}
+	plain := &plainstorage.MemoryStorage{}
+	storage := NewStorage(media, plain, zap.NewNop())
+
+	userUUID := uuid.New().String()
+	used, unused := []byte("used chunk"), []byte("unused chunk")
+	require.NoError(t, storage.Put(ctx, userUUID, testHash(used), used, nil))
+	require.NoError(t, storage.Put(ctx, userUUID, testHash(unused), unused, nil))
+	require.NoError(t, plain.SetMediaChunks(ctx, userUUID, uuid.New().String(), []string{testHash(used)}))
+
+	assert.ErrorIs(t, storage.Collect(ctx, userUUID, testHash(used)), plainstorage.ErrEntityNotFound)
+	assert.Equal(t, used, mediastoragetest.DownloadObject(t, media, ObjectKey(userUUID, testHash(used))))
+
+	require.NoError(t, storage.Collect(ctx, userUUID, testHash(unused)))
+	_, err := storage.Open(ctx, userUUID, testHash(unused))
+	assert.ErrorIs(t, err, mediastorage.ErrObjectNotFound)
+
+	missing, err := storage.Missing(ctx, userUUID, []string{testHash(unused)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{testHash(unused)}, missing, "collected chunk must be uploaded again")
+}
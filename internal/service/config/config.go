@@ -6,8 +6,17 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"github.com/nessai1/gophkeeper/pkg/bytesize"
 	"os"
 	"sync"
+	"time"
+)
+
+const (
+	defaultReaperInterval = Duration(time.Minute)
+
+	// defaultFsckGracePeriod age of objects and chunks after that they can be removed by consistency check
+	defaultFsckGracePeriod = Duration(24 * time.Hour)
 )
 
 type Config struct {
@@ -19,11 +28,83 @@ type Config struct {
 
 	PlainStorageConfig *PlainStorageConfig `json:"plain_storage"`
 
+	// MediaStorage driver of media storage, without driver media storage is selected by s3 or filesystem section
+	MediaStorage *DriverConfig `json:"media_storage"`
+
+	// S3Config media storage in S3, used if media storage driver is not set
 	S3Config *S3Config `json:"s3"`
 
+	// FilesystemConfig media storage in local directory, used if neither media storage driver nor S3 is configured
+	FilesystemConfig *FilesystemConfig `json:"filesystem"`
+
+	// ReaperInterval interval between removes of expired secrets
+	ReaperInterval Duration `json:"reaper_interval"`
+
+	// UserQuota limits of storage consumption of every user, nil for unlimited users
+	UserQuota *UserQuotaConfig `json:"user_quota"`
+
+	// Fsck periodic consistency check of media storage
+	Fsck FsckConfig `json:"fsck"`
+
+	// Encryption encryption at rest of plain secrets data and media objects, nil stores them as sent by clients
+	Encryption *EncryptionConfig `json:"encryption"`
+
 	FileConfigPath string
 }
 
+// Duration time.Duration that unmarshal from JSON string like "1h30m"
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var raw string
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return fmt.Errorf("duration must be a string: %w", err)
+	}
+
+	parsed, err := time.ParseDuration(raw)
+	if err != nil {
+		return fmt.Errorf("cannot parse duration '%s': %w", raw, err)
+	}
+
+	*d = Duration(parsed)
+
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// ByteSize size that unmarshal from JSON string like "10GB" or number of bytes
+type ByteSize bytesize.ByteSize
+
+func (b *ByteSize) UnmarshalJSON(raw []byte) error {
+	var n int64
+	if err := json.Unmarshal(raw, &n); err == nil {
+		if n < 0 {
+			return fmt.Errorf("size can't be negative")
+		}
+
+		*b = ByteSize(n)
+
+		return nil
+	}
+
+	var str string
+	if err := json.Unmarshal(raw, &str); err != nil {
+		return fmt.Errorf("size must be a string or number: %w", err)
+	}
+
+	parsed, err := bytesize.Parse(str)
+	if err != nil {
+		return fmt.Errorf("cannot parse size: %w", err)
+	}
+
+	*b = ByteSize(parsed)
+
+	return nil
+}
+
 type TLSCredentials struct {
 	// Path to server crt file
 	Crt string `json:"crt"`
@@ -38,10 +119,93 @@ type S3Config struct {
 	SigningRegion string `json:"signing_region"`
 
 	Credentials *S3Credentials `json:"credentials"`
+
+	// PathStyle addresses bucket by path of URL instead of subdomain, required by most S3-compatible storages
+	PathStyle bool `json:"path_style"`
+	// PresignedURLs allows clients to transfer media directly with S3 by presigned URLs
+	PresignedURLs bool `json:"presigned_urls"`
+	// PresignTTL lifetime of presigned URLs
+	PresignTTL Duration `json:"presign_ttl"`
+}
+
+type FilesystemConfig struct {
+	// Dir directory of stored media, creates if not exists
+	Dir string `json:"dir"`
+	// Quota max size of all stored media, 0 for unlimited storage
+	Quota ByteSize `json:"quota"`
 }
 
+// UserQuotaConfig limits of storage consumption of every user, zero values mean no limit
+type UserQuotaConfig struct {
+	// MaxBytes max size of stored media content and plain secrets data
+	MaxBytes ByteSize `json:"max_bytes"`
+	// MaxMedia max count of media secrets
+	MaxMedia int `json:"max_media"`
+	// MaxSecrets max count of plain secrets
+	MaxSecrets int `json:"max_secrets"`
+}
+
+// FsckConfig consistency check of media storage, that finds objects without secrets and secrets without objects
+type FsckConfig struct {
+	// Interval interval between checks, 0 disables periodic check
+	Interval Duration `json:"interval"`
+	// Delete removes found orphan objects and unused chunks, otherwise they are only reported
+	Delete bool `json:"delete"`
+	// GracePeriod objects and chunks younger than grace period are skipped, so check doesn't touch active uploads
+	GracePeriod Duration `json:"grace_period"`
+}
+
+// EncryptionConfig key-encryption keys (KEK) of encryption at rest. Key is 32 bytes encoded by base64,
+// key file contains 32 raw bytes or base64 encoded key
+type EncryptionConfig struct {
+	// Key current KEK, it wraps data keys of new secrets and objects. Used if key file is not set
+	Key     string `json:"key"`
+	KeyFile string `json:"key_file"`
+	// PreviousKeys and PreviousKeyFiles keys replaced by current key, they only unwrap data keys
+	// until rotate-kek rewraps them by current key
+	PreviousKeys     []string `json:"previous_keys"`
+	PreviousKeyFiles []string `json:"previous_key_files"`
+}
+
+// DriverConfig storage driver selected by name, options are specific for driver and decoded by it
+type DriverConfig struct {
+	Driver  string          `json:"driver"`
+	Options json.RawMessage `json:"options"`
+}
+
+// Names of storage drivers
+const (
+	DriverS3         = "s3"
+	DriverFilesystem = "filesystem"
+	DriverMemory     = "memory"
+	DriverMulti      = "multi"
+	DriverMirror     = "mirror"
+	DriverPostgres   = "postgres"
+	DriverSQLite     = "sqlite"
+)
+
+// MultiStorageConfig options of multi media storage, new objects are written to the first backend
+// and objects are read from the first backend that has them
+type MultiStorageConfig struct {
+	Backends []DriverConfig `json:"backends"`
+}
+
+// MirrorStorageConfig options of mirror media storage, that writes every object to both backends
+type MirrorStorageConfig struct {
+	Primary   DriverConfig `json:"primary"`
+	Secondary DriverConfig `json:"secondary"`
+	// RepairInterval interval between copies of objects missing from one backend, 0 disables repair
+	RepairInterval Duration `json:"repair_interval"`
+	// RepairGracePeriod objects younger than grace period are not repaired, so repair doesn't touch active uploads
+	RepairGracePeriod Duration `json:"repair_grace_period"`
+}
+
+// PlainStorageConfig plain storage by driver, without driver storage is selected by postgres or sqlite section
 type PlainStorageConfig struct {
-	PSQLStorage *PSQLPlainStorageConfig `json:"postgres"`
+	DriverConfig
+
+	PSQLStorage   *PSQLPlainStorageConfig   `json:"postgres"`
+	SQLiteStorage *SQLitePlainStorageConfig `json:"sqlite"`
 }
 
 type PSQLPlainStorageConfig struct {
@@ -52,6 +216,11 @@ type PSQLPlainStorageConfig struct {
 	DBName   string `json:"dbname"`
 }
 
+type SQLitePlainStorageConfig struct {
+	// Path to database file, creates if not exists
+	Path string `json:"path"`
+}
+
 type S3Credentials struct {
 	AccessKeyID     string `json:"access_key_id"`
 	SecretAccessKey string `json:"secret_access_key"`
@@ -139,9 +308,99 @@ func mergeConfigs(flagConfig, fileConfig Config) (Config, error) {
 		return Config{}, errors.New("any one config must have secret token")
 	}
 
-	if fileConfig.S3Config == nil {
-		return Config{}, errors.New("service must have storage config (S3)")
+	mediaStorage, err := mediaStorageDriver(fileConfig)
+	if err != nil {
+		return Config{}, err
+	}
+	fileConfig.MediaStorage = mediaStorage
+
+	plainStorage, err := plainStorageDriver(fileConfig.PlainStorageConfig)
+	if err != nil {
+		return Config{}, err
+	}
+	fileConfig.PlainStorageConfig = plainStorage
+
+	if fileConfig.UserQuota != nil && (fileConfig.UserQuota.MaxBytes < 0 || fileConfig.UserQuota.MaxMedia < 0 || fileConfig.UserQuota.MaxSecrets < 0) {
+		return Config{}, errors.New("user quota can't be negative")
+	}
+
+	if fileConfig.ReaperInterval <= 0 {
+		fileConfig.ReaperInterval = defaultReaperInterval
+	}
+
+	if fileConfig.Fsck.Interval < 0 {
+		return Config{}, errors.New("fsck interval can't be negative")
+	}
+
+	if fileConfig.Fsck.GracePeriod <= 0 {
+		fileConfig.Fsck.GracePeriod = defaultFsckGracePeriod
+	}
+
+	if fileConfig.Encryption != nil && fileConfig.Encryption.Key == "" && fileConfig.Encryption.KeyFile == "" {
+		return Config{}, errors.New("encryption must have key or key file")
 	}
 
 	return fileConfig, nil
 }
+
+// mediaStorageDriver returns configured driver of media storage, s3 or filesystem section is converted to driver
+// if driver is not set. S3 is preferred if both S3 and filesystem are configured
+func mediaStorageDriver(c Config) (*DriverConfig, error) {
+	if c.MediaStorage != nil {
+		if c.MediaStorage.Driver == "" {
+			return nil, errors.New("media storage must have driver")
+		}
+
+		return c.MediaStorage, nil
+	}
+
+	if c.S3Config != nil {
+		return newDriverConfig(DriverS3, c.S3Config)
+	}
+
+	if c.FilesystemConfig != nil {
+		return newDriverConfig(DriverFilesystem, c.FilesystemConfig)
+	}
+
+	return nil, errors.New("service must have media storage config (media_storage driver, S3 or filesystem)")
+}
+
+// plainStorageDriver returns config of plain storage with driver, postgres or sqlite section is converted to driver
+// if driver is not set. Postgres is preferred if both postgres and sqlite are configured
+func plainStorageDriver(c *PlainStorageConfig) (*PlainStorageConfig, error) {
+	if c == nil {
+		return nil, errors.New("service must have plain storage config")
+	}
+
+	if c.Driver != "" {
+		return c, nil
+	}
+
+	var driver *DriverConfig
+	var err error
+	if c.PSQLStorage != nil {
+		driver, err = newDriverConfig(DriverPostgres, c.PSQLStorage)
+	} else if c.SQLiteStorage != nil {
+		driver, err = newDriverConfig(DriverSQLite, c.SQLiteStorage)
+	} else {
+		return nil, errors.New("plain storage must have driver, postgres or sqlite config")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	converted := *c
+	converted.DriverConfig = *driver
+
+	return &converted, nil
+}
+
+func newDriverConfig(driver string, options any) (*DriverConfig, error) {
+	raw, err := json.Marshal(options)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal options of %s driver: %w", driver, err)
+	}
+
+	return &DriverConfig{Driver: driver, Options: raw}, nil
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/logger"
+	"github.com/nessai1/gophkeeper/internal/service/chunkstorage"
+	"github.com/nessai1/gophkeeper/internal/service/config"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"go.uber.org/zap"
+	"log"
+	"os"
+	"time"
+)
+
+// FsckReport result of consistency check of media storage
+type FsckReport struct {
+	// Objects count of checked objects of media storage
+	Objects int
+	// Orphans objects older than grace period that belong to no media and no chunk
+	Orphans []mediastorage.ObjectInfo
+	// UnusedChunks chunks older than grace period that are used by no media
+	UnusedChunks []plainstorage.MediaChunk
+	// MissingMedia media stored as single object, whose object is missing
+	MissingMedia []plainstorage.SecretMetadata
+	// MissingChunks chunks whose object is missing
+	MissingChunks []plainstorage.MediaChunk
+
+	// RemovedOrphans count of deleted orphans, RemovedChunks count of removed unused chunks
+	RemovedOrphans int
+	RemovedChunks  int
+}
+
+// Consistent reports that check found no problems or all found problems are fixed
+func (r FsckReport) Consistent() bool {
+	return len(r.MissingMedia) == 0 && len(r.MissingChunks) == 0 &&
+		r.RemovedOrphans == len(r.Orphans) && r.RemovedChunks == len(r.UnusedChunks)
+}
+
+// RunFsck checks consistency of media storage once, prints report and exits with code 1 if storage stays inconsistent.
+// Filesystem storage removes temporary files of uploads on open, so check of filesystem storage must run while service
+// is stopped, running service checks its storage by fsck.interval of config
+func RunFsck() {
+	remove := flag.Bool("delete", false, "Delete orphan objects and unused chunks")
+	gracePeriod := flag.Duration("grace", 0, "Skip objects and chunks younger than grace period (fsck.grace_period of config by default)")
+
+	c, err := config.FetchConfig()
+	if err != nil {
+		log.Fatalf("Cannot fetch config for service: %s", err.Error())
+	}
+
+	l, err := logger.BuildLogger(logger.LevelDev, os.Stdout)
+	if err != nil {
+		log.Fatalf("Cannot build logger: %s", err.Error())
+	}
+
+	// storages are used only by command, so their background jobs stop on return
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ms, s, err := buildStorages(ctx, c, l)
+	if err != nil {
+		log.Fatalf("Cannot build storages: %s", err.Error())
+	}
+
+	server := Server{
+		ctx:          ctx,
+		mediaStorage: ms,
+		plainStorage: s,
+		chunkStorage: chunkstorage.NewStorage(ms, s, l),
+		logger:       l,
+		config:       c,
+	}
+
+	fsckConfig := c.Fsck
+	fsckConfig.Delete = *remove
+	if *gracePeriod > 0 {
+		fsckConfig.GracePeriod = config.Duration(*gracePeriod)
+	}
+
+	report, err := server.fsck(ctx, fsckConfig)
+	if err != nil {
+		log.Fatalf("Cannot check media storage: %s", err.Error())
+	}
+
+	printFsckReport(report)
+	if !report.Consistent() {
+		os.Exit(1)
+	}
+}
+
+func printFsckReport(report FsckReport) {
+	for _, v := range report.Orphans {
+		fmt.Printf("Orphan object %s (%d bytes, modified %s)\n", v.Key, v.Size, v.Modified.Format(time.RFC3339))
+	}
+
+	for _, v := range report.UnusedChunks {
+		fmt.Printf("Unused chunk %s of user %s (%d bytes, created %s)\n", v.Hash, v.OwnerUUID, v.Size, v.Created.Format(time.RFC3339))
+	}
+
+	for _, v := range report.MissingMedia {
+		fmt.Printf("Missing object of media %s of user %s\n", v.UUID, v.UserUUID)
+	}
+
+	for _, v := range report.MissingChunks {
+		fmt.Printf("Missing object of chunk %s of user %s (%d references)\n", v.Hash, v.OwnerUUID, v.Refs)
+	}
+
+	fmt.Printf("Checked %d objects: %d orphans (%d deleted), %d unused chunks (%d removed), %d missing media, %d missing chunks\n",
+		report.Objects,
+		len(report.Orphans), report.RemovedOrphans,
+		len(report.UnusedChunks), report.RemovedChunks,
+		len(report.MissingMedia), len(report.MissingChunks),
+	)
+}
+
+// runFsck periodically checks consistency of media storage until context is done
+func (s *Server) runFsck(ctx context.Context, cfg config.FsckConfig) {
+	ticker := time.NewTicker(time.Duration(cfg.Interval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := s.fsck(ctx, cfg)
+			if err != nil {
+				s.logger.Error("Cannot check media storage", zap.Error(err))
+
+				continue
+			}
+
+			if !report.Consistent() {
+				s.logger.Warn(
+					"Media storage is inconsistent",
+					zap.Int("orphans", len(report.Orphans)),
+					zap.Int("unused_chunks", len(report.UnusedChunks)),
+					zap.Int("missing_media", len(report.MissingMedia)),
+					zap.Int("missing_chunks", len(report.MissingChunks)),
+				)
+			}
+		}
+	}
+}
+
+// fsck cross-checks objects of media storage with media and chunks of plain storage. Objects without media and chunks,
+// and chunks without references are reported and removed if cfg.Delete is set. Objects and chunks younger than grace
+// period are skipped, because upload stores object before its metadata.
+// Media and chunks without objects are only reported, their content can't be restored
+func (s *Server) fsck(ctx context.Context, cfg config.FsckConfig) (FsckReport, error) {
+	// plain storage is read before listing of objects, so object of media saved while check is younger than grace period
+	media, err := s.plainStorage.GetObjectMediaMetadata(ctx)
+	if err != nil {
+		return FsckReport{}, fmt.Errorf("cannot get media metadata: %w", err)
+	}
+
+	chunks, err := s.plainStorage.GetAllMediaChunks(ctx)
+	if err != nil {
+		return FsckReport{}, fmt.Errorf("cannot get media chunks: %w", err)
+	}
+
+	deadline := time.Now().Add(-time.Duration(cfg.GracePeriod))
+	report := FsckReport{}
+
+	known := make(map[string]bool, len(media)+len(chunks))
+	for _, v := range media {
+		known[v.UUID] = false
+	}
+	for _, v := range chunks {
+		known[chunkstorage.ObjectKey(v.OwnerUUID, v.Hash)] = false
+	}
+
+	err = s.mediaStorage.List(ctx, func(info mediastorage.ObjectInfo) error {
+		report.Objects++
+
+		if _, ok := known[info.Key]; ok {
+			known[info.Key] = true
+
+			return nil
+		}
+
+		if info.Modified.Before(deadline) {
+			report.Orphans = append(report.Orphans, info)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return FsckReport{}, fmt.Errorf("cannot list media objects: %w", err)
+	}
+
+	for _, v := range media {
+		if !known[v.UUID] {
+			report.MissingMedia = append(report.MissingMedia, v)
+		}
+	}
+
+	for _, v := range chunks {
+		if v.Refs == 0 {
+			// chunk without references loses no content without object
+			if v.Created.Before(deadline) {
+				report.UnusedChunks = append(report.UnusedChunks, v)
+			}
+
+			continue
+		}
+
+		if !known[chunkstorage.ObjectKey(v.OwnerUUID, v.Hash)] {
+			report.MissingChunks = append(report.MissingChunks, v)
+		}
+	}
+
+	for _, v := range report.MissingMedia {
+		s.logger.Error("Media object is missing", zap.String("media_uuid", v.UUID), zap.String("owner_uuid", v.UserUUID))
+	}
+
+	for _, v := range report.MissingChunks {
+		s.logger.Error("Chunk object is missing", zap.String("hash", v.Hash), zap.String("owner_uuid", v.OwnerUUID), zap.Int("refs", v.Refs))
+	}
+
+	if cfg.Delete {
+		s.removeOrphans(ctx, &report)
+	}
+
+	return report, nil
+}
+
+// removeOrphans deletes orphan objects and unused chunks of report
+func (s *Server) removeOrphans(ctx context.Context, report *FsckReport) {
+	for _, v := range report.Orphans {
+		err := s.mediaStorage.Delete(ctx, v.Key)
+		if err != nil && !errors.Is(err, mediastorage.ErrObjectNotFound) {
+			s.logger.Error("Cannot delete orphan object", zap.Error(err), zap.String("key", v.Key))
+
+			continue
+		}
+
+		s.logger.Info("Orphan object deleted", zap.String("key", v.Key), zap.Int64("size", v.Size))
+		report.RemovedOrphans++
+	}
+
+	for _, v := range report.UnusedChunks {
+		err := s.chunkStorage.Collect(ctx, v.OwnerUUID, v.Hash)
+		if errors.Is(err, plainstorage.ErrEntityNotFound) {
+			// chunk is committed to media while check
+			s.logger.Debug("Unused chunk is used again", zap.String("hash", v.Hash), zap.String("owner_uuid", v.OwnerUUID))
+		} else if err != nil {
+			s.logger.Error("Cannot remove unused chunk", zap.Error(err), zap.String("hash", v.Hash), zap.String("owner_uuid", v.OwnerUUID))
+
+			continue
+		} else {
+			s.logger.Info("Unused chunk removed", zap.String("hash", v.Hash), zap.String("owner_uuid", v.OwnerUUID))
+		}
+
+		report.RemovedChunks++
+	}
+}
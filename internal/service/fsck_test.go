@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/google/uuid"
+	"github.com/nessai1/gophkeeper/internal/service/chunkstorage"
+	"github.com/nessai1/gophkeeper/internal/service/config"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage/mediastoragetest"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestServer_fsck(t *testing.T) {
+	ctx := context.Background()
+	server, media, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	old := time.Now().Add(-48 * time.Hour)
+	userUUID := uuid.New().String()
+
+	// uploadObject stores object with modification time
+	uploadObject := func(key string, modified time.Time) {
+		mediastoragetest.UploadObject(t, media, key, []byte("content of "+key), 16)
+		require.NoError(t, os.Chtimes(filepath.Join(media.StorageDir, key), modified, modified))
+	}
+
+	storedUUID, missingUUID := uuid.New().String(), uuid.New().String()
+	uploadObject(storedUUID, old)
+	for _, v := range []string{storedUUID, missingUUID} {
+		plain.SecretList = append(plain.SecretList, plainstorage.PlainSecret{Metadata: plainstorage.SecretMetadata{UUID: v, UserUUID: userUUID, Name: v, Type: plainstorage.SecretTypeMedia}})
+	}
+
+	orphanKey, freshKey := uuid.New().String(), uuid.New().String()
+	uploadObject(orphanKey, old)
+	uploadObject(freshKey, time.Now())
+
+	hash := func(content string) string {
+		sum := sha256.Sum256([]byte(content))
+
+		return hex.EncodeToString(sum[:])
+	}
+	used, lost, unused, fresh := hash("used"), hash("lost"), hash("unused"), hash("fresh")
+	uploadObject(chunkstorage.ObjectKey(userUUID, used), old)
+	uploadObject(chunkstorage.ObjectKey(userUUID, unused), old)
+	uploadObject(chunkstorage.ObjectKey(userUUID, fresh), old)
+	plain.MediaChunks = append(plain.MediaChunks,
+		plainstorage.MediaChunk{OwnerUUID: userUUID, Hash: used, Size: 1, Refs: 1, Created: old},
+		plainstorage.MediaChunk{OwnerUUID: userUUID, Hash: lost, Size: 1, Refs: 2, Created: old},
+		plainstorage.MediaChunk{OwnerUUID: userUUID, Hash: unused, Size: 1, Created: old},
+		plainstorage.MediaChunk{OwnerUUID: userUUID, Hash: fresh, Size: 1, Created: time.Now()},
+	)
+
+	cfg := config.FsckConfig{GracePeriod: config.Duration(24 * time.Hour)}
+	report, err := server.fsck(ctx, cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, 6, report.Objects)
+	require.Len(t, report.Orphans, 1)
+	assert.Equal(t, orphanKey, report.Orphans[0].Key, "fresh object must be skipped by grace period")
+	require.Len(t, report.UnusedChunks, 1)
+	assert.Equal(t, unused, report.UnusedChunks[0].Hash, "fresh chunk must be skipped by grace period")
+	require.Len(t, report.MissingMedia, 1)
+	assert.Equal(t, missingUUID, report.MissingMedia[0].UUID)
+	require.Len(t, report.MissingChunks, 1)
+	assert.Equal(t, lost, report.MissingChunks[0].Hash)
+	assert.Zero(t, report.RemovedOrphans)
+	assert.Zero(t, report.RemovedChunks)
+	assert.False(t, report.Consistent())
+
+	mediastoragetest.DownloadObject(t, media, orphanKey)
+
+	cfg.Delete = true
+	report, err = server.fsck(ctx, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.RemovedOrphans)
+	assert.Equal(t, 1, report.RemovedChunks)
+
+	_, err = media.StartDownload(ctx, orphanKey)
+	assert.ErrorIs(t, err, mediastorage.ErrObjectNotFound)
+	_, err = media.StartDownload(ctx, chunkstorage.ObjectKey(userUUID, unused))
+	assert.ErrorIs(t, err, mediastorage.ErrObjectNotFound)
+	mediastoragetest.DownloadObject(t, media, freshKey)
+	mediastoragetest.DownloadObject(t, media, storedUUID)
+
+	existing, err := plain.GetExistingMediaChunks(ctx, userUUID, []string{used, lost, unused, fresh})
+	require.NoError(t, err)
+	assert.Equal(t, []string{used, lost, fresh}, existing)
+
+	report, err = server.fsck(ctx, cfg)
+	require.NoError(t, err)
+	assert.Empty(t, report.Orphans)
+	assert.Empty(t, report.UnusedChunks)
+	assert.Len(t, report.MissingMedia, 1, "media without object must be kept")
+	assert.Len(t, report.MissingChunks, 1)
+}
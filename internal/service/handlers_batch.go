@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"errors"
+	pb "github.com/nessai1/gophkeeper/api/proto"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxBatchSize max count of items in one batch request
+const maxBatchSize = 1000
+
+// errBatchAborted returns from batch transaction when some items failed, results of items contain failure reasons
+var errBatchAborted = errors.New("batch aborted")
+
+func (s *Server) BatchSet(ctx context.Context, request *pb.BatchSetRequest) (*pb.BatchSetResponse, error) {
+	userCtxVal := ctx.Value(UserContextKey)
+	user := userCtxVal.(*plainstorage.User)
+
+	s.logger.Info("User try set batch of secrets", zap.String("login", user.Login), zap.Int("count", len(request.GetItems())))
+
+	if err := validateBatchSize(len(request.GetItems())); err != nil {
+		return nil, err
+	}
+
+	results := make([]*pb.BatchItemResult, len(request.GetItems()))
+	secrets := make([]plainstorage.NewPlainSecret, len(request.GetItems()))
+	failed := false
+	for i, v := range request.GetItems() {
+		results[i] = &pb.BatchItemResult{SecretType: v.GetSecretType(), Name: v.GetName()}
+
+		secret, err := buildNewPlainSecret(v)
+		if err != nil {
+			setBatchItemError(results[i], err)
+			failed = true
+
+			continue
+		}
+
+		secrets[i] = secret
+	}
+
+	if !failed {
+		failed = s.markExistingSecrets(ctx, user.UUID, secrets, results)
+	}
+
+	if failed {
+		return &pb.BatchSetResponse{Results: abortBatchResults(results)}, nil
+	}
+
+	var size int64
+	for _, v := range secrets {
+		size += int64(len(v.Data))
+	}
+
+	_, err := s.checkQuota(ctx, user, false, len(secrets), size)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.plainStorage.InTransaction(ctx, func(tx plainstorage.PlainStorage) error {
+		_, err := tx.AddPlainSecrets(ctx, user.UUID, secrets)
+		if err != nil && errors.Is(plainstorage.ErrEntityAlreadyExists, err) {
+			s.logger.Info("User try to add existing secrets in batch", zap.String("login", user.Login))
+
+			return status.Error(codes.AlreadyExists, "secrets with names of batch already exist")
+		} else if err != nil {
+			s.logger.Error("Cannot add batch of plain secrets", zap.String("login", user.Login), zap.Error(err))
+
+			return status.Error(codes.Internal, "internal error while save batch of secrets")
+		}
+
+		return s.checkStoredQuota(ctx, tx, user, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.BatchSetResponse{Results: results, Committed: true}, nil
+}
+
+// buildNewPlainSecret validates item of batch set request, returns grpc status error
+func buildNewPlainSecret(request *pb.SecretSetRequest) (plainstorage.NewPlainSecret, error) {
+	secretType, err := translateGRPCSecretTypeToSecretType(request.GetSecretType())
+	if err != nil {
+		return plainstorage.NewPlainSecret{}, status.Error(codes.InvalidArgument, "invalid secret type got")
+	}
+
+	if secretType == plainstorage.SecretTypeMedia {
+		return plainstorage.NewPlainSecret{}, status.Error(codes.InvalidArgument, "cannot set media secret to plain storage")
+	}
+
+	if request.GetName() == "" {
+		return plainstorage.NewPlainSecret{}, status.Error(codes.InvalidArgument, "secret name can't be empty")
+	}
+
+	expiresAt, maxReads, err := translateGRPCSecretLimits(request.GetExpireTimestamp(), request.GetMaxReads())
+	if err != nil {
+		return plainstorage.NewPlainSecret{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return plainstorage.NewPlainSecret{
+		Name:          request.GetName(),
+		EncryptedName: request.GetEncryptedName(),
+		Type:          secretType,
+		Data:          request.GetContent(),
+		Folder:        request.GetFolder(),
+		ExpiresAt:     expiresAt,
+		MaxReads:      maxReads,
+	}, nil
+}
+
+// markExistingSecrets sets AlreadyExists to results of secrets that user has or that repeat in batch, returns true if any found
+func (s *Server) markExistingSecrets(ctx context.Context, userUUID string, secrets []plainstorage.NewPlainSecret, results []*pb.BatchItemResult) bool {
+	names := make([]string, len(secrets))
+	for i, v := range secrets {
+		names[i] = v.Name
+	}
+
+	existing, err := s.plainStorage.GetUserSecretsMetadata(ctx, userUUID, plainstorage.SecretFilter{AnyType: true, Names: names}, plainstorage.SecretPage{})
+	if err != nil {
+		s.logger.Error("Cannot get existing secrets of batch", zap.Error(err), zap.String("user_uuid", userUUID))
+		for _, v := range results {
+			setBatchItemError(v, status.Error(codes.Internal, "internal error while check secret"))
+		}
+
+		return true
+	}
+
+	type secretKey struct {
+		name       string
+		secretType plainstorage.SecretType
+	}
+
+	taken := make(map[secretKey]struct{}, len(existing)+len(secrets))
+	for _, v := range existing {
+		taken[secretKey{name: v.Name, secretType: v.Type}] = struct{}{}
+	}
+
+	found := false
+	for i, v := range secrets {
+		key := secretKey{name: v.Name, secretType: v.Type}
+		if _, ok := taken[key]; ok {
+			setBatchItemError(results[i], status.Error(codes.AlreadyExists, "secret with name already exists"))
+			found = true
+		}
+
+		taken[key] = struct{}{}
+	}
+
+	return found
+}
+
+func (s *Server) BatchGet(ctx context.Context, request *pb.BatchGetRequest) (*pb.BatchGetResponse, error) {
+	userCtxVal := ctx.Value(UserContextKey)
+	user := userCtxVal.(*plainstorage.User)
+
+	s.logger.Info("User try get batch of secrets", zap.String("login", user.Login), zap.Int("count", len(request.GetItems())))
+
+	if err := validateBatchSize(len(request.GetItems())); err != nil {
+		return nil, err
+	}
+
+	results := make([]*pb.BatchItemResult, len(request.GetItems()))
+	for i, v := range request.GetItems() {
+		results[i] = &pb.BatchItemResult{SecretType: v.GetSecretType(), Name: v.GetName()}
+	}
+
+	// secrets are read by transaction that registers their reads, so concurrent read can't spend same read of secret
+	secrets := make([]*plainstorage.PlainSecret, len(request.GetItems()))
+	err := s.plainStorage.InTransaction(ctx, func(tx plainstorage.PlainStorage) error {
+		failed := false
+		for i, v := range request.GetItems() {
+			secret, err := s.getBatchPlainSecret(ctx, tx, user.UUID, v.GetSecretType(), v.GetName())
+			if err != nil {
+				setBatchItemError(results[i], err)
+				failed = true
+
+				continue
+			}
+
+			secrets[i] = secret
+		}
+
+		if failed {
+			return errBatchAborted
+		}
+
+		for i, v := range secrets {
+			if !v.Metadata.HasLimits() {
+				continue
+			}
+
+			md, err := s.registerSecretRead(ctx, tx, v.Metadata)
+			if err != nil {
+				setBatchItemError(results[i], err)
+
+				return errBatchAborted
+			}
+
+			v.Metadata = *md
+		}
+
+		return nil
+	})
+
+	if err != nil && !errors.Is(err, errBatchAborted) {
+		s.logger.Error("Cannot read batch of secrets", zap.Error(err), zap.String("login", user.Login))
+
+		return nil, status.Error(codes.Internal, "internal error while read batch of secrets")
+	}
+
+	failed := err != nil
+
+	getResults := make([]*pb.BatchGetResult, len(results))
+	if failed {
+		for i, v := range abortBatchResults(results) {
+			getResults[i] = &pb.BatchGetResult{Result: v}
+		}
+
+		return &pb.BatchGetResponse{Results: getResults}, nil
+	}
+
+	for i, v := range results {
+		getResults[i] = &pb.BatchGetResult{Result: v, Secret: buildGRPCSecret(secrets[i].Metadata, secrets[i].Data)}
+	}
+
+	return &pb.BatchGetResponse{Results: getResults, Committed: true}, nil
+}
+
+// getBatchPlainSecret finds plain secret of item of batch, returns grpc status error
+func (s *Server) getBatchPlainSecret(ctx context.Context, storage plainstorage.PlainStorage, userUUID string, grpcType pb.SecretType, name string) (*plainstorage.PlainSecret, error) {
+	secretType, err := translateGRPCSecretTypeToSecretType(grpcType)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid secret type got")
+	}
+
+	if secretType == plainstorage.SecretTypeMedia {
+		return nil, status.Error(codes.InvalidArgument, "cannot get media secret from plain storage")
+	}
+
+	secret, err := storage.GetUserSecretByName(ctx, userUUID, name, secretType)
+	if err != nil && errors.Is(plainstorage.ErrEntityNotFound, err) {
+		return nil, status.Errorf(codes.NotFound, "secret '%s' not found", name)
+	} else if err != nil {
+		s.logger.Error("Error while get secret of batch", zap.Error(err), zap.String("user_uuid", userUUID))
+
+		return nil, status.Error(codes.Internal, "internal error while get secret")
+	}
+
+	return secret, nil
+}
+
+func (s *Server) BatchDelete(ctx context.Context, request *pb.BatchDeleteRequest) (*pb.BatchDeleteResponse, error) {
+	userCtxVal := ctx.Value(UserContextKey)
+	user := userCtxVal.(*plainstorage.User)
+
+	s.logger.Info("User try delete batch of secrets", zap.String("login", user.Login), zap.Int("count", len(request.GetItems())))
+
+	if err := validateBatchSize(len(request.GetItems())); err != nil {
+		return nil, err
+	}
+
+	results := make([]*pb.BatchItemResult, len(request.GetItems()))
+	secrets := make([]plainstorage.SecretMetadata, len(request.GetItems()))
+	uuids := make([]string, len(request.GetItems()))
+	failed := false
+	for i, v := range request.GetItems() {
+		results[i] = &pb.BatchItemResult{SecretType: v.GetSecretType(), Name: v.GetSecretName()}
+
+		secretType, err := translateGRPCSecretTypeToSecretType(v.GetSecretType())
+		if err != nil {
+			setBatchItemError(results[i], status.Error(codes.InvalidArgument, "invalid secret type got"))
+			failed = true
+
+			continue
+		}
+
+		secret, err := s.plainStorage.GetUserSecretByName(ctx, user.UUID, v.GetSecretName(), secretType)
+		if err != nil && errors.Is(plainstorage.ErrEntityNotFound, err) {
+			setBatchItemError(results[i], status.Errorf(codes.NotFound, "secret '%s' not found", v.GetSecretName()))
+			failed = true
+
+			continue
+		} else if err != nil {
+			s.logger.Error("Error while get secret of batch", zap.Error(err), zap.String("login", user.Login))
+			setBatchItemError(results[i], status.Error(codes.Internal, "internal error while get secret"))
+			failed = true
+
+			continue
+		}
+
+		secrets[i] = secret.Metadata
+		uuids[i] = secret.Metadata.UUID
+	}
+
+	if failed {
+		return &pb.BatchDeleteResponse{Results: abortBatchResults(results)}, nil
+	}
+
+	err := s.plainStorage.InTransaction(ctx, func(tx plainstorage.PlainStorage) error {
+		return tx.RemoveSecretsByUUID(ctx, uuids)
+	})
+	if err != nil {
+		s.logger.Error("Cannot remove batch of secrets", zap.Error(err), zap.String("login", user.Login))
+
+		return nil, status.Error(codes.Internal, "cannot remove batch of secrets")
+	}
+
+	// media objects are removed after metadata, so secrets can't be read even if media storage fails
+	for _, v := range secrets {
+		if v.Type != plainstorage.SecretTypeMedia {
+			continue
+		}
+
+		err = s.removeMediaContent(ctx, v.UserUUID, v.UUID)
+		if err != nil {
+			s.logger.Error("Cannot delete media of removed secret", zap.Error(err), zap.String("media_uuid", v.UUID))
+		}
+	}
+
+	return &pb.BatchDeleteResponse{Results: results, Committed: true}, nil
+}
+
+func validateBatchSize(size int) error {
+	if size == 0 {
+		return status.Error(codes.InvalidArgument, "batch can't be empty")
+	}
+
+	if size > maxBatchSize {
+		return status.Errorf(codes.InvalidArgument, "batch can contain max %d items", maxBatchSize)
+	}
+
+	return nil
+}
+
+// setBatchItemError sets code and message of grpc status error to item result
+func setBatchItemError(result *pb.BatchItemResult, err error) {
+	st := status.Convert(err)
+
+	result.Code = uint32(st.Code())
+	result.Error = st.Message()
+}
+
+// abortBatchResults marks successful items of failed batch as aborted
+func abortBatchResults(results []*pb.BatchItemResult) []*pb.BatchItemResult {
+	for _, v := range results {
+		if codes.Code(v.Code) == codes.OK {
+			v.Code = uint32(codes.Aborted)
+			v.Error = "batch is aborted by failed items"
+		}
+	}
+
+	return results
+}
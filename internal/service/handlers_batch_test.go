@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	pb "github.com/nessai1/gophkeeper/api/proto"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"testing"
+)
+
+func TestServer_BatchSet(t *testing.T) {
+	server, _, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	testUser := plainstorage.User{
+		UUID:         uuid.New().String(),
+		Login:        "testUser",
+		PasswordHash: "somesecrethash",
+	}
+	plain.Users = append(plain.Users, testUser)
+
+	ctx := context.WithValue(context.Background(), UserContextKey, &testUser)
+
+	_, err = server.SecretSet(ctx, &pb.SecretSetRequest{
+		SecretType: pb.SecretType_TEXT,
+		Name:       "existing_secret",
+		Content:    []byte("some text"),
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		items         []*pb.SecretSetRequest
+		expectedCodes []codes.Code
+		committed     bool
+	}{
+		{
+			name: "Existing secret",
+			items: []*pb.SecretSetRequest{
+				{SecretType: pb.SecretType_TEXT, Name: "new_secret", Content: []byte("text")},
+				{SecretType: pb.SecretType_TEXT, Name: "existing_secret", Content: []byte("text")},
+			},
+			expectedCodes: []codes.Code{codes.Aborted, codes.AlreadyExists},
+		},
+		{
+			name: "Duplicated secret",
+			items: []*pb.SecretSetRequest{
+				{SecretType: pb.SecretType_TEXT, Name: "new_secret", Content: []byte("text")},
+				{SecretType: pb.SecretType_TEXT, Name: "new_secret", Content: []byte("text")},
+			},
+			expectedCodes: []codes.Code{codes.Aborted, codes.AlreadyExists},
+		},
+		{
+			name: "Media secret",
+			items: []*pb.SecretSetRequest{
+				{SecretType: pb.SecretType_MEDIA, Name: "new_media", Content: []byte("text")},
+				{SecretType: pb.SecretType_TEXT, Name: "new_secret", Content: []byte("text")},
+			},
+			expectedCodes: []codes.Code{codes.InvalidArgument, codes.Aborted},
+		},
+		{
+			name: "Valid batch",
+			items: []*pb.SecretSetRequest{
+				{SecretType: pb.SecretType_TEXT, Name: "new_secret", Content: []byte("text")},
+				{SecretType: pb.SecretType_CREDENTIALS, Name: "new_secret", Content: []byte("credentials")},
+				{SecretType: pb.SecretType_CREDIT_CARD, Name: "new_card", Content: []byte("card"), MaxReads: 1, Folder: "folder"},
+			},
+			expectedCodes: []codes.Code{codes.OK, codes.OK, codes.OK},
+			committed:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(plain.SecretList)
+
+			res, err := server.BatchSet(ctx, &pb.BatchSetRequest{Items: tt.items})
+			require.NoError(t, err)
+			assert.Equal(t, tt.committed, res.Committed)
+
+			resultCodes := make([]codes.Code, len(res.Results))
+			for i, v := range res.Results {
+				resultCodes[i] = codes.Code(v.Code)
+			}
+			assert.Equal(t, tt.expectedCodes, resultCodes)
+
+			if tt.committed {
+				assert.Len(t, plain.SecretList, before+len(tt.items))
+			} else {
+				assert.Len(t, plain.SecretList, before)
+			}
+		})
+	}
+
+	card, err := plain.GetUserSecretByName(ctx, testUser.UUID, "new_card", plainstorage.SecretTypeCard)
+	require.NoError(t, err)
+	assert.Equal(t, 1, card.Metadata.MaxReads)
+	assert.Equal(t, "folder", card.Metadata.Folder)
+
+	_, err = server.BatchSet(ctx, &pb.BatchSetRequest{})
+	assert.Error(t, err)
+}
+
+func TestServer_BatchGetDelete(t *testing.T) {
+	server, _, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	testUser := plainstorage.User{
+		UUID:         uuid.New().String(),
+		Login:        "testUser",
+		PasswordHash: "somesecrethash",
+	}
+	plain.Users = append(plain.Users, testUser)
+
+	ctx := context.WithValue(context.Background(), UserContextKey, &testUser)
+
+	res, err := server.BatchSet(ctx, &pb.BatchSetRequest{Items: []*pb.SecretSetRequest{
+		{SecretType: pb.SecretType_TEXT, Name: "first_secret", Content: []byte("first")},
+		{SecretType: pb.SecretType_TEXT, Name: "second_secret", Content: []byte("second"), MaxReads: 1},
+	}})
+	require.NoError(t, err)
+	require.True(t, res.Committed)
+
+	getRes, err := server.BatchGet(ctx, &pb.BatchGetRequest{Items: []*pb.SecretGetRequest{
+		{SecretType: pb.SecretType_TEXT, Name: "second_secret"},
+		{SecretType: pb.SecretType_TEXT, Name: "not_existing_secret"},
+	}})
+	require.NoError(t, err)
+	assert.False(t, getRes.Committed)
+	assert.Equal(t, uint32(codes.Aborted), getRes.Results[0].Result.Code)
+	assert.Nil(t, getRes.Results[0].Secret)
+	assert.Equal(t, uint32(codes.NotFound), getRes.Results[1].Result.Code)
+
+	getRes, err = server.BatchGet(ctx, &pb.BatchGetRequest{Items: []*pb.SecretGetRequest{
+		{SecretType: pb.SecretType_TEXT, Name: "first_secret"},
+		{SecretType: pb.SecretType_TEXT, Name: "second_secret"},
+	}})
+	require.NoError(t, err)
+	require.True(t, getRes.Committed)
+	assert.Equal(t, []byte("first"), getRes.Results[0].Secret.Content)
+	assert.Equal(t, []byte("second"), getRes.Results[1].Secret.Content)
+	assert.Equal(t, int32(1), getRes.Results[1].Secret.Reads)
+
+	deleteRes, err := server.BatchDelete(ctx, &pb.BatchDeleteRequest{Items: []*pb.SecretDeleteRequest{
+		{SecretType: pb.SecretType_TEXT, SecretName: "first_secret"},
+		{SecretType: pb.SecretType_TEXT, SecretName: "second_secret"},
+	}})
+	require.NoError(t, err)
+	assert.False(t, deleteRes.Committed)
+	assert.Len(t, plain.SecretList, 1)
+
+	deleteRes, err = server.BatchDelete(ctx, &pb.BatchDeleteRequest{Items: []*pb.SecretDeleteRequest{
+		{SecretType: pb.SecretType_TEXT, SecretName: "first_secret"},
+	}})
+	require.NoError(t, err)
+	assert.True(t, deleteRes.Committed)
+	assert.Empty(t, plain.SecretList)
+}
@@ -1,17 +1,21 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	pb "github.com/nessai1/gophkeeper/api/proto"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
 	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
 	"github.com/nessai1/gophkeeper/pkg/bytesize"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"io"
+	"time"
 )
 
 func (s *Server) UploadMediaSecret(stream pb.KeeperService_UploadMediaSecretServer) error {
@@ -23,83 +27,56 @@ func (s *Server) UploadMediaSecret(stream pb.KeeperService_UploadMediaSecretServ
 		return status.Error(codes.DataLoss, "cannot recover media metadata package")
 	}
 
+	userCtxVal := stream.Context().Value(UserContextKey)
+	user := userCtxVal.(*plainstorage.User)
+
+	if resume := req.GetResume(); resume != nil {
+		return s.resumeMediaUpload(stream, user, resume)
+	}
+
 	metadata := req.GetMetadata()
 	if metadata == nil {
 		s.logger.Error("Client sends empty media metadata by first package")
 
-		return status.Error(codes.InvalidArgument, "first argument must be media metadata")
+		return status.Error(codes.InvalidArgument, "first argument must be media metadata or resume of upload session")
 	}
 
-	userCtxVal := stream.Context().Value(UserContextKey)
-	user := userCtxVal.(*plainstorage.User)
-
 	s.logger.Info("User sends new media", zap.String("login", user.Login), zap.String("filename", metadata.Name))
 
 	mediaUUID := uuid.New().String()
-	var completeUpload func() error
-	secret, getSecretErr := s.plainStorage.GetUserSecretByName(stream.Context(), user.UUID, metadata.Name, plainstorage.SecretTypeMedia)
-	if getSecretErr != nil && !errors.Is(plainstorage.ErrEntityNotFound, getSecretErr) {
-		s.logger.Error("Cannot check existing media secret", zap.Error(getSecretErr), zap.String("login", user.Login), zap.String("filename", metadata.Name))
-
-		return status.Errorf(codes.Internal, "Cannot check existing media secret '%s'", metadata.Name)
+	saveMedia, replaced, err := s.prepareMediaSave(stream.Context(), user, metadata, mediaUUID)
+	if err != nil {
+		return err
 	}
 
-	if metadata.Overwrite {
-		if getSecretErr != nil && errors.Is(plainstorage.ErrEntityNotFound, getSecretErr) {
-			s.logger.Info("Cannot overwrite not existing media", zap.String("login", user.Login), zap.String("filename", metadata.Name))
-
-			return status.Errorf(codes.NotFound, "Cannot overwrite not existing media '%s'", metadata.Name)
-		}
-
-		s.logger.Info("Start remove old media secret", zap.String("filename", metadata.Name))
-
-		completeUpload = func() error {
-			err := s.plainStorage.UpdateSecretMetadataUUID(stream.Context(), user.UUID, secret.Metadata.UUID, mediaUUID, plainstorage.SecretTypeMedia)
-			if err != nil {
-				s.logger.Error("Cannot update plain storage to new media UUID", zap.Error(err), zap.String("filename", metadata.Name), zap.String("login", user.Login))
-
-				return status.Errorf(codes.Internal, "cannot update plain storage for new media UUID")
-			}
-
-			go func() {
-				err := s.mediaStorage.Delete(context.TODO(), secret.Metadata.UUID)
-				if err != nil {
-					s.logger.Error("Cannot delete old media", zap.Error(err), zap.String("filename", metadata.Name), zap.String("login", user.Login))
-				}
-			}()
-
-			return nil
-		}
-	} else {
-		if !errors.Is(plainstorage.ErrEntityNotFound, getSecretErr) {
-			s.logger.Info("User try to create existing media", zap.String("login", user.Login), zap.String("filename", metadata.Name))
-
-			return status.Errorf(codes.AlreadyExists, "Cannot create existing media secret '%s'", metadata.Name)
-		}
-
-		completeUpload = func() error {
-			_, err := s.plainStorage.AddSecretMetadata(stream.Context(), user.UUID, mediaUUID, metadata.Name, plainstorage.SecretTypeMedia)
-			if err != nil {
-				return status.Error(codes.Internal, "Cannot add secret media to plain storage")
-			}
-
-			return nil
-		}
+	// content of stream is counted by reservation until media is saved
+	reservation, err := s.reserveQuota(stream.Context(), user, newMediaCount(metadata), 0)
+	if err != nil {
+		return err
 	}
+	defer s.uploadSessions.release(reservation)
 
 	upload, err := s.mediaStorage.StartUpload(stream.Context(), mediaUUID)
 	if err != nil {
 		s.logger.Error("Cannot start media upload", zap.Error(err))
+
+		return status.Error(codes.Internal, "server cannot start media upload")
 	}
 
 	cancelUpload := func(err error) {
 		s.logger.Error("Error while upload media", zap.Error(err))
-		err = upload.Abort(context.TODO())
+		ctx, cancel := s.cleanupContext()
+		defer cancel()
+
+		err = upload.Abort(ctx)
 		if err != nil {
 			s.logger.Error("Error while abort media upload", zap.Error(err))
 		}
 	}
 
+	hash := sha256.New()
+	var size int64
+	var trailer *pb.MediaUploadTrailer
 	for {
 		req, err := stream.Recv()
 		if err == io.EOF {
@@ -112,20 +89,46 @@ func (s *Server) UploadMediaSecret(stream pb.KeeperService_UploadMediaSecretServ
 			return status.Error(codes.DataLoss, "error while receive media data")
 		}
 
+		if req.GetTrailer() != nil && trailer == nil {
+			trailer = req.GetTrailer()
+
+			continue
+		}
+
 		data := req.GetData()
-		if data == nil {
+		if data == nil || trailer != nil {
 			cancelUpload(errors.New("user sends no data"))
 
-			return status.Error(codes.InvalidArgument, "packages must contain data after metadata package")
+			return status.Error(codes.InvalidArgument, "packages must contain data between metadata package and trailer")
+		}
+
+		if !s.uploadSessions.grow(reservation, int64(len(data.Chunk))) {
+			cancelUpload(errors.New("user exceeds storage quota"))
+
+			return errQuotaExceeded
 		}
 
 		s.logger.Debug("Send media part to storage", zap.String("content_size", fmt.Sprintf("%f KB", float64(len(data.Chunk))/float64(bytesize.KB))))
 		err = upload.Upload(stream.Context(), data.Chunk)
-		if err != nil {
+		if err != nil && errors.Is(err, mediastorage.ErrQuotaExceeded) {
+			cancelUpload(err)
+
+			return status.Error(codes.ResourceExhausted, "media storage has no space left")
+		} else if err != nil {
 			cancelUpload(err)
 
 			return status.Error(codes.Internal, "server cannot save data-chunk")
 		}
+
+		hash.Write(data.Chunk)
+		size += int64(len(data.Chunk))
+	}
+
+	contentHash := hash.Sum(nil)
+	if err = checkMediaTrailer(trailer, contentHash); err != nil {
+		cancelUpload(err)
+
+		return status.Error(codes.DataLoss, "checksum of media mismatches received content")
 	}
 
 	err = upload.Complete(stream.Context())
@@ -135,22 +138,160 @@ func (s *Server) UploadMediaSecret(stream pb.KeeperService_UploadMediaSecretServ
 		return status.Error(codes.Internal, "server cannot complete data upload")
 	}
 
-	err = completeUpload()
+	err = s.plainStorage.InTransaction(stream.Context(), s.withMediaInfo(stream.Context(), user, saveMedia, mediaUUID, plainstorage.MediaInfo{
+		Size:          size,
+		ContentHash:   contentHash,
+		EncryptedInfo: metadata.GetEncryptedInfo(),
+	}))
 	if err != nil {
+		ctx, cancel := s.cleanupContext()
+		defer cancel()
+
+		deleteErr := s.mediaStorage.Delete(ctx, mediaUUID)
+		if deleteErr != nil {
+			s.logger.Error("Cannot delete media of not saved secret", zap.Error(deleteErr), zap.String("media_uuid", mediaUUID))
+		}
+
 		return err
 	}
 
+	s.removeReplacedMedia(replaced)
+
 	return stream.SendAndClose(&pb.UploadMediaSecretResponse{
 		Uuid: mediaUUID,
 		Name: metadata.Name,
 	})
 }
 
+// prepareMediaSave checks that media can be saved by metadata, returns function that saves metadata of media
+// by storage of transaction and metadata of media secret replaced by new media, nil if media is new.
+// Errors are grpc status errors
+func (s *Server) prepareMediaSave(ctx context.Context, user *plainstorage.User, metadata *pb.MediaSecretMetadata, mediaUUID string) (func(tx plainstorage.PlainStorage) error, *plainstorage.SecretMetadata, error) {
+	expiresAt, maxReads, err := translateGRPCSecretLimits(metadata.GetExpireTimestamp(), metadata.GetMaxReads())
+	if err != nil {
+		s.logger.Info("User send invalid media limits", zap.String("login", user.Login), zap.Error(err))
+
+		return nil, nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	secret, getSecretErr := s.plainStorage.GetUserSecretByName(ctx, user.UUID, metadata.Name, plainstorage.SecretTypeMedia)
+	if getSecretErr != nil && !errors.Is(plainstorage.ErrEntityNotFound, getSecretErr) {
+		s.logger.Error("Cannot check existing media secret", zap.Error(getSecretErr), zap.String("login", user.Login), zap.String("filename", metadata.Name))
+
+		return nil, nil, status.Errorf(codes.Internal, "Cannot check existing media secret '%s'", metadata.Name)
+	}
+
+	if metadata.Overwrite {
+		if getSecretErr != nil && errors.Is(plainstorage.ErrEntityNotFound, getSecretErr) {
+			s.logger.Info("Cannot overwrite not existing media", zap.String("login", user.Login), zap.String("filename", metadata.Name))
+
+			return nil, nil, status.Errorf(codes.NotFound, "Cannot overwrite not existing media '%s'", metadata.Name)
+		}
+
+		s.logger.Info("Start remove old media secret", zap.String("filename", metadata.Name))
+
+		return func(tx plainstorage.PlainStorage) error {
+			err := tx.UpdateSecretMetadataUUID(ctx, user.UUID, secret.Metadata.UUID, mediaUUID, plainstorage.SecretTypeMedia)
+			if err != nil {
+				s.logger.Error("Cannot update plain storage to new media UUID", zap.Error(err), zap.String("filename", metadata.Name), zap.String("login", user.Login))
+
+				return status.Errorf(codes.Internal, "cannot update plain storage for new media UUID")
+			}
+
+			return nil
+		}, &secret.Metadata, nil
+	}
+
+	if !errors.Is(plainstorage.ErrEntityNotFound, getSecretErr) {
+		s.logger.Info("User try to create existing media", zap.String("login", user.Login), zap.String("filename", metadata.Name))
+
+		return nil, nil, status.Errorf(codes.AlreadyExists, "Cannot create existing media secret '%s'", metadata.Name)
+	}
+
+	return func(tx plainstorage.PlainStorage) error {
+		_, err := tx.AddSecretMetadata(ctx, user.UUID, mediaUUID, metadata.Name, metadata.EncryptedName, plainstorage.SecretTypeMedia)
+		if err != nil {
+			return status.Error(codes.Internal, "Cannot add secret media to plain storage")
+		}
+
+		if metadata.GetFolder() != "" {
+			err = tx.SetSecretFolder(ctx, mediaUUID, metadata.GetFolder())
+			if err != nil {
+				s.logger.Error("Cannot set media secret folder", zap.Error(err), zap.String("login", user.Login))
+
+				return status.Error(codes.Internal, "Cannot set media secret folder")
+			}
+		}
+
+		if expiresAt == nil && maxReads == 0 {
+			return nil
+		}
+
+		err = tx.SetSecretLimits(ctx, mediaUUID, expiresAt, maxReads)
+		if err != nil {
+			s.logger.Error("Cannot set media secret limits", zap.Error(err), zap.String("login", user.Login))
+
+			return status.Error(codes.Internal, "Cannot set media secret limits")
+		}
+
+		return nil
+	}, nil, nil
+}
+
+// withMediaInfo extends save of media metadata by save of media info, info of replaced media is overwritten too.
+// Quota of user is checked by saved usage, so concurrent uploads can't exceed it
+func (s *Server) withMediaInfo(ctx context.Context, user *plainstorage.User, saveMedia func(tx plainstorage.PlainStorage) error, mediaUUID string, info plainstorage.MediaInfo) func(tx plainstorage.PlainStorage) error {
+	return func(tx plainstorage.PlainStorage) error {
+		err := saveMedia(tx)
+		if err != nil {
+			return err
+		}
+
+		err = tx.SetMediaInfo(ctx, mediaUUID, info)
+		if err != nil {
+			s.logger.Error("Cannot save info of media", zap.Error(err), zap.String("media_uuid", mediaUUID))
+
+			return status.Error(codes.Internal, "cannot save info of media")
+		}
+
+		return s.checkStoredQuota(ctx, tx, user, true)
+	}
+}
+
+// checkMediaTrailer compares checksum of trailer with hash of received content, nil trailer is sent by old clients and passes
+func checkMediaTrailer(trailer *pb.MediaUploadTrailer, contentHash []byte) error {
+	if trailer == nil || bytes.Equal(trailer.GetSha256(), contentHash) {
+		return nil
+	}
+
+	return fmt.Errorf("media checksum %x mismatches received content %x", trailer.GetSha256(), contentHash)
+}
+
+// removeReplacedMedia removes content of media replaced by overwrite in background of server, nil replaced media is ignored.
+// Content is removed after commit of replace, so failed replace keeps old media. Shutdown waits for removal,
+// content left by cancelled removal is found by fsck as orphan
+func (s *Server) removeReplacedMedia(replaced *plainstorage.SecretMetadata) {
+	if replaced == nil {
+		return
+	}
+
+	s.runBackground(func(ctx context.Context) {
+		err := s.removeMediaContent(ctx, replaced.UserUUID, replaced.UUID)
+		if err != nil {
+			s.logger.Error("Cannot delete old media", zap.Error(err), zap.String("media_uuid", replaced.UUID))
+		}
+	})
+}
+
 func (s *Server) DownloadMediaSecret(req *pb.DownloadMediaSecretRequest, stream pb.KeeperService_DownloadMediaSecretServer) error {
 	userCtxVal := stream.Context().Value(UserContextKey)
 	user := userCtxVal.(*plainstorage.User)
 
-	s.logger.Info("User try to get media", zap.String("login", user.Login), zap.String("filename", req.SecretName))
+	s.logger.Info("User try to get media", zap.String("login", user.Login), zap.String("filename", req.SecretName), zap.Int64("offset", req.GetOffset()))
+
+	if err := mediastorage.ValidateRange(req.GetOffset(), req.GetLength()); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
 
 	secret, err := s.plainStorage.GetUserSecretByName(stream.Context(), user.UUID, req.SecretName, plainstorage.SecretTypeMedia)
 	if errors.Is(plainstorage.ErrEntityNotFound, err) {
@@ -163,31 +304,80 @@ func (s *Server) DownloadMediaSecret(req *pb.DownloadMediaSecretRequest, stream
 		return status.Error(codes.Internal, "cannot get secret info from DB")
 	}
 
-	rc, err := s.mediaStorage.StartDownload(stream.Context(), secret.Metadata.UUID)
+	// read of limited media is counted once per download by stream from start of media, resumed and ranged streams
+	// continue counted download. Media is removed after its last read is sent to the end
+	md := &secret.Metadata
+	if md.HasLimits() && req.GetOffset() == 0 {
+		md, err = s.registerSecretRead(stream.Context(), s.plainStorage, secret.Metadata)
+		if err != nil {
+			return err
+		}
+	} else if md.ExpiresAt != nil && !md.ExpiresAt.After(time.Now()) {
+		// media without reads left isn't rejected, because its last read can be resumed until it's removed
+		return status.Errorf(codes.NotFound, "cannot found secret with name %s", req.SecretName)
+	}
+
+	err = s.sendMedia(stream, user, *md, req.GetOffset(), req.GetLength())
+	if err == nil && md.MaxReads > 0 && md.Reads >= md.MaxReads && req.GetLength() == 0 {
+		// secret is removed even if client is gone, so server context is used instead of stream context
+		removeErr := s.removeSecret(s.ctx, *md)
+		if removeErr != nil {
+			s.logger.Error("Cannot remove media after last download", zap.Error(removeErr), zap.String("media_uuid", md.UUID))
+		}
+	}
+
+	return err
+}
+
+// sendMedia sends range of media content by stream, zero length sends content to the end
+func (s *Server) sendMedia(stream pb.KeeperService_DownloadMediaSecretServer, user *plainstorage.User, md plainstorage.SecretMetadata, offset int64, length int64) error {
+	chunks, err := s.plainStorage.GetMediaChunks(stream.Context(), md.UUID)
+	if err != nil {
+		s.logger.Error("Cannot get chunks of media", zap.String("media_uuid", md.UUID), zap.Error(err))
+
+		return status.Error(codes.Internal, "cannot get chunks of media from DB")
+	}
+
+	if len(chunks) > 0 {
+		return s.sendMediaChunks(stream, user, md.UUID, chunks, offset, length)
+	}
+
+	rc, err := s.mediaStorage.StartDownloadRange(stream.Context(), md.UUID, offset, length)
 	if err != nil {
-		s.logger.Error("Cannot start media download from storage", zap.String("media_uuid", secret.Metadata.UUID), zap.Error(err))
+		s.logger.Error("Cannot start media download from storage", zap.String("media_uuid", md.UUID), zap.Error(err))
 
 		return status.Error(codes.Internal, "cannot start media file download from storage")
 	}
+	defer rc.Close()
 
+	// checksum is sent by first part only
+	contentHash := md.ContentHash
 	var b []byte
 	for {
 		b = make([]byte, bytesize.MB)
-		n, err := rc.Read(b)
-		if errors.Is(io.EOF, err) {
-			break
+		// storage readers may return last data together with io.EOF, so parts are filled by ReadFull
+		n, readErr := io.ReadFull(rc, b)
+		if readErr != nil && !errors.Is(readErr, io.EOF) && !errors.Is(readErr, io.ErrUnexpectedEOF) {
+			s.logger.Error("Cannot read media from storage", zap.String("media_uuid", md.UUID), zap.Error(readErr))
+
+			return status.Error(codes.Internal, "cannot read media file from storage")
 		}
 
-		if n != int(bytesize.MB) {
-			b = b[:n]
+		if n == 0 {
+			break
 		}
 
-		err = stream.Send(&pb.DownloadMediaSecretResponse{SecretPart: &pb.MediaSecret{Chunk: b}})
+		err = stream.Send(&pb.DownloadMediaSecretResponse{SecretPart: &pb.MediaSecret{Chunk: b[:n]}, Sha256: contentHash})
+		contentHash = nil
 		if err != nil {
-			s.logger.Error("Cannot send media part to client", zap.String("login", user.Login), zap.String("media_uuid", secret.Metadata.UUID), zap.Error(err))
+			s.logger.Error("Cannot send media part to client", zap.String("login", user.Login), zap.String("media_uuid", md.UUID), zap.Error(err))
 
 			return status.Error(codes.DataLoss, "error while sending media data to client")
 		}
+
+		if readErr != nil {
+			break
+		}
 	}
 
 	return nil
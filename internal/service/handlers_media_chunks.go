@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	pb "github.com/nessai1/gophkeeper/api/proto"
+	"github.com/nessai1/gophkeeper/internal/service/chunkstorage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+)
+
+func (s *Server) GetMissingMediaChunks(ctx context.Context, request *pb.MediaChunksRequest) (*pb.MediaChunksResponse, error) {
+	userCtxVal := ctx.Value(UserContextKey)
+	user := userCtxVal.(*plainstorage.User)
+
+	if len(request.GetHashes()) > maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "request can contain max %d chunks", maxBatchSize)
+	}
+
+	missing, err := s.chunkStorage.Missing(ctx, user.UUID, request.GetHashes())
+	if err != nil && errors.Is(err, chunkstorage.ErrInvalidHash) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil {
+		s.logger.Error("Cannot get missing chunks of user", zap.Error(err), zap.String("login", user.Login))
+
+		return nil, status.Error(codes.Internal, "cannot get missing chunks")
+	}
+
+	return &pb.MediaChunksResponse{Missing: missing}, nil
+}
+
+func (s *Server) UploadMediaChunk(ctx context.Context, request *pb.UploadMediaChunkRequest) (*pb.UploadMediaChunkResponse, error) {
+	userCtxVal := ctx.Value(UserContextKey)
+	user := userCtxVal.(*plainstorage.User)
+
+	_, err := s.checkQuota(ctx, user, true, 0, int64(len(request.GetData())))
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.chunkStorage.Put(ctx, user.UUID, request.GetHash(), request.GetData(), func(tx plainstorage.PlainStorage) error {
+		return s.checkStoredQuota(ctx, tx, user, true)
+	})
+	if status.Code(err) == codes.ResourceExhausted {
+		return nil, err
+	} else if err != nil && (errors.Is(err, chunkstorage.ErrInvalidHash) || errors.Is(err, chunkstorage.ErrHashMismatch)) {
+		s.logger.Info("User sends invalid chunk", zap.Error(err), zap.String("login", user.Login))
+
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	} else if err != nil && errors.Is(err, mediastorage.ErrQuotaExceeded) {
+		s.logger.Error("Cannot save chunk of media", zap.Error(err), zap.String("login", user.Login))
+
+		return nil, status.Error(codes.ResourceExhausted, "media storage has no space left")
+	} else if err != nil {
+		s.logger.Error("Cannot save chunk of media", zap.Error(err), zap.String("login", user.Login))
+
+		return nil, status.Error(codes.Internal, "server cannot save chunk")
+	}
+
+	return &pb.UploadMediaChunkResponse{}, nil
+}
+
+func (s *Server) CommitChunkedMedia(ctx context.Context, request *pb.CommitChunkedMediaRequest) (*pb.UploadMediaSecretResponse, error) {
+	userCtxVal := ctx.Value(UserContextKey)
+	user := userCtxVal.(*plainstorage.User)
+
+	metadata := request.GetMetadata()
+	if metadata == nil {
+		return nil, status.Error(codes.InvalidArgument, "media metadata can't be empty")
+	}
+
+	if len(request.GetHashes()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "chunked media must contain chunks")
+	}
+
+	for _, v := range request.GetHashes() {
+		if err := chunkstorage.ValidateHash(v); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	s.logger.Info("User commits chunked media", zap.String("login", user.Login), zap.String("filename", metadata.Name), zap.Int("chunks", len(request.GetHashes())))
+
+	mediaUUID := uuid.New().String()
+	saveMedia, replaced, err := s.prepareMediaSave(ctx, user, metadata, mediaUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// content of media is counted by uploaded chunks, so only count of media is reserved
+	reservation, err := s.reserveQuota(ctx, user, newMediaCount(metadata), 0)
+	if err != nil {
+		return nil, err
+	}
+	defer s.uploadSessions.release(reservation)
+
+	err = s.plainStorage.InTransaction(ctx, func(tx plainstorage.PlainStorage) error {
+		err := tx.SetMediaChunks(ctx, user.UUID, mediaUUID, request.GetHashes())
+		if err != nil && errors.Is(err, plainstorage.ErrEntityNotFound) {
+			return status.Error(codes.FailedPrecondition, "some chunks of media are not uploaded")
+		} else if err != nil {
+			s.logger.Error("Cannot save chunks of media", zap.Error(err), zap.String("login", user.Login))
+
+			return status.Error(codes.Internal, "cannot save chunks of media")
+		}
+
+		chunks, err := tx.GetMediaChunks(ctx, mediaUUID)
+		if err != nil {
+			s.logger.Error("Cannot get saved chunks of media", zap.Error(err), zap.String("login", user.Login))
+
+			return status.Error(codes.Internal, "cannot get chunks of media")
+		}
+
+		var size int64
+		for _, v := range chunks {
+			size += v.Size
+		}
+
+		return s.withMediaInfo(ctx, user, saveMedia, mediaUUID, plainstorage.MediaInfo{Size: size, EncryptedInfo: metadata.GetEncryptedInfo()})(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.removeReplacedMedia(replaced)
+
+	return &pb.UploadMediaSecretResponse{
+		Uuid: mediaUUID,
+		Name: metadata.Name,
+	}, nil
+}
+
+// sendMediaChunks sends every chunk of media by separate message. Offset must be start of chunk or end of media,
+// chunks are sent until length is covered, zero length sends chunks until end
+func (s *Server) sendMediaChunks(stream pb.KeeperService_DownloadMediaSecretServer, user *plainstorage.User, mediaUUID string, chunks []plainstorage.MediaChunk, offset int64, length int64) error {
+	var position int64
+	for len(chunks) > 0 && position < offset {
+		position += chunks[0].Size
+		chunks = chunks[1:]
+	}
+
+	if position > offset {
+		return status.Errorf(codes.InvalidArgument, "offset %d is not start of media chunk", offset)
+	}
+
+	var sent int64
+	for _, v := range chunks {
+		if length > 0 && sent >= length {
+			break
+		}
+
+		data, err := s.readChunk(stream.Context(), v)
+		if err != nil {
+			s.logger.Error("Cannot read chunk of media", zap.String("media_uuid", mediaUUID), zap.String("hash", v.Hash), zap.Error(err))
+
+			return status.Error(codes.Internal, "cannot read chunk of media from storage")
+		}
+
+		err = stream.Send(&pb.DownloadMediaSecretResponse{SecretPart: &pb.MediaSecret{Chunk: data}, Chunked: true})
+		if err != nil {
+			s.logger.Error("Cannot send media chunk to client", zap.String("login", user.Login), zap.String("media_uuid", mediaUUID), zap.Error(err))
+
+			return status.Error(codes.DataLoss, "error while sending media data to client")
+		}
+
+		sent += int64(len(data))
+	}
+
+	return nil
+}
+
+func (s *Server) readChunk(ctx context.Context, chunk plainstorage.MediaChunk) ([]byte, error) {
+	rc, err := s.chunkStorage.Open(ctx, chunk.OwnerUUID, chunk.Hash)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read chunk content: %w", err)
+	}
+
+	return data, nil
+}
+
+// removeMediaChunks removes chunks list of media by storage, returns false if media isn't chunked.
+// Objects of chunks left without references must be released by chunk storage after commit of storage changes
+func (s *Server) removeMediaChunks(ctx context.Context, storage plainstorage.PlainStorage, mediaUUID string) ([]string, bool, error) {
+	chunks, err := storage.GetMediaChunks(ctx, mediaUUID)
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot get chunks of media: %w", err)
+	}
+
+	if len(chunks) == 0 {
+		return nil, false, nil
+	}
+
+	released, err := storage.RemoveMediaChunks(ctx, mediaUUID)
+	if err != nil {
+		return nil, true, fmt.Errorf("cannot remove chunks of media: %w", err)
+	}
+
+	return released, true, nil
+}
+
+// removeMediaContent removes content of media which metadata is already removed:
+// chunks of chunked media are released, object of other media is deleted
+func (s *Server) removeMediaContent(ctx context.Context, userUUID string, mediaUUID string) error {
+	released, chunked, err := s.removeMediaChunks(ctx, s.plainStorage, mediaUUID)
+	if err != nil {
+		return err
+	}
+
+	if !chunked {
+		return s.mediaStorage.Delete(ctx, mediaUUID)
+	}
+
+	return s.chunkStorage.Release(ctx, userUUID, released)
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/google/uuid"
+	pb "github.com/nessai1/gophkeeper/api/proto"
+	"github.com/nessai1/gophkeeper/internal/service/chunkstorage"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testDownloadStream collects messages of media download
+type testDownloadStream struct {
+	grpc.ServerStream
+
+	ctx      context.Context
+	messages []*pb.DownloadMediaSecretResponse
+	// err fails send of messages, like broken connection
+	err error
+}
+
+func (s *testDownloadStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testDownloadStream) Send(response *pb.DownloadMediaSecretResponse) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	s.messages = append(s.messages, response)
+
+	return nil
+}
+
+func testChunkHash(data []byte) string {
+	hash := sha256.Sum256(data)
+
+	return hex.EncodeToString(hash[:])
+}
+
+func uploadTestChunks(t *testing.T, server *Server, ctx context.Context, chunks [][]byte) []string {
+	hashes := make([]string, len(chunks))
+	for i, v := range chunks {
+		hashes[i] = testChunkHash(v)
+	}
+
+	missing, err := server.GetMissingMediaChunks(ctx, &pb.MediaChunksRequest{Hashes: hashes})
+	require.NoError(t, err)
+
+	for _, v := range chunks {
+		for _, hash := range missing.Missing {
+			if hash == testChunkHash(v) {
+				_, err = server.UploadMediaChunk(ctx, &pb.UploadMediaChunkRequest{Hash: hash, Data: v})
+				require.NoError(t, err)
+			}
+		}
+	}
+
+	return hashes
+}
+
+func TestServer_ChunkedMedia(t *testing.T) {
+	server, media, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	testUser := plainstorage.User{
+		UUID:         uuid.New().String(),
+		Login:        "testUser",
+		PasswordHash: "somesecrethash",
+	}
+	plain.Users = append(plain.Users, testUser)
+
+	ctx := context.WithValue(context.Background(), UserContextKey, &testUser)
+
+	first, second, third := []byte("first chunk"), []byte("second chunk"), []byte("third chunk")
+
+	_, err = server.UploadMediaChunk(ctx, &pb.UploadMediaChunkRequest{Hash: testChunkHash(first), Data: second})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "chunk with wrong hash must be rejected")
+
+	_, err = server.GetMissingMediaChunks(ctx, &pb.MediaChunksRequest{Hashes: []string{"not a hash"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.CommitChunkedMedia(ctx, &pb.CommitChunkedMediaRequest{
+		Metadata: &pb.MediaSecretMetadata{Name: "media"},
+		Hashes:   []string{testChunkHash(first)},
+	})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "media with not uploaded chunks must be rejected")
+
+	hashes := uploadTestChunks(t, server, ctx, [][]byte{first, second, first})
+	_, err = server.CommitChunkedMedia(ctx, &pb.CommitChunkedMediaRequest{
+		Metadata: &pb.MediaSecretMetadata{Name: "media"},
+		Hashes:   hashes,
+	})
+	require.NoError(t, err)
+	assert.Len(t, plain.MediaChunks, 2, "equal chunks must be stored once")
+
+	missing, err := server.GetMissingMediaChunks(ctx, &pb.MediaChunksRequest{Hashes: []string{testChunkHash(second), testChunkHash(third)}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{testChunkHash(third)}, missing.Missing)
+
+	stream := &testDownloadStream{ctx: ctx}
+	require.NoError(t, server.DownloadMediaSecret(&pb.DownloadMediaSecretRequest{SecretName: "media"}, stream))
+	require.Len(t, stream.messages, 3)
+	downloaded := make([][]byte, len(stream.messages))
+	for i, v := range stream.messages {
+		assert.True(t, v.Chunked)
+		downloaded[i] = v.SecretPart.Chunk
+	}
+	assert.Equal(t, [][]byte{first, second, first}, downloaded)
+
+	content, err := downloadTestMedia(t, server, ctx, &pb.DownloadMediaSecretRequest{SecretName: "media", Offset: int64(len(first)), Length: 1})
+	require.NoError(t, err)
+	assert.Equal(t, second, content, "chunked media must be sent by whole chunks")
+
+	_, err = downloadTestMedia(t, server, ctx, &pb.DownloadMediaSecretRequest{SecretName: "media", Offset: 1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "offset inside chunk must be rejected")
+
+	// overwrite keeps shared chunk and releases chunk that is not used anymore
+	hashes = uploadTestChunks(t, server, ctx, [][]byte{second, third})
+	_, err = server.CommitChunkedMedia(ctx, &pb.CommitChunkedMediaRequest{
+		Metadata: &pb.MediaSecretMetadata{Name: "media", Overwrite: true, EncryptedInfo: []byte("encrypted info")},
+		Hashes:   hashes,
+	})
+	require.NoError(t, err)
+
+	list, err := server.SecretList(ctx, &pb.SecretListRequest{SecretType: pb.SecretType_MEDIA})
+	require.NoError(t, err)
+	require.Len(t, list.Secrets, 1)
+	assert.Equal(t, int64(len(second)+len(third)), list.Secrets[0].Size)
+	assert.Equal(t, []byte("encrypted info"), list.Secrets[0].EncryptedMediaInfo)
+
+	require.Eventually(t, func() bool {
+		existing, err := plain.GetExistingMediaChunks(ctx, testUser.UUID, []string{testChunkHash(first)})
+
+		return err == nil && len(existing) == 0
+	}, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		_, err := media.StartDownload(ctx, chunkstorage.ObjectKey(testUser.UUID, testChunkHash(first)))
+
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = server.SecretDelete(ctx, &pb.SecretDeleteRequest{SecretType: pb.SecretType_MEDIA, SecretName: "media"})
+	require.NoError(t, err)
+	assert.Empty(t, plain.MediaChunks)
+	assert.Empty(t, plain.MediaChunkRefs)
+	for _, v := range [][]byte{second, third} {
+		assert.NoFileExists(t, filepath.Join(media.StorageDir, chunkstorage.ObjectKey(testUser.UUID, testChunkHash(v))))
+	}
+}
+
+func TestServer_ChunkedMediaIsolation(t *testing.T) {
+	server, _, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	firstUser := plainstorage.User{UUID: uuid.New().String(), Login: "firstUser"}
+	secondUser := plainstorage.User{UUID: uuid.New().String(), Login: "secondUser"}
+	plain.Users = append(plain.Users, firstUser, secondUser)
+
+	firstCtx := context.WithValue(context.Background(), UserContextKey, &firstUser)
+	secondCtx := context.WithValue(context.Background(), UserContextKey, &secondUser)
+
+	chunk := bytes.Repeat([]byte("a"), 100)
+	uploadTestChunks(t, server, firstCtx, [][]byte{chunk})
+
+	missing, err := server.GetMissingMediaChunks(secondCtx, &pb.MediaChunksRequest{Hashes: []string{testChunkHash(chunk)}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{testChunkHash(chunk)}, missing.Missing, "chunks of other users must not be shared")
+
+	_, err = server.CommitChunkedMedia(secondCtx, &pb.CommitChunkedMediaRequest{
+		Metadata: &pb.MediaSecretMetadata{Name: "media"},
+		Hashes:   []string{testChunkHash(chunk)},
+	})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	pb "github.com/nessai1/gophkeeper/api/proto"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// errPresignDisabled returns by presigned transfers if media storage can't presign URLs or they are disabled by config,
+// then client transfers media through service
+var errPresignDisabled = status.Error(codes.Unimplemented, "service doesn't allow presigned media transfers")
+
+// presigner returns media storage that presigns URLs and lifetime of URLs, false if presigned transfers are not allowed
+func (s *Server) presigner() (mediastorage.Presigner, time.Duration, bool) {
+	presigner, ok := s.mediaStorage.(mediastorage.Presigner)
+	if !ok || presigner.PresignTTL() <= 0 {
+		return nil, 0, false
+	}
+
+	return presigner, presigner.PresignTTL(), true
+}
+
+func (s *Server) StartPresignedMediaUpload(ctx context.Context, req *pb.PresignedUploadRequest) (*pb.PresignedUploadResponse, error) {
+	userCtxVal := ctx.Value(UserContextKey)
+	user := userCtxVal.(*plainstorage.User)
+
+	presigner, ttl, ok := s.presigner()
+	if !ok {
+		return nil, errPresignDisabled
+	}
+
+	metadata := req.GetMetadata()
+	if metadata == nil || req.GetSize() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "presigned upload must have metadata and not negative size")
+	}
+
+	s.logger.Info("User starts presigned media upload", zap.String("login", user.Login), zap.String("filename", metadata.Name), zap.Int64("size", req.GetSize()))
+
+	mediaUUID := uuid.New().String()
+
+	// metadata is checked again on commit of upload, there it's checked to fail before upload of content
+	_, _, err := s.prepareMediaSave(ctx, user, metadata, mediaUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation, err := s.reserveQuota(ctx, user, newMediaCount(metadata), req.GetSize())
+	if err != nil {
+		return nil, err
+	}
+
+	upload, err := presigner.PresignUpload(ctx, mediaUUID, req.GetSize(), ttl)
+	if err != nil {
+		s.uploadSessions.release(reservation)
+		s.logger.Error("Cannot start presigned media upload", zap.Error(err))
+
+		return nil, status.Error(codes.Internal, "server cannot start presigned media upload")
+	}
+
+	session := &uploadSession{
+		id:        uuid.New().String(),
+		userUUID:  user.UUID,
+		mediaUUID: mediaUUID,
+		metadata:  metadata,
+		presigned: upload,
+		// declared size of content is reserved, real size is checked on commit
+		reservation: reservation,
+		// client uploads content without calls of service, so session is kept while URLs are valid
+		lastActive: time.Now().Add(ttl),
+	}
+
+	err = s.uploadSessions.add(session)
+	if err != nil {
+		s.abortUploadSession(session)
+
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+
+	return &pb.PresignedUploadResponse{
+		SessionId: session.id,
+		PartUrls:  upload.PartURLs(),
+		PartSize:  upload.PartSize(),
+	}, nil
+}
+
+// CommitMediaUpload completes presigned upload session and saves media. Session is kept if storage can't complete
+// upload, so client can upload parts again and repeat commit
+func (s *Server) CommitMediaUpload(ctx context.Context, req *pb.CommitMediaUploadRequest) (*pb.UploadMediaSecretResponse, error) {
+	userCtxVal := ctx.Value(UserContextKey)
+	user := userCtxVal.(*plainstorage.User)
+
+	session, ok := s.uploadSessions.get(req.GetSessionId(), user.UUID)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "upload session '%s' not found", req.GetSessionId())
+	}
+
+	if !session.mu.TryLock() {
+		return nil, status.Error(codes.Aborted, "upload session is used by other request")
+	}
+	defer session.mu.Unlock()
+
+	if session.presigned == nil {
+		return nil, status.Error(codes.FailedPrecondition, "upload session is not presigned, its content is committed by upload stream")
+	}
+
+	size, err := session.presigned.Complete(ctx, req.GetEtags())
+	if err != nil {
+		s.logger.Info("Cannot complete presigned media upload", zap.Error(err), zap.String("session_id", session.id))
+
+		return nil, status.Error(codes.FailedPrecondition, "cannot complete upload, all parts must be uploaded by presigned URLs")
+	}
+	s.uploadSessions.remove(session.id)
+
+	deleteMedia := func() {
+		ctx, cancel := s.cleanupContext()
+		defer cancel()
+
+		deleteErr := s.mediaStorage.Delete(ctx, session.mediaUUID)
+		if deleteErr != nil && !errors.Is(deleteErr, mediastorage.ErrObjectNotFound) {
+			s.logger.Error("Cannot delete media of not saved secret", zap.Error(deleteErr), zap.String("media_uuid", session.mediaUUID))
+		}
+	}
+
+	// saved media is counted by usage of user, so reservation is released after save
+	defer s.uploadSessions.release(session.reservation)
+
+	// client can upload more than size declared on start
+	if !s.uploadSessions.grow(session.reservation, size-session.reservation.bytes) {
+		deleteMedia()
+
+		return nil, errQuotaExceeded
+	}
+
+	// content of presigned parts doesn't pass through service, so checksum of client can't be verified and isn't stored
+	saveMedia, replaced, err := s.prepareMediaSave(ctx, user, session.metadata, session.mediaUUID)
+	if err == nil {
+		err = s.plainStorage.InTransaction(ctx, s.withMediaInfo(ctx, user, saveMedia, session.mediaUUID, plainstorage.MediaInfo{
+			Size:          size,
+			EncryptedInfo: session.metadata.GetEncryptedInfo(),
+		}))
+	}
+
+	if err != nil {
+		deleteMedia()
+
+		return nil, err
+	}
+
+	s.removeReplacedMedia(replaced)
+
+	return &pb.UploadMediaSecretResponse{
+		Uuid: session.mediaUUID,
+		Name: session.metadata.Name,
+	}, nil
+}
+
+// GetPresignedMediaDownload gives URL for download of media stored as single object. Chunked media and media
+// with limits are downloaded through service, that counts reads of media
+func (s *Server) GetPresignedMediaDownload(ctx context.Context, req *pb.DownloadMediaSecretRequest) (*pb.PresignedDownloadResponse, error) {
+	userCtxVal := ctx.Value(UserContextKey)
+	user := userCtxVal.(*plainstorage.User)
+
+	presigner, ttl, ok := s.presigner()
+	if !ok {
+		return nil, errPresignDisabled
+	}
+
+	secret, err := s.plainStorage.GetUserSecretByName(ctx, user.UUID, req.SecretName, plainstorage.SecretTypeMedia)
+	if errors.Is(plainstorage.ErrEntityNotFound, err) {
+		return nil, status.Errorf(codes.NotFound, "cannot found secret with name %s", req.SecretName)
+	} else if err != nil {
+		s.logger.Error("Cannot get secret for presigned media download", zap.String("login", user.Login), zap.Error(err))
+
+		return nil, status.Error(codes.Internal, "cannot get secret info from DB")
+	}
+
+	if secret.Metadata.HasLimits() {
+		return nil, status.Error(codes.FailedPrecondition, "media with limits is downloaded through service")
+	}
+
+	chunks, err := s.plainStorage.GetMediaChunks(ctx, secret.Metadata.UUID)
+	if err != nil {
+		s.logger.Error("Cannot get chunks of media", zap.String("media_uuid", secret.Metadata.UUID), zap.Error(err))
+
+		return nil, status.Error(codes.Internal, "cannot get chunks of media from DB")
+	}
+
+	if len(chunks) > 0 {
+		return nil, status.Error(codes.FailedPrecondition, "chunked media is downloaded through service")
+	}
+
+	url, err := presigner.PresignDownload(ctx, secret.Metadata.UUID, ttl)
+	if err != nil {
+		s.logger.Error("Cannot presign media download", zap.String("media_uuid", secret.Metadata.UUID), zap.Error(err))
+
+		return nil, status.Error(codes.Internal, "cannot presign media download")
+	}
+
+	s.logger.Info("User gets presigned media download", zap.String("login", user.Login), zap.String("media_uuid", secret.Metadata.UUID))
+
+	return &pb.PresignedDownloadResponse{
+		Url:    url,
+		Size:   secret.Metadata.MediaSize,
+		Sha256: secret.Metadata.ContentHash,
+	}, nil
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"github.com/google/uuid"
+	pb "github.com/nessai1/gophkeeper/api/proto"
+	"github.com/nessai1/gophkeeper/internal/service/chunkstorage"
+	"github.com/nessai1/gophkeeper/internal/service/config"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage/s3storage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage/s3storage/s3test"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// newPresignTestServer returns test server with S3 storage on stand-in, that allows presigned transfers
+func newPresignTestServer(t *testing.T) (*Server, *plainstorage.MemoryStorage, *s3test.Server) {
+	server, _, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	s3Server := s3test.NewServer("keeper")
+	t.Cleanup(s3Server.Close)
+
+	s3Config := config.S3Config{
+		URL:           s3Server.URL,
+		PartitionID:   "test",
+		SigningRegion: "test-region",
+		PathStyle:     true,
+		PresignedURLs: true,
+		PresignTTL:    config.Duration(time.Minute),
+		Credentials:   &config.S3Credentials{AccessKeyID: "access", SecretAccessKey: "secret"},
+	}
+
+	media, err := s3storage.NewStorage(s3Config, zap.NewNop())
+	require.NoError(t, err)
+
+	server.mediaStorage = media
+	server.chunkStorage = chunkstorage.NewStorage(media, plain, zap.NewNop())
+
+	return server, plain, s3Server
+}
+
+// uploadPresignedParts uploads content by part URLs and returns ETags of parts
+func uploadPresignedParts(t *testing.T, upload *pb.PresignedUploadResponse, content []byte) []string {
+	etags := make([]string, len(upload.PartUrls))
+	for i, url := range upload.PartUrls {
+		start := min(int64(i)*upload.PartSize, int64(len(content)))
+		end := min(start+upload.PartSize, int64(len(content)))
+
+		req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(content[start:end]))
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		etags[i] = resp.Header.Get("ETag")
+	}
+
+	return etags
+}
+
+func TestServer_PresignedMedia(t *testing.T) {
+	server, plain, s3Server := newPresignTestServer(t)
+
+	testUser := plainstorage.User{UUID: uuid.New().String(), Login: "testUser"}
+	plain.Users = append(plain.Users, testUser)
+	ctx := context.WithValue(context.Background(), UserContextKey, &testUser)
+
+	content := bytes.Repeat([]byte("direct media content "), 512*1024)
+
+	upload, err := server.StartPresignedMediaUpload(ctx, &pb.PresignedUploadRequest{Metadata: &pb.MediaSecretMetadata{Name: "media"}, Size: int64(len(content))})
+	require.NoError(t, err)
+	require.Len(t, upload.PartUrls, 2)
+
+	err = server.UploadMediaSecret(buildResumeStream(ctx, upload.SessionId, 0, "content"))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "presigned session must not be uploaded by stream")
+
+	etags := uploadPresignedParts(t, upload, content)
+
+	_, err = server.CommitMediaUpload(ctx, &pb.CommitMediaUploadRequest{SessionId: upload.SessionId, Etags: etags[:1]})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	res, err := server.CommitMediaUpload(ctx, &pb.CommitMediaUploadRequest{SessionId: upload.SessionId, Etags: etags})
+	require.NoError(t, err, "failed commit must keep session")
+	assert.Equal(t, "media", res.Name)
+	assert.Zero(t, s3Server.Uploads())
+
+	_, err = server.CommitMediaUpload(ctx, &pb.CommitMediaUploadRequest{SessionId: upload.SessionId, Etags: etags})
+	assert.Equal(t, codes.NotFound, status.Code(err), "committed session must be removed")
+
+	secret, err := plain.GetUserSecretByName(ctx, testUser.UUID, "media", plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), secret.Metadata.MediaSize)
+
+	download, err := server.GetPresignedMediaDownload(ctx, &pb.DownloadMediaSecretRequest{SecretName: "media"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), download.Size)
+	assert.Empty(t, download.Sha256, "checksum of presigned upload isn't known by service")
+
+	resp, err := http.Get(download.Url)
+	require.NoError(t, err)
+	downloaded, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, content, downloaded)
+
+	streamed, err := downloadTestMedia(t, server, ctx, &pb.DownloadMediaSecretRequest{SecretName: "media"})
+	require.NoError(t, err)
+	assert.Equal(t, content, streamed, "presigned media must be downloaded by stream too")
+
+	_, err = server.GetPresignedMediaDownload(ctx, &pb.DownloadMediaSecretRequest{SecretName: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	t.Run("Abort", func(t *testing.T) {
+		upload, err := server.StartPresignedMediaUpload(ctx, &pb.PresignedUploadRequest{Metadata: &pb.MediaSecretMetadata{Name: "aborted"}, Size: 10})
+		require.NoError(t, err)
+
+		_, err = server.AbortMediaUpload(ctx, &pb.MediaUploadSessionRequest{SessionId: upload.SessionId})
+		require.NoError(t, err)
+		assert.Zero(t, s3Server.Uploads())
+	})
+
+	t.Run("Existing media", func(t *testing.T) {
+		_, err := server.StartPresignedMediaUpload(ctx, &pb.PresignedUploadRequest{Metadata: &pb.MediaSecretMetadata{Name: "media"}, Size: 10})
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("Limited media", func(t *testing.T) {
+		upload, err := server.StartPresignedMediaUpload(ctx, &pb.PresignedUploadRequest{Metadata: &pb.MediaSecretMetadata{Name: "limited", MaxReads: 1}, Size: 7})
+		require.NoError(t, err)
+
+		_, err = server.CommitMediaUpload(ctx, &pb.CommitMediaUploadRequest{SessionId: upload.SessionId, Etags: uploadPresignedParts(t, upload, []byte("limited"))})
+		require.NoError(t, err)
+
+		_, err = server.GetPresignedMediaDownload(ctx, &pb.DownloadMediaSecretRequest{SecretName: "limited"})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err), "reads of limited media must be counted by service")
+	})
+
+	t.Run("Quota", func(t *testing.T) {
+		server.config.UserQuota = &config.UserQuotaConfig{MaxBytes: config.ByteSize(len(content) + 10)}
+		defer func() {
+			server.config.UserQuota = nil
+		}()
+
+		_, err := server.StartPresignedMediaUpload(ctx, &pb.PresignedUploadRequest{Metadata: &pb.MediaSecretMetadata{Name: "large"}, Size: 100})
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+		upload, err := server.StartPresignedMediaUpload(ctx, &pb.PresignedUploadRequest{Metadata: &pb.MediaSecretMetadata{Name: "large"}, Size: 1})
+		require.NoError(t, err)
+
+		_, err = server.CommitMediaUpload(ctx, &pb.CommitMediaUploadRequest{SessionId: upload.SessionId, Etags: uploadPresignedParts(t, upload, make([]byte, 100))})
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err), "commit must check size of uploaded content")
+
+		_, err = plain.GetUserSecretByName(ctx, testUser.UUID, "large", plainstorage.SecretTypeMedia)
+		assert.ErrorIs(t, err, plainstorage.ErrEntityNotFound)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		presignStorage := server.mediaStorage
+		defer func() {
+			server.mediaStorage = presignStorage
+		}()
+
+		media, err := s3storage.NewStorage(config.S3Config{
+			URL:           s3Server.URL,
+			PartitionID:   "test",
+			SigningRegion: "test-region",
+			PathStyle:     true,
+			Credentials:   &config.S3Credentials{AccessKeyID: "access", SecretAccessKey: "secret"},
+		}, zap.NewNop())
+		require.NoError(t, err)
+		server.mediaStorage = media
+
+		_, err = server.StartPresignedMediaUpload(ctx, &pb.PresignedUploadRequest{Metadata: &pb.MediaSecretMetadata{Name: "disabled"}})
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+		_, err = server.GetPresignedMediaDownload(ctx, &pb.DownloadMediaSecretRequest{SecretName: "media"})
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+}
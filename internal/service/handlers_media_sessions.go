@@ -0,0 +1,432 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	pb "github.com/nessai1/gophkeeper/api/proto"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"hash"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	// uploadSessionTTL time of inactivity after that upload session is aborted
+	uploadSessionTTL = time.Hour
+	// maxUserUploadSessions max count of not finished upload sessions of user
+	maxUserUploadSessions = 16
+)
+
+// uploadSession upload of media object that can be continued by new stream after failure of stream
+type uploadSession struct {
+	id        string
+	userUUID  string
+	mediaUUID string
+	metadata  *pb.MediaSecretMetadata
+	upload    mediastorage.MultipartUpload
+	// reservation quota held by session until commit or abort
+	reservation *quotaReservation
+	// presigned upload of content by client directly to media storage, upload is nil for presigned sessions
+	presigned mediastorage.PresignedUpload
+
+	// mu is held by stream that uploads data of session
+	mu sync.Mutex
+	// offset count of bytes passed to upload
+	offset int64
+	// hash of bytes passed to upload
+	hash       hash.Hash
+	lastActive time.Time
+}
+
+// uploadSessions active upload sessions of all users and quota reserved by not finished uploads of sessions and streams
+type uploadSessions struct {
+	mu           sync.Mutex
+	sessions     map[string]*uploadSession
+	reservations map[*quotaReservation]struct{}
+}
+
+// quotaReservation quota held by not finished media upload of user: count of media added by upload
+// and bytes received by upload. Reservations of user are counted by quota checks as used
+type quotaReservation struct {
+	userUUID string
+	media    int
+	bytes    int64
+	// limit max count of bytes reserved by all uploads of user, it's set by owner of reservation before upload
+	limit int64
+}
+
+// reserve adds reservation of count of media and bytes for upload of user
+func (s *uploadSessions) reserve(userUUID string, media int, bytes int64) *quotaReservation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.reservations == nil {
+		s.reservations = make(map[*quotaReservation]struct{})
+	}
+
+	reservation := &quotaReservation{userUUID: userUUID, media: media, bytes: bytes, limit: unlimitedBytes}
+	s.reservations[reservation] = struct{}{}
+
+	return reservation
+}
+
+// reserved returns count of media and bytes reserved by not finished uploads of user
+func (s *uploadSessions) reserved(userUUID string) (int, int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.reservedLocked(userUUID)
+}
+
+func (s *uploadSessions) reservedLocked(userUUID string) (int, int64) {
+	var media int
+	var bytes int64
+	for v := range s.reservations {
+		if v.userUUID == userUUID {
+			media += v.media
+			bytes += v.bytes
+		}
+	}
+
+	return media, bytes
+}
+
+// grow adds n bytes to reservation, returns false if bytes reserved by all uploads of user exceed limit of reservation
+func (s *uploadSessions) grow(reservation *quotaReservation, n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, reserved := s.reservedLocked(reservation.userUUID)
+	if n > 0 && reserved+n > reservation.limit {
+		return false
+	}
+
+	reservation.bytes += n
+
+	return true
+}
+
+// release removes reservation after commit or abort of upload, nil reservation is ignored
+func (s *uploadSessions) release(reservation *quotaReservation) {
+	if reservation == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.reservations, reservation)
+}
+
+func (s *uploadSessions) add(session *uploadSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sessions == nil {
+		s.sessions = make(map[string]*uploadSession)
+	}
+
+	count := 0
+	for _, v := range s.sessions {
+		if v.userUUID == session.userUUID {
+			count++
+		}
+	}
+
+	if count >= maxUserUploadSessions {
+		return fmt.Errorf("user can have max %d upload sessions", maxUserUploadSessions)
+	}
+
+	s.sessions[session.id] = session
+
+	return nil
+}
+
+// get returns session of user, sessions of other users are not found
+func (s *uploadSessions) get(id string, userUUID string) (*uploadSession, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok || session.userUUID != userUUID {
+		return nil, false
+	}
+
+	return session, true
+}
+
+func (s *uploadSessions) remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, id)
+}
+
+// takeExpired removes and returns sessions that are not used by streams and inactive since moment
+func (s *uploadSessions) takeExpired(moment time.Time) []*uploadSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expired := make([]*uploadSession, 0)
+	for id, v := range s.sessions {
+		if !v.mu.TryLock() {
+			continue
+		}
+
+		if v.lastActive.Before(moment) {
+			expired = append(expired, v)
+			delete(s.sessions, id)
+		}
+
+		v.mu.Unlock()
+	}
+
+	return expired
+}
+
+func (s *Server) StartMediaUpload(ctx context.Context, metadata *pb.MediaSecretMetadata) (*pb.MediaUploadSessionResponse, error) {
+	userCtxVal := ctx.Value(UserContextKey)
+	user := userCtxVal.(*plainstorage.User)
+
+	s.logger.Info("User starts media upload session", zap.String("login", user.Login), zap.String("filename", metadata.Name))
+
+	mediaUUID := uuid.New().String()
+
+	// metadata is checked again on complete of upload, there it's checked to fail before upload of content
+	_, _, err := s.prepareMediaSave(ctx, user, metadata, mediaUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation, err := s.reserveQuota(ctx, user, newMediaCount(metadata), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	upload, err := s.mediaStorage.StartUpload(ctx, mediaUUID)
+	if err != nil {
+		s.uploadSessions.release(reservation)
+		s.logger.Error("Cannot start media upload", zap.Error(err))
+
+		return nil, status.Error(codes.Internal, "server cannot start media upload")
+	}
+
+	session := &uploadSession{
+		id:          uuid.New().String(),
+		userUUID:    user.UUID,
+		mediaUUID:   mediaUUID,
+		metadata:    metadata,
+		upload:      upload,
+		reservation: reservation,
+		hash:        sha256.New(),
+		lastActive:  time.Now(),
+	}
+
+	err = s.uploadSessions.add(session)
+	if err != nil {
+		s.abortUploadSession(session)
+
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+
+	return &pb.MediaUploadSessionResponse{SessionId: session.id}, nil
+}
+
+func (s *Server) GetMediaUploadSession(ctx context.Context, request *pb.MediaUploadSessionRequest) (*pb.MediaUploadSessionResponse, error) {
+	userCtxVal := ctx.Value(UserContextKey)
+	user := userCtxVal.(*plainstorage.User)
+
+	session, ok := s.uploadSessions.get(request.GetSessionId(), user.UUID)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "upload session '%s' not found", request.GetSessionId())
+	}
+
+	if !session.mu.TryLock() {
+		return nil, status.Error(codes.Aborted, "upload session is used by other stream")
+	}
+	defer session.mu.Unlock()
+
+	return &pb.MediaUploadSessionResponse{SessionId: session.id, Offset: session.offset}, nil
+}
+
+func (s *Server) AbortMediaUpload(ctx context.Context, request *pb.MediaUploadSessionRequest) (*pb.MediaUploadSessionResponse, error) {
+	userCtxVal := ctx.Value(UserContextKey)
+	user := userCtxVal.(*plainstorage.User)
+
+	session, ok := s.uploadSessions.get(request.GetSessionId(), user.UUID)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "upload session '%s' not found", request.GetSessionId())
+	}
+
+	if !session.mu.TryLock() {
+		return nil, status.Error(codes.Aborted, "upload session is used by other stream")
+	}
+	defer session.mu.Unlock()
+
+	s.uploadSessions.remove(session.id)
+	s.abortUploadSession(session)
+
+	return &pb.MediaUploadSessionResponse{SessionId: session.id, Offset: session.offset}, nil
+}
+
+// resumeMediaUpload uploads data of stream to upload session. Failure of stream keeps session for next stream,
+// failure of storage aborts session. Upload completes when client closes stream
+func (s *Server) resumeMediaUpload(stream pb.KeeperService_UploadMediaSecretServer, user *plainstorage.User, resume *pb.MediaUploadResume) error {
+	session, ok := s.uploadSessions.get(resume.GetSessionId(), user.UUID)
+	if !ok {
+		return status.Errorf(codes.NotFound, "upload session '%s' not found", resume.GetSessionId())
+	}
+
+	if !session.mu.TryLock() {
+		return status.Error(codes.Aborted, "upload session is used by other stream")
+	}
+	defer session.mu.Unlock()
+
+	if session.presigned != nil {
+		return status.Error(codes.FailedPrecondition, "content of presigned upload session is uploaded by its URLs")
+	}
+
+	if resume.GetOffset() != session.offset {
+		return status.Errorf(codes.FailedPrecondition, "upload session committed %d bytes, stream must continue from them", session.offset)
+	}
+
+	abort := func(err error) {
+		s.logger.Error("Error while upload media by session", zap.Error(err), zap.String("session_id", session.id))
+		s.uploadSessions.remove(session.id)
+		s.abortUploadSession(session)
+	}
+
+	var trailer *pb.MediaUploadTrailer
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+
+		session.lastActive = time.Now()
+		if err != nil {
+			s.logger.Info("Stream of upload session is broken, session is kept", zap.Error(err), zap.String("session_id", session.id), zap.Int64("offset", session.offset))
+
+			return status.Error(codes.DataLoss, "error while receive media data")
+		}
+
+		if req.GetTrailer() != nil && trailer == nil {
+			trailer = req.GetTrailer()
+
+			continue
+		}
+
+		data := req.GetData()
+		if data == nil || trailer != nil {
+			return status.Error(codes.InvalidArgument, "packages must contain data between resume package and trailer")
+		}
+
+		// content of session is not stored yet, so it's counted by reservation of session
+		if !s.uploadSessions.grow(session.reservation, int64(len(data.Chunk))) {
+			abort(errors.New("user exceeds storage quota"))
+
+			return errQuotaExceeded
+		}
+
+		// received part is stored by server context, so client gone during upload of part doesn't break session
+		err = session.upload.Upload(s.ctx, data.Chunk)
+		if err != nil {
+			abort(err)
+			if errors.Is(err, mediastorage.ErrQuotaExceeded) {
+				return status.Error(codes.ResourceExhausted, "media storage has no space left")
+			}
+
+			return status.Error(codes.Internal, "server cannot save data-chunk")
+		}
+
+		session.offset += int64(len(data.Chunk))
+		session.hash.Write(data.Chunk)
+	}
+
+	contentHash := session.hash.Sum(nil)
+	if err := checkMediaTrailer(trailer, contentHash); err != nil {
+		abort(err)
+
+		return status.Error(codes.DataLoss, "checksum of media mismatches received content")
+	}
+
+	err := session.upload.Complete(stream.Context())
+	if err != nil {
+		abort(fmt.Errorf("error while complete upload user media: %w", err))
+
+		return status.Error(codes.Internal, "server cannot complete data upload")
+	}
+	s.uploadSessions.remove(session.id)
+
+	// saved media is counted by usage of user, so reservation is released after save
+	defer s.uploadSessions.release(session.reservation)
+
+	saveMedia, replaced, err := s.prepareMediaSave(stream.Context(), user, session.metadata, session.mediaUUID)
+	if err == nil {
+		err = s.plainStorage.InTransaction(stream.Context(), s.withMediaInfo(stream.Context(), user, saveMedia, session.mediaUUID, plainstorage.MediaInfo{
+			Size:          session.offset,
+			ContentHash:   contentHash,
+			EncryptedInfo: session.metadata.GetEncryptedInfo(),
+		}))
+	}
+
+	if err != nil {
+		ctx, cancel := s.cleanupContext()
+		defer cancel()
+
+		deleteErr := s.mediaStorage.Delete(ctx, session.mediaUUID)
+		if deleteErr != nil {
+			s.logger.Error("Cannot delete media of not saved secret", zap.Error(deleteErr), zap.String("media_uuid", session.mediaUUID))
+		}
+
+		return err
+	}
+
+	s.removeReplacedMedia(replaced)
+
+	return stream.SendAndClose(&pb.UploadMediaSecretResponse{
+		Uuid: session.mediaUUID,
+		Name: session.metadata.Name,
+	})
+}
+
+// abortUploadSession aborts upload of session removed from registry and releases quota reserved by session
+func (s *Server) abortUploadSession(session *uploadSession) {
+	s.uploadSessions.release(session.reservation)
+
+	ctx, cancel := s.cleanupContext()
+	defer cancel()
+
+	var err error
+	if session.presigned != nil {
+		err = session.presigned.Abort(ctx)
+	} else {
+		err = session.upload.Abort(ctx)
+	}
+
+	if err != nil {
+		s.logger.Error("Cannot abort upload of session", zap.Error(err), zap.String("session_id", session.id))
+	}
+}
+
+// reapUploadSessions aborts upload sessions inactive for uploadSessionTTL, returns count of aborted sessions
+func (s *Server) reapUploadSessions(moment time.Time) int {
+	expired := s.uploadSessions.takeExpired(moment.Add(-uploadSessionTTL))
+	for _, v := range expired {
+		s.abortUploadSession(v)
+	}
+
+	if len(expired) > 0 {
+		s.logger.Info("Expired upload sessions aborted", zap.Int("count", len(expired)))
+	}
+
+	return len(expired)
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"github.com/google/uuid"
+	pb "github.com/nessai1/gophkeeper/api/proto"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"testing"
+	"time"
+)
+
+// testUploadStream returns requests of upload and then err, io.EOF if err is nil
+type testUploadStream struct {
+	grpc.ServerStream
+
+	ctx      context.Context
+	requests []*pb.UploadMediaSecretRequest
+	err      error
+	response *pb.UploadMediaSecretResponse
+}
+
+func (s *testUploadStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testUploadStream) Recv() (*pb.UploadMediaSecretRequest, error) {
+	if len(s.requests) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+
+		return nil, io.EOF
+	}
+
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+
+	return req, nil
+}
+
+func (s *testUploadStream) SendAndClose(response *pb.UploadMediaSecretResponse) error {
+	s.response = response
+
+	return nil
+}
+
+func buildResumeStream(ctx context.Context, sessionID string, offset int64, parts ...string) *testUploadStream {
+	stream := &testUploadStream{ctx: ctx}
+	stream.requests = append(stream.requests, &pb.UploadMediaSecretRequest{Request: &pb.UploadMediaSecretRequest_Resume{
+		Resume: &pb.MediaUploadResume{SessionId: sessionID, Offset: offset},
+	}})
+
+	for _, v := range parts {
+		stream.requests = append(stream.requests, &pb.UploadMediaSecretRequest{Request: &pb.UploadMediaSecretRequest_Data{
+			Data: &pb.MediaSecret{Chunk: []byte(v)},
+		}})
+	}
+
+	return stream
+}
+
+func downloadTestMedia(t *testing.T, server *Server, ctx context.Context, request *pb.DownloadMediaSecretRequest) ([]byte, error) {
+	stream := &testDownloadStream{ctx: ctx}
+	err := server.DownloadMediaSecret(request, stream)
+
+	content := make([][]byte, len(stream.messages))
+	for i, v := range stream.messages {
+		content[i] = v.SecretPart.Chunk
+	}
+
+	return bytes.Join(content, nil), err
+}
+
+func TestServer_MediaUploadSession(t *testing.T) {
+	server, _, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	testUser := plainstorage.User{UUID: uuid.New().String(), Login: "testUser"}
+	otherUser := plainstorage.User{UUID: uuid.New().String(), Login: "otherUser"}
+	plain.Users = append(plain.Users, testUser, otherUser)
+
+	ctx := context.WithValue(context.Background(), UserContextKey, &testUser)
+	otherCtx := context.WithValue(context.Background(), UserContextKey, &otherUser)
+
+	session, err := server.StartMediaUpload(ctx, &pb.MediaSecretMetadata{Name: "media"})
+	require.NoError(t, err)
+
+	_, err = server.GetMediaUploadSession(otherCtx, &pb.MediaUploadSessionRequest{SessionId: session.SessionId})
+	assert.Equal(t, codes.NotFound, status.Code(err), "session of other user must not be found")
+
+	stream := buildResumeStream(ctx, session.SessionId, 0, "hello ", "new ")
+	stream.err = errors.New("connection lost")
+	err = server.UploadMediaSecret(stream)
+	assert.Equal(t, codes.DataLoss, status.Code(err))
+
+	res, err := server.GetMediaUploadSession(ctx, &pb.MediaUploadSessionRequest{SessionId: session.SessionId})
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), res.Offset, "broken stream must keep committed data")
+
+	err = server.UploadMediaSecret(buildResumeStream(ctx, session.SessionId, 6, "world"))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "stream must continue from committed offset")
+
+	stream = buildResumeStream(ctx, session.SessionId, 10, "world")
+	require.NoError(t, server.UploadMediaSecret(stream))
+	assert.Equal(t, "media", stream.response.Name)
+
+	_, err = server.GetMediaUploadSession(ctx, &pb.MediaUploadSessionRequest{SessionId: session.SessionId})
+	assert.Equal(t, codes.NotFound, status.Code(err), "completed session must be removed")
+
+	tests := []struct {
+		name     string
+		offset   int64
+		length   int64
+		expected string
+	}{
+		{name: "Whole media", expected: "hello new world"},
+		{name: "From offset", offset: 10, expected: "world"},
+		{name: "Bounded", offset: 6, length: 3, expected: "new"},
+		{name: "After end", offset: 100, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := downloadTestMedia(t, server, ctx, &pb.DownloadMediaSecretRequest{SecretName: "media", Offset: tt.offset, Length: tt.length})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(content))
+		})
+	}
+
+	_, err = downloadTestMedia(t, server, ctx, &pb.DownloadMediaSecretRequest{SecretName: "media", Offset: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.StartMediaUpload(ctx, &pb.MediaSecretMetadata{Name: "media"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err), "session must not start for existing media")
+}
+
+func TestServer_MediaUploadSessionExpire(t *testing.T) {
+	server, _, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	testUser := plainstorage.User{UUID: uuid.New().String(), Login: "testUser"}
+	plain.Users = append(plain.Users, testUser)
+
+	ctx := context.WithValue(context.Background(), UserContextKey, &testUser)
+
+	first, err := server.StartMediaUpload(ctx, &pb.MediaSecretMetadata{Name: "first"})
+	require.NoError(t, err)
+
+	second, err := server.StartMediaUpload(ctx, &pb.MediaSecretMetadata{Name: "second"})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, server.reapUploadSessions(time.Now()))
+	assert.Equal(t, 2, server.reapUploadSessions(time.Now().Add(uploadSessionTTL+time.Minute)))
+
+	_, err = server.GetMediaUploadSession(ctx, &pb.MediaUploadSessionRequest{SessionId: first.SessionId})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	third, err := server.StartMediaUpload(ctx, &pb.MediaSecretMetadata{Name: "third"})
+	require.NoError(t, err)
+
+	_, err = server.AbortMediaUpload(ctx, &pb.MediaUploadSessionRequest{SessionId: third.SessionId})
+	require.NoError(t, err)
+
+	err = server.UploadMediaSecret(buildResumeStream(ctx, third.SessionId, 0, "data"))
+	assert.Equal(t, codes.NotFound, status.Code(err), "aborted session can't be continued")
+
+	_, err = server.AbortMediaUpload(ctx, &pb.MediaUploadSessionRequest{SessionId: second.SessionId})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func withTrailer(stream *testUploadStream, checksum []byte) *testUploadStream {
+	stream.requests = append(stream.requests, &pb.UploadMediaSecretRequest{Request: &pb.UploadMediaSecretRequest_Trailer{
+		Trailer: &pb.MediaUploadTrailer{Sha256: checksum},
+	}})
+
+	return stream
+}
+
+func TestServer_MediaChecksum(t *testing.T) {
+	server, _, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	testUser := plainstorage.User{UUID: uuid.New().String(), Login: "testUser"}
+	plain.Users = append(plain.Users, testUser)
+
+	ctx := context.WithValue(context.Background(), UserContextKey, &testUser)
+	checksum := sha256.Sum256([]byte("hello world"))
+
+	session, err := server.StartMediaUpload(ctx, &pb.MediaSecretMetadata{Name: "media"})
+	require.NoError(t, err)
+
+	stream := buildResumeStream(ctx, session.SessionId, 0, "hello ")
+	stream.err = errors.New("connection lost")
+	assert.Equal(t, codes.DataLoss, status.Code(server.UploadMediaSecret(stream)))
+
+	require.NoError(t, server.UploadMediaSecret(withTrailer(buildResumeStream(ctx, session.SessionId, 6, "world"), checksum[:])))
+
+	list, err := server.SecretList(ctx, &pb.SecretListRequest{SecretType: pb.SecretType_MEDIA})
+	require.NoError(t, err)
+	require.Len(t, list.Secrets, 1)
+	assert.Equal(t, int64(11), list.Secrets[0].Size, "size must include content of all streams of session")
+
+	downloadStream := &testDownloadStream{ctx: ctx}
+	require.NoError(t, server.DownloadMediaSecret(&pb.DownloadMediaSecretRequest{SecretName: "media"}, downloadStream))
+	require.Len(t, downloadStream.messages, 1)
+	assert.Equal(t, checksum[:], downloadStream.messages[0].Sha256, "checksum must include content of all streams of session")
+
+	session, err = server.StartMediaUpload(ctx, &pb.MediaSecretMetadata{Name: "corrupted"})
+	require.NoError(t, err)
+
+	err = server.UploadMediaSecret(withTrailer(buildResumeStream(ctx, session.SessionId, 0, "hello word"), checksum[:]))
+	assert.Equal(t, codes.DataLoss, status.Code(err))
+
+	_, err = server.GetMediaUploadSession(ctx, &pb.MediaUploadSessionRequest{SessionId: session.SessionId})
+	assert.Equal(t, codes.NotFound, status.Code(err), "session with corrupted content must be aborted")
+
+	stream = &testUploadStream{ctx: ctx, requests: []*pb.UploadMediaSecretRequest{
+		{Request: &pb.UploadMediaSecretRequest_Metadata{Metadata: &pb.MediaSecretMetadata{Name: "corrupted"}}},
+		{Request: &pb.UploadMediaSecretRequest_Data{Data: &pb.MediaSecret{Chunk: []byte("hello word")}}},
+	}}
+	assert.Equal(t, codes.DataLoss, status.Code(server.UploadMediaSecret(withTrailer(stream, checksum[:]))))
+
+	_, err = downloadTestMedia(t, server, ctx, &pb.DownloadMediaSecretRequest{SecretName: "corrupted"})
+	assert.Equal(t, codes.NotFound, status.Code(err), "media with corrupted content must not be saved")
+
+	session, err = server.StartMediaUpload(ctx, &pb.MediaSecretMetadata{Name: "trailing"})
+	require.NoError(t, err)
+
+	stream = withTrailer(buildResumeStream(ctx, session.SessionId, 0, "hello world"), checksum[:])
+	stream.requests = append(stream.requests, &pb.UploadMediaSecretRequest{Request: &pb.UploadMediaSecretRequest_Data{Data: &pb.MediaSecret{Chunk: []byte("!")}}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(server.UploadMediaSecret(stream)), "data must not be sent after trailer")
+}
+
+func TestServer_DownloadLimitedMediaResume(t *testing.T) {
+	server, _, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	testUser := plainstorage.User{UUID: uuid.New().String(), Login: "testUser"}
+	plain.Users = append(plain.Users, testUser)
+	ctx := context.WithValue(context.Background(), UserContextKey, &testUser)
+
+	require.NoError(t, server.UploadMediaSecret(&testUploadStream{ctx: ctx, requests: []*pb.UploadMediaSecretRequest{
+		{Request: &pb.UploadMediaSecretRequest_Metadata{Metadata: &pb.MediaSecretMetadata{Name: "once", MaxReads: 1}}},
+		{Request: &pb.UploadMediaSecretRequest_Data{Data: &pb.MediaSecret{Chunk: []byte("hello world")}}},
+	}}))
+
+	content, err := downloadTestMedia(t, server, ctx, &pb.DownloadMediaSecretRequest{SecretName: "once", Offset: 2, Length: 3})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("llo"), content)
+
+	secret, err := plain.GetUserSecretByName(ctx, testUser.UUID, "once", plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+	assert.Zero(t, secret.Metadata.Reads, "ranged stream must not count read")
+
+	broken := &testDownloadStream{ctx: ctx, err: errors.New("connection lost")}
+	assert.Equal(t, codes.DataLoss, status.Code(server.DownloadMediaSecret(&pb.DownloadMediaSecretRequest{SecretName: "once"}, broken)))
+
+	secret, err = plain.GetUserSecretByName(ctx, testUser.UUID, "once", plainstorage.SecretTypeMedia)
+	require.NoError(t, err, "media must be kept for resume of interrupted last read")
+	assert.Equal(t, 1, secret.Metadata.Reads)
+
+	content, err = downloadTestMedia(t, server, ctx, &pb.DownloadMediaSecretRequest{SecretName: "once", Offset: 5})
+	require.NoError(t, err)
+	assert.Equal(t, []byte(" world"), content)
+
+	_, err = downloadTestMedia(t, server, ctx, &pb.DownloadMediaSecretRequest{SecretName: "once"})
+	assert.Equal(t, codes.NotFound, status.Code(err), "media must be removed after its last read is sent")
+}
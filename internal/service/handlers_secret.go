@@ -1,48 +1,69 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	pb "github.com/nessai1/gophkeeper/api/proto"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
 	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"time"
 )
 
 func (s *Server) SecretList(ctx context.Context, request *pb.SecretListRequest) (*pb.SecretListResponse, error) {
 	userCtxVal := ctx.Value(UserContextKey)
 	user := userCtxVal.(*plainstorage.User)
 
-	s.logger.Info("User list secrets", zap.String("login", user.Login), zap.String("secret_type", request.SecretType.String()))
+	s.logger.Info("User list secrets", zap.String("login", user.Login), zap.String("secret_type", request.SecretType.String()), zap.Bool("any_type", request.GetAnyType()))
 	secretType, err := translateGRPCSecretTypeToSecretType(request.GetSecretType())
-	if err != nil {
+	if err != nil && !request.GetAnyType() {
 		s.logger.Info("User sends invalid secret type", zap.String("login", user.Login))
 
 		return nil, status.Error(codes.InvalidArgument, "invalid secret type got")
 	}
 
-	secrets, err := s.plainStorage.GetUserSecretsMetadataByType(ctx, user.UUID, secretType)
+	page, err := buildSecretPage(request)
+	if err != nil {
+		s.logger.Info("User sends invalid page of secrets list", zap.String("login", user.Login), zap.Error(err))
+
+		return nil, status.Error(codes.InvalidArgument, "invalid page of secrets list got")
+	}
+
+	// request one extra secret to know that next page exists
+	limit := page.Limit
+	page.Limit++
+
+	secrets, err := s.plainStorage.GetUserSecretsMetadata(ctx, user.UUID, buildSecretFilter(request, secretType, time.Now()), page)
 	if err != nil {
 		s.logger.Error("Cannot get list of secrets for user", zap.String("login", user.Login))
 
 		return nil, status.Error(codes.Internal, "cannot list user secrets")
 	}
 
-	outputSecrets := make([]*pb.Secret, len(secrets))
-	for i, v := range secrets {
-		outputSecrets[i] = &pb.Secret{
-			SecretType:      request.SecretType,
-			Name:            v.Name,
-			CreateTimestamp: v.Created.Unix(),
-			UpdateTimestamp: v.Updated.Unix(),
-			Content:         nil,
+	nextPageToken := ""
+	if len(secrets) > limit {
+		secrets = secrets[:limit]
+
+		nextPageToken, err = buildPageToken(request, secrets[limit-1])
+		if err != nil {
+			s.logger.Error("Cannot build next page token", zap.String("login", user.Login), zap.Error(err))
+
+			return nil, status.Error(codes.Internal, "cannot list user secrets")
 		}
 	}
 
+	outputSecrets := make([]*pb.Secret, 0, len(secrets))
+	for _, v := range secrets {
+		outputSecrets = append(outputSecrets, buildGRPCSecret(v, nil))
+	}
+
 	return &pb.SecretListResponse{
-		Secrets: outputSecrets,
+		Secrets:       outputSecrets,
+		NextPageToken: nextPageToken,
 	}, nil
 }
 
@@ -71,19 +92,37 @@ func (s *Server) SecretDelete(ctx context.Context, request *pb.SecretDeleteReque
 	}
 
 	if request.GetSecretType() == pb.SecretType_MEDIA {
-		err := s.plainStorage.InTransaction(ctx, func() error {
-			deleteErr := s.mediaStorage.Delete(ctx, userSecret.Metadata.UUID)
+		// metadata removes in transaction before media, so failed media delete keeps secret available.
+		// Chunks of chunked media are released after commit, because other media may reference them
+		var released []string
+		err := s.plainStorage.InTransaction(ctx, func(tx plainstorage.PlainStorage) error {
+			deleteErr := tx.RemoveSecretByUUID(ctx, userSecret.Metadata.UUID)
 			if deleteErr != nil {
-				s.logger.Error("Error while delete media secret", zap.Error(err), zap.String("login", user.Login), zap.String("secret_name", request.SecretName))
+				s.logger.Error("Cannot remove secret from DB", zap.Error(deleteErr), zap.String("login", user.Login), zap.String("secret_name", request.SecretName))
 
-				return errors.New("internal error while remove media secret")
+				return errors.New("cannot remove secret from DB")
 			}
 
-			deleteErr = s.plainStorage.RemoveSecretByUUID(ctx, userSecret.Metadata.UUID)
-			if err != nil {
-				s.logger.Error("Cannot remove secret from DB", zap.Error(err), zap.String("login", user.Login), zap.String("secret_name", request.SecretName))
+			var chunked bool
+			released, chunked, deleteErr = s.removeMediaChunks(ctx, tx, userSecret.Metadata.UUID)
+			if deleteErr != nil {
+				s.logger.Error("Cannot remove chunks of media secret", zap.Error(deleteErr), zap.String("login", user.Login), zap.String("secret_name", request.SecretName))
 
-				return errors.New("cannot remove secret from DB")
+				return errors.New("cannot remove chunks of media secret")
+			}
+
+			if chunked {
+				return nil
+			}
+
+			// object may be already removed by previous failed delete or lost by storage, secret is removed anyway
+			deleteErr = s.mediaStorage.Delete(ctx, userSecret.Metadata.UUID)
+			if errors.Is(deleteErr, mediastorage.ErrObjectNotFound) {
+				s.logger.Warn("Media object of removed secret not found", zap.String("login", user.Login), zap.String("secret_uuid", userSecret.Metadata.UUID))
+			} else if deleteErr != nil {
+				s.logger.Error("Error while delete media secret", zap.Error(deleteErr), zap.String("login", user.Login), zap.String("secret_name", request.SecretName))
+
+				return errors.New("internal error while remove media secret")
 			}
 
 			return nil
@@ -92,6 +131,11 @@ func (s *Server) SecretDelete(ctx context.Context, request *pb.SecretDeleteReque
 		if err != nil {
 			return nil, status.Error(codes.Internal, err.Error())
 		}
+
+		err = s.chunkStorage.Release(ctx, user.UUID, released)
+		if err != nil {
+			s.logger.Error("Cannot release chunks of removed media", zap.Error(err), zap.String("login", user.Login))
+		}
 	} else {
 		err = s.plainStorage.RemoveSecretByUUID(ctx, userSecret.Metadata.UUID)
 		if err != nil {
@@ -123,15 +167,55 @@ func (s *Server) SecretSet(ctx context.Context, request *pb.SecretSetRequest) (*
 		return nil, status.Error(codes.InvalidArgument, "cannot set media secret to plain storage")
 	}
 
-	_, err = s.plainStorage.AddPlainSecret(ctx, user.UUID, request.Name, secretType, request.Content)
-	if err != nil && errors.Is(plainstorage.ErrEntityAlreadyExists, err) {
-		s.logger.Info("User try to add existing secret", zap.String("secret_name", request.GetName()), zap.String("secret_type", request.GetSecretType().String()))
+	expiresAt, maxReads, err := translateGRPCSecretLimits(request.GetExpireTimestamp(), request.GetMaxReads())
+	if err != nil {
+		s.logger.Info("User send invalid secret limits", zap.String("login", user.Login), zap.Error(err))
 
-		return nil, status.Error(codes.AlreadyExists, "secret with name already exists")
-	} else if err != nil {
-		s.logger.Error("Cannot add plain secret", zap.String("login", user.Login), zap.Error(err))
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 
-		return nil, status.Error(codes.Internal, "internal error while save secret")
+	_, err = s.checkQuota(ctx, user, false, 1, int64(len(request.GetContent())))
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.plainStorage.InTransaction(ctx, func(tx plainstorage.PlainStorage) error {
+		secret, err := tx.AddPlainSecret(ctx, user.UUID, request.Name, request.EncryptedName, secretType, request.Content)
+		if err != nil && errors.Is(plainstorage.ErrEntityAlreadyExists, err) {
+			s.logger.Info("User try to add existing secret", zap.String("secret_name", request.GetName()), zap.String("secret_type", request.GetSecretType().String()))
+
+			return status.Error(codes.AlreadyExists, "secret with name already exists")
+		} else if err != nil {
+			s.logger.Error("Cannot add plain secret", zap.String("login", user.Login), zap.Error(err))
+
+			return status.Error(codes.Internal, "internal error while save secret")
+		}
+
+		if request.GetFolder() != "" {
+			err = tx.SetSecretFolder(ctx, secret.Metadata.UUID, request.GetFolder())
+			if err != nil {
+				s.logger.Error("Cannot set plain secret folder", zap.String("login", user.Login), zap.Error(err))
+
+				return status.Error(codes.Internal, "internal error while save secret folder")
+			}
+		}
+
+		if expiresAt == nil && maxReads == 0 {
+			return s.checkStoredQuota(ctx, tx, user, false)
+		}
+
+		err = tx.SetSecretLimits(ctx, secret.Metadata.UUID, expiresAt, maxReads)
+		if err != nil {
+			s.logger.Error("Cannot set plain secret limits", zap.String("login", user.Login), zap.Error(err))
+
+			return status.Error(codes.Internal, "internal error while save secret limits")
+		}
+
+		return s.checkStoredQuota(ctx, tx, user, false)
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
 	return &pb.SecretSetResponse{}, nil
@@ -167,14 +251,17 @@ func (s *Server) SecretGet(ctx context.Context, request *pb.SecretGetRequest) (*
 		return nil, status.Error(codes.Internal, "internal error while get secret")
 	}
 
+	if secret.Metadata.HasLimits() {
+		md, err := s.registerSecretRead(ctx, s.plainStorage, secret.Metadata)
+		if err != nil {
+			return nil, err
+		}
+
+		secret.Metadata = *md
+	}
+
 	return &pb.SecretGetResponse{
-		Secret: &pb.Secret{
-			SecretType:      request.GetSecretType(),
-			Name:            secret.Metadata.Name,
-			CreateTimestamp: secret.Metadata.Created.Unix(),
-			UpdateTimestamp: secret.Metadata.Updated.Unix(),
-			Content:         secret.Data,
-		},
+		Secret: buildGRPCSecret(secret.Metadata, secret.Data),
 	}, nil
 }
 
@@ -197,16 +284,303 @@ func (s *Server) SecretUpdate(ctx context.Context, request *pb.SecretUpdateReque
 		return nil, status.Error(codes.InvalidArgument, "cannot get media secret to plain storage")
 	}
 
-	err = s.plainStorage.UpdatePlainSecretDataByName(ctx, user.UUID, request.GetName(), secretType, request.GetContent())
+	expiresAt, maxReads, err := translateGRPCSecretLimits(request.GetExpireTimestamp(), request.GetMaxReads())
 	if err != nil {
-		s.logger.Error("Cannot update plain secret", zap.Error(err), zap.String("login", user.Login))
+		s.logger.Info("User send invalid secret limits", zap.String("login", user.Login), zap.Error(err))
 
-		return nil, status.Error(codes.Internal, "cannot update plain secret")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = s.plainStorage.InTransaction(ctx, func(tx plainstorage.PlainStorage) error {
+		err := s.updatePlainSecret(ctx, tx, user, request, secretType, expiresAt, maxReads)
+		if err != nil {
+			s.logger.Error("Cannot update plain secret", zap.Error(err), zap.String("login", user.Login))
+
+			return status.Error(codes.Internal, "cannot update plain secret")
+		}
+
+		// content of secret is replaced, so usage is checked after update and growth of content over quota rolls it back
+		return s.checkStoredQuota(ctx, tx, user, false)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &pb.SecretUpdateResponse{}, nil
 }
 
+// updatePlainSecret replaces content of secret by name and its limits if request replaces them
+func (s *Server) updatePlainSecret(ctx context.Context, tx plainstorage.PlainStorage, user *plainstorage.User, request *pb.SecretUpdateRequest, secretType plainstorage.SecretType, expiresAt *time.Time, maxReads int) error {
+	err := tx.UpdatePlainSecretDataByName(ctx, user.UUID, request.GetName(), secretType, request.GetContent())
+	if err != nil || !request.GetReplaceLimits() {
+		return err
+	}
+
+	secret, err := tx.GetUserSecretByName(ctx, user.UUID, request.GetName(), secretType)
+	if err != nil {
+		return err
+	}
+
+	return tx.SetSecretLimits(ctx, secret.Metadata.UUID, expiresAt, maxReads)
+}
+
+func (s *Server) SecretMigrateName(ctx context.Context, request *pb.SecretMigrateNameRequest) (*pb.SecretMigrateNameResponse, error) {
+	userCtxVal := ctx.Value(UserContextKey)
+	user := userCtxVal.(*plainstorage.User)
+
+	s.logger.Info("User try migrate secret name", zap.String("login", user.Login), zap.String("secret_type", request.GetSecretType().String()))
+
+	secretType, err := translateGRPCSecretTypeToSecretType(request.GetSecretType())
+	if err != nil {
+		s.logger.Error("User send invalid secret type", zap.String("login", user.Login))
+
+		return nil, status.Error(codes.InvalidArgument, "invalid secret type got")
+	}
+
+	if request.GetNameIndex() == "" || len(request.GetEncryptedName()) == 0 {
+		s.logger.Info("User send empty encrypted name for migration", zap.String("login", user.Login))
+
+		return nil, status.Error(codes.InvalidArgument, "name index and encrypted name are required")
+	}
+
+	secret, err := s.plainStorage.GetUserSecretByName(ctx, user.UUID, request.GetName(), secretType)
+	if err != nil && errors.Is(plainstorage.ErrEntityNotFound, err) {
+		s.logger.Info("Cannot find secret for migration", zap.String("login", user.Login))
+
+		return nil, status.Error(codes.NotFound, "secret not found")
+	} else if err != nil {
+		s.logger.Error("Error while get secret for migration", zap.Error(err), zap.String("login", user.Login))
+
+		return nil, status.Error(codes.Internal, "internal error while get secret")
+	}
+
+	if len(secret.Metadata.EncryptedName) != 0 {
+		s.logger.Info("User try to migrate already encrypted secret name", zap.String("login", user.Login))
+
+		return nil, status.Error(codes.FailedPrecondition, "secret name is already encrypted")
+	}
+
+	err = s.renameSecret(ctx, secret.Metadata, request.GetNameIndex(), request.GetEncryptedName(), secretType, request.GetFolder())
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.SecretMigrateNameResponse{}, nil
+}
+
+func (s *Server) SecretRename(ctx context.Context, request *pb.SecretRenameRequest) (*pb.SecretRenameResponse, error) {
+	userCtxVal := ctx.Value(UserContextKey)
+	user := userCtxVal.(*plainstorage.User)
+
+	s.logger.Info("User try rename secret", zap.String("login", user.Login), zap.String("secret_name", request.GetName()), zap.String("secret_type", request.GetSecretType().String()))
+
+	secretType, err := translateGRPCSecretTypeToSecretType(request.GetSecretType())
+	if err != nil {
+		s.logger.Error("User send invalid secret type", zap.String("login", user.Login))
+
+		return nil, status.Error(codes.InvalidArgument, "invalid secret type got")
+	}
+
+	newSecretType := secretType
+	if request.NewSecretType != nil {
+		newSecretType, err = translateGRPCSecretTypeToSecretType(request.GetNewSecretType())
+		if err != nil {
+			s.logger.Error("User send invalid new secret type", zap.String("login", user.Login))
+
+			return nil, status.Error(codes.InvalidArgument, "invalid new secret type got")
+		}
+	}
+
+	if newSecretType != secretType && (newSecretType == plainstorage.SecretTypeMedia || secretType == plainstorage.SecretTypeMedia) {
+		s.logger.Info("User try to change type of media secret", zap.String("login", user.Login))
+
+		return nil, status.Error(codes.InvalidArgument, "type can be changed only between plain secret types")
+	}
+
+	if request.GetNewName() == "" {
+		s.logger.Info("User send empty new name of secret", zap.String("login", user.Login))
+
+		return nil, status.Error(codes.InvalidArgument, "new name of secret can't be empty")
+	}
+
+	secret, err := s.plainStorage.GetUserSecretByName(ctx, user.UUID, request.GetName(), secretType)
+	if err != nil && errors.Is(plainstorage.ErrEntityNotFound, err) {
+		s.logger.Info("Cannot find secret by name", zap.String("login", user.Login), zap.String("secret_name", request.GetName()))
+
+		return nil, status.Errorf(codes.NotFound, "secret '%s' not found", request.GetName())
+	} else if err != nil {
+		s.logger.Error("Error while get secret", zap.Error(err), zap.String("login", user.Login), zap.String("secret_name", request.GetName()))
+
+		return nil, status.Error(codes.Internal, "internal error while get secret")
+	}
+
+	err = s.renameSecret(ctx, secret.Metadata, request.GetNewName(), request.GetNewEncryptedName(), newSecretType, request.GetNewFolder())
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.SecretRenameResponse{}, nil
+}
+
+// renameSecret changes name, type and folder of secret in one transaction, returns grpc status error
+func (s *Server) renameSecret(ctx context.Context, md plainstorage.SecretMetadata, newName string, newEncryptedName []byte, newType plainstorage.SecretType, newFolder string) error {
+	return s.plainStorage.InTransaction(ctx, func(tx plainstorage.PlainStorage) error {
+		if newName != md.Name || newType != md.Type || !bytes.Equal(newEncryptedName, md.EncryptedName) {
+			err := tx.RenameSecret(ctx, md.UUID, newName, newEncryptedName, newType)
+			if err != nil && errors.Is(plainstorage.ErrEntityAlreadyExists, err) {
+				s.logger.Info("User try to rename secret to existing one", zap.String("user_uuid", md.UserUUID), zap.String("secret_name", newName))
+
+				return status.Error(codes.AlreadyExists, "secret with new name already exists")
+			} else if err != nil {
+				s.logger.Error("Cannot update secret name", zap.Error(err), zap.String("user_uuid", md.UserUUID))
+
+				return status.Error(codes.Internal, "cannot update secret name")
+			}
+		}
+
+		if newFolder != md.Folder {
+			err := tx.SetSecretFolder(ctx, md.UUID, newFolder)
+			if err != nil {
+				s.logger.Error("Cannot update secret folder", zap.Error(err), zap.String("user_uuid", md.UserUUID))
+
+				return status.Error(codes.Internal, "cannot update secret folder")
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *Server) SecretSetTags(ctx context.Context, request *pb.SecretSetTagsRequest) (*pb.SecretSetTagsResponse, error) {
+	userCtxVal := ctx.Value(UserContextKey)
+	user := userCtxVal.(*plainstorage.User)
+
+	s.logger.Info("User try set secret tags", zap.String("login", user.Login), zap.String("secret_name", request.GetName()), zap.String("secret_type", request.GetSecretType().String()))
+
+	secretType, err := translateGRPCSecretTypeToSecretType(request.GetSecretType())
+	if err != nil {
+		s.logger.Error("User send invalid secret type", zap.String("login", user.Login))
+
+		return nil, status.Error(codes.InvalidArgument, "invalid secret type got")
+	}
+
+	tags := make([]plainstorage.SecretTag, len(request.GetTags()))
+	for i, v := range request.GetTags() {
+		if v.GetTag() == "" {
+			s.logger.Info("User send empty tag", zap.String("login", user.Login))
+
+			return nil, status.Error(codes.InvalidArgument, "tag can't be empty")
+		}
+
+		tags[i] = plainstorage.SecretTag{Tag: v.GetTag(), EncryptedTag: v.GetEncryptedTag()}
+	}
+
+	secret, err := s.plainStorage.GetUserSecretByName(ctx, user.UUID, request.GetName(), secretType)
+	if err != nil && errors.Is(plainstorage.ErrEntityNotFound, err) {
+		s.logger.Info("Cannot find secret by name", zap.String("login", user.Login), zap.String("secret_name", request.GetName()))
+
+		return nil, status.Errorf(codes.NotFound, "secret '%s' not found", request.GetName())
+	} else if err != nil {
+		s.logger.Error("Error while get secret", zap.Error(err), zap.String("login", user.Login), zap.String("secret_name", request.GetName()))
+
+		return nil, status.Error(codes.Internal, "internal error while get secret")
+	}
+
+	err = s.plainStorage.SetSecretTags(ctx, secret.Metadata.UUID, tags)
+	if err != nil {
+		s.logger.Error("Cannot set secret tags", zap.Error(err), zap.String("login", user.Login))
+
+		return nil, status.Error(codes.Internal, "cannot set secret tags")
+	}
+
+	return &pb.SecretSetTagsResponse{}, nil
+}
+
+// registerSecretRead counts read of secret with limits, secret that has no reads left after it will be removed.
+// Returns grpc status error if secret can't be read
+func (s *Server) registerSecretRead(ctx context.Context, storage plainstorage.PlainStorage, md plainstorage.SecretMetadata) (*plainstorage.SecretMetadata, error) {
+	readMetadata, err := storage.RegisterSecretRead(ctx, md.UUID)
+	if err != nil && errors.Is(plainstorage.ErrEntityNotFound, err) {
+		s.logger.Info("User try to read expired secret", zap.String("secret_uuid", md.UUID))
+
+		return nil, status.Error(codes.NotFound, "secret not found")
+	} else if err != nil {
+		s.logger.Error("Cannot register secret read", zap.Error(err), zap.String("secret_uuid", md.UUID))
+
+		return nil, status.Error(codes.Internal, "internal error while read secret")
+	}
+
+	if readMetadata.MaxReads > 0 && readMetadata.Reads >= readMetadata.MaxReads && md.Type != plainstorage.SecretTypeMedia {
+		// removal is nested transaction, so its failure in transaction of storage rollbacks only removal and keeps read
+		err = storage.InTransaction(ctx, func(tx plainstorage.PlainStorage) error {
+			return tx.RemoveSecretByUUID(ctx, readMetadata.UUID)
+		})
+		if err != nil {
+			// reaper removes it later
+			s.logger.Error("Cannot remove secret after last read", zap.Error(err), zap.String("secret_uuid", md.UUID))
+		}
+	}
+
+	return readMetadata, nil
+}
+
+func buildGRPCSecret(md plainstorage.SecretMetadata, content []byte) *pb.Secret {
+	secret := &pb.Secret{
+		SecretType:         translateSecretTypeToGRPCSecretType(md.Type),
+		Name:               md.Name,
+		EncryptedName:      md.EncryptedName,
+		CreateTimestamp:    md.Created.Unix(),
+		UpdateTimestamp:    md.Updated.Unix(),
+		Content:            content,
+		MaxReads:           int32(md.MaxReads),
+		Reads:              int32(md.Reads),
+		Folder:             md.Folder,
+		Size:               md.MediaSize,
+		EncryptedMediaInfo: md.EncryptedMediaInfo,
+	}
+
+	for _, v := range md.Tags {
+		secret.Tags = append(secret.Tags, &pb.SecretTag{Tag: v.Tag, EncryptedTag: v.EncryptedTag})
+	}
+
+	if md.ExpiresAt != nil {
+		secret.ExpireTimestamp = md.ExpiresAt.Unix()
+	}
+
+	return secret
+}
+
+// translateGRPCSecretLimits validates secret limits from request, zero values mean no limits
+func translateGRPCSecretLimits(expireTimestamp int64, maxReads int32) (*time.Time, int, error) {
+	if maxReads < 0 {
+		return nil, 0, fmt.Errorf("max reads can't be negative")
+	}
+
+	if expireTimestamp == 0 {
+		return nil, int(maxReads), nil
+	}
+
+	expiresAt := time.Unix(expireTimestamp, 0)
+	if !expiresAt.After(time.Now()) {
+		return nil, 0, fmt.Errorf("expire time must be in future")
+	}
+
+	return &expiresAt, int(maxReads), nil
+}
+
+func translateSecretTypeToGRPCSecretType(secretType plainstorage.SecretType) pb.SecretType {
+	switch secretType {
+	case plainstorage.SecretTypeText:
+		return pb.SecretType_TEXT
+	case plainstorage.SecretTypeMedia:
+		return pb.SecretType_MEDIA
+	case plainstorage.SecretTypeCard:
+		return pb.SecretType_CREDIT_CARD
+	default:
+		return pb.SecretType_CREDENTIALS
+	}
+}
+
 func translateGRPCSecretTypeToSecretType(secretType pb.SecretType) (plainstorage.SecretType, error) {
 	switch secretType {
 	case pb.SecretType_CREDENTIALS:
@@ -8,6 +8,8 @@ import (
 	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -147,7 +149,9 @@ func TestServer_SecretSetGet(t *testing.T) {
 		},
 	}
 
-	for _, tt := range tests {
+	for i := range tests {
+		// requests contain lock of proto message, so cases are used by pointer
+		tt := &tests[i]
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := context.WithValue(context.Background(), UserContextKey, &tt.user)
 			getRes, err := server.SecretGet(ctx, &pb.SecretGetRequest{
@@ -273,7 +277,9 @@ func TestServer_SecretUpdate(t *testing.T) {
 		},
 	}
 
-	for _, tt := range tests {
+	for i := range tests {
+		// requests contain lock of proto message, so cases are used by pointer
+		tt := &tests[i]
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := context.WithValue(context.Background(), UserContextKey, &tt.user)
 
@@ -298,6 +304,55 @@ func TestServer_SecretUpdate(t *testing.T) {
 	}
 }
 
+func TestServer_SecretUpdateLimits(t *testing.T) {
+	server, _, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	testUser := plainstorage.User{
+		UUID:         uuid.New().String(),
+		Login:        "testUser",
+		PasswordHash: "somesecrethash",
+	}
+	plain.Users = append(plain.Users, testUser)
+
+	ctx := context.WithValue(context.Background(), UserContextKey, &testUser)
+
+	_, err = server.SecretSet(ctx, &pb.SecretSetRequest{SecretType: pb.SecretType_TEXT, Name: "limited", Content: []byte("text"), MaxReads: 3})
+	require.NoError(t, err)
+	_, err = server.SecretGet(ctx, &pb.SecretGetRequest{SecretType: pb.SecretType_TEXT, Name: "limited"})
+	require.NoError(t, err)
+
+	_, err = server.SecretUpdate(ctx, &pb.SecretUpdateRequest{SecretType: pb.SecretType_TEXT, Name: "limited", Content: []byte("updated")})
+	require.NoError(t, err)
+
+	secret, err := plain.GetUserSecretByName(ctx, testUser.UUID, "limited", plainstorage.SecretTypeText)
+	require.NoError(t, err)
+	assert.Equal(t, 3, secret.Metadata.MaxReads, "limits must be kept by update")
+	assert.Equal(t, 1, secret.Metadata.Reads)
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	_, err = server.SecretUpdate(ctx, &pb.SecretUpdateRequest{
+		SecretType:      pb.SecretType_TEXT,
+		Name:            "limited",
+		Content:         []byte("replaced"),
+		ReplaceLimits:   true,
+		ExpireTimestamp: expiresAt.Unix(),
+		MaxReads:        2,
+	})
+	require.NoError(t, err)
+
+	secret, err = plain.GetUserSecretByName(ctx, testUser.UUID, "limited", plainstorage.SecretTypeText)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("replaced"), secret.Data)
+	assert.Equal(t, 2, secret.Metadata.MaxReads)
+	assert.Zero(t, secret.Metadata.Reads, "reads of replaced limits must be counted again")
+	require.NotNil(t, secret.Metadata.ExpiresAt)
+	assert.True(t, expiresAt.Equal(*secret.Metadata.ExpiresAt))
+
+	_, err = server.SecretUpdate(ctx, &pb.SecretUpdateRequest{SecretType: pb.SecretType_TEXT, Name: "limited", ReplaceLimits: true, MaxReads: -1})
+	assert.Error(t, err)
+}
+
 func TestServer_SecretDelete(t *testing.T) {
 	server, _, plain, err := NewTestServer()
 	require.NoError(t, err)
@@ -375,7 +430,9 @@ func TestServer_SecretDelete(t *testing.T) {
 		},
 	}
 
-	for _, tt := range tests {
+	for i := range tests {
+		// requests contain lock of proto message, so cases are used by pointer
+		tt := &tests[i]
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := context.WithValue(context.Background(), UserContextKey, &tt.user)
 			if tt.secretExists {
@@ -405,6 +462,482 @@ func TestServer_SecretDelete(t *testing.T) {
 	}
 }
 
+func TestServer_SecretDeleteMediaRollback(t *testing.T) {
+	server, media, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	testUser := plainstorage.User{
+		UUID:         uuid.New().String(),
+		Login:        "testUser",
+		PasswordHash: "somesecrethash",
+	}
+	plain.Users = append(plain.Users, testUser)
+
+	ctx := context.WithValue(context.Background(), UserContextKey, &testUser)
+
+	mediaUUID := uuid.New().String()
+	_, err = plain.AddSecretMetadata(ctx, testUser.UUID, mediaUUID, "media_secret", nil, plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+
+	// not empty directory in place of media object can't be removed, so removing of metadata must be rolled back
+	mediaPath := filepath.Join(media.StorageDir, mediaUUID)
+	require.NoError(t, os.MkdirAll(filepath.Join(mediaPath, "nested"), 0700))
+
+	_, err = server.SecretDelete(ctx, &pb.SecretDeleteRequest{SecretType: pb.SecretType_MEDIA, SecretName: "media_secret"})
+	require.Error(t, err)
+
+	_, err = plain.GetUserSecretByName(ctx, testUser.UUID, "media_secret", plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+
+	require.NoError(t, os.RemoveAll(mediaPath))
+	require.NoError(t, os.WriteFile(filepath.Join(media.StorageDir, mediaUUID), []byte("media"), 0600))
+
+	_, err = server.SecretDelete(ctx, &pb.SecretDeleteRequest{SecretType: pb.SecretType_MEDIA, SecretName: "media_secret"})
+	require.NoError(t, err)
+
+	_, err = plain.GetUserSecretByName(ctx, testUser.UUID, "media_secret", plainstorage.SecretTypeMedia)
+	assert.ErrorIs(t, err, plainstorage.ErrEntityNotFound)
+	assert.NoFileExists(t, filepath.Join(media.StorageDir, mediaUUID))
+}
+
+func TestServer_SecretDeleteMissingMedia(t *testing.T) {
+	server, _, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	testUser := plainstorage.User{
+		UUID:         uuid.New().String(),
+		Login:        "testUser",
+		PasswordHash: "somesecrethash",
+	}
+	plain.Users = append(plain.Users, testUser)
+
+	ctx := context.WithValue(context.Background(), UserContextKey, &testUser)
+
+	_, err = plain.AddSecretMetadata(ctx, testUser.UUID, uuid.New().String(), "media_secret", nil, plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+
+	// media object doesn't exist in storage, so secret without content is removed anyway
+	_, err = server.SecretDelete(ctx, &pb.SecretDeleteRequest{SecretType: pb.SecretType_MEDIA, SecretName: "media_secret"})
+	require.NoError(t, err)
+
+	_, err = plain.GetUserSecretByName(ctx, testUser.UUID, "media_secret", plainstorage.SecretTypeMedia)
+	assert.ErrorIs(t, err, plainstorage.ErrEntityNotFound)
+}
+
+func TestServer_SecretMigrateName(t *testing.T) {
+	server, _, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	testUser := plainstorage.User{
+		UUID:         uuid.New().String(),
+		Login:        "testUser",
+		PasswordHash: "somesecrethash",
+	}
+	plain.Users = append(plain.Users, testUser)
+
+	ctx := context.WithValue(context.Background(), UserContextKey, &testUser)
+	_, err = server.SecretSet(ctx, &pb.SecretSetRequest{
+		SecretType: pb.SecretType_TEXT,
+		Name:       "legacy_secret",
+		Content:    []byte("some text"),
+	})
+	require.NoError(t, err)
+
+	_, err = server.SecretMigrateName(ctx, &pb.SecretMigrateNameRequest{
+		SecretType:    pb.SecretType_TEXT,
+		Name:          "not_existing_secret",
+		NameIndex:     "someindex",
+		EncryptedName: []byte("encrypted"),
+	})
+	require.Error(t, err)
+
+	_, err = server.SecretMigrateName(ctx, &pb.SecretMigrateNameRequest{
+		SecretType:    pb.SecretType_TEXT,
+		Name:          "legacy_secret",
+		NameIndex:     "someindex",
+		EncryptedName: []byte("encrypted"),
+	})
+	require.NoError(t, err)
+
+	_, err = server.SecretGet(ctx, &pb.SecretGetRequest{
+		SecretType: pb.SecretType_TEXT,
+		Name:       "legacy_secret",
+	})
+	require.Error(t, err)
+
+	getRes, err := server.SecretGet(ctx, &pb.SecretGetRequest{
+		SecretType: pb.SecretType_TEXT,
+		Name:       "someindex",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("encrypted"), getRes.Secret.EncryptedName)
+	assert.Equal(t, []byte("some text"), getRes.Secret.Content)
+
+	_, err = server.SecretMigrateName(ctx, &pb.SecretMigrateNameRequest{
+		SecretType:    pb.SecretType_TEXT,
+		Name:          "someindex",
+		NameIndex:     "anotherindex",
+		EncryptedName: []byte("encrypted"),
+	})
+	require.Error(t, err)
+}
+
+func TestServer_SecretGetWithLimits(t *testing.T) {
+	server, _, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	testUser := plainstorage.User{
+		UUID:         uuid.New().String(),
+		Login:        "testUser",
+		PasswordHash: "somesecrethash",
+	}
+	plain.Users = append(plain.Users, testUser)
+
+	ctx := context.WithValue(context.Background(), UserContextKey, &testUser)
+
+	_, err = server.SecretSet(ctx, &pb.SecretSetRequest{
+		SecretType:      pb.SecretType_TEXT,
+		Name:            "expired_secret",
+		ExpireTimestamp: time.Now().Add(-time.Hour).Unix(),
+	})
+	require.Error(t, err)
+
+	_, err = server.SecretSet(ctx, &pb.SecretSetRequest{
+		SecretType: pb.SecretType_TEXT,
+		Name:       "one_time_secret",
+		Content:    []byte("read me once"),
+		MaxReads:   2,
+	})
+	require.NoError(t, err)
+
+	listRes, err := server.SecretList(ctx, &pb.SecretListRequest{SecretType: pb.SecretType_TEXT})
+	require.NoError(t, err)
+	require.Len(t, listRes.Secrets, 1)
+	assert.Equal(t, int32(2), listRes.Secrets[0].MaxReads)
+
+	for i := 1; i <= 2; i++ {
+		getRes, err := server.SecretGet(ctx, &pb.SecretGetRequest{
+			SecretType: pb.SecretType_TEXT,
+			Name:       "one_time_secret",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []byte("read me once"), getRes.Secret.Content)
+		assert.Equal(t, int32(i), getRes.Secret.Reads)
+	}
+
+	_, err = server.SecretGet(ctx, &pb.SecretGetRequest{
+		SecretType: pb.SecretType_TEXT,
+		Name:       "one_time_secret",
+	})
+	require.Error(t, err)
+	assert.Empty(t, plain.SecretList)
+}
+
+func TestServer_SecretFoldersAndTags(t *testing.T) {
+	server, _, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	testUser := plainstorage.User{
+		UUID:         uuid.New().String(),
+		Login:        "testUser",
+		PasswordHash: "somesecrethash",
+	}
+	plain.Users = append(plain.Users, testUser)
+
+	ctx := context.WithValue(context.Background(), UserContextKey, &testUser)
+
+	for _, name := range []string{"root_secret", "work_secret", "other_work_secret"} {
+		folder := ""
+		if name != "root_secret" {
+			folder = "work_folder"
+		}
+
+		_, err = server.SecretSet(ctx, &pb.SecretSetRequest{
+			SecretType: pb.SecretType_TEXT,
+			Name:       name,
+			Content:    []byte("some text"),
+			Folder:     folder,
+		})
+		require.NoError(t, err)
+	}
+
+	listRes, err := server.SecretList(ctx, &pb.SecretListRequest{SecretType: pb.SecretType_TEXT, Folder: "work_folder"})
+	require.NoError(t, err)
+	assert.Len(t, listRes.Secrets, 2)
+
+	_, err = server.SecretRename(ctx, &pb.SecretRenameRequest{
+		SecretType: pb.SecretType_TEXT,
+		Name:       "work_secret",
+		NewName:    "other_work_secret",
+		NewFolder:  "",
+	})
+	require.Error(t, err)
+
+	_, err = server.SecretRename(ctx, &pb.SecretRenameRequest{
+		SecretType:       pb.SecretType_TEXT,
+		Name:             "work_secret",
+		NewName:          "moved_secret",
+		NewEncryptedName: []byte("encrypted"),
+		NewFolder:        "",
+	})
+	require.NoError(t, err)
+
+	listRes, err = server.SecretList(ctx, &pb.SecretListRequest{SecretType: pb.SecretType_TEXT, Folder: "work_folder"})
+	require.NoError(t, err)
+	require.Len(t, listRes.Secrets, 1)
+	assert.Equal(t, "other_work_secret", listRes.Secrets[0].Name)
+
+	_, err = server.SecretSetTags(ctx, &pb.SecretSetTagsRequest{
+		SecretType: pb.SecretType_TEXT,
+		Name:       "moved_secret",
+		Tags:       []*pb.SecretTag{{Tag: ""}},
+	})
+	require.Error(t, err)
+
+	_, err = server.SecretSetTags(ctx, &pb.SecretSetTagsRequest{
+		SecretType: pb.SecretType_TEXT,
+		Name:       "moved_secret",
+		Tags:       []*pb.SecretTag{{Tag: "prod", EncryptedTag: []byte("encrypted_prod")}, {Tag: "db", EncryptedTag: []byte("encrypted_db")}},
+	})
+	require.NoError(t, err)
+
+	listRes, err = server.SecretList(ctx, &pb.SecretListRequest{SecretType: pb.SecretType_TEXT, Tag: "prod"})
+	require.NoError(t, err)
+	require.Len(t, listRes.Secrets, 1)
+	assert.Equal(t, "moved_secret", listRes.Secrets[0].Name)
+	assert.Len(t, listRes.Secrets[0].Tags, 2)
+
+	_, err = server.SecretSetTags(ctx, &pb.SecretSetTagsRequest{
+		SecretType: pb.SecretType_TEXT,
+		Name:       "moved_secret",
+	})
+	require.NoError(t, err)
+
+	listRes, err = server.SecretList(ctx, &pb.SecretListRequest{SecretType: pb.SecretType_TEXT, Tag: "prod"})
+	require.NoError(t, err)
+	assert.Empty(t, listRes.Secrets)
+}
+
+func TestServer_SecretListPages(t *testing.T) {
+	server, _, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	testUser := plainstorage.User{
+		UUID:         uuid.New().String(),
+		Login:        "testUser",
+		PasswordHash: "somesecrethash",
+	}
+	plain.Users = append(plain.Users, testUser)
+
+	ctx := context.WithValue(context.Background(), UserContextKey, &testUser)
+
+	for i := 0; i < 5; i++ {
+		_, err = server.SecretSet(ctx, &pb.SecretSetRequest{
+			SecretType: pb.SecretType_TEXT,
+			Name:       fmt.Sprintf("secret_%d", i),
+			Content:    []byte("some text"),
+		})
+		require.NoError(t, err)
+
+		// keeps created dates of secrets different
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err = server.SecretSet(ctx, &pb.SecretSetRequest{
+		SecretType: pb.SecretType_TEXT,
+		Name:       "another_secret",
+		Content:    []byte("some text"),
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		request       *pb.SecretListRequest
+		expectedNames []string
+	}{
+		{
+			name:          "Sort by created date",
+			request:       &pb.SecretListRequest{SecretType: pb.SecretType_TEXT, PageSize: 2, Sort: pb.SecretSortField_CREATED},
+			expectedNames: []string{"secret_0", "secret_1", "secret_2", "secret_3", "secret_4", "another_secret"},
+		},
+		{
+			name:          "Sort by created date descending",
+			request:       &pb.SecretListRequest{SecretType: pb.SecretType_TEXT, PageSize: 4, Sort: pb.SecretSortField_CREATED, Descending: true},
+			expectedNames: []string{"another_secret", "secret_4", "secret_3", "secret_2", "secret_1", "secret_0"},
+		},
+		{
+			name:          "Filter by created date",
+			request:       &pb.SecretListRequest{SecretType: pb.SecretType_TEXT, CreatedBefore: time.Now().Add(-time.Hour).Unix()},
+			expectedNames: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for {
+				res, err := server.SecretList(ctx, tt.request)
+				require.NoError(t, err)
+				if tt.request.PageSize > 0 {
+					require.LessOrEqual(t, len(res.Secrets), int(tt.request.PageSize))
+				}
+
+				for _, v := range res.Secrets {
+					names = append(names, v.Name)
+				}
+
+				if res.NextPageToken == "" {
+					break
+				}
+
+				tt.request.PageToken = res.NextPageToken
+			}
+
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+
+	_, err = server.SecretList(ctx, &pb.SecretListRequest{SecretType: pb.SecretType_TEXT, PageToken: "invalid"})
+	assert.Error(t, err)
+
+	res, err := server.SecretList(ctx, &pb.SecretListRequest{SecretType: pb.SecretType_TEXT, PageSize: 1, Sort: pb.SecretSortField_UPDATED})
+	require.NoError(t, err)
+	_, err = server.SecretList(ctx, &pb.SecretListRequest{SecretType: pb.SecretType_TEXT, PageToken: res.NextPageToken})
+	assert.Error(t, err)
+}
+
+func TestServer_SecretListAnyType(t *testing.T) {
+	server, _, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	testUser := plainstorage.User{
+		UUID:         uuid.New().String(),
+		Login:        "testUser",
+		PasswordHash: "somesecrethash",
+	}
+	plain.Users = append(plain.Users, testUser)
+
+	ctx := context.WithValue(context.Background(), UserContextKey, &testUser)
+
+	for _, secretType := range []pb.SecretType{pb.SecretType_TEXT, pb.SecretType_CREDENTIALS, pb.SecretType_CREDIT_CARD} {
+		_, err = server.SecretSet(ctx, &pb.SecretSetRequest{
+			SecretType: secretType,
+			Name:       "github",
+			Content:    []byte("some content"),
+		})
+		require.NoError(t, err)
+	}
+
+	res, err := server.SecretList(ctx, &pb.SecretListRequest{AnyType: true, SecretType: pb.SecretType(100)})
+	require.NoError(t, err)
+	require.Len(t, res.Secrets, 3)
+
+	types := make([]pb.SecretType, 0, len(res.Secrets))
+	for _, v := range res.Secrets {
+		assert.Equal(t, "github", v.Name)
+		types = append(types, v.SecretType)
+	}
+	assert.ElementsMatch(t, []pb.SecretType{pb.SecretType_TEXT, pb.SecretType_CREDENTIALS, pb.SecretType_CREDIT_CARD}, types)
+
+	_, err = server.SecretList(ctx, &pb.SecretListRequest{SecretType: pb.SecretType(100)})
+	assert.Error(t, err)
+}
+
+func TestServer_SecretRename(t *testing.T) {
+	server, _, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	testUser := plainstorage.User{
+		UUID:         uuid.New().String(),
+		Login:        "testUser",
+		PasswordHash: "somesecrethash",
+	}
+	plain.Users = append(plain.Users, testUser)
+
+	ctx := context.WithValue(context.Background(), UserContextKey, &testUser)
+
+	for _, name := range []string{"first_secret", "second_secret"} {
+		_, err = server.SecretSet(ctx, &pb.SecretSetRequest{
+			SecretType: pb.SecretType_CREDENTIALS,
+			Name:       name,
+			Content:    []byte(name + " content"),
+		})
+		require.NoError(t, err)
+	}
+
+	_, err = server.SecretRename(ctx, &pb.SecretRenameRequest{
+		SecretType: pb.SecretType_CREDENTIALS,
+		Name:       "first_secret",
+		NewName:    "second_secret",
+	})
+	assert.Error(t, err)
+
+	_, err = server.SecretRename(ctx, &pb.SecretRenameRequest{
+		SecretType: pb.SecretType_CREDENTIALS,
+		Name:       "not_existing_secret",
+		NewName:    "third_secret",
+	})
+	assert.Error(t, err)
+
+	newType := pb.SecretType_TEXT
+	_, err = server.SecretRename(ctx, &pb.SecretRenameRequest{
+		SecretType:    pb.SecretType_CREDENTIALS,
+		Name:          "first_secret",
+		NewName:       "second_secret",
+		NewSecretType: &newType,
+	})
+	require.NoError(t, err)
+
+	getRes, err := server.SecretGet(ctx, &pb.SecretGetRequest{
+		SecretType: pb.SecretType_TEXT,
+		Name:       "second_secret",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("first_secret content"), getRes.Secret.Content)
+
+	_, err = server.SecretGet(ctx, &pb.SecretGetRequest{
+		SecretType: pb.SecretType_CREDENTIALS,
+		Name:       "first_secret",
+	})
+	assert.Error(t, err)
+
+	mediaUUID := uuid.New().String()
+	_, err = plain.AddSecretMetadata(ctx, testUser.UUID, mediaUUID, "media_secret", nil, plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+
+	_, err = server.SecretRename(ctx, &pb.SecretRenameRequest{
+		SecretType:    pb.SecretType_MEDIA,
+		Name:          "media_secret",
+		NewName:       "renamed_media",
+		NewSecretType: &newType,
+	})
+	assert.Error(t, err)
+
+	_, err = server.SecretRename(ctx, &pb.SecretRenameRequest{
+		SecretType: pb.SecretType_MEDIA,
+		Name:       "media_secret",
+		NewName:    "renamed_media",
+	})
+	require.NoError(t, err)
+
+	secret, err := plain.GetUserSecretByName(ctx, testUser.UUID, "renamed_media", plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+	assert.Equal(t, mediaUUID, secret.Metadata.UUID)
+
+	_, err = server.SecretRename(ctx, &pb.SecretRenameRequest{
+		SecretType: pb.SecretType_MEDIA,
+		Name:       "renamed_media",
+		NewName:    "folder/renamed_media",
+		NewFolder:  "folder",
+	})
+	require.NoError(t, err)
+
+	secret, err = plain.GetUserSecretByName(ctx, testUser.UUID, "folder/renamed_media", plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+	assert.Equal(t, mediaUUID, secret.Metadata.UUID)
+	assert.Equal(t, "folder", secret.Metadata.Folder)
+}
+
 func translateSecret(secret plainstorage.PlainSecret) (*pb.Secret, error) {
 	st, err := translatePlainStorageSecretTypeToGRPC(secret.Metadata.Type)
 	if err != nil {
@@ -414,6 +947,7 @@ func translateSecret(secret plainstorage.PlainSecret) (*pb.Secret, error) {
 	return &pb.Secret{
 		SecretType:      st,
 		Name:            secret.Metadata.Name,
+		EncryptedName:   secret.Metadata.EncryptedName,
 		CreateTimestamp: secret.Metadata.Created.Unix(),
 		UpdateTimestamp: secret.Metadata.Updated.Unix(),
 		Content:         nil,
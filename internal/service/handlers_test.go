@@ -1,6 +1,8 @@
 package service
 
 import (
+	"context"
+	"github.com/nessai1/gophkeeper/internal/service/chunkstorage"
 	"github.com/nessai1/gophkeeper/internal/service/config"
 	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
 	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
@@ -20,8 +22,10 @@ func NewTestServer() (*Server, *mediastorage.MediaStorageLocal, *plainstorage.Me
 	}
 
 	s := Server{
+		ctx:          context.Background(),
 		plainStorage: &plain,
 		mediaStorage: &media,
+		chunkStorage: chunkstorage.NewStorage(&media, &plain, zap.NewNop()),
 		logger:       zap.NewNop(),
 		config: config.Config{
 			Address:     "localhost",
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	pb "github.com/nessai1/gophkeeper/api/proto"
+	"github.com/nessai1/gophkeeper/internal/service/config"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"github.com/nessai1/gophkeeper/pkg/bytesize"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"math"
+)
+
+// unlimitedBytes bytes left for user if size of stored content is not limited by quota
+const unlimitedBytes = math.MaxInt64
+
+// errQuotaExceeded returns when upload of media content exceeds quota of user
+var errQuotaExceeded = status.Error(codes.ResourceExhausted, "storage quota of user exceeded")
+
+func (s *Server) GetUsage(ctx context.Context, _ *pb.UsageRequest) (*pb.UsageResponse, error) {
+	userCtxVal := ctx.Value(UserContextKey)
+	user := userCtxVal.(*plainstorage.User)
+
+	usage, err := s.plainStorage.GetUserUsage(ctx, user.UUID)
+	if err != nil {
+		s.logger.Error("Cannot get usage of user", zap.Error(err), zap.String("login", user.Login))
+
+		return nil, status.Error(codes.Internal, "cannot get usage of user")
+	}
+
+	quota := s.userQuota()
+
+	return &pb.UsageResponse{
+		MediaCount: int32(usage.MediaCount),
+		PlainCount: int32(usage.PlainCount),
+		MediaBytes: usage.MediaBytes,
+		PlainBytes: usage.PlainBytes,
+		MaxBytes:   int64(quota.MaxBytes),
+		MaxMedia:   int32(quota.MaxMedia),
+		MaxSecrets: int32(quota.MaxSecrets),
+	}, nil
+}
+
+// userQuota returns configured quota of every user, zero quota if users are unlimited
+func (s *Server) userQuota() config.UserQuotaConfig {
+	if s.config.UserQuota == nil {
+		return config.UserQuotaConfig{}
+	}
+
+	return *s.config.UserQuota
+}
+
+// checkQuota checks that user can add count of media or plain secrets with content of size without quota excess,
+// quota reserved by not finished uploads of user is counted as used.
+// Returns count of bytes left for user after adding, unlimitedBytes if size is not limited. Errors are grpc status errors
+func (s *Server) checkQuota(ctx context.Context, user *plainstorage.User, media bool, count int, size int64) (int64, error) {
+	quota := s.userQuota()
+	if quota == (config.UserQuotaConfig{}) {
+		return unlimitedBytes, nil
+	}
+
+	usage, err := s.plainStorage.GetUserUsage(ctx, user.UUID)
+	if err != nil {
+		s.logger.Error("Cannot get usage of user for quota check", zap.Error(err), zap.String("login", user.Login))
+
+		return 0, status.Error(codes.Internal, "cannot get usage of user")
+	}
+
+	reservedMedia, reservedBytes := s.uploadSessions.reserved(user.UUID)
+	usage.MediaCount += reservedMedia
+	usage.MediaBytes += reservedBytes
+
+	return s.quotaLeft(user, quota, usage, media, count, size)
+}
+
+// reserveQuota reserves count of media and bytes of content for upload of user, reservation must be released
+// on commit or abort of upload. Bytes received by upload are added to reservation by grow of upload sessions.
+// Errors are grpc status errors
+func (s *Server) reserveQuota(ctx context.Context, user *plainstorage.User, count int, size int64) (*quotaReservation, error) {
+	// reservation is added before check, so concurrent uploads of user see each other
+	reservation := s.uploadSessions.reserve(user.UUID, count, size)
+
+	quota := s.userQuota()
+	if quota == (config.UserQuotaConfig{}) {
+		return reservation, nil
+	}
+
+	usage, err := s.plainStorage.GetUserUsage(ctx, user.UUID)
+	if err != nil {
+		s.uploadSessions.release(reservation)
+		s.logger.Error("Cannot get usage of user for quota reservation", zap.Error(err), zap.String("login", user.Login))
+
+		return nil, status.Error(codes.Internal, "cannot get usage of user")
+	}
+
+	if quota.MaxBytes > 0 {
+		reservation.limit = int64(quota.MaxBytes) - usage.MediaBytes - usage.PlainBytes
+	}
+
+	reservedMedia, reservedBytes := s.uploadSessions.reserved(user.UUID)
+	usage.MediaCount += reservedMedia
+	usage.MediaBytes += reservedBytes
+
+	if _, err = s.quotaLeft(user, quota, usage, true, 0, 0); err != nil {
+		s.uploadSessions.release(reservation)
+
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// checkStoredQuota checks usage of user by storage of transaction that saves media or plain secrets, after they are saved.
+// Quota is checked again there, because stored usage could be changed by other requests after check on start of request
+func (s *Server) checkStoredQuota(ctx context.Context, tx plainstorage.PlainStorage, user *plainstorage.User, media bool) error {
+	quota := s.userQuota()
+	if quota == (config.UserQuotaConfig{}) {
+		return nil
+	}
+
+	usage, err := tx.GetUserUsage(ctx, user.UUID)
+	if err != nil {
+		s.logger.Error("Cannot get usage of user for quota check", zap.Error(err), zap.String("login", user.Login))
+
+		return status.Error(codes.Internal, "cannot get usage of user")
+	}
+
+	_, err = s.quotaLeft(user, quota, usage, media, 0, 0)
+
+	return err
+}
+
+// quotaLeft checks that user with usage can add count of media or plain secrets with content of size,
+// returns count of bytes left after adding
+func (s *Server) quotaLeft(user *plainstorage.User, quota config.UserQuotaConfig, usage plainstorage.Usage, media bool, count int, size int64) (int64, error) {
+	if media && quota.MaxMedia > 0 && usage.MediaCount+count > quota.MaxMedia {
+		s.logger.Info("User exceeds quota of media count", zap.String("login", user.Login), zap.Int("media_count", usage.MediaCount))
+
+		return 0, status.Errorf(codes.ResourceExhausted, "quota of %d media exceeded", quota.MaxMedia)
+	}
+
+	if !media && quota.MaxSecrets > 0 && usage.PlainCount+count > quota.MaxSecrets {
+		s.logger.Info("User exceeds quota of secrets count", zap.String("login", user.Login), zap.Int("plain_count", usage.PlainCount))
+
+		return 0, status.Errorf(codes.ResourceExhausted, "quota of %d secrets exceeded", quota.MaxSecrets)
+	}
+
+	if quota.MaxBytes == 0 {
+		return unlimitedBytes, nil
+	}
+
+	left := int64(quota.MaxBytes) - usage.MediaBytes - usage.PlainBytes - size
+	if left < 0 {
+		s.logger.Info("User exceeds quota of stored bytes", zap.String("login", user.Login), zap.Int64("media_bytes", usage.MediaBytes), zap.Int64("plain_bytes", usage.PlainBytes))
+
+		return 0, status.Errorf(codes.ResourceExhausted, "quota of %s exceeded", bytesize.ByteSize(quota.MaxBytes))
+	}
+
+	return left, nil
+}
+
+// newMediaCount returns count of media added by upload, overwrite of media doesn't add new one
+func newMediaCount(metadata *pb.MediaSecretMetadata) int {
+	if metadata.GetOverwrite() {
+		return 0
+	}
+
+	return 1
+}
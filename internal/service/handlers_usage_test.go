@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	pb "github.com/nessai1/gophkeeper/api/proto"
+	"github.com/nessai1/gophkeeper/internal/service/config"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"os"
+	"testing"
+)
+
+func TestServer_UserQuota(t *testing.T) {
+	server, media, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	server.config.UserQuota = &config.UserQuotaConfig{MaxBytes: 20, MaxMedia: 1, MaxSecrets: 2}
+
+	testUser := plainstorage.User{UUID: uuid.New().String(), Login: "testUser"}
+	plain.Users = append(plain.Users, testUser)
+
+	ctx := context.WithValue(context.Background(), UserContextKey, &testUser)
+
+	for _, name := range []string{"first", "second"} {
+		_, err = server.SecretSet(ctx, &pb.SecretSetRequest{SecretType: pb.SecretType_TEXT, Name: name, Content: []byte("12345")})
+		require.NoError(t, err)
+	}
+
+	_, err = server.SecretSet(ctx, &pb.SecretSetRequest{SecretType: pb.SecretType_TEXT, Name: "third", Content: []byte("1")})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "count of secrets must be limited")
+
+	res, err := server.BatchSet(ctx, &pb.BatchSetRequest{Items: []*pb.SecretSetRequest{
+		{SecretType: pb.SecretType_TEXT, Name: "third", Content: []byte("1")},
+	}})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "batch must be limited by quota too")
+	assert.Nil(t, res)
+
+	_, err = server.SecretUpdate(ctx, &pb.SecretUpdateRequest{SecretType: pb.SecretType_TEXT, Name: "first", Content: []byte("1234567890123456")})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "growth of secret content must be limited")
+
+	stream := &testUploadStream{ctx: ctx, requests: []*pb.UploadMediaSecretRequest{
+		{Request: &pb.UploadMediaSecretRequest_Metadata{Metadata: &pb.MediaSecretMetadata{Name: "media"}}},
+		{Request: &pb.UploadMediaSecretRequest_Data{Data: &pb.MediaSecret{Chunk: []byte("12345678")}}},
+		{Request: &pb.UploadMediaSecretRequest_Data{Data: &pb.MediaSecret{Chunk: []byte("12345678")}}},
+	}}
+	assert.Equal(t, codes.ResourceExhausted, status.Code(server.UploadMediaSecret(stream)), "upload must be aborted after quota excess")
+
+	entries, err := os.ReadDir(media.StorageDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "content of aborted upload must be removed")
+
+	stream = &testUploadStream{ctx: ctx, requests: []*pb.UploadMediaSecretRequest{
+		{Request: &pb.UploadMediaSecretRequest_Metadata{Metadata: &pb.MediaSecretMetadata{Name: "media"}}},
+		{Request: &pb.UploadMediaSecretRequest_Data{Data: &pb.MediaSecret{Chunk: []byte("12345678")}}},
+	}}
+	require.NoError(t, server.UploadMediaSecret(stream))
+
+	_, err = server.StartMediaUpload(ctx, &pb.MediaSecretMetadata{Name: "other"})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "count of media must be limited")
+
+	_, err = server.StartMediaUpload(ctx, &pb.MediaSecretMetadata{Name: "media", Overwrite: true})
+	assert.NoError(t, err, "overwrite doesn't add media")
+
+	chunk := []byte("chunk")
+	_, err = server.UploadMediaChunk(ctx, &pb.UploadMediaChunkRequest{Hash: testChunkHash(chunk), Data: chunk})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "chunks must be limited by quota")
+
+	usage, err := server.GetUsage(ctx, &pb.UsageRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, &pb.UsageResponse{
+		MediaCount: 1,
+		PlainCount: 2,
+		MediaBytes: 8,
+		PlainBytes: 10,
+		MaxBytes:   20,
+		MaxMedia:   1,
+		MaxSecrets: 2,
+	}, usage)
+}
+
+func TestServer_UserQuotaReservations(t *testing.T) {
+	server, _, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	server.config.UserQuota = &config.UserQuotaConfig{MaxBytes: 10, MaxMedia: 2}
+
+	testUser := plainstorage.User{UUID: uuid.New().String(), Login: "testUser"}
+	plain.Users = append(plain.Users, testUser)
+
+	ctx := context.WithValue(context.Background(), UserContextKey, &testUser)
+
+	first, err := server.StartMediaUpload(ctx, &pb.MediaSecretMetadata{Name: "first"})
+	require.NoError(t, err)
+
+	second, err := server.StartMediaUpload(ctx, &pb.MediaSecretMetadata{Name: "second"})
+	require.NoError(t, err)
+
+	_, err = server.StartMediaUpload(ctx, &pb.MediaSecretMetadata{Name: "third"})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "count of media must include not finished uploads")
+
+	stream := buildResumeStream(ctx, first.SessionId, 0, "123456")
+	stream.err = errors.New("connection lost")
+	assert.Equal(t, codes.DataLoss, status.Code(server.UploadMediaSecret(stream)))
+
+	err = server.UploadMediaSecret(buildResumeStream(ctx, second.SessionId, 0, "123456"))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "bytes of not finished uploads must be reserved")
+
+	_, err = server.StartMediaUpload(ctx, &pb.MediaSecretMetadata{Name: "third"})
+	require.NoError(t, err, "aborted upload must release its reservation")
+
+	// secret added bypassing quota check changes stored usage while upload, so commit must check quota again
+	_, err = plain.AddPlainSecret(ctx, testUser.UUID, "text", nil, plainstorage.SecretTypeText, []byte("12345"))
+	require.NoError(t, err)
+
+	err = server.UploadMediaSecret(buildResumeStream(ctx, first.SessionId, 6))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "quota must be checked by save of media")
+
+	_, err = plain.GetUserSecretByName(ctx, testUser.UUID, "first", plainstorage.SecretTypeMedia)
+	assert.ErrorIs(t, err, plainstorage.ErrEntityNotFound)
+}
+
+// staleUsageStorage gives usage without stored secrets out of transaction, like usage read before write of concurrent request
+type staleUsageStorage struct {
+	plainstorage.PlainStorage
+}
+
+func (s *staleUsageStorage) GetUserUsage(_ context.Context, _ string) (plainstorage.Usage, error) {
+	return plainstorage.Usage{}, nil
+}
+
+func TestServer_UserQuotaStored(t *testing.T) {
+	server, media, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	server.config.UserQuota = &config.UserQuotaConfig{MaxBytes: 10, MaxSecrets: 1}
+
+	testUser := plainstorage.User{UUID: uuid.New().String(), Login: "testUser"}
+	plain.Users = append(plain.Users, testUser)
+
+	ctx := context.WithValue(context.Background(), UserContextKey, &testUser)
+
+	_, err = server.SecretSet(ctx, &pb.SecretSetRequest{SecretType: pb.SecretType_TEXT, Name: "first", Content: []byte("12345")})
+	require.NoError(t, err)
+
+	server.plainStorage = &staleUsageStorage{PlainStorage: server.plainStorage}
+
+	_, err = server.SecretSet(ctx, &pb.SecretSetRequest{SecretType: pb.SecretType_TEXT, Name: "second", Content: []byte("1")})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "count of secrets must be checked by write of secret")
+
+	_, err = server.BatchSet(ctx, &pb.BatchSetRequest{Items: []*pb.SecretSetRequest{
+		{SecretType: pb.SecretType_TEXT, Name: "second", Content: []byte("1")},
+	}})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "count of secrets must be checked by write of batch")
+
+	_, err = server.SecretUpdate(ctx, &pb.SecretUpdateRequest{SecretType: pb.SecretType_TEXT, Name: "first", Content: []byte("12345678901")})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "size of secrets must be checked by update of secret")
+
+	chunk := []byte("chunk1")
+	_, err = server.UploadMediaChunk(ctx, &pb.UploadMediaChunkRequest{Hash: testChunkHash(chunk), Data: chunk})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "size of chunks must be checked by registration of chunk")
+
+	entries, err := os.ReadDir(media.StorageDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "object of rejected chunk must be removed")
+
+	usage, err := plain.GetUserUsage(ctx, testUser.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, plainstorage.Usage{PlainCount: 1, PlainBytes: 5}, usage, "rejected writes must be rolled back")
+}
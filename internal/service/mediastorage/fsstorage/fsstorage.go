@@ -0,0 +1,338 @@
+package fsstorage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/service/config"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"go.uber.org/zap"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	objectsDir = "objects"
+	tempDir    = "tmp"
+
+	// shardLevels count of nested shard directories, shardWidth length of name of shard directory.
+	// Objects are spread over 65536 directories to keep directories small
+	shardLevels = 2
+	shardWidth  = 2
+)
+
+// FSStorage keeps media objects as files in local directory. Uploads are written to temporary files
+// and atomically moved to sharded object directories on complete, so readers never see partial objects
+type FSStorage struct {
+	dir string
+	// quota max size of all objects and uploads in bytes, 0 for unlimited storage
+	quota int64
+
+	mu sync.Mutex
+	// used size of stored objects and reserved by active uploads in bytes
+	used int64
+
+	logger *zap.Logger
+}
+
+func NewStorage(storageConfig config.FilesystemConfig, logger *zap.Logger) (*FSStorage, error) {
+	if storageConfig.Dir == "" {
+		return nil, errors.New("directory of filesystem storage can't be empty")
+	}
+
+	storage := FSStorage{
+		dir:    storageConfig.Dir,
+		quota:  int64(storageConfig.Quota),
+		logger: logger,
+	}
+
+	for _, dir := range []string{objectsDir, tempDir} {
+		if err := os.MkdirAll(filepath.Join(storage.dir, dir), 0700); err != nil {
+			return nil, fmt.Errorf("cannot create %s directory of filesystem storage: %w", dir, err)
+		}
+	}
+
+	if err := storage.removeOrphanUploads(); err != nil {
+		return nil, fmt.Errorf("cannot remove orphan uploads: %w", err)
+	}
+
+	used, err := storage.countUsedSize()
+	if err != nil {
+		return nil, fmt.Errorf("cannot count used size of filesystem storage: %w", err)
+	}
+	storage.used = used
+
+	return &storage, nil
+}
+
+// removeOrphanUploads removes temporary files of uploads interrupted by service stop
+func (s *FSStorage) removeOrphanUploads() error {
+	entries, err := os.ReadDir(filepath.Join(s.dir, tempDir))
+	if err != nil {
+		return fmt.Errorf("cannot read temp directory: %w", err)
+	}
+
+	for _, v := range entries {
+		err = os.RemoveAll(filepath.Join(s.dir, tempDir, v.Name()))
+		if err != nil {
+			return fmt.Errorf("cannot remove orphan upload %s: %w", v.Name(), err)
+		}
+
+		s.logger.Info("Removed orphan upload of filesystem storage", zap.String("file", v.Name()))
+	}
+
+	return nil
+}
+
+func (s *FSStorage) countUsedSize() (int64, error) {
+	var used int64
+	err := filepath.WalkDir(filepath.Join(s.dir, objectsDir), func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		used += info.Size()
+
+		return nil
+	})
+
+	return used, err
+}
+
+// Used returns size of stored objects and active uploads in bytes
+func (s *FSStorage) Used() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.used
+}
+
+// reserve takes size from quota, returns mediastorage.ErrQuotaExceeded if quota has no space for it
+func (s *FSStorage) reserve(size int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.quota > 0 && s.used+size > s.quota {
+		return fmt.Errorf("cannot reserve %d bytes of disk: %w", size, mediastorage.ErrQuotaExceeded)
+	}
+
+	s.used += size
+
+	return nil
+}
+
+func (s *FSStorage) release(size int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.used -= size
+}
+
+// objectPath returns path of object file: objects/ab/cd/key, where shards are taken from hash of key
+func (s *FSStorage) objectPath(key string) (string, error) {
+	if key == "" || key == "." || key == ".." || strings.ContainsAny(key, `/\`) {
+		return "", fmt.Errorf("invalid media key '%s'", key)
+	}
+
+	hash := sha256.Sum256([]byte(key))
+	shard := hex.EncodeToString(hash[:])
+
+	parts := []string{s.dir, objectsDir}
+	for i := 0; i < shardLevels; i++ {
+		parts = append(parts, shard[i*shardWidth:(i+1)*shardWidth])
+	}
+
+	return filepath.Join(append(parts, key)...), nil
+}
+
+func (s *FSStorage) StartUpload(_ context.Context, key string) (mediastorage.MultipartUpload, error) {
+	path, err := s.objectPath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.CreateTemp(filepath.Join(s.dir, tempDir), "upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create temp file of upload: %w", err)
+	}
+
+	return &FSMultipartUpload{storage: s, file: f, path: path}, nil
+}
+
+func (s *FSStorage) StartDownload(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.objectPath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("cannot open media object %s: %w", key, mediastorage.ErrObjectNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot open media object %s: %w", key, err)
+	}
+
+	return f, nil
+}
+
+func (s *FSStorage) StartDownloadRange(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	if err := mediastorage.ValidateRange(offset, length); err != nil {
+		return nil, err
+	}
+
+	rc, err := s.StartDownload(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return mediastorage.OpenFileRange(rc.(*os.File), offset, length)
+}
+
+func (s *FSStorage) Delete(_ context.Context, key string) error {
+	path, err := s.objectPath(key)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("cannot delete media object %s: %w", key, mediastorage.ErrObjectNotFound)
+	} else if err != nil {
+		return fmt.Errorf("cannot get info of media object %s: %w", key, err)
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("cannot delete media object %s: %w", key, mediastorage.ErrObjectNotFound)
+	} else if err != nil {
+		return fmt.Errorf("cannot delete media object %s: %w", key, err)
+	}
+
+	s.release(info.Size())
+
+	return nil
+}
+
+func (s *FSStorage) List(_ context.Context, fn func(info mediastorage.ObjectInfo) error) error {
+	return filepath.WalkDir(filepath.Join(s.dir, objectsDir), func(_ string, d fs.DirEntry, err error) error {
+		if errors.Is(err, os.ErrNotExist) {
+			// object or shard is removed while walk
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("cannot walk objects directory: %w", err)
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("cannot get info of media object %s: %w", d.Name(), err)
+		}
+
+		return fn(mediastorage.ObjectInfo{Key: d.Name(), Size: info.Size(), Modified: info.ModTime()})
+	})
+}
+
+// FSMultipartUpload writes content to temp file, that is moved to object path on Complete
+type FSMultipartUpload struct {
+	storage *FSStorage
+	file    *os.File
+	path    string
+	// size of written content, that is reserved in quota of storage
+	size int64
+}
+
+func (u *FSMultipartUpload) Upload(_ context.Context, content []byte) error {
+	err := u.storage.reserve(int64(len(content)))
+	if err != nil {
+		return err
+	}
+
+	n, err := u.file.Write(content)
+	u.size += int64(n)
+	u.storage.release(int64(len(content) - n))
+	if err != nil {
+		return fmt.Errorf("cannot write content to temp file: %w", err)
+	}
+
+	return nil
+}
+
+func (u *FSMultipartUpload) Complete(_ context.Context) error {
+	if err := u.file.Sync(); err != nil {
+		return errors.Join(fmt.Errorf("cannot sync temp file: %w", err), u.remove())
+	}
+
+	if err := u.file.Close(); err != nil {
+		return errors.Join(fmt.Errorf("cannot close temp file: %w", err), u.remove())
+	}
+
+	shardDir := filepath.Dir(u.path)
+	if err := os.MkdirAll(shardDir, 0700); err != nil {
+		return errors.Join(fmt.Errorf("cannot create shard directory: %w", err), u.remove())
+	}
+
+	// replaced object frees its space in quota
+	var replacedSize int64
+	if info, err := os.Stat(u.path); err == nil {
+		replacedSize = info.Size()
+	}
+
+	if err := os.Rename(u.file.Name(), u.path); err != nil {
+		return errors.Join(fmt.Errorf("cannot move temp file to object: %w", err), u.remove())
+	}
+	u.storage.release(replacedSize)
+
+	// rename is durable only after sync of directory
+	if err := syncDir(shardDir); err != nil {
+		return fmt.Errorf("cannot sync shard directory: %w", err)
+	}
+
+	return nil
+}
+
+func (u *FSMultipartUpload) Abort(_ context.Context) error {
+	err := u.file.Close()
+	if err != nil && !errors.Is(err, os.ErrClosed) {
+		return errors.Join(fmt.Errorf("cannot close temp file: %w", err), u.remove())
+	}
+
+	return u.remove()
+}
+
+// remove removes temp file of upload and releases its space in quota
+func (u *FSMultipartUpload) remove() error {
+	u.storage.release(u.size)
+	u.size = 0
+
+	err := os.Remove(u.file.Name())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("cannot remove temp file: %w", err)
+	}
+
+	return nil
+}
+
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(dir.Sync(), dir.Close())
+}
@@ -0,0 +1,109 @@
+package fsstorage
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nessai1/gophkeeper/internal/service/config"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage/mediastoragetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFSStorage(t *testing.T) {
+	storage, err := NewStorage(config.FilesystemConfig{Dir: t.TempDir()}, zap.NewNop())
+	require.NoError(t, err)
+
+	mediastoragetest.TestMediaStorage(t, storage)
+	assert.Zero(t, storage.Used())
+}
+
+func TestFSStorage_Quota(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	storage, err := NewStorage(config.FilesystemConfig{Dir: dir, Quota: 16}, zap.NewNop())
+	require.NoError(t, err)
+
+	key := uuid.New().String()
+	mediastoragetest.UploadObject(t, storage, key, []byte("123456"), 3)
+	assert.Equal(t, int64(6), storage.Used())
+
+	upload, err := storage.StartUpload(ctx, uuid.New().String())
+	require.NoError(t, err)
+	require.NoError(t, upload.Upload(ctx, []byte("1234567890")))
+	assert.ErrorIs(t, upload.Upload(ctx, []byte("5")), mediastorage.ErrQuotaExceeded)
+	require.NoError(t, upload.Abort(ctx))
+	assert.Equal(t, int64(6), storage.Used())
+
+	// replaced object frees its space
+	mediastoragetest.UploadObject(t, storage, key, []byte("12345678"), 8)
+	assert.Equal(t, int64(8), storage.Used())
+
+	// used size is restored from stored objects
+	restarted, err := NewStorage(config.FilesystemConfig{Dir: dir, Quota: 16}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), restarted.Used())
+
+	require.NoError(t, restarted.Delete(ctx, key))
+	assert.Zero(t, restarted.Used())
+}
+
+func TestFSStorage_OrphanUploads(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	storage, err := NewStorage(config.FilesystemConfig{Dir: dir}, zap.NewNop())
+	require.NoError(t, err)
+
+	upload, err := storage.StartUpload(ctx, uuid.New().String())
+	require.NoError(t, err)
+	require.NoError(t, upload.Upload(ctx, []byte("interrupted upload")))
+
+	entries, err := os.ReadDir(filepath.Join(dir, tempDir))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	restarted, err := NewStorage(config.FilesystemConfig{Dir: dir}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Zero(t, restarted.Used())
+
+	entries, err = os.ReadDir(filepath.Join(dir, tempDir))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestFSStorage_objectPath(t *testing.T) {
+	storage, err := NewStorage(config.FilesystemConfig{Dir: t.TempDir()}, zap.NewNop())
+	require.NoError(t, err)
+
+	key := uuid.New().String()
+	path, err := storage.objectPath(key)
+	require.NoError(t, err)
+
+	rel, err := filepath.Rel(storage.dir, path)
+	require.NoError(t, err)
+
+	parts := splitPath(rel)
+	require.Len(t, parts, shardLevels+2)
+	assert.Equal(t, objectsDir, parts[0])
+	assert.Equal(t, key, parts[len(parts)-1])
+
+	for _, invalid := range []string{"", ".", "..", "../key", "dir/key"} {
+		_, err = storage.objectPath(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func splitPath(path string) []string {
+	dir, file := filepath.Split(path)
+	if dir == "" {
+		return []string{file}
+	}
+
+	return append(splitPath(filepath.Clean(dir)), file)
+}
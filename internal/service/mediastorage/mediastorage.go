@@ -2,17 +2,95 @@ package mediastorage
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"os"
+	"time"
 )
 
+// ErrObjectNotFound returns by storage for download or delete of object that doesn't exist
+var ErrObjectNotFound = errors.New("media object not found")
+
+// ErrQuotaExceeded returns by upload if storage has no space left for content
+var ErrQuotaExceeded = errors.New("quota of media storage exceeded")
+
 type MediaStorage interface {
+	// StartUpload starts upload of object, object is available for download only after Complete of upload
 	StartUpload(ctx context.Context, key string) (MultipartUpload, error)
+	// StartDownload opens reader of object content, returns ErrObjectNotFound if object doesn't exist
 	StartDownload(ctx context.Context, key string) (io.ReadCloser, error)
+	// StartDownloadRange opens reader of object content from offset, zero length reads content until end.
+	// Range after end of object gives empty content, returns ErrObjectNotFound if object doesn't exist
+	StartDownloadRange(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error)
+	// Delete removes object, returns ErrObjectNotFound if object doesn't exist
 	Delete(ctx context.Context, key string) error
+	// List calls fn for every stored object, objects of not completed uploads are not listed.
+	// Listing stops on first error of fn, that is returned by List
+	List(ctx context.Context, fn func(info ObjectInfo) error) error
+}
+
+// ObjectInfo stored object of media storage
+type ObjectInfo struct {
+	Key  string
+	Size int64
+	// Modified time of object upload complete
+	Modified time.Time
 }
 
 type MultipartUpload interface {
+	// Upload appends content to object, content can be reused by caller after return
 	Upload(ctx context.Context, content []byte) error
 	Complete(ctx context.Context) error
 	Abort(ctx context.Context) error
 }
+
+// Presigner is implemented by media storage that gives clients direct access to objects by presigned URLs
+type Presigner interface {
+	// PresignTTL returns lifetime of presigned URLs, 0 if storage doesn't allow presigned transfers
+	PresignTTL() time.Duration
+	// PresignUpload starts upload of object with content of size, content is uploaded by parts with HTTP PUT
+	// to presigned URLs of upload. URLs expire after ttl
+	PresignUpload(ctx context.Context, key string, size int64, ttl time.Duration) (PresignedUpload, error)
+	// PresignDownload returns URL for HTTP GET of object content, URL expires after ttl.
+	// Returns ErrObjectNotFound if object doesn't exist
+	PresignDownload(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// PresignedUpload upload of object by client, object is available for download only after Complete of upload
+type PresignedUpload interface {
+	// PartURLs returns URLs for HTTP PUT of parts, part i contains content from i*PartSize
+	PartURLs() []string
+	PartSize() int64
+	// Complete completes upload by ETags given by storage on upload of parts in order of parts, returns size of object
+	Complete(ctx context.Context, etags []string) (int64, error)
+	Abort(ctx context.Context) error
+}
+
+// ValidateRange checks that range of object content is not negative
+func ValidateRange(offset int64, length int64) error {
+	if offset < 0 || length < 0 {
+		return fmt.Errorf("invalid range of media object: offset %d, length %d", offset, length)
+	}
+
+	return nil
+}
+
+// OpenFileRange returns reader of file content from offset, zero length reads content until end. Reader closes file
+func OpenFileRange(file *os.File, offset int64, length int64) (io.ReadCloser, error) {
+	_, err := file.Seek(offset, io.SeekStart)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("cannot seek file to range offset: %w", err), file.Close())
+	}
+
+	if length == 0 {
+		return file, nil
+	}
+
+	return fileRange{Reader: io.LimitReader(file, length), Closer: file}, nil
+}
+
+type fileRange struct {
+	io.Reader
+	io.Closer
+}
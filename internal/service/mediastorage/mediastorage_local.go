@@ -7,52 +7,96 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // File storage contains files in folder, using for tests only
 
+// uploadSuffix suffix of file of not completed upload
+const uploadSuffix = ".upload"
+
 type MediaStorageLocal struct {
 	StorageDir string
 }
 
 func (m *MediaStorageLocal) StartUpload(_ context.Context, key string) (MultipartUpload, error) {
-	_, err := os.Stat(filepath.Join(m.StorageDir, key))
-	if err != nil && errors.Is(err, os.ErrNotExist) {
-		f, err := os.Open(filepath.Join(m.StorageDir, key))
-		if err != nil {
-			return nil, err
-		}
-
-		return &MultipartLocal{File: f}, nil
+	path := filepath.Join(m.StorageDir, key)
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("file %s exists", key)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
 	}
 
+	f, err := os.OpenFile(path+uploadSuffix, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("cannot create file of upload: %w", err)
 	}
 
-	return nil, fmt.Errorf("file exists")
+	return &MultipartLocal{File: f, path: path}, nil
 }
 
 func (m *MediaStorageLocal) StartDownload(_ context.Context, key string) (io.ReadCloser, error) {
-	if _, err := os.Stat(filepath.Join(m.StorageDir, key)); errors.Is(err, os.ErrNotExist) {
-		return nil, fmt.Errorf("file %s doesnt exists", key)
-	}
-
 	f, err := os.Open(filepath.Join(m.StorageDir, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("file %s doesnt exists: %w", key, ErrObjectNotFound)
+	}
 
 	return f, err
 }
 
+func (m *MediaStorageLocal) StartDownloadRange(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	if err := ValidateRange(offset, length); err != nil {
+		return nil, err
+	}
+
+	rc, err := m.StartDownload(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return OpenFileRange(rc.(*os.File), offset, length)
+}
+
 func (m *MediaStorageLocal) Delete(_ context.Context, key string) error {
-	if _, err := os.Stat(filepath.Join(m.StorageDir, key)); errors.Is(err, os.ErrNotExist) {
-		return fmt.Errorf("file %s doesnt exists", key)
+	err := os.Remove(filepath.Join(m.StorageDir, key))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("file %s doesnt exists: %w", key, ErrObjectNotFound)
 	}
 
-	return os.Remove(filepath.Join(m.StorageDir, key))
+	return err
 }
 
+func (m *MediaStorageLocal) List(_ context.Context, fn func(info ObjectInfo) error) error {
+	entries, err := os.ReadDir(m.StorageDir)
+	if err != nil {
+		return fmt.Errorf("cannot read storage directory: %w", err)
+	}
+
+	for _, v := range entries {
+		if v.IsDir() || strings.HasSuffix(v.Name(), uploadSuffix) {
+			continue
+		}
+
+		info, err := v.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("cannot get info of file %s: %w", v.Name(), err)
+		}
+
+		err = fn(ObjectInfo{Key: v.Name(), Size: info.Size(), Modified: info.ModTime()})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MultipartLocal writes upload to temporary file, that is renamed to object file on complete
 type MultipartLocal struct {
 	File *os.File
+	path string
 }
 
 func (m *MultipartLocal) Upload(_ context.Context, content []byte) error {
@@ -61,7 +105,12 @@ func (m *MultipartLocal) Upload(_ context.Context, content []byte) error {
 }
 
 func (m *MultipartLocal) Complete(_ context.Context) error {
-	return m.File.Close()
+	err := m.File.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(m.File.Name(), m.path)
 }
 
 func (m *MultipartLocal) Abort(_ context.Context) error {
@@ -0,0 +1,133 @@
+package mediastorage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStorage keeps objects in memory, objects are lost on service stop. Used by tests and dev instances
+type MemoryStorage struct {
+	mu      sync.RWMutex
+	objects map[string]memoryObject
+}
+
+type memoryObject struct {
+	content  []byte
+	modified time.Time
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{objects: make(map[string]memoryObject)}
+}
+
+func (m *MemoryStorage) StartUpload(_ context.Context, key string) (MultipartUpload, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if _, ok := m.objects[key]; ok {
+		return nil, fmt.Errorf("object %s exists", key)
+	}
+
+	return &MultipartMemory{storage: m, key: key}, nil
+}
+
+func (m *MemoryStorage) StartDownload(ctx context.Context, key string) (io.ReadCloser, error) {
+	return m.StartDownloadRange(ctx, key, 0, 0)
+}
+
+func (m *MemoryStorage) StartDownloadRange(_ context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	if err := ValidateRange(offset, length); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	object, ok := m.objects[key]
+	m.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("object %s doesnt exists: %w", key, ErrObjectNotFound)
+	}
+
+	// content of object is never changed after upload, so it's read without copy
+	content := object.content[min(offset, int64(len(object.content))):]
+	if length > 0 && length < int64(len(content)) {
+		content = content[:length]
+	}
+
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+func (m *MemoryStorage) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.objects[key]; !ok {
+		return fmt.Errorf("object %s doesnt exists: %w", key, ErrObjectNotFound)
+	}
+
+	delete(m.objects, key)
+
+	return nil
+}
+
+func (m *MemoryStorage) List(_ context.Context, fn func(info ObjectInfo) error) error {
+	m.mu.RLock()
+	infos := make([]ObjectInfo, 0, len(m.objects))
+	for key, object := range m.objects {
+		infos = append(infos, ObjectInfo{Key: key, Size: int64(len(object.content)), Modified: object.modified})
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Key < infos[j].Key
+	})
+
+	for _, info := range infos {
+		if err := fn(info); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MultipartMemory collects content of upload, that is stored as object on complete
+type MultipartMemory struct {
+	storage *MemoryStorage
+	key     string
+	content []byte
+}
+
+func (m *MultipartMemory) Upload(_ context.Context, content []byte) error {
+	m.content = append(m.content, content...)
+
+	return nil
+}
+
+func (m *MultipartMemory) Complete(_ context.Context) error {
+	m.storage.mu.Lock()
+	defer m.storage.mu.Unlock()
+
+	if _, ok := m.storage.objects[m.key]; ok {
+		return fmt.Errorf("object %s exists", m.key)
+	}
+
+	if m.content == nil {
+		m.content = []byte{}
+	}
+
+	m.storage.objects[m.key] = memoryObject{content: m.content, modified: time.Now()}
+
+	return nil
+}
+
+func (m *MultipartMemory) Abort(_ context.Context) error {
+	m.content = nil
+
+	return nil
+}
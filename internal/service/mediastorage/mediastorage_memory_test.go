@@ -0,0 +1,11 @@
+package mediastorage_test
+
+import (
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage/mediastoragetest"
+	"testing"
+)
+
+func TestMemoryStorage(t *testing.T) {
+	mediastoragetest.TestMediaStorage(t, mediastorage.NewMemoryStorage())
+}
@@ -0,0 +1,307 @@
+// Package mediastoragetest contains conformance suite for implementations of mediastorage.MediaStorage
+package mediastoragetest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"sync"
+	"testing"
+)
+
+// concurrentUploads count of uploads started at the same time by concurrency check
+const concurrentUploads = 8
+
+// TestMediaStorage checks behavior of storage that all implementations of MediaStorage must share.
+// Every check uses new random keys, so storage may contain objects of other runs
+func TestMediaStorage(t *testing.T, storage mediastorage.MediaStorage) {
+	t.Run("Upload", func(t *testing.T) {
+		testUpload(t, storage)
+	})
+
+	t.Run("Abort", func(t *testing.T) {
+		testAbort(t, storage)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		testDelete(t, storage)
+	})
+
+	t.Run("Range", func(t *testing.T) {
+		testRange(t, storage)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		testList(t, storage)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		testNotFound(t, storage)
+	})
+
+	t.Run("Concurrency", func(t *testing.T) {
+		testConcurrency(t, storage)
+	})
+}
+
+// UploadObject uploads content to storage by parts of partSize
+func UploadObject(t *testing.T, storage mediastorage.MediaStorage, key string, content []byte, partSize int) {
+	require.NoError(t, uploadObject(storage, key, content, partSize))
+}
+
+// DownloadObject returns content of object in storage
+func DownloadObject(t *testing.T, storage mediastorage.MediaStorage, key string) []byte {
+	content, err := downloadObject(storage, key)
+	require.NoError(t, err)
+
+	return content
+}
+
+func uploadObject(storage mediastorage.MediaStorage, key string, content []byte, partSize int) error {
+	ctx := context.Background()
+
+	upload, err := storage.StartUpload(ctx, key)
+	if err != nil {
+		return fmt.Errorf("cannot start upload: %w", err)
+	}
+
+	// part buffer is reused, like stream of service does
+	part := make([]byte, partSize)
+	for start := 0; start < len(content); start += partSize {
+		n := copy(part, content[start:])
+		if err = upload.Upload(ctx, part[:n]); err != nil {
+			return errors.Join(fmt.Errorf("cannot upload part: %w", err), upload.Abort(ctx))
+		}
+	}
+
+	return upload.Complete(ctx)
+}
+
+func downloadObject(storage mediastorage.MediaStorage, key string) ([]byte, error) {
+	reader, err := storage.StartDownload(context.Background(), key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot start download: %w", err)
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+func testUpload(t *testing.T, storage mediastorage.MediaStorage) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		content  []byte
+		partSize int
+	}{
+		{
+			name:     "One part",
+			content:  []byte("some media content"),
+			partSize: 1024,
+		},
+		{
+			name:     "Many parts",
+			content:  bytes.Repeat([]byte("0123456789"), 1000),
+			partSize: 999,
+		},
+		{
+			name:     "Empty object",
+			content:  []byte{},
+			partSize: 16,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := uuid.New().String()
+			UploadObject(t, storage, key, tt.content, tt.partSize)
+			defer func() {
+				assert.NoError(t, storage.Delete(ctx, key))
+			}()
+
+			assert.Equal(t, tt.content, DownloadObject(t, storage, key))
+		})
+	}
+
+	t.Run("Not completed upload", func(t *testing.T) {
+		key := uuid.New().String()
+
+		upload, err := storage.StartUpload(ctx, key)
+		require.NoError(t, err)
+		require.NoError(t, upload.Upload(ctx, []byte("some media content")))
+
+		_, err = storage.StartDownload(ctx, key)
+		assert.ErrorIs(t, err, mediastorage.ErrObjectNotFound)
+
+		require.NoError(t, upload.Abort(ctx))
+	})
+}
+
+func testAbort(t *testing.T, storage mediastorage.MediaStorage) {
+	ctx := context.Background()
+	key := uuid.New().String()
+
+	upload, err := storage.StartUpload(ctx, key)
+	require.NoError(t, err)
+	require.NoError(t, upload.Upload(ctx, []byte("some media content")))
+	require.NoError(t, upload.Abort(ctx))
+
+	_, err = storage.StartDownload(ctx, key)
+	assert.ErrorIs(t, err, mediastorage.ErrObjectNotFound)
+
+	// key of aborted upload can be used again
+	UploadObject(t, storage, key, []byte("new content"), 4)
+	assert.Equal(t, []byte("new content"), DownloadObject(t, storage, key))
+	assert.NoError(t, storage.Delete(ctx, key))
+}
+
+func testRange(t *testing.T, storage mediastorage.MediaStorage) {
+	ctx := context.Background()
+
+	key := uuid.New().String()
+	UploadObject(t, storage, key, []byte("0123456789"), 4)
+
+	tests := []struct {
+		name     string
+		offset   int64
+		length   int64
+		expected string
+	}{
+		{name: "Whole object", offset: 0, length: 0, expected: "0123456789"},
+		{name: "From offset", offset: 3, length: 0, expected: "3456789"},
+		{name: "Bounded", offset: 3, length: 4, expected: "3456"},
+		{name: "Length after end", offset: 8, length: 10, expected: "89"},
+		{name: "Offset at end", offset: 10, length: 0, expected: ""},
+		{name: "Offset after end", offset: 20, length: 5, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := storage.StartDownloadRange(ctx, key, tt.offset, tt.length)
+			require.NoError(t, err)
+
+			content, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			require.NoError(t, reader.Close())
+			assert.Equal(t, tt.expected, string(content))
+		})
+	}
+
+	_, err := storage.StartDownloadRange(ctx, key, -1, 0)
+	assert.Error(t, err)
+
+	_, err = storage.StartDownloadRange(ctx, uuid.New().String(), 1, 1)
+	assert.ErrorIs(t, err, mediastorage.ErrObjectNotFound)
+
+	require.NoError(t, storage.Delete(ctx, key))
+}
+
+func testDelete(t *testing.T, storage mediastorage.MediaStorage) {
+	ctx := context.Background()
+	key := uuid.New().String()
+	otherKey := uuid.New().String()
+
+	UploadObject(t, storage, key, []byte("some media content"), 4)
+	UploadObject(t, storage, otherKey, []byte("other media content"), 4)
+
+	require.NoError(t, storage.Delete(ctx, key))
+
+	_, err := storage.StartDownload(ctx, key)
+	assert.ErrorIs(t, err, mediastorage.ErrObjectNotFound)
+	assert.ErrorIs(t, storage.Delete(ctx, key), mediastorage.ErrObjectNotFound)
+
+	assert.Equal(t, []byte("other media content"), DownloadObject(t, storage, otherKey))
+	assert.NoError(t, storage.Delete(ctx, otherKey))
+}
+
+func testList(t *testing.T, storage mediastorage.MediaStorage) {
+	ctx := context.Background()
+	key := uuid.New().String()
+	otherKey := uuid.New().String()
+	uploadKey := uuid.New().String()
+
+	UploadObject(t, storage, key, []byte("some media content"), 4)
+	UploadObject(t, storage, otherKey, []byte{}, 4)
+
+	upload, err := storage.StartUpload(ctx, uploadKey)
+	require.NoError(t, err)
+	require.NoError(t, upload.Upload(ctx, []byte("not completed content")))
+
+	listed := make(map[string]int64)
+	err = storage.List(ctx, func(info mediastorage.ObjectInfo) error {
+		assert.False(t, info.Modified.IsZero())
+		listed[info.Key] = info.Size
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(len("some media content")), listed[key])
+	assert.Contains(t, listed, otherKey)
+	assert.NotContains(t, listed, uploadKey, "not completed upload must not be listed")
+
+	stopErr := errors.New("stop listing")
+	calls := 0
+	err = storage.List(ctx, func(_ mediastorage.ObjectInfo) error {
+		calls++
+
+		return stopErr
+	})
+	assert.ErrorIs(t, err, stopErr)
+	assert.Equal(t, 1, calls)
+
+	require.NoError(t, upload.Abort(ctx))
+	require.NoError(t, storage.Delete(ctx, key))
+	require.NoError(t, storage.Delete(ctx, otherKey))
+}
+
+func testNotFound(t *testing.T, storage mediastorage.MediaStorage) {
+	ctx := context.Background()
+	key := uuid.New().String()
+
+	_, err := storage.StartDownload(ctx, key)
+	assert.ErrorIs(t, err, mediastorage.ErrObjectNotFound)
+	assert.ErrorIs(t, storage.Delete(ctx, key), mediastorage.ErrObjectNotFound)
+}
+
+func testConcurrency(t *testing.T, storage mediastorage.MediaStorage) {
+	ctx := context.Background()
+
+	keys := make([]string, concurrentUploads)
+	contents := make([][]byte, concurrentUploads)
+	for i := range keys {
+		keys[i] = uuid.New().String()
+		contents[i] = bytes.Repeat([]byte{byte('a' + i)}, 4096+i)
+	}
+
+	wg := sync.WaitGroup{}
+	for i := range keys {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			assert.NoError(t, uploadObject(storage, keys[i], contents[i], 1000))
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range keys {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			content, err := downloadObject(storage, keys[i])
+			assert.NoError(t, err)
+			assert.Equal(t, contents[i], content)
+			assert.NoError(t, storage.Delete(ctx, keys[i]))
+		}(i)
+	}
+	wg.Wait()
+}
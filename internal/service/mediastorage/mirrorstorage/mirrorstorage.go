@@ -0,0 +1,322 @@
+package mirrorstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/pkg/bytesize"
+	"go.uber.org/zap"
+	"io"
+	"time"
+)
+
+// copyPartSize size of parts of object copied by repair
+const copyPartSize = 8 * int(bytesize.MB)
+
+// MirrorStorage writes every object to both backends and reads it from primary backend, falling back to secondary
+// if primary fails. Upload succeeds only if both backends store object, objects missing from one side
+// (e.g. after restore of backend) are copied by Repair
+type MirrorStorage struct {
+	primary   mediastorage.MediaStorage
+	secondary mediastorage.MediaStorage
+
+	logger *zap.Logger
+}
+
+func NewStorage(primary, secondary mediastorage.MediaStorage, logger *zap.Logger) *MirrorStorage {
+	return &MirrorStorage{primary: primary, secondary: secondary, logger: logger}
+}
+
+func (s *MirrorStorage) StartUpload(ctx context.Context, key string) (mediastorage.MultipartUpload, error) {
+	primary, err := s.primary.StartUpload(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot start upload to primary backend: %w", err)
+	}
+
+	secondary, err := s.secondary.StartUpload(ctx, key)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("cannot start upload to secondary backend: %w", err), primary.Abort(ctx))
+	}
+
+	return &MirrorUpload{storage: s, key: key, primary: primary, secondary: secondary}, nil
+}
+
+func (s *MirrorStorage) StartDownload(ctx context.Context, key string) (io.ReadCloser, error) {
+	reader, err := s.primary.StartDownload(ctx, key)
+	if err == nil {
+		return reader, nil
+	}
+
+	s.logFallback(key, err)
+	secondaryReader, secondaryErr := s.secondary.StartDownload(ctx, key)
+
+	return fallback(err, secondaryReader, secondaryErr)
+}
+
+func (s *MirrorStorage) StartDownloadRange(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	if err := mediastorage.ValidateRange(offset, length); err != nil {
+		return nil, err
+	}
+
+	reader, err := s.primary.StartDownloadRange(ctx, key, offset, length)
+	if err == nil {
+		return reader, nil
+	}
+
+	s.logFallback(key, err)
+	secondaryReader, secondaryErr := s.secondary.StartDownloadRange(ctx, key, offset, length)
+
+	return fallback(err, secondaryReader, secondaryErr)
+}
+
+func (s *MirrorStorage) logFallback(key string, primaryErr error) {
+	if errors.Is(primaryErr, mediastorage.ErrObjectNotFound) {
+		s.logger.Warn("Media object is missing in primary backend of mirror, it's read from secondary", zap.String("key", key))
+	} else {
+		s.logger.Error("Cannot read media object from primary backend of mirror, it's read from secondary", zap.String("key", key), zap.Error(primaryErr))
+	}
+}
+
+// fallback returns result of secondary download. If secondary hasn't object, but primary failed by other reason,
+// error of primary is returned, so missing object is reported only if both backends miss it
+func fallback(primaryErr error, reader io.ReadCloser, err error) (io.ReadCloser, error) {
+	if err == nil {
+		return reader, nil
+	}
+
+	if errors.Is(err, mediastorage.ErrObjectNotFound) && !errors.Is(primaryErr, mediastorage.ErrObjectNotFound) {
+		return nil, fmt.Errorf("cannot read object from primary backend: %w", primaryErr)
+	}
+
+	return nil, fmt.Errorf("cannot read object from both backends: %w", errors.Join(primaryErr, err))
+}
+
+// Delete removes object from both backends, returns ErrObjectNotFound if no one backend has object
+func (s *MirrorStorage) Delete(ctx context.Context, key string) error {
+	primaryErr := s.primary.Delete(ctx, key)
+	secondaryErr := s.secondary.Delete(ctx, key)
+
+	primaryMissing := errors.Is(primaryErr, mediastorage.ErrObjectNotFound)
+	secondaryMissing := errors.Is(secondaryErr, mediastorage.ErrObjectNotFound)
+	if primaryMissing && secondaryMissing {
+		return fmt.Errorf("object %s not found in mirror: %w", key, mediastorage.ErrObjectNotFound)
+	}
+
+	if primaryErr != nil && !primaryMissing {
+		return fmt.Errorf("cannot delete object from primary backend: %w", primaryErr)
+	}
+
+	if secondaryErr != nil && !secondaryMissing {
+		return fmt.Errorf("cannot delete object from secondary backend: %w", secondaryErr)
+	}
+
+	return nil
+}
+
+// List lists objects of both backends, object stored in both backends is listed once by info of primary
+func (s *MirrorStorage) List(ctx context.Context, fn func(info mediastorage.ObjectInfo) error) error {
+	listed := make(map[string]struct{})
+	for _, backend := range []mediastorage.MediaStorage{s.primary, s.secondary} {
+		var fnErr error
+		err := backend.List(ctx, func(info mediastorage.ObjectInfo) error {
+			if _, ok := listed[info.Key]; ok {
+				return nil
+			}
+			listed[info.Key] = struct{}{}
+
+			fnErr = fn(info)
+
+			return fnErr
+		})
+
+		if fnErr != nil {
+			return fnErr
+		} else if err != nil {
+			return fmt.Errorf("cannot list objects of mirror backend: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// MirrorUpload writes content of upload to both backends
+type MirrorUpload struct {
+	storage *MirrorStorage
+	key     string
+
+	primary   mediastorage.MultipartUpload
+	secondary mediastorage.MultipartUpload
+}
+
+func (u *MirrorUpload) Upload(ctx context.Context, content []byte) error {
+	if err := u.primary.Upload(ctx, content); err != nil {
+		return fmt.Errorf("cannot upload to primary backend: %w", err)
+	}
+
+	if err := u.secondary.Upload(ctx, content); err != nil {
+		return fmt.Errorf("cannot upload to secondary backend: %w", err)
+	}
+
+	return nil
+}
+
+// Complete completes upload in both backends. If secondary can't complete upload, object is removed from primary,
+// so object is either stored by both backends or by no one
+func (u *MirrorUpload) Complete(ctx context.Context) error {
+	if err := u.primary.Complete(ctx); err != nil {
+		return errors.Join(fmt.Errorf("cannot complete upload to primary backend: %w", err), u.secondary.Abort(ctx))
+	}
+
+	if err := u.secondary.Complete(ctx); err != nil {
+		return errors.Join(fmt.Errorf("cannot complete upload to secondary backend: %w", err), u.storage.primary.Delete(ctx, u.key))
+	}
+
+	return nil
+}
+
+func (u *MirrorUpload) Abort(ctx context.Context) error {
+	return errors.Join(u.primary.Abort(ctx), u.secondary.Abort(ctx))
+}
+
+// RepairReport result of mirror repair
+type RepairReport struct {
+	// CopiedToPrimary keys of objects copied from secondary to primary backend
+	CopiedToPrimary []string
+	// CopiedToSecondary keys of objects copied from primary to secondary backend
+	CopiedToSecondary []string
+	// Failed count of objects that can't be copied
+	Failed int
+}
+
+// Repair copies objects missing from one backend by other backend. Objects younger than grace period are skipped,
+// because they can belong to upload that is completing now
+func (s *MirrorStorage) Repair(ctx context.Context, gracePeriod time.Duration) (RepairReport, error) {
+	primaryObjects, err := listObjects(ctx, s.primary)
+	if err != nil {
+		return RepairReport{}, fmt.Errorf("cannot list primary backend: %w", err)
+	}
+
+	secondaryObjects, err := listObjects(ctx, s.secondary)
+	if err != nil {
+		return RepairReport{}, fmt.Errorf("cannot list secondary backend: %w", err)
+	}
+
+	report := RepairReport{}
+	deadline := time.Now().Add(-gracePeriod)
+	repair := func(from, to mediastorage.MediaStorage, source, target map[string]mediastorage.ObjectInfo, copied *[]string) error {
+		for key, info := range source {
+			if _, ok := target[key]; ok || info.Modified.After(deadline) {
+				continue
+			}
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			err := copyObject(ctx, from, to, info)
+			if errors.Is(err, mediastorage.ErrObjectNotFound) {
+				// object was deleted after listing
+				continue
+			} else if err != nil {
+				report.Failed++
+				s.logger.Error("Cannot repair media object of mirror", zap.String("key", key), zap.Error(err))
+
+				continue
+			}
+
+			*copied = append(*copied, key)
+		}
+
+		return nil
+	}
+
+	if err = repair(s.primary, s.secondary, primaryObjects, secondaryObjects, &report.CopiedToSecondary); err != nil {
+		return report, err
+	}
+
+	if err = repair(s.secondary, s.primary, secondaryObjects, primaryObjects, &report.CopiedToPrimary); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+// RunRepair repairs mirror every interval until ctx is done
+func (s *MirrorStorage) RunRepair(ctx context.Context, interval time.Duration, gracePeriod time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := s.Repair(ctx, gracePeriod)
+			if err != nil {
+				s.logger.Error("Cannot repair media storage mirror", zap.Error(err))
+
+				continue
+			}
+
+			if len(report.CopiedToPrimary) > 0 || len(report.CopiedToSecondary) > 0 || report.Failed > 0 {
+				s.logger.Warn(
+					"Media storage mirror is repaired",
+					zap.Int("copied_to_primary", len(report.CopiedToPrimary)),
+					zap.Int("copied_to_secondary", len(report.CopiedToSecondary)),
+					zap.Int("failed", report.Failed),
+				)
+			}
+		}
+	}
+}
+
+func listObjects(ctx context.Context, storage mediastorage.MediaStorage) (map[string]mediastorage.ObjectInfo, error) {
+	objects := make(map[string]mediastorage.ObjectInfo)
+	err := storage.List(ctx, func(info mediastorage.ObjectInfo) error {
+		objects[info.Key] = info
+
+		return nil
+	})
+
+	return objects, err
+}
+
+// copyObject copies object from one storage to other, copy is aborted if its size differs from listed size
+func copyObject(ctx context.Context, from, to mediastorage.MediaStorage, info mediastorage.ObjectInfo) error {
+	reader, err := from.StartDownload(ctx, info.Key)
+	if err != nil {
+		return fmt.Errorf("cannot download object for copy: %w", err)
+	}
+	defer reader.Close()
+
+	upload, err := to.StartUpload(ctx, info.Key)
+	if err != nil {
+		return fmt.Errorf("cannot start upload of copy: %w", err)
+	}
+
+	var copied int64
+	part := make([]byte, copyPartSize)
+	for {
+		n, err := io.ReadFull(reader, part)
+		if n > 0 {
+			if uploadErr := upload.Upload(ctx, part[:n]); uploadErr != nil {
+				return errors.Join(fmt.Errorf("cannot upload part of copy: %w", uploadErr), upload.Abort(ctx))
+			}
+
+			copied += int64(n)
+		}
+
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		} else if err != nil {
+			return errors.Join(fmt.Errorf("cannot read object for copy: %w", err), upload.Abort(ctx))
+		}
+	}
+
+	if copied != info.Size {
+		return errors.Join(fmt.Errorf("copied %d bytes of object, but object has %d bytes", copied, info.Size), upload.Abort(ctx))
+	}
+
+	return upload.Complete(ctx)
+}
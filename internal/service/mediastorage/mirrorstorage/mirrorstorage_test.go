@@ -0,0 +1,132 @@
+package mirrorstorage
+
+import (
+	"context"
+	"errors"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage/mediastoragetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"io"
+	"testing"
+	"time"
+)
+
+var errBackendDown = errors.New("backend is down")
+
+// failingStorage memory storage, that fails reads or completes of uploads if they are broken
+type failingStorage struct {
+	*mediastorage.MemoryStorage
+
+	brokenReads     bool
+	brokenCompletes bool
+}
+
+func (s *failingStorage) StartUpload(ctx context.Context, key string) (mediastorage.MultipartUpload, error) {
+	upload, err := s.MemoryStorage.StartUpload(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &failingUpload{MultipartUpload: upload, storage: s}, nil
+}
+
+func (s *failingStorage) StartDownload(ctx context.Context, key string) (io.ReadCloser, error) {
+	if s.brokenReads {
+		return nil, errBackendDown
+	}
+
+	return s.MemoryStorage.StartDownload(ctx, key)
+}
+
+type failingUpload struct {
+	mediastorage.MultipartUpload
+	storage *failingStorage
+}
+
+func (u *failingUpload) Complete(ctx context.Context) error {
+	if u.storage.brokenCompletes {
+		return errBackendDown
+	}
+
+	return u.MultipartUpload.Complete(ctx)
+}
+
+func TestMirrorStorage(t *testing.T) {
+	mediastoragetest.TestMediaStorage(t, NewStorage(mediastorage.NewMemoryStorage(), mediastorage.NewMemoryStorage(), zap.NewNop()))
+}
+
+func TestMirrorStorage_Mirroring(t *testing.T) {
+	ctx := context.Background()
+	primary := &failingStorage{MemoryStorage: mediastorage.NewMemoryStorage()}
+	secondary := &failingStorage{MemoryStorage: mediastorage.NewMemoryStorage()}
+	storage := NewStorage(primary, secondary, zap.NewNop())
+
+	mediastoragetest.UploadObject(t, storage, "both", []byte("mirrored content"), 4)
+	assert.Equal(t, []byte("mirrored content"), mediastoragetest.DownloadObject(t, primary, "both"))
+	assert.Equal(t, []byte("mirrored content"), mediastoragetest.DownloadObject(t, secondary, "both"))
+
+	t.Run("Read fallback", func(t *testing.T) {
+		primary.brokenReads = true
+		defer func() {
+			primary.brokenReads = false
+		}()
+
+		assert.Equal(t, []byte("mirrored content"), mediastoragetest.DownloadObject(t, storage, "both"))
+
+		_, err := storage.StartDownload(ctx, "missing")
+		assert.ErrorIs(t, err, errBackendDown, "failure of primary must not be reported as missing object")
+	})
+
+	t.Run("Failed secondary upload", func(t *testing.T) {
+		secondary.brokenCompletes = true
+		defer func() {
+			secondary.brokenCompletes = false
+		}()
+
+		upload, err := storage.StartUpload(ctx, "failed")
+		require.NoError(t, err)
+		require.NoError(t, upload.Upload(ctx, []byte("content")))
+		assert.ErrorIs(t, upload.Complete(ctx), errBackendDown)
+
+		_, err = primary.StartDownload(ctx, "failed")
+		assert.ErrorIs(t, err, mediastorage.ErrObjectNotFound, "object must be removed from primary if secondary can't store it")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, primary.Delete(ctx, "both"))
+		require.NoError(t, storage.Delete(ctx, "both"), "object of one backend must be deleted")
+
+		_, err := secondary.StartDownload(ctx, "both")
+		assert.ErrorIs(t, err, mediastorage.ErrObjectNotFound)
+		assert.ErrorIs(t, storage.Delete(ctx, "both"), mediastorage.ErrObjectNotFound)
+	})
+}
+
+func TestMirrorStorage_Repair(t *testing.T) {
+	ctx := context.Background()
+	primary := mediastorage.NewMemoryStorage()
+	secondary := mediastorage.NewMemoryStorage()
+	storage := NewStorage(primary, secondary, zap.NewNop())
+
+	mediastoragetest.UploadObject(t, storage, "both", []byte("mirrored content"), 4)
+	mediastoragetest.UploadObject(t, primary, "primary only", []byte("primary content"), 4)
+	mediastoragetest.UploadObject(t, secondary, "secondary only", []byte{}, 4)
+
+	report, err := storage.Repair(ctx, time.Hour)
+	require.NoError(t, err)
+	assert.Empty(t, report.CopiedToPrimary, "young objects must be skipped")
+	assert.Empty(t, report.CopiedToSecondary, "young objects must be skipped")
+
+	report, err = storage.Repair(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, RepairReport{CopiedToPrimary: []string{"secondary only"}, CopiedToSecondary: []string{"primary only"}}, report)
+
+	assert.Equal(t, []byte("primary content"), mediastoragetest.DownloadObject(t, secondary, "primary only"))
+	assert.Equal(t, []byte{}, mediastoragetest.DownloadObject(t, primary, "secondary only"))
+
+	report, err = storage.Repair(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, RepairReport{}, report, "repaired mirror must have nothing to copy")
+}
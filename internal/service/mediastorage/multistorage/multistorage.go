@@ -0,0 +1,101 @@
+package multistorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"io"
+)
+
+// MultiStorage combines media storages, new objects are written to the first storage and objects are read from
+// the first storage that has them. So media can be moved to other backend: new backend is placed first and old
+// objects stay readable from former backends
+type MultiStorage struct {
+	backends []mediastorage.MediaStorage
+}
+
+func NewStorage(backends []mediastorage.MediaStorage) (*MultiStorage, error) {
+	if len(backends) == 0 {
+		return nil, errors.New("multi storage must have at least one backend")
+	}
+
+	return &MultiStorage{backends: backends}, nil
+}
+
+func (s *MultiStorage) StartUpload(ctx context.Context, key string) (mediastorage.MultipartUpload, error) {
+	return s.backends[0].StartUpload(ctx, key)
+}
+
+func (s *MultiStorage) StartDownload(ctx context.Context, key string) (io.ReadCloser, error) {
+	for _, backend := range s.backends {
+		reader, err := backend.StartDownload(ctx, key)
+		if !errors.Is(err, mediastorage.ErrObjectNotFound) {
+			return reader, err
+		}
+	}
+
+	return nil, fmt.Errorf("object %s not found in any backend: %w", key, mediastorage.ErrObjectNotFound)
+}
+
+func (s *MultiStorage) StartDownloadRange(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	if err := mediastorage.ValidateRange(offset, length); err != nil {
+		return nil, err
+	}
+
+	for _, backend := range s.backends {
+		reader, err := backend.StartDownloadRange(ctx, key, offset, length)
+		if !errors.Is(err, mediastorage.ErrObjectNotFound) {
+			return reader, err
+		}
+	}
+
+	return nil, fmt.Errorf("object %s not found in any backend: %w", key, mediastorage.ErrObjectNotFound)
+}
+
+// Delete removes object from all backends, returns ErrObjectNotFound if no one backend has object
+func (s *MultiStorage) Delete(ctx context.Context, key string) error {
+	found := false
+	for i, backend := range s.backends {
+		err := backend.Delete(ctx, key)
+		if errors.Is(err, mediastorage.ErrObjectNotFound) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("cannot delete object from backend %d: %w", i, err)
+		}
+
+		found = true
+	}
+
+	if !found {
+		return fmt.Errorf("object %s not found in any backend: %w", key, mediastorage.ErrObjectNotFound)
+	}
+
+	return nil
+}
+
+// List lists objects of all backends, object stored in several backends is listed once by info of first backend
+func (s *MultiStorage) List(ctx context.Context, fn func(info mediastorage.ObjectInfo) error) error {
+	listed := make(map[string]struct{})
+	for i, backend := range s.backends {
+		var fnErr error
+		err := backend.List(ctx, func(info mediastorage.ObjectInfo) error {
+			if _, ok := listed[info.Key]; ok {
+				return nil
+			}
+			listed[info.Key] = struct{}{}
+
+			fnErr = fn(info)
+
+			return fnErr
+		})
+
+		if fnErr != nil {
+			return fnErr
+		} else if err != nil {
+			return fmt.Errorf("cannot list objects of backend %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package multistorage
+
+import (
+	"context"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage/mediastoragetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestMultiStorage(t *testing.T) {
+	storage, err := NewStorage([]mediastorage.MediaStorage{mediastorage.NewMemoryStorage(), mediastorage.NewMemoryStorage()})
+	require.NoError(t, err)
+
+	mediastoragetest.TestMediaStorage(t, storage)
+
+	_, err = NewStorage(nil)
+	assert.Error(t, err)
+}
+
+func TestMultiStorage_Fallback(t *testing.T) {
+	ctx := context.Background()
+	primary := mediastorage.NewMemoryStorage()
+	former := mediastorage.NewMemoryStorage()
+
+	mediastoragetest.UploadObject(t, former, "old", []byte("old content"), 4)
+	mediastoragetest.UploadObject(t, primary, "both", []byte("new content"), 4)
+	mediastoragetest.UploadObject(t, former, "both", []byte("former content"), 4)
+
+	storage, err := NewStorage([]mediastorage.MediaStorage{primary, former})
+	require.NoError(t, err)
+
+	mediastoragetest.UploadObject(t, storage, "new", []byte("content"), 4)
+	_, err = primary.StartDownload(ctx, "new")
+	assert.NoError(t, err, "new objects must be written to first backend")
+
+	assert.Equal(t, []byte("old content"), mediastoragetest.DownloadObject(t, storage, "old"))
+	assert.Equal(t, []byte("new content"), mediastoragetest.DownloadObject(t, storage, "both"))
+
+	reader, err := storage.StartDownloadRange(ctx, "old", 4, 3)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+
+	listed := make([]string, 0)
+	err = storage.List(ctx, func(info mediastorage.ObjectInfo) error {
+		listed = append(listed, info.Key)
+
+		return nil
+	})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"both", "new", "old"}, listed)
+
+	require.NoError(t, storage.Delete(ctx, "both"))
+	_, err = former.StartDownload(ctx, "both")
+	assert.ErrorIs(t, err, mediastorage.ErrObjectNotFound, "object must be deleted from all backends")
+}
@@ -0,0 +1,146 @@
+package s3storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/nessai1/gophkeeper/internal/service/config"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/pkg/bytesize"
+	"time"
+)
+
+const (
+	// presignPartSize size of parts of presigned upload, S3 requires 5MB or more for all parts except last
+	presignPartSize = 8 * int64(bytesize.MB)
+	// maxUploadParts max count of parts of S3 multipart upload
+	maxUploadParts = 10000
+
+	defaultPresignTTL = config.Duration(time.Hour)
+)
+
+// PresignTTL returns lifetime of presigned URLs, 0 if presigned URLs are disabled by config
+func (s *S3Storage) PresignTTL() time.Duration {
+	if !s.config.PresignedURLs {
+		return 0
+	}
+
+	return time.Duration(s.config.PresignTTL)
+}
+
+func (s *S3Storage) PresignUpload(ctx context.Context, key string, size int64, ttl time.Duration) (mediastorage.PresignedUpload, error) {
+	if size < 0 {
+		return nil, fmt.Errorf("invalid size of presigned upload: %d", size)
+	}
+
+	partSize := presignPartSize
+	if size > partSize*maxUploadParts {
+		partSize = (size + maxUploadParts - 1) / maxUploadParts
+	}
+
+	// empty content is uploaded by one empty part
+	parts := max(1, (size+partSize-1)/partSize)
+
+	bucketID := keeperBucketID
+	o, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{Bucket: &bucketID, Key: &key})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create S3 upload for presign: %w", err)
+	}
+
+	u := S3PresignedUpload{
+		key:      key,
+		bucketID: bucketID,
+		uploadID: aws.ToString(o.UploadId),
+		partSize: partSize,
+		urls:     make([]string, parts),
+		client:   s.client,
+	}
+
+	presigner := s3.NewPresignClient(s.client)
+	for i := range u.urls {
+		number := int32(i + 1)
+		req, err := presigner.PresignUploadPart(ctx, &s3.UploadPartInput{Bucket: &bucketID, Key: &key, UploadId: &u.uploadID, PartNumber: &number}, s3.WithPresignExpires(ttl))
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("cannot presign upload of part %d: %w", number, err), u.Abort(ctx))
+		}
+
+		u.urls[i] = req.URL
+	}
+
+	return &u, nil
+}
+
+func (s *S3Storage) PresignDownload(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	bucketID := keeperBucketID
+
+	// presigned URL of missing object is valid, so existence is checked before
+	_, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: &bucketID, Key: &key})
+	var notFound *types.NotFound
+	if errors.As(err, &notFound) {
+		return "", fmt.Errorf("cannot presign download of s3 media object: %w", mediastorage.ErrObjectNotFound)
+	} else if err != nil {
+		return "", fmt.Errorf("cannot check s3 media object: %w", err)
+	}
+
+	req, err := s3.NewPresignClient(s.client).PresignGetObject(ctx, &s3.GetObjectInput{Bucket: &bucketID, Key: &key}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("cannot presign download of s3 media object: %w", err)
+	}
+
+	return req.URL, nil
+}
+
+// S3PresignedUpload multipart upload, which parts are uploaded by client with presigned URLs
+type S3PresignedUpload struct {
+	key      string
+	bucketID string
+	uploadID string
+
+	partSize int64
+	urls     []string
+
+	client *s3.Client
+}
+
+func (u *S3PresignedUpload) PartURLs() []string {
+	return u.urls
+}
+
+func (u *S3PresignedUpload) PartSize() int64 {
+	return u.partSize
+}
+
+func (u *S3PresignedUpload) Complete(ctx context.Context, etags []string) (int64, error) {
+	if len(etags) != len(u.urls) {
+		return 0, fmt.Errorf("upload has %d parts, but %d ETags are given", len(u.urls), len(etags))
+	}
+
+	parts := make([]types.CompletedPart, len(etags))
+	for i := range etags {
+		parts[i] = types.CompletedPart{ETag: &etags[i], PartNumber: aws.Int32(int32(i + 1))}
+	}
+
+	_, err := u.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{Bucket: &u.bucketID, Key: &u.key, UploadId: &u.uploadID, MultipartUpload: &types.CompletedMultipartUpload{Parts: parts}})
+	if err != nil {
+		return 0, fmt.Errorf("cannot complete presigned s3 upload: %w", err)
+	}
+
+	head, err := u.client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: &u.bucketID, Key: &u.key})
+	if err != nil {
+		return 0, fmt.Errorf("cannot get size of uploaded s3 object: %w", err)
+	}
+
+	return aws.ToInt64(head.ContentLength), nil
+}
+
+func (u *S3PresignedUpload) Abort(ctx context.Context) error {
+	_, err := u.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{Bucket: &u.bucketID, Key: &u.key, UploadId: &u.uploadID})
+	if err != nil {
+		return fmt.Errorf("cannot abort presigned s3 upload: %w", err)
+	}
+
+	return nil
+}
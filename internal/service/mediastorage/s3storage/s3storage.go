@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	"github.com/aws/smithy-go/logging"
 	"github.com/nessai1/gophkeeper/internal/service/config"
 	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
@@ -30,6 +31,10 @@ type S3Storage struct {
 }
 
 func NewStorage(storageConfig config.S3Config, logger *zap.Logger) (*S3Storage, error) {
+	if storageConfig.PresignTTL <= 0 {
+		storageConfig.PresignTTL = defaultPresignTTL
+	}
+
 	storage := S3Storage{config: storageConfig}
 	customResolver := aws.EndpointResolverWithOptionsFunc(func(serviceID, region string, options ...interface{}) (aws.Endpoint, error) {
 		if serviceID == s3.ServiceID && region == storage.config.SigningRegion {
@@ -61,7 +66,9 @@ func NewStorage(storageConfig config.S3Config, logger *zap.Logger) (*S3Storage,
 	cfg.Logger = l
 	storage.logger = &l
 
-	storage.client = s3.NewFromConfig(*cfg)
+	storage.client = s3.NewFromConfig(*cfg, func(o *s3.Options) {
+		o.UsePathStyle = storage.config.PathStyle
+	})
 	if storage.getBucket(keeperBucketID) == nil {
 		return nil, fmt.Errorf("cannot find bucket for keeper service (%s) in S3 storage", keeperBucketID)
 	}
@@ -88,13 +95,42 @@ func (s *S3Storage) StartDownload(ctx context.Context, key string) (io.ReadClose
 	bucketID := keeperBucketID
 
 	object, err := s.client.GetObject(ctx, &s3.GetObjectInput{Bucket: &bucketID, Key: &key})
-	if err != nil {
+	var noSuchKey *types.NoSuchKey
+	if errors.As(err, &noSuchKey) {
+		return nil, fmt.Errorf("cannot get object from S3 storage: %w", mediastorage.ErrObjectNotFound)
+	} else if err != nil {
 		return nil, fmt.Errorf("cannot get object from S3 storage: %w", err)
 	}
 
 	return object.Body, nil
 }
 
+func (s *S3Storage) StartDownloadRange(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	if err := mediastorage.ValidateRange(offset, length); err != nil {
+		return nil, err
+	}
+
+	bucketID := keeperBucketID
+	contentRange := fmt.Sprintf("bytes=%d-", offset)
+	if length > 0 {
+		contentRange = fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+	}
+
+	object, err := s.client.GetObject(ctx, &s3.GetObjectInput{Bucket: &bucketID, Key: &key, Range: &contentRange})
+	var noSuchKey *types.NoSuchKey
+	var apiErr smithy.APIError
+	if errors.As(err, &noSuchKey) {
+		return nil, fmt.Errorf("cannot get object range from S3 storage: %w", mediastorage.ErrObjectNotFound)
+	} else if errors.As(err, &apiErr) && apiErr.ErrorCode() == "InvalidRange" {
+		// S3 rejects ranges that start after end of object
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot get object range from S3 storage: %w", err)
+	}
+
+	return object.Body, nil
+}
+
 func (s *S3Storage) StartUpload(ctx context.Context, key string) (mediastorage.MultipartUpload, error) {
 	u, err := newS3MultipartUpload(ctx, key, keeperBucketID, s.client)
 	if err != nil {
@@ -106,7 +142,17 @@ func (s *S3Storage) StartUpload(ctx context.Context, key string) (mediastorage.M
 
 func (s *S3Storage) Delete(ctx context.Context, key string) error {
 	bucketID := keeperBucketID
-	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: &bucketID, Key: &key})
+
+	// S3 deletes missing objects without error, so existence is checked before
+	_, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: &bucketID, Key: &key})
+	var notFound *types.NotFound
+	if errors.As(err, &notFound) {
+		return fmt.Errorf("cannot delete s3 media object: %w", mediastorage.ErrObjectNotFound)
+	} else if err != nil {
+		return fmt.Errorf("cannot check s3 media object: %w", err)
+	}
+
+	_, err = s.client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: &bucketID, Key: &key})
 
 	if err != nil {
 		return fmt.Errorf("cannot delete s3 media object: %w", err)
@@ -115,12 +161,33 @@ func (s *S3Storage) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+func (s *S3Storage) List(ctx context.Context, fn func(info mediastorage.ObjectInfo) error) error {
+	bucketID := keeperBucketID
+
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{Bucket: &bucketID})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("cannot list s3 media objects: %w", err)
+		}
+
+		for _, v := range page.Contents {
+			info := mediastorage.ObjectInfo{Key: aws.ToString(v.Key), Size: aws.ToInt64(v.Size), Modified: aws.ToTime(v.LastModified)}
+			if err = fn(info); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 type s3Logger struct {
 	logger *zap.Logger
 }
 
 func (s s3Logger) Logf(classification logging.Classification, format string, v ...interface{}) {
-	logMsg := fmt.Sprintf(format, v)
+	logMsg := fmt.Sprintf(format, v...)
 	logMsg = fmt.Sprintf("[S3 LOG] %s", logMsg)
 
 	s.logger.Info(logMsg, zap.String("classification", string(classification)))
@@ -158,11 +225,8 @@ func newS3MultipartUpload(ctx context.Context, key string, bucketID string, clie
 }
 
 func (u *S3MultipartUpload) Upload(ctx context.Context, content []byte) error {
-	if u.activeContent != nil {
-		u.activeContent = append(u.activeContent, content...)
-	} else {
-		u.activeContent = content
-	}
+	// content is copied, because caller can reuse it for next parts
+	u.activeContent = append(u.activeContent, content...)
 
 	// Yandex cloud docs: content part must be 5MB or more
 	if len(u.activeContent) > int(bytesize.MB)*5 {
@@ -174,7 +238,8 @@ func (u *S3MultipartUpload) Upload(ctx context.Context, content []byte) error {
 
 func (u *S3MultipartUpload) sendActiveContent(ctx context.Context) error {
 	l := int64(len(u.activeContent))
-	b := bytes.NewBuffer(u.activeContent)
+	// seekable body lets client sign payload without TLS
+	b := bytes.NewReader(u.activeContent)
 
 	resp, err := u.client.UploadPart(ctx, &s3.UploadPartInput{Bucket: &u.bucketID, Key: &u.key, UploadId: &u.uploadID, PartNumber: &u.partsCount, ContentLength: &l, Body: b})
 	if err != nil {
@@ -194,7 +259,8 @@ func (u *S3MultipartUpload) sendActiveContent(ctx context.Context) error {
 }
 
 func (u *S3MultipartUpload) Complete(ctx context.Context) error {
-	if u.activeContent != nil {
+	// S3 can't complete upload without parts, so empty content is sent by one empty part
+	if u.activeContent != nil || len(u.parts) == 0 {
 		err := u.sendActiveContent(ctx)
 		if err != nil {
 			return fmt.Errorf("cannot send active content while complete S3 upload: %w", err)
@@ -0,0 +1,120 @@
+package s3storage
+
+import (
+	"bytes"
+	"context"
+	"github.com/nessai1/gophkeeper/internal/service/config"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage/mediastoragetest"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage/s3storage/s3test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestS3Storage runs on S3 from KEEPER_TEST_S3_* environment, skips if KEEPER_TEST_S3_URL is not set
+func TestS3Storage(t *testing.T) {
+	url := os.Getenv("KEEPER_TEST_S3_URL")
+	if url == "" {
+		t.Skip("KEEPER_TEST_S3_URL is not set")
+	}
+
+	storage, err := NewStorage(config.S3Config{
+		URL:           url,
+		PartitionID:   os.Getenv("KEEPER_TEST_S3_PARTITION_ID"),
+		SigningRegion: os.Getenv("KEEPER_TEST_S3_SIGNING_REGION"),
+		Credentials: &config.S3Credentials{
+			AccessKeyID:     os.Getenv("KEEPER_TEST_S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("KEEPER_TEST_S3_SECRET_ACCESS_KEY"),
+		},
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	mediastoragetest.TestMediaStorage(t, storage)
+}
+
+// newLocalStorage returns storage on S3 stand-in
+func newLocalStorage(t *testing.T) (*S3Storage, *s3test.Server) {
+	server := s3test.NewServer(keeperBucketID)
+	t.Cleanup(server.Close)
+
+	storage, err := NewStorage(config.S3Config{
+		URL:           server.URL,
+		PartitionID:   "test",
+		SigningRegion: "test-region",
+		PathStyle:     true,
+		Credentials:   &config.S3Credentials{AccessKeyID: "access", SecretAccessKey: "secret"},
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	return storage, server
+}
+
+func TestS3Storage_Local(t *testing.T) {
+	storage, server := newLocalStorage(t)
+
+	mediastoragetest.TestMediaStorage(t, storage)
+	assert.Zero(t, server.Uploads(), "all uploads must be completed or aborted")
+
+	t.Run("Large object", func(t *testing.T) {
+		content := bytes.Repeat([]byte("0123456789abcdef"), 800*1024)
+		mediastoragetest.UploadObject(t, storage, "large", content, 1024*1024)
+		assert.Equal(t, content, mediastoragetest.DownloadObject(t, storage, "large"))
+	})
+}
+
+func TestS3Storage_Presign(t *testing.T) {
+	ctx := context.Background()
+	storage, server := newLocalStorage(t)
+
+	content := bytes.Repeat([]byte("presigned content "), 1024*1024)
+	upload, err := storage.PresignUpload(ctx, "presigned", int64(len(content)), time.Minute)
+	require.NoError(t, err)
+	require.Len(t, upload.PartURLs(), 3)
+	assert.Equal(t, presignPartSize, upload.PartSize())
+
+	etags := make([]string, 0)
+	for i, url := range upload.PartURLs() {
+		part := content[int64(i)*upload.PartSize() : min(int64(i+1)*upload.PartSize(), int64(len(content)))]
+		req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(part))
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		etags = append(etags, resp.Header.Get("ETag"))
+	}
+
+	_, err = upload.Complete(ctx, etags[:1])
+	assert.Error(t, err, "upload must not be completed without all parts")
+
+	size, err := upload.Complete(ctx, etags)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), size)
+	assert.Zero(t, server.Uploads())
+
+	url, err := storage.PresignDownload(ctx, "presigned", time.Minute)
+	require.NoError(t, err)
+
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	downloaded, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, content, downloaded)
+
+	_, err = storage.PresignDownload(ctx, "missing", time.Minute)
+	assert.ErrorIs(t, err, mediastorage.ErrObjectNotFound)
+
+	aborted, err := storage.PresignUpload(ctx, "aborted", 0, time.Minute)
+	require.NoError(t, err)
+	assert.Len(t, aborted.PartURLs(), 1)
+	require.NoError(t, aborted.Abort(ctx))
+	assert.Zero(t, server.Uploads())
+}
@@ -0,0 +1,411 @@
+// Package s3test contains in-memory S3-compatible server, that stands in for S3 in tests of S3 storage and
+// direct media transfers. Server supports path-style requests of operations used by keeper: list of buckets and objects,
+// multipart uploads, ranged downloads and deletes. Signatures of requests are not checked, so presigned URLs work too
+package s3test
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MinPartSize min size of not last part of multipart upload, like in S3
+const MinPartSize = 5 * 1024 * 1024
+
+// listMaxKeys default max count of objects of one list page
+const listMaxKeys = 1000
+
+const timeFormat = "2006-01-02T15:04:05.000Z"
+
+type object struct {
+	data     []byte
+	etag     string
+	modified time.Time
+}
+
+type part struct {
+	data []byte
+	etag string
+}
+
+type multipartUpload struct {
+	bucket string
+	key    string
+	parts  map[int]part
+}
+
+// Server S3-compatible server that keeps buckets in memory
+type Server struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	buckets map[string]map[string]object
+	uploads map[string]*multipartUpload
+}
+
+// NewServer starts server with empty buckets, server must be closed by Close
+func NewServer(buckets ...string) *Server {
+	s := &Server{
+		buckets: make(map[string]map[string]object),
+		uploads: make(map[string]*multipartUpload),
+	}
+
+	for _, v := range buckets {
+		s.buckets[v] = make(map[string]object)
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+
+	return s
+}
+
+// Object returns content of object, false if object doesn't exist
+func (s *Server) Object(bucket string, key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj, ok := s.buckets[bucket][key]
+
+	return obj.data, ok
+}
+
+// Uploads returns count of not completed multipart uploads
+func (s *Server) Uploads() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.uploads)
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	if path == "" {
+		s.listBuckets(w)
+
+		return
+	}
+
+	bucket, key, _ := strings.Cut(path, "/")
+	objects, ok := s.buckets[bucket]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchBucket", "bucket does not exist")
+
+		return
+	}
+
+	query := r.URL.Query()
+	if key == "" {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "only list of bucket is supported")
+
+			return
+		}
+
+		listObjects(w, objects, query)
+
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		s.createUpload(w, bucket, key)
+	case r.Method == http.MethodPut && query.Has("uploadId"):
+		s.uploadPart(w, r, query)
+	case r.Method == http.MethodPost && query.Has("uploadId"):
+		s.completeUpload(w, r, objects, bucket, key, query.Get("uploadId"))
+	case r.Method == http.MethodDelete && query.Has("uploadId"):
+		s.abortUpload(w, query.Get("uploadId"))
+	case r.Method == http.MethodPut:
+		putObject(w, r, objects, key)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		getObject(w, r, objects, key)
+	case r.Method == http.MethodDelete:
+		delete(objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "method is not supported")
+	}
+}
+
+func (s *Server) listBuckets(w http.ResponseWriter) {
+	type bucket struct {
+		Name         string `xml:"Name"`
+		CreationDate string `xml:"CreationDate"`
+	}
+
+	result := struct {
+		XMLName xml.Name `xml:"ListAllMyBucketsResult"`
+		Buckets []bucket `xml:"Buckets>Bucket"`
+	}{}
+
+	for name := range s.buckets {
+		result.Buckets = append(result.Buckets, bucket{Name: name, CreationDate: time.Now().UTC().Format(timeFormat)})
+	}
+
+	writeXML(w, http.StatusOK, result)
+}
+
+func listObjects(w http.ResponseWriter, objects map[string]object, query url.Values) {
+	type content struct {
+		Key          string `xml:"Key"`
+		LastModified string `xml:"LastModified"`
+		ETag         string `xml:"ETag"`
+		Size         int64  `xml:"Size"`
+	}
+
+	maxKeys := listMaxKeys
+	if v, err := strconv.Atoi(query.Get("max-keys")); err == nil && v > 0 {
+		maxKeys = v
+	}
+
+	keys := make([]string, 0, len(objects))
+	for k := range objects {
+		if strings.HasPrefix(k, query.Get("prefix")) && k > query.Get("continuation-token") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	result := struct {
+		XMLName               xml.Name  `xml:"ListBucketResult"`
+		KeyCount              int       `xml:"KeyCount"`
+		MaxKeys               int       `xml:"MaxKeys"`
+		IsTruncated           bool      `xml:"IsTruncated"`
+		NextContinuationToken string    `xml:"NextContinuationToken,omitempty"`
+		Contents              []content `xml:"Contents"`
+	}{MaxKeys: maxKeys}
+
+	if len(keys) > maxKeys {
+		keys = keys[:maxKeys]
+		result.IsTruncated = true
+		result.NextContinuationToken = keys[maxKeys-1]
+	}
+
+	for _, k := range keys {
+		obj := objects[k]
+		result.Contents = append(result.Contents, content{Key: k, LastModified: obj.modified.UTC().Format(timeFormat), ETag: obj.etag, Size: int64(len(obj.data))})
+	}
+	result.KeyCount = len(result.Contents)
+
+	writeXML(w, http.StatusOK, result)
+}
+
+func (s *Server) createUpload(w http.ResponseWriter, bucket string, key string) {
+	id := uuid.New().String()
+	s.uploads[id] = &multipartUpload{bucket: bucket, key: key, parts: make(map[int]part)}
+
+	writeXML(w, http.StatusOK, struct {
+		XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+		Bucket   string   `xml:"Bucket"`
+		Key      string   `xml:"Key"`
+		UploadID string   `xml:"UploadId"`
+	}{Bucket: bucket, Key: key, UploadID: id})
+}
+
+func (s *Server) uploadPart(w http.ResponseWriter, r *http.Request, query url.Values) {
+	upload, ok := s.uploads[query.Get("uploadId")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", "upload does not exist")
+
+		return
+	}
+
+	number, err := strconv.Atoi(query.Get("partNumber"))
+	if err != nil || number < 1 {
+		writeError(w, http.StatusBadRequest, "InvalidArgument", "invalid part number")
+
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+
+		return
+	}
+
+	p := part{data: data, etag: etag(data)}
+	upload.parts[number] = p
+
+	w.Header().Set("ETag", p.etag)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) completeUpload(w http.ResponseWriter, r *http.Request, objects map[string]object, bucket string, key string, id string) {
+	upload, ok := s.uploads[id]
+	if !ok || upload.bucket != bucket || upload.key != key {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", "upload does not exist")
+
+		return
+	}
+
+	request := struct {
+		Parts []struct {
+			PartNumber int    `xml:"PartNumber"`
+			ETag       string `xml:"ETag"`
+		} `xml:"Part"`
+	}{}
+
+	if err := xml.NewDecoder(r.Body).Decode(&request); err != nil || len(request.Parts) == 0 {
+		writeError(w, http.StatusBadRequest, "MalformedXML", "list of parts is invalid or empty")
+
+		return
+	}
+
+	content := make([]byte, 0)
+	for i, v := range request.Parts {
+		p, ok := upload.parts[v.PartNumber]
+		if !ok || p.etag != v.ETag || (i > 0 && v.PartNumber <= request.Parts[i-1].PartNumber) {
+			writeError(w, http.StatusBadRequest, "InvalidPart", fmt.Sprintf("part %d is not uploaded or has other ETag", v.PartNumber))
+
+			return
+		}
+
+		if i < len(request.Parts)-1 && len(p.data) < MinPartSize {
+			writeError(w, http.StatusBadRequest, "EntityTooSmall", fmt.Sprintf("part %d is smaller than min part size", v.PartNumber))
+
+			return
+		}
+
+		content = append(content, p.data...)
+	}
+
+	delete(s.uploads, id)
+	obj := object{data: content, etag: etag(content), modified: time.Now()}
+	objects[key] = obj
+
+	writeXML(w, http.StatusOK, struct {
+		XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+		Bucket  string   `xml:"Bucket"`
+		Key     string   `xml:"Key"`
+		ETag    string   `xml:"ETag"`
+	}{Bucket: bucket, Key: key, ETag: obj.etag})
+}
+
+func (s *Server) abortUpload(w http.ResponseWriter, id string) {
+	if _, ok := s.uploads[id]; !ok {
+		writeError(w, http.StatusNotFound, "NoSuchUpload", "upload does not exist")
+
+		return
+	}
+
+	delete(s.uploads, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func putObject(w http.ResponseWriter, r *http.Request, objects map[string]object, key string) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+
+		return
+	}
+
+	obj := object{data: data, etag: etag(data), modified: time.Now()}
+	objects[key] = obj
+
+	w.Header().Set("ETag", obj.etag)
+	w.WriteHeader(http.StatusOK)
+}
+
+func getObject(w http.ResponseWriter, r *http.Request, objects map[string]object, key string) {
+	obj, ok := objects[key]
+	if !ok {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		writeError(w, http.StatusNotFound, "NoSuchKey", "object does not exist")
+
+		return
+	}
+
+	w.Header().Set("ETag", obj.etag)
+	w.Header().Set("Last-Modified", obj.modified.UTC().Format(http.TimeFormat))
+
+	start, end, ranged, err := parseRange(r.Header.Get("Range"), int64(len(obj.data)))
+	if err != nil {
+		writeError(w, http.StatusRequestedRangeNotSatisfiable, "InvalidRange", err.Error())
+
+		return
+	}
+
+	w.Header().Set("Content-Length", strconv.FormatInt(end-start, 10))
+	if ranged {
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end-1, len(obj.data)))
+		w.WriteHeader(http.StatusPartialContent)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(obj.data[start:end])
+	}
+}
+
+// parseRange parses range header like "bytes=10-" or "bytes=10-19", returns bounds of content [start, end)
+func parseRange(header string, size int64) (start int64, end int64, ranged bool, err error) {
+	if header == "" {
+		return 0, size, false, nil
+	}
+
+	from, to, ok := strings.Cut(strings.TrimPrefix(header, "bytes="), "-")
+	if !ok {
+		return 0, 0, false, fmt.Errorf("invalid range '%s'", header)
+	}
+
+	start, err = strconv.ParseInt(from, 10, 64)
+	if err != nil || start >= size {
+		return 0, 0, false, fmt.Errorf("range '%s' is not satisfiable", header)
+	}
+
+	end = size
+	if to != "" {
+		last, err := strconv.ParseInt(to, 10, 64)
+		if err != nil || last < start {
+			return 0, 0, false, fmt.Errorf("invalid range '%s'", header)
+		}
+
+		end = min(last+1, size)
+	}
+
+	return start, end, true, nil
+}
+
+func etag(data []byte) string {
+	sum := md5.Sum(data)
+
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func writeError(w http.ResponseWriter, code int, errCode string, message string) {
+	writeXML(w, code, struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string   `xml:"Code"`
+		Message string   `xml:"Message"`
+	}{Code: errCode, Message: message})
+}
+
+func writeXML(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(code)
+
+	_, _ = w.Write([]byte(xml.Header))
+	_ = xml.NewEncoder(w).Encode(v)
+}
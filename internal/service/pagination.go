@@ -0,0 +1,118 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	pb "github.com/nessai1/gophkeeper/api/proto"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"time"
+)
+
+const (
+	defaultSecretPageSize = 100
+	maxSecretPageSize     = 1000
+)
+
+// pageToken opaque for client position of last secret of page, encoded in next_page_token
+type pageToken struct {
+	Sort       pb.SecretSortField `json:"s"`
+	Descending bool               `json:"d"`
+	Created    int64              `json:"c"`
+	Updated    int64              `json:"u"`
+	UUID       string             `json:"id"`
+}
+
+// buildSecretPage translates list request to page of storage, next_page_token must be made for same sort
+func buildSecretPage(request *pb.SecretListRequest) (plainstorage.SecretPage, error) {
+	page := plainstorage.SecretPage{
+		Descending: request.GetDescending(),
+		Limit:      defaultSecretPageSize,
+	}
+
+	switch request.GetSort() {
+	case pb.SecretSortField_CREATED:
+		page.Sort = plainstorage.SecretSortCreated
+	case pb.SecretSortField_UPDATED:
+		page.Sort = plainstorage.SecretSortUpdated
+	default:
+		return page, fmt.Errorf("undefined sort field %d", request.GetSort())
+	}
+
+	if request.GetPageSize() < 0 || request.GetPageSize() > maxSecretPageSize {
+		return page, fmt.Errorf("page size must be between 0 and %d", maxSecretPageSize)
+	} else if request.GetPageSize() > 0 {
+		page.Limit = int(request.GetPageSize())
+	}
+
+	if request.GetPageToken() == "" {
+		return page, nil
+	}
+
+	rawToken, err := base64.RawURLEncoding.DecodeString(request.GetPageToken())
+	if err != nil {
+		return page, fmt.Errorf("cannot decode page token: %w", err)
+	}
+
+	var token pageToken
+	err = json.Unmarshal(rawToken, &token)
+	if err != nil {
+		return page, fmt.Errorf("cannot unmarshal page token: %w", err)
+	}
+
+	if token.Sort != request.GetSort() || token.Descending != request.GetDescending() {
+		return page, fmt.Errorf("page token was made for another sort")
+	}
+
+	page.After = &plainstorage.SecretCursor{
+		Created: time.Unix(0, token.Created),
+		Updated: time.Unix(0, token.Updated),
+		UUID:    token.UUID,
+	}
+
+	return page, nil
+}
+
+// buildPageToken returns token of page that starts after secret
+func buildPageToken(request *pb.SecretListRequest, md plainstorage.SecretMetadata) (string, error) {
+	rawToken, err := json.Marshal(pageToken{
+		Sort:       request.GetSort(),
+		Descending: request.GetDescending(),
+		Created:    md.Created.UnixNano(),
+		Updated:    md.Updated.UnixNano(),
+		UUID:       md.UUID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot marshal page token: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(rawToken), nil
+}
+
+// buildSecretFilter translates list request to filter of storage
+func buildSecretFilter(request *pb.SecretListRequest, secretType plainstorage.SecretType, moment time.Time) plainstorage.SecretFilter {
+	filter := plainstorage.SecretFilter{
+		Type:     secretType,
+		AnyType:  request.GetAnyType(),
+		Folder:   request.GetFolder(),
+		Tag:      request.GetTag(),
+		ActiveAt: moment,
+	}
+
+	bounds := []struct {
+		timestamp int64
+		bound     *time.Time
+	}{
+		{request.GetCreatedAfter(), &filter.CreatedAfter},
+		{request.GetCreatedBefore(), &filter.CreatedBefore},
+		{request.GetUpdatedAfter(), &filter.UpdatedAfter},
+		{request.GetUpdatedBefore(), &filter.UpdatedBefore},
+	}
+	for _, v := range bounds {
+		if v.timestamp != 0 {
+			*v.bound = time.Unix(v.timestamp, 0)
+		}
+	}
+
+	return filter
+}
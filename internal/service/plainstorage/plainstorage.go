@@ -3,6 +3,8 @@ package plainstorage
 import (
 	"context"
 	"errors"
+	"github.com/google/uuid"
+	"slices"
 	"time"
 )
 
@@ -16,22 +18,102 @@ const (
 type SecretType uint8
 
 type PlainStorage interface {
-	InTransaction(ctx context.Context, transaction func() error) error
+	// InTransaction runs transaction callback with storage of transaction: changes made through tx are committed
+	// if callback returns nil and rolled back otherwise. Nested calls on tx make savepoints
+	InTransaction(ctx context.Context, transaction func(tx PlainStorage) error) error
 
 	GetUserByLogin(ctx context.Context, login string) (*User, error)
 	GetUserByUUID(ctx context.Context, uuid string) (*User, error)
 	CreateUser(ctx context.Context, login string, password string) (*User, error)
-	GetUserSecretsMetadataByType(ctx context.Context, userUUID string, secretType SecretType) ([]SecretMetadata, error)
+	// GetUserSecretsMetadata returns page of metadata with tags of user secrets matched by filter
+	GetUserSecretsMetadata(ctx context.Context, userUUID string, filter SecretFilter, page SecretPage) ([]SecretMetadata, error)
 
-	AddSecretMetadata(ctx context.Context, userUUID string, secretUUID, name string, dataType SecretType) (*SecretMetadata, error)
-	AddPlainSecret(ctx context.Context, userUUID string, name string, dataType SecretType, data []byte) (*PlainSecret, error)
+	AddSecretMetadata(ctx context.Context, userUUID string, secretUUID, name string, encryptedName []byte, dataType SecretType) (*SecretMetadata, error)
+	AddPlainSecret(ctx context.Context, userUUID string, name string, encryptedName []byte, dataType SecretType, data []byte) (*PlainSecret, error)
+	// AddPlainSecrets creates all plain secrets of user or none of them, returns ErrEntityAlreadyExists if any name is taken
+	AddPlainSecrets(ctx context.Context, userUUID string, secrets []NewPlainSecret) ([]PlainSecret, error)
 
 	UpdateSecretMetadataUUID(ctx context.Context, userUUID string, oldUUID string, newUUID string, dataType SecretType) error
+	// RenameSecret replaces name and type of secret, returns ErrEntityAlreadyExists if user has other secret with same name and type
+	RenameSecret(ctx context.Context, secretUUID string, name string, encryptedName []byte, dataType SecretType) error
+	// SetSecretLimits sets expiration time and max count of reads of secret, nil expiresAt and zero maxReads mean no limits.
+	// Reads of secret are counted again
+	SetSecretLimits(ctx context.Context, secretUUID string, expiresAt *time.Time, maxReads int) error
+	// RegisterSecretRead atomically increments reads counter of not expired secret and returns updated metadata.
+	// Returns ErrEntityNotFound if secret not exists, expired or has no reads left
+	RegisterSecretRead(ctx context.Context, secretUUID string) (*SecretMetadata, error)
+	// GetExpiredSecretsMetadata returns metadata of secrets of all users, that expired at moment or have no reads left
+	GetExpiredSecretsMetadata(ctx context.Context, moment time.Time) ([]SecretMetadata, error)
+	// SetSecretFolder moves secret to folder
+	SetSecretFolder(ctx context.Context, secretUUID string, folder string) error
+	// SetMediaInfo sets info of stored media content
+	SetMediaInfo(ctx context.Context, secretUUID string, info MediaInfo) error
+	// SetSecretTags replaces all tags of secret
+	SetSecretTags(ctx context.Context, secretUUID string, tags []SecretTag) error
 
 	UpdatePlainSecretDataByName(ctx context.Context, ownerUUID string, name string, dataType SecretType, data []byte) error
 	RemoveSecretByUUID(ctx context.Context, secretUUID string) error
+	// RemoveSecretsByUUID removes secrets with their plain content
+	RemoveSecretsByUUID(ctx context.Context, secretUUIDs []string) error
 
 	GetUserSecretByName(ctx context.Context, userUUID string, secretName string, secretType SecretType) (*PlainSecret, error)
+
+	// GetExistingMediaChunks returns hashes of chunks that user has stored, hashes are returned in order of request
+	GetExistingMediaChunks(ctx context.Context, userUUID string, hashes []string) ([]string, error)
+	// AddMediaChunk registers stored chunk of user without references, does nothing if user already has chunk
+	AddMediaChunk(ctx context.Context, userUUID string, hash string, size int64) error
+	// SetMediaChunks saves ordered chunks of media and increments references of chunks.
+	// Returns ErrEntityNotFound if user has no some chunk, ErrEntityAlreadyExists if media already has chunks
+	SetMediaChunks(ctx context.Context, userUUID string, mediaUUID string, hashes []string) error
+	// GetMediaChunks returns ordered chunks of media, empty for media stored as single object
+	GetMediaChunks(ctx context.Context, mediaUUID string) ([]MediaChunk, error)
+	// RemoveMediaChunks removes chunks list of media and decrements references of its chunks.
+	// Chunks left without references are removed, their hashes are returned
+	RemoveMediaChunks(ctx context.Context, mediaUUID string) ([]string, error)
+	// GetAllMediaChunks returns chunks of all users, including chunks without references
+	GetAllMediaChunks(ctx context.Context) ([]MediaChunk, error)
+	// RemoveUnusedMediaChunk removes chunk of user that has no references.
+	// Returns ErrEntityNotFound if user has no chunk or chunk is used by some media
+	RemoveUnusedMediaChunk(ctx context.Context, userUUID string, hash string) error
+	// GetObjectMediaMetadata returns metadata of media of all users that is stored as single object, chunked media is not included
+	GetObjectMediaMetadata(ctx context.Context) ([]SecretMetadata, error)
+
+	// GetUserUsage returns storage consumption of user, secrets that are expired but not removed yet are counted too
+	GetUserUsage(ctx context.Context, userUUID string) (Usage, error)
+
+	// GetPlainSecretsData returns data of plain secrets of all users with UUID greater than afterUUID,
+	// at most limit secrets ordered by UUID
+	GetPlainSecretsData(ctx context.Context, afterUUID string, limit int) ([]SecretData, error)
+	// ReplacePlainSecretData replaces data of secret if it's still equal to old, returns ErrEntityNotFound
+	// if secret is removed or its data is changed
+	ReplacePlainSecretData(ctx context.Context, secretUUID string, old []byte, data []byte) error
+
+	// GetMediaObjectKey returns data key of media object, returns ErrEntityNotFound if object has no key
+	GetMediaObjectKey(ctx context.Context, objectKey string) (*MediaObjectKey, error)
+	// SetMediaObjectKey saves data key of media object, key of object is replaced if it exists
+	SetMediaObjectKey(ctx context.Context, key MediaObjectKey) error
+	// RemoveMediaObjectKey removes data key of media object, returns ErrEntityNotFound if object has no key
+	RemoveMediaObjectKey(ctx context.Context, objectKey string) error
+	// GetMediaObjectKeys returns data keys of objects with object key greater than afterKey,
+	// at most limit keys ordered by object key
+	GetMediaObjectKeys(ctx context.Context, afterKey string, limit int) ([]MediaObjectKey, error)
+	// ReplaceMediaObjectKey replaces data key of object if its wrapped key is still equal to old,
+	// returns ErrEntityNotFound if key is removed or changed
+	ReplaceMediaObjectKey(ctx context.Context, old MediaObjectKey, key MediaObjectKey) error
+
+	// InSnapshot runs read callback with storage of consistent snapshot: reads through snapshot don't see changes
+	// committed after start of snapshot. Snapshot is read-only
+	InSnapshot(ctx context.Context, read func(snapshot PlainStorage) error) error
+	// GetUsers returns users with UUID greater than afterUUID, at most limit users ordered by UUID
+	GetUsers(ctx context.Context, afterUUID string, limit int) ([]User, error)
+	// GetSecrets returns secrets of all users with UUID greater than afterUUID, at most limit secrets ordered by UUID.
+	// Secrets have tags and data, data of media is nil
+	GetSecrets(ctx context.Context, afterUUID string, limit int) ([]PlainSecret, error)
+	// RestoreUser creates user with UUID and password hash of user, returns ErrEntityAlreadyExists if UUID or login is taken
+	RestoreUser(ctx context.Context, user User) error
+	// RestoreSecret creates secret with all fields of metadata, tags and data, secret without data is created
+	// without plain content. Returns ErrEntityAlreadyExists if UUID or name of secret is taken
+	RestoreSecret(ctx context.Context, secret PlainSecret) error
 }
 
 var ErrEntityNotFound = errors.New("entity not found")
@@ -52,11 +134,232 @@ type SecretMetadata struct {
 	UUID     string `db:"uuid"`
 	UserUUID string `db:"owner_uuid"`
 
-	// Name for plains - title, for media - filename
+	// Name blind index of secret name (title for plains, filename for media), computed by client.
+	// Secrets created before names encryption contain plain name there
 	Name string `db:"name"`
 
+	// EncryptedName secret name encrypted by client, nil for secrets with plain name
+	EncryptedName []byte `db:"encrypted_name"`
+
 	Type SecretType `db:"type"`
 
 	Created time.Time `db:"created"`
 	Updated time.Time `db:"updated"`
+
+	// ExpiresAt time after that secret is unavailable, nil for endless secrets
+	ExpiresAt *time.Time `db:"expires_at"`
+	// MaxReads count of reads after that secret is unavailable, 0 for unlimited reads
+	MaxReads int `db:"max_reads"`
+	// Reads count of secret reads, increments only for secrets with limits
+	Reads int `db:"reads"`
+
+	// Folder blind index of folder that contains secret, empty for secrets that saved before folders
+	Folder string `db:"folder"`
+
+	// ContentHash SHA-256 of stored media content, nil for plain secrets, chunked media, media uploaded before integrity checks
+	// and presigned uploads, because service doesn't receive their content
+	ContentHash []byte `db:"content_hash"`
+	// MediaSize count of bytes of stored media content, 0 for plain secrets and media uploaded before media info
+	MediaSize int64 `db:"media_size"`
+	// EncryptedMediaInfo media info (plain size, content type, checksum etc.) encrypted by client, nil if client sends no info
+	EncryptedMediaInfo []byte `db:"encrypted_media_info"`
+
+	Tags []SecretTag `db:"-"`
+}
+
+// MediaInfo info of stored media content
+type MediaInfo struct {
+	Size int64
+	// ContentHash SHA-256 of stored content, nil if service hasn't computed it
+	ContentHash []byte
+	// EncryptedInfo media info encrypted by client
+	EncryptedInfo []byte
+}
+
+// NewPlainSecret plain secret for create with limits and folder
+type NewPlainSecret struct {
+	// UUID of created secret, storage generates it if empty
+	UUID          string
+	Name          string
+	EncryptedName []byte
+	Type          SecretType
+	Data          []byte
+	Folder        string
+	ExpiresAt     *time.Time
+	MaxReads      int
+}
+
+// secretUUID returns given UUID of secret or new UUID
+func (s NewPlainSecret) secretUUID() string {
+	if s.UUID != "" {
+		return s.UUID
+	}
+
+	return uuid.New().String()
+}
+
+// Usage storage consumption of user
+type Usage struct {
+	MediaCount int `db:"media_count"`
+	PlainCount int `db:"plain_count"`
+	// MediaBytes size of stored media content: objects of not chunked media and all chunks of user,
+	// including chunks that are not committed to media yet
+	MediaBytes int64 `db:"media_bytes"`
+	// PlainBytes size of plain secrets data
+	PlainBytes int64 `db:"plain_bytes"`
+}
+
+// MediaChunk encrypted part of media content. Chunk is stored once for all media of user with same part
+type MediaChunk struct {
+	OwnerUUID string `db:"owner_uuid"`
+	// Hash hex encoded sha256 of encrypted chunk
+	Hash string `db:"hash"`
+	Size int64  `db:"size"`
+	// Refs count of media parts that are stored by chunk
+	Refs int `db:"refs"`
+	// Created time of chunk upload, chunk without references is not committed to media yet or left by failed upload
+	Created time.Time `db:"created"`
+}
+
+// SecretData stored data of plain secret
+type SecretData struct {
+	UUID string `db:"uuid"`
+	Data []byte `db:"data"`
+}
+
+// MediaObjectKey data key of media object encrypted at rest, wrapped by key-encryption key of service
+type MediaObjectKey struct {
+	ObjectKey string `db:"object_key"`
+	// KEKID identifier of key-encryption key that wraps data key
+	KEKID      string `db:"kek_id"`
+	WrappedKey []byte `db:"wrapped_key"`
+}
+
+// SecretTag free-form mark of secret
+type SecretTag struct {
+	// Tag blind index of tag, computed by client
+	Tag          string `db:"tag"`
+	EncryptedTag []byte `db:"encrypted_tag"`
+}
+
+// SecretFilter conditions of user secrets list
+type SecretFilter struct {
+	Type SecretType
+	// AnyType lists secrets of all types, Type is ignored
+	AnyType bool
+	// Folder blind index of folder for list only its secrets, empty for secrets of all folders
+	Folder string
+	// Tag blind index of tag for list only marked secrets, empty for secrets with any tags
+	Tag string
+	// Names lists only secrets with one of names, nil for any names
+	Names []string
+
+	// CreatedAfter, CreatedBefore, UpdatedAfter, UpdatedBefore bounds (exclusive) of secret dates, zero for unbounded
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+
+	// ActiveAt excludes secrets expired at this moment, zero for list expired secrets too
+	ActiveAt time.Time
+}
+
+// Match checks that secret metadata satisfies filter
+func (f SecretFilter) Match(md SecretMetadata) bool {
+	if !f.AnyType && md.Type != f.Type {
+		return false
+	}
+
+	if f.Folder != "" && md.Folder != f.Folder {
+		return false
+	}
+
+	if f.Names != nil && !slices.Contains(f.Names, md.Name) {
+		return false
+	}
+
+	if !f.CreatedAfter.IsZero() && !md.Created.After(f.CreatedAfter) || !f.CreatedBefore.IsZero() && !md.Created.Before(f.CreatedBefore) {
+		return false
+	}
+
+	if !f.UpdatedAfter.IsZero() && !md.Updated.After(f.UpdatedAfter) || !f.UpdatedBefore.IsZero() && !md.Updated.Before(f.UpdatedBefore) {
+		return false
+	}
+
+	if !f.ActiveAt.IsZero() && md.IsExpired(f.ActiveAt) {
+		return false
+	}
+
+	if f.Tag == "" {
+		return true
+	}
+
+	for _, v := range md.Tags {
+		if v.Tag == f.Tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SecretSort field of secrets list order. Names aren't sortable: encrypted names are stored as blind indexes
+type SecretSort uint8
+
+const (
+	SecretSortCreated SecretSort = iota
+	SecretSortUpdated
+)
+
+// SecretPage order and bounds of secrets list page. Pages use keyset pagination:
+// next page starts right after last secret of previous page in selected order
+type SecretPage struct {
+	Sort       SecretSort
+	Descending bool
+	// Limit max count of secrets in page, 0 for unlimited page
+	Limit int
+	// After last secret of previous page, nil for first page
+	After *SecretCursor
+}
+
+// SecretCursor position of secret in sorted list
+type SecretCursor struct {
+	Created time.Time
+	Updated time.Time
+	UUID    string
+}
+
+// CursorOf returns position of secret metadata in list
+func CursorOf(md SecretMetadata) SecretCursor {
+	return SecretCursor{Created: md.Created, Updated: md.Updated, UUID: md.UUID}
+}
+
+// Less compares positions of secrets in ascending order of sort field, secrets with same value compared by uuid
+func (s SecretSort) Less(a, b SecretCursor) bool {
+	switch s {
+	case SecretSortUpdated:
+		if !a.Updated.Equal(b.Updated) {
+			return a.Updated.Before(b.Updated)
+		}
+	default:
+		if !a.Created.Equal(b.Created) {
+			return a.Created.Before(b.Created)
+		}
+	}
+
+	return a.UUID < b.UUID
+}
+
+// HasLimits checks that secret is one-time or expiring
+func (m SecretMetadata) HasLimits() bool {
+	return m.ExpiresAt != nil || m.MaxReads > 0
+}
+
+// IsExpired checks that secret expired at moment or has no reads left
+func (m SecretMetadata) IsExpired(moment time.Time) bool {
+	if m.ExpiresAt != nil && !m.ExpiresAt.After(moment) {
+		return true
+	}
+
+	return m.MaxReads > 0 && m.Reads >= m.MaxReads
 }
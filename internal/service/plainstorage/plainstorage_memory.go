@@ -1,24 +1,73 @@
 package plainstorage
 
 import (
+	"bytes"
 	"context"
 	"github.com/google/uuid"
 	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
-// In-memory storage, using for tests only
+// In-memory storage, used by tests and dev instances without database
+
+// MediaChunkRef position of chunk in media
+type MediaChunkRef struct {
+	MediaUUID string
+	Position  int
+	OwnerUUID string
+	Hash      string
+}
 
 type MemoryStorage struct {
 	Users      []User
 	SecretList []PlainSecret
+
+	MediaChunks []MediaChunk
+	// MediaChunkRefs ordered chunks of media
+	MediaChunkRefs []MediaChunkRef
+
+	MediaObjectKeys []MediaObjectKey
+
+	// mu guards lists of storage, it's held by transaction until its end
+	mu sync.Mutex
 }
 
-func (m *MemoryStorage) InTransaction(_ context.Context, transaction func() error) error {
-	return transaction()
+// InTransaction runs callback on copy of storage, that replaces lists of storage if callback succeeds.
+// Other calls of storage wait for end of transaction
+func (m *MemoryStorage) InTransaction(_ context.Context, transaction func(tx PlainStorage) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tx := &MemoryStorage{
+		Users:      slices.Clone(m.Users),
+		SecretList: slices.Clone(m.SecretList),
+
+		MediaChunks:     slices.Clone(m.MediaChunks),
+		MediaChunkRefs:  slices.Clone(m.MediaChunkRefs),
+		MediaObjectKeys: slices.Clone(m.MediaObjectKeys),
+	}
+
+	err := transaction(tx)
+	if err != nil {
+		return err
+	}
+
+	m.Users = tx.Users
+	m.SecretList = tx.SecretList
+	m.MediaChunks = tx.MediaChunks
+	m.MediaChunkRefs = tx.MediaChunkRefs
+	m.MediaObjectKeys = tx.MediaObjectKeys
+
+	return nil
 }
 
 func (m *MemoryStorage) GetUserByLogin(_ context.Context, login string) (*User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	for _, v := range m.Users {
 		if v.Login == login {
 			return &v, nil
@@ -29,6 +78,9 @@ func (m *MemoryStorage) GetUserByLogin(_ context.Context, login string) (*User,
 }
 
 func (m *MemoryStorage) GetUserByUUID(_ context.Context, userUUID string) (*User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	for _, v := range m.Users {
 		if v.UUID == userUUID {
 			return &v, nil
@@ -39,6 +91,9 @@ func (m *MemoryStorage) GetUserByUUID(_ context.Context, userUUID string) (*User
 }
 
 func (m *MemoryStorage) CreateUser(_ context.Context, login string, password string) (*User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	for _, v := range m.Users {
 		if v.Login == login {
 			return nil, ErrEntityAlreadyExists
@@ -56,18 +111,46 @@ func (m *MemoryStorage) CreateUser(_ context.Context, login string, password str
 	return &newUser, nil
 }
 
-func (m *MemoryStorage) GetUserSecretsMetadataByType(_ context.Context, userUUID string, secretType SecretType) ([]SecretMetadata, error) {
+func (m *MemoryStorage) GetUserSecretsMetadata(_ context.Context, userUUID string, filter SecretFilter, page SecretPage) ([]SecretMetadata, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	less := func(a, b SecretCursor) bool {
+		if page.Descending {
+			return page.Sort.Less(b, a)
+		}
+
+		return page.Sort.Less(a, b)
+	}
+
 	rs := make([]SecretMetadata, 0)
 	for _, val := range m.SecretList {
-		if val.Metadata.UserUUID == userUUID && val.Metadata.Type == secretType {
-			rs = append(rs, val.Metadata)
+		if val.Metadata.UserUUID != userUUID || !filter.Match(val.Metadata) {
+			continue
+		}
+
+		if page.After != nil && !less(*page.After, CursorOf(val.Metadata)) {
+			continue
 		}
+
+		rs = append(rs, val.Metadata)
+	}
+
+	sort.Slice(rs, func(i, j int) bool {
+		return less(CursorOf(rs[i]), CursorOf(rs[j]))
+	})
+
+	if page.Limit > 0 && len(rs) > page.Limit {
+		rs = rs[:page.Limit]
 	}
 
 	return rs, nil
 }
 
-func (m *MemoryStorage) AddSecretMetadata(_ context.Context, userUUID string, secretUUID, name string, dataType SecretType) (*SecretMetadata, error) {
+func (m *MemoryStorage) AddSecretMetadata(_ context.Context, userUUID string, secretUUID, name string, encryptedName []byte, dataType SecretType) (*SecretMetadata, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	for _, v := range m.SecretList {
 		if v.Metadata.UserUUID == userUUID && v.Metadata.Name == name && v.Metadata.Type == dataType {
 			return nil, ErrEntityAlreadyExists
@@ -76,12 +159,13 @@ func (m *MemoryStorage) AddSecretMetadata(_ context.Context, userUUID string, se
 
 	secret := PlainSecret{
 		Metadata: SecretMetadata{
-			UUID:     secretUUID,
-			UserUUID: userUUID,
-			Name:     name,
-			Type:     dataType,
-			Created:  time.Now(),
-			Updated:  time.Now(),
+			UUID:          secretUUID,
+			UserUUID:      userUUID,
+			Name:          name,
+			EncryptedName: encryptedName,
+			Type:          dataType,
+			Created:       time.Now(),
+			Updated:       time.Now(),
 		},
 		Data: nil,
 	}
@@ -91,7 +175,10 @@ func (m *MemoryStorage) AddSecretMetadata(_ context.Context, userUUID string, se
 	return &secret.Metadata, nil
 }
 
-func (m *MemoryStorage) AddPlainSecret(_ context.Context, userUUID string, name string, dataType SecretType, data []byte) (*PlainSecret, error) {
+func (m *MemoryStorage) AddPlainSecret(_ context.Context, userUUID string, name string, encryptedName []byte, dataType SecretType, data []byte) (*PlainSecret, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	for _, v := range m.SecretList {
 		if v.Metadata.UserUUID == userUUID && v.Metadata.Name == name && v.Metadata.Type == dataType {
 			return nil, ErrEntityAlreadyExists
@@ -102,12 +189,13 @@ func (m *MemoryStorage) AddPlainSecret(_ context.Context, userUUID string, name
 
 	secret := PlainSecret{
 		Metadata: SecretMetadata{
-			UUID:     secretUUID,
-			UserUUID: userUUID,
-			Name:     name,
-			Type:     dataType,
-			Created:  time.Now(),
-			Updated:  time.Now(),
+			UUID:          secretUUID,
+			UserUUID:      userUUID,
+			Name:          name,
+			EncryptedName: encryptedName,
+			Type:          dataType,
+			Created:       time.Now(),
+			Updated:       time.Now(),
 		},
 		Data: data,
 	}
@@ -117,7 +205,53 @@ func (m *MemoryStorage) AddPlainSecret(_ context.Context, userUUID string, name
 	return &secret, nil
 }
 
+func (m *MemoryStorage) AddPlainSecrets(_ context.Context, userUUID string, secrets []NewPlainSecret) ([]PlainSecret, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, v := range secrets {
+		for _, existing := range m.SecretList {
+			if existing.Metadata.UserUUID == userUUID && existing.Metadata.Name == v.Name && existing.Metadata.Type == v.Type {
+				return nil, ErrEntityAlreadyExists
+			}
+		}
+
+		for _, other := range secrets[:i] {
+			if other.Name == v.Name && other.Type == v.Type {
+				return nil, ErrEntityAlreadyExists
+			}
+		}
+	}
+
+	now := time.Now()
+	created := make([]PlainSecret, len(secrets))
+	for i, v := range secrets {
+		created[i] = PlainSecret{
+			Metadata: SecretMetadata{
+				UUID:          v.secretUUID(),
+				UserUUID:      userUUID,
+				Name:          v.Name,
+				EncryptedName: v.EncryptedName,
+				Type:          v.Type,
+				Created:       now,
+				Updated:       now,
+				ExpiresAt:     v.ExpiresAt,
+				MaxReads:      v.MaxReads,
+				Folder:        v.Folder,
+			},
+			Data: v.Data,
+		}
+	}
+
+	m.SecretList = append(m.SecretList, created...)
+
+	return created, nil
+}
+
 func (m *MemoryStorage) UpdateSecretMetadataUUID(_ context.Context, userUUID string, oldUUID string, newUUID string, dataType SecretType) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	var secret *SecretMetadata
 	for i, v := range m.SecretList {
 		if v.Metadata.UserUUID == userUUID && v.Metadata.UUID == oldUUID && v.Metadata.Type == dataType {
@@ -138,7 +272,147 @@ func (m *MemoryStorage) UpdateSecretMetadataUUID(_ context.Context, userUUID str
 	return nil
 }
 
+func (m *MemoryStorage) RenameSecret(_ context.Context, secretUUID string, name string, encryptedName []byte, dataType SecretType) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var secret *SecretMetadata
+	for i, v := range m.SecretList {
+		if v.Metadata.UUID == secretUUID {
+			secret = &m.SecretList[i].Metadata
+		}
+	}
+
+	if secret == nil {
+		return ErrEntityNotFound
+	}
+
+	for _, v := range m.SecretList {
+		if v.Metadata.UUID != secretUUID && v.Metadata.UserUUID == secret.UserUUID && v.Metadata.Name == name && v.Metadata.Type == dataType {
+			return ErrEntityAlreadyExists
+		}
+	}
+
+	secret.Name = name
+	secret.EncryptedName = encryptedName
+	secret.Type = dataType
+	secret.Updated = time.Now()
+
+	return nil
+}
+
+func (m *MemoryStorage) SetSecretLimits(_ context.Context, secretUUID string, expiresAt *time.Time, maxReads int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, v := range m.SecretList {
+		if v.Metadata.UUID == secretUUID {
+			m.SecretList[i].Metadata.ExpiresAt = expiresAt
+			m.SecretList[i].Metadata.MaxReads = maxReads
+			m.SecretList[i].Metadata.Reads = 0
+
+			return nil
+		}
+	}
+
+	return ErrEntityNotFound
+}
+
+func (m *MemoryStorage) RegisterSecretRead(_ context.Context, secretUUID string) (*SecretMetadata, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, v := range m.SecretList {
+		if v.Metadata.UUID == secretUUID {
+			if v.Metadata.IsExpired(time.Now()) {
+				return nil, ErrEntityNotFound
+			}
+
+			m.SecretList[i].Metadata.Reads++
+			md := m.SecretList[i].Metadata
+
+			return &md, nil
+		}
+	}
+
+	return nil, ErrEntityNotFound
+}
+
+func (m *MemoryStorage) GetExpiredSecretsMetadata(_ context.Context, moment time.Time) ([]SecretMetadata, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rs := make([]SecretMetadata, 0)
+	for _, v := range m.SecretList {
+		if v.Metadata.IsExpired(moment) {
+			rs = append(rs, v.Metadata)
+		}
+	}
+
+	return rs, nil
+}
+
+func (m *MemoryStorage) SetSecretFolder(_ context.Context, secretUUID string, folder string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, v := range m.SecretList {
+		if v.Metadata.UUID == secretUUID {
+			m.SecretList[i].Metadata.Folder = folder
+			m.SecretList[i].Metadata.Updated = time.Now()
+
+			return nil
+		}
+	}
+
+	return ErrEntityNotFound
+}
+
+func (m *MemoryStorage) SetMediaInfo(_ context.Context, secretUUID string, info MediaInfo) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, v := range m.SecretList {
+		if v.Metadata.UUID == secretUUID {
+			m.SecretList[i].Metadata.ContentHash = info.ContentHash
+			m.SecretList[i].Metadata.MediaSize = info.Size
+			m.SecretList[i].Metadata.EncryptedMediaInfo = info.EncryptedInfo
+
+			return nil
+		}
+	}
+
+	return ErrEntityNotFound
+}
+
+func (m *MemoryStorage) SetSecretTags(_ context.Context, secretUUID string, tags []SecretTag) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, v := range m.SecretList {
+		if v.Metadata.UUID == secretUUID {
+			// tags are kept in order of tag and without duplicates like in SQL storages
+			sorted := slices.Clone(tags)
+			slices.SortStableFunc(sorted, func(a, b SecretTag) int {
+				return strings.Compare(a.Tag, b.Tag)
+			})
+
+			m.SecretList[i].Metadata.Tags = slices.CompactFunc(sorted, func(a, b SecretTag) bool {
+				return a.Tag == b.Tag
+			})
+			m.SecretList[i].Metadata.Updated = time.Now()
+
+			return nil
+		}
+	}
+
+	return ErrEntityNotFound
+}
+
 func (m *MemoryStorage) UpdatePlainSecretDataByName(_ context.Context, ownerUUID string, name string, secretType SecretType, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	var secret *PlainSecret
 	for i, v := range m.SecretList {
 		if v.Metadata.UserUUID == ownerUUID && v.Metadata.Name == name && v.Metadata.Type == secretType {
@@ -157,28 +431,414 @@ func (m *MemoryStorage) UpdatePlainSecretDataByName(_ context.Context, ownerUUID
 }
 
 func (m *MemoryStorage) RemoveSecretByUUID(_ context.Context, secretUUID string) error {
-	pos := -1
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.SecretList = slices.DeleteFunc(m.SecretList, func(secret PlainSecret) bool {
+		return secret.Metadata.UUID == secretUUID
+	})
+
+	return nil
+}
+
+func (m *MemoryStorage) GetUserSecretByName(_ context.Context, userUUID string, secretName string, secretType SecretType) (*PlainSecret, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, v := range m.SecretList {
+		if v.Metadata.UserUUID == userUUID && v.Metadata.Name == secretName && v.Metadata.Type == secretType {
+			return &v, nil
+		}
+	}
+
+	return nil, ErrEntityNotFound
+}
+
+func (m *MemoryStorage) RemoveSecretsByUUID(_ context.Context, secretUUIDs []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.SecretList = slices.DeleteFunc(m.SecretList, func(secret PlainSecret) bool {
+		return slices.Contains(secretUUIDs, secret.Metadata.UUID)
+	})
+
+	return nil
+}
+
+func (m *MemoryStorage) GetExistingMediaChunks(_ context.Context, userUUID string, hashes []string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rs := make([]string, 0)
+	for _, hash := range hashes {
+		if m.mediaChunkIndex(userUUID, hash) != -1 {
+			rs = append(rs, hash)
+		}
+	}
+
+	return rs, nil
+}
+
+func (m *MemoryStorage) mediaChunkIndex(userUUID string, hash string) int {
+	return slices.IndexFunc(m.MediaChunks, func(chunk MediaChunk) bool {
+		return chunk.OwnerUUID == userUUID && chunk.Hash == hash
+	})
+}
+
+func (m *MemoryStorage) AddMediaChunk(_ context.Context, userUUID string, hash string, size int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.mediaChunkIndex(userUUID, hash) == -1 {
+		m.MediaChunks = append(m.MediaChunks, MediaChunk{OwnerUUID: userUUID, Hash: hash, Size: size, Created: time.Now()})
+	}
+
+	return nil
+}
+
+func (m *MemoryStorage) SetMediaChunks(_ context.Context, userUUID string, mediaUUID string, hashes []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, v := range m.MediaChunkRefs {
+		if v.MediaUUID == mediaUUID {
+			return ErrEntityAlreadyExists
+		}
+	}
+
+	indexes := make([]int, len(hashes))
+	for i, hash := range hashes {
+		indexes[i] = m.mediaChunkIndex(userUUID, hash)
+		if indexes[i] == -1 {
+			return ErrEntityNotFound
+		}
+	}
+
+	for i, hash := range hashes {
+		m.MediaChunks[indexes[i]].Refs++
+		m.MediaChunkRefs = append(m.MediaChunkRefs, MediaChunkRef{MediaUUID: mediaUUID, Position: i, OwnerUUID: userUUID, Hash: hash})
+	}
+
+	return nil
+}
+
+func (m *MemoryStorage) GetUserUsage(_ context.Context, userUUID string) (Usage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	chunked := make(map[string]struct{})
+	for _, v := range m.MediaChunkRefs {
+		chunked[v.MediaUUID] = struct{}{}
+	}
+
+	usage := Usage{}
+	for _, v := range m.SecretList {
+		if v.Metadata.UserUUID != userUUID {
+			continue
+		}
+
+		if v.Metadata.Type != SecretTypeMedia {
+			usage.PlainCount++
+			usage.PlainBytes += int64(len(v.Data))
+
+			continue
+		}
+
+		usage.MediaCount++
+		if _, ok := chunked[v.Metadata.UUID]; !ok {
+			usage.MediaBytes += v.Metadata.MediaSize
+		}
+	}
+
+	for _, v := range m.MediaChunks {
+		if v.OwnerUUID == userUUID {
+			usage.MediaBytes += v.Size
+		}
+	}
+
+	return usage, nil
+}
+
+func (m *MemoryStorage) GetMediaChunks(_ context.Context, mediaUUID string) ([]MediaChunk, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	refs := make([]MediaChunkRef, 0)
+	for _, v := range m.MediaChunkRefs {
+		if v.MediaUUID == mediaUUID {
+			refs = append(refs, v)
+		}
+	}
+
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].Position < refs[j].Position
+	})
+
+	rs := make([]MediaChunk, len(refs))
+	for i, v := range refs {
+		rs[i] = m.MediaChunks[m.mediaChunkIndex(v.OwnerUUID, v.Hash)]
+	}
+
+	return rs, nil
+}
+
+func (m *MemoryStorage) RemoveMediaChunks(_ context.Context, mediaUUID string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	released := make([]string, 0)
+	for _, v := range m.MediaChunkRefs {
+		if v.MediaUUID != mediaUUID {
+			continue
+		}
+
+		i := m.mediaChunkIndex(v.OwnerUUID, v.Hash)
+		m.MediaChunks[i].Refs--
+		if m.MediaChunks[i].Refs == 0 {
+			released = append(released, v.Hash)
+			m.MediaChunks = slices.Delete(m.MediaChunks, i, i+1)
+		}
+	}
+
+	m.MediaChunkRefs = slices.DeleteFunc(m.MediaChunkRefs, func(ref MediaChunkRef) bool {
+		return ref.MediaUUID == mediaUUID
+	})
+
+	return released, nil
+}
+
+func (m *MemoryStorage) GetAllMediaChunks(_ context.Context) ([]MediaChunk, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.MediaChunks), nil
+}
+
+func (m *MemoryStorage) RemoveUnusedMediaChunk(_ context.Context, userUUID string, hash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.mediaChunkIndex(userUUID, hash)
+	if i == -1 || m.MediaChunks[i].Refs > 0 {
+		return ErrEntityNotFound
+	}
+
+	m.MediaChunks = slices.Delete(m.MediaChunks, i, i+1)
+
+	return nil
+}
+
+func (m *MemoryStorage) GetObjectMediaMetadata(_ context.Context) ([]SecretMetadata, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	chunked := make(map[string]struct{})
+	for _, v := range m.MediaChunkRefs {
+		chunked[v.MediaUUID] = struct{}{}
+	}
+
+	rs := make([]SecretMetadata, 0)
+	for _, v := range m.SecretList {
+		if _, ok := chunked[v.Metadata.UUID]; v.Metadata.Type == SecretTypeMedia && !ok {
+			rs = append(rs, v.Metadata)
+		}
+	}
+
+	return rs, nil
+}
+
+func (m *MemoryStorage) GetPlainSecretsData(_ context.Context, afterUUID string, limit int) ([]SecretData, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rs := make([]SecretData, 0)
+	for _, v := range m.SecretList {
+		if v.Data != nil && v.Metadata.UUID > afterUUID {
+			rs = append(rs, SecretData{UUID: v.Metadata.UUID, Data: v.Data})
+		}
+	}
+
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].UUID < rs[j].UUID
+	})
+
+	return rs[:min(limit, len(rs))], nil
+}
+
+func (m *MemoryStorage) ReplacePlainSecretData(_ context.Context, secretUUID string, old []byte, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	for i, v := range m.SecretList {
-		if v.Metadata.UUID == secretUUID {
-			pos = i
+		if v.Metadata.UUID == secretUUID && v.Data != nil && bytes.Equal(v.Data, old) {
+			m.SecretList[i].Data = data
+
+			return nil
 		}
 	}
 
-	if pos == -1 {
+	return ErrEntityNotFound
+}
+
+func (m *MemoryStorage) mediaObjectKeyIndex(objectKey string) int {
+	return slices.IndexFunc(m.MediaObjectKeys, func(key MediaObjectKey) bool {
+		return key.ObjectKey == objectKey
+	})
+}
+
+func (m *MemoryStorage) GetMediaObjectKey(_ context.Context, objectKey string) (*MediaObjectKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.mediaObjectKeyIndex(objectKey)
+	if i == -1 {
+		return nil, ErrEntityNotFound
+	}
+
+	key := m.MediaObjectKeys[i]
+
+	return &key, nil
+}
+
+func (m *MemoryStorage) SetMediaObjectKey(_ context.Context, key MediaObjectKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if i := m.mediaObjectKeyIndex(key.ObjectKey); i != -1 {
+		m.MediaObjectKeys[i] = key
+	} else {
+		m.MediaObjectKeys = append(m.MediaObjectKeys, key)
+	}
+
+	return nil
+}
+
+func (m *MemoryStorage) RemoveMediaObjectKey(_ context.Context, objectKey string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.mediaObjectKeyIndex(objectKey)
+	if i == -1 {
 		return ErrEntityNotFound
 	}
 
-	m.SecretList = slices.Delete(m.SecretList, pos, pos+1)
+	m.MediaObjectKeys = slices.Delete(m.MediaObjectKeys, i, i+1)
 
 	return nil
 }
 
-func (m *MemoryStorage) GetUserSecretByName(_ context.Context, userUUID string, secretName string, secretType SecretType) (*PlainSecret, error) {
+func (m *MemoryStorage) GetMediaObjectKeys(_ context.Context, afterKey string, limit int) ([]MediaObjectKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rs := make([]MediaObjectKey, 0)
+	for _, v := range m.MediaObjectKeys {
+		if v.ObjectKey > afterKey {
+			rs = append(rs, v)
+		}
+	}
+
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].ObjectKey < rs[j].ObjectKey
+	})
+
+	return rs[:min(limit, len(rs))], nil
+}
+
+func (m *MemoryStorage) ReplaceMediaObjectKey(_ context.Context, old MediaObjectKey, key MediaObjectKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	i := m.mediaObjectKeyIndex(old.ObjectKey)
+	if i == -1 || !bytes.Equal(m.MediaObjectKeys[i].WrappedKey, old.WrappedKey) {
+		return ErrEntityNotFound
+	}
+
+	key.ObjectKey = old.ObjectKey
+	m.MediaObjectKeys[i] = key
+
+	return nil
+}
+
+// InSnapshot runs read callback on copy of storage, so storage isn't locked while callback runs
+func (m *MemoryStorage) InSnapshot(_ context.Context, read func(snapshot PlainStorage) error) error {
+	m.mu.Lock()
+	snapshot := &MemoryStorage{
+		Users:      slices.Clone(m.Users),
+		SecretList: slices.Clone(m.SecretList),
+
+		MediaChunks:     slices.Clone(m.MediaChunks),
+		MediaChunkRefs:  slices.Clone(m.MediaChunkRefs),
+		MediaObjectKeys: slices.Clone(m.MediaObjectKeys),
+	}
+	m.mu.Unlock()
+
+	return read(snapshot)
+}
+
+func (m *MemoryStorage) GetUsers(_ context.Context, afterUUID string, limit int) ([]User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rs := make([]User, 0)
+	for _, v := range m.Users {
+		if v.UUID > afterUUID {
+			rs = append(rs, v)
+		}
+	}
+
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].UUID < rs[j].UUID
+	})
+
+	return rs[:min(limit, len(rs))], nil
+}
+
+func (m *MemoryStorage) GetSecrets(_ context.Context, afterUUID string, limit int) ([]PlainSecret, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rs := make([]PlainSecret, 0)
 	for _, v := range m.SecretList {
-		if v.Metadata.UserUUID == userUUID && v.Metadata.Name == secretName && v.Metadata.Type == secretType {
-			return &v, nil
+		if v.Metadata.UUID > afterUUID {
+			rs = append(rs, v)
 		}
 	}
 
-	return nil, ErrEntityNotFound
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].Metadata.UUID < rs[j].Metadata.UUID
+	})
+
+	return rs[:min(limit, len(rs))], nil
+}
+
+func (m *MemoryStorage) RestoreUser(_ context.Context, user User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, v := range m.Users {
+		if v.UUID == user.UUID || v.Login == user.Login {
+			return ErrEntityAlreadyExists
+		}
+	}
+
+	m.Users = append(m.Users, user)
+
+	return nil
+}
+
+func (m *MemoryStorage) RestoreSecret(_ context.Context, secret PlainSecret) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	md := secret.Metadata
+	for _, v := range m.SecretList {
+		if v.Metadata.UUID == md.UUID || (v.Metadata.UserUUID == md.UserUUID && v.Metadata.Name == md.Name && v.Metadata.Type == md.Type) {
+			return ErrEntityAlreadyExists
+		}
+	}
+
+	m.SecretList = append(m.SecretList, secret)
+
+	return nil
 }
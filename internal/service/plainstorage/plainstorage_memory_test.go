@@ -0,0 +1,11 @@
+package plainstorage_test
+
+import (
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage/plainstoragetest"
+	"testing"
+)
+
+func TestMemoryStorage(t *testing.T) {
+	plainstoragetest.TestPlainStorage(t, &plainstorage.MemoryStorage{})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/nessai1/gophkeeper/internal/service/config"
 	"github.com/nessai1/gophkeeper/pkg/postgrescodes"
 	"go.uber.org/zap"
+	"strings"
 	"time"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
@@ -21,10 +22,19 @@ import (
 	"github.com/google/uuid"
 )
 
+// secretMetadataColumns columns of secret_metadata table in order of SecretMetadata fields
+const secretMetadataColumns = "uuid, owner_uuid, name, encrypted_name, type, created, updated, expires_at, max_reads, reads, folder, content_hash, media_size, encrypted_media_info"
+
 type PSQLPlainStorage struct {
 	config config.PSQLPlainStorageConfig
 	db     *sqlx.DB
-	logger *zap.Logger
+	// q executes queries of storage: db or transaction of storage given to InTransaction callback
+	q sqlx.ExtContext
+	// tx transaction of storage, nil for storage out of transaction
+	tx *sqlx.Tx
+	// savepoints count of savepoints opened in transaction
+	savepoints int
+	logger     *zap.Logger
 }
 
 func NewPSQLPlainStorage(cfg config.PSQLPlainStorageConfig, l *zap.Logger) (*PSQLPlainStorage, error) {
@@ -49,13 +59,16 @@ func NewPSQLPlainStorage(cfg config.PSQLPlainStorageConfig, l *zap.Logger) (*PSQ
 	}
 
 	err = initPSQLMigrations(db)
-	if err != nil && !errors.As(migrate.ErrNoChange, &err) {
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
 		return nil, fmt.Errorf("cannot init psql migrations: %w", err)
 	}
 
+	dbx := sqlx.NewDb(db, "pgx")
+
 	return &PSQLPlainStorage{
 		config: cfg,
-		db:     sqlx.NewDb(db, "pgx"),
+		db:     dbx,
+		q:      dbx,
 		logger: l,
 	}, nil
 }
@@ -78,13 +91,25 @@ func initPSQLMigrations(db *sql.DB) error {
 	return nil
 }
 
-func (s *PSQLPlainStorage) InTransaction(ctx context.Context, transaction func() error) error {
-	tx, txErr := s.db.BeginTx(ctx, nil)
+// InTransaction runs transaction callback with storage, that routes all queries through one transaction.
+// Nested calls on storage of transaction create savepoints, so error of nested callback rollbacks only its queries
+func (s *PSQLPlainStorage) InTransaction(ctx context.Context, transaction func(tx PlainStorage) error) error {
+	return s.inTransaction(ctx, func(tx *PSQLPlainStorage) error {
+		return transaction(tx)
+	})
+}
+
+func (s *PSQLPlainStorage) inTransaction(ctx context.Context, transaction func(tx *PSQLPlainStorage) error) error {
+	if s.tx != nil {
+		return s.inSavepoint(ctx, transaction)
+	}
+
+	tx, txErr := s.db.BeginTxx(ctx, nil)
 	if txErr != nil {
 		return fmt.Errorf("cannot start intransaction: %w", txErr)
 	}
 
-	err := transaction()
+	err := transaction(&PSQLPlainStorage{config: s.config, db: s.db, q: tx, tx: tx, logger: s.logger})
 	if err != nil {
 		txErr := tx.Rollback()
 		if txErr != nil {
@@ -106,9 +131,38 @@ func (s *PSQLPlainStorage) InTransaction(ctx context.Context, transaction func()
 	return nil
 }
 
+func (s *PSQLPlainStorage) inSavepoint(ctx context.Context, transaction func(tx *PSQLPlainStorage) error) error {
+	s.savepoints++
+	savepoint := fmt.Sprintf("savepoint_%d", s.savepoints)
+
+	_, spErr := s.tx.ExecContext(ctx, "SAVEPOINT "+savepoint)
+	if spErr != nil {
+		return fmt.Errorf("cannot create savepoint: %w", spErr)
+	}
+
+	err := transaction(s)
+	if err != nil {
+		_, spErr := s.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint)
+		if spErr != nil {
+			s.logger.Error("Cannot rollback to savepoint", zap.Error(spErr))
+
+			return errors.Join(err, fmt.Errorf("cannot rollback to savepoint: %w", spErr))
+		}
+
+		return err
+	}
+
+	_, spErr = s.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint)
+	if spErr != nil {
+		return fmt.Errorf("cannot release savepoint: %w", spErr)
+	}
+
+	return nil
+}
+
 func (s *PSQLPlainStorage) GetUserByLogin(ctx context.Context, login string) (*User, error) {
 	var user User
-	err := s.db.QueryRowContext(
+	err := s.q.QueryRowxContext(
 		ctx,
 		"SELECT uuid, login, password FROM users WHERE login = $1",
 		login,
@@ -125,7 +179,7 @@ func (s *PSQLPlainStorage) GetUserByLogin(ctx context.Context, login string) (*U
 
 func (s *PSQLPlainStorage) GetUserByUUID(ctx context.Context, uuid string) (*User, error) {
 	var user User
-	err := s.db.QueryRowContext(
+	err := s.q.QueryRowxContext(
 		ctx,
 		"SELECT uuid, login, password FROM users WHERE uuid = $1",
 		uuid,
@@ -142,7 +196,7 @@ func (s *PSQLPlainStorage) GetUserByUUID(ctx context.Context, uuid string) (*Use
 
 func (s *PSQLPlainStorage) CreateUser(ctx context.Context, login string, password string) (*User, error) {
 	userUUID := uuid.New().String()
-	_, err := s.db.ExecContext(ctx, "INSERT INTO users (uuid, login, password) VALUES ($1, $2, $3)", userUUID, login, password)
+	_, err := s.q.ExecContext(ctx, "INSERT INTO users (uuid, login, password) VALUES ($1, $2, $3)", userUUID, login, password)
 
 	if err != nil {
 		var pgErr *pgconn.PgError
@@ -162,30 +216,140 @@ func (s *PSQLPlainStorage) CreateUser(ctx context.Context, login string, passwor
 	}, nil
 }
 
-func (s *PSQLPlainStorage) GetUserSecretsMetadataByType(ctx context.Context, userUUID string, secretType SecretType) ([]SecretMetadata, error) {
-	query := `SELECT uuid, owner_uuid, name, type, created, updated FROM secret_metadata WHERE owner_uuid = ? AND type = ?`
-	query, args, err := sqlx.In(query, userUUID, secretType)
-	if err != nil {
-		s.logger.Error("Error preparing list secrets query", zap.Error(err))
+func (s *PSQLPlainStorage) GetUserSecretsMetadata(ctx context.Context, userUUID string, filter SecretFilter, page SecretPage) ([]SecretMetadata, error) {
+	query := `SELECT ` + secretMetadataColumns + ` FROM secret_metadata WHERE owner_uuid = ?`
+	args := []any{userUUID}
+
+	if !filter.AnyType {
+		query += ` AND type = ?`
+		args = append(args, filter.Type)
+	}
 
-		return nil, fmt.Errorf("error preparing list secrets query: %w", err)
+	if filter.Folder != "" {
+		query += ` AND folder = ?`
+		args = append(args, filter.Folder)
 	}
 
-	query = s.db.Rebind(query)
+	if filter.Tag != "" {
+		query += ` AND uuid IN (SELECT secret_uuid FROM secret_tag WHERE tag = ?)`
+		args = append(args, filter.Tag)
+	}
+
+	if filter.Names != nil {
+		if len(filter.Names) == 0 {
+			return []SecretMetadata{}, nil
+		}
+
+		query += ` AND name IN (?` + strings.Repeat(`, ?`, len(filter.Names)-1) + `)`
+		for _, v := range filter.Names {
+			args = append(args, v)
+		}
+	}
+
+	bounds := []struct {
+		condition string
+		value     time.Time
+	}{
+		{` AND created > ?`, filter.CreatedAfter},
+		{` AND created < ?`, filter.CreatedBefore},
+		{` AND updated > ?`, filter.UpdatedAfter},
+		{` AND updated < ?`, filter.UpdatedBefore},
+	}
+	for _, v := range bounds {
+		if !v.value.IsZero() {
+			query += v.condition
+			args = append(args, v.value)
+		}
+	}
+
+	if !filter.ActiveAt.IsZero() {
+		query += ` AND (expires_at IS NULL OR expires_at > ?) AND (max_reads = 0 OR reads < max_reads)`
+		args = append(args, filter.ActiveAt)
+	}
+
+	sortColumn := "created"
+	if page.Sort == SecretSortUpdated {
+		sortColumn = "updated"
+	}
+
+	direction, comparison := "ASC", ">"
+	if page.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	if page.After != nil {
+		sortValue := page.After.Created
+		if page.Sort == SecretSortUpdated {
+			sortValue = page.After.Updated
+		}
+
+		query += fmt.Sprintf(` AND (%s, uuid) %s (?, ?)`, sortColumn, comparison)
+		args = append(args, sortValue, page.After.UUID)
+	}
+
+	query += fmt.Sprintf(` ORDER BY %s %s, uuid %s`, sortColumn, direction, direction)
+
+	if page.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, page.Limit)
+	}
+
+	query = s.q.Rebind(query)
 
 	var secrets []SecretMetadata
-	err = s.db.SelectContext(ctx, &secrets, query, args...)
+	err := sqlx.SelectContext(ctx, s.q, &secrets, query, args...)
 	if err != nil {
 		s.logger.Error("Error while get list of secrets", zap.Error(err))
 
 		return nil, fmt.Errorf("error while get list of secrets")
 	}
 
+	err = s.loadSecretsTags(ctx, secrets)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load tags of secrets list: %w", err)
+	}
+
 	return secrets, nil
 }
 
-func (s *PSQLPlainStorage) AddSecretMetadata(ctx context.Context, userUUID string, secretUUID, name string, dataType SecretType) (*SecretMetadata, error) {
-	_, err := s.db.ExecContext(ctx, "INSERT INTO secret_metadata (uuid, owner_uuid, name, type) VALUES ($1, $2, $3, $4)", secretUUID, userUUID, name, dataType)
+// loadSecretsTags fills tags of secrets by one query
+func (s *PSQLPlainStorage) loadSecretsTags(ctx context.Context, secrets []SecretMetadata) error {
+	if len(secrets) == 0 {
+		return nil
+	}
+
+	positions := make(map[string]int, len(secrets))
+	uuids := make([]string, len(secrets))
+	for i, v := range secrets {
+		positions[v.UUID] = i
+		uuids[i] = v.UUID
+	}
+
+	query, args, err := sqlx.In(`SELECT secret_uuid, tag, encrypted_tag FROM secret_tag WHERE secret_uuid IN (?) ORDER BY tag`, uuids)
+	if err != nil {
+		return fmt.Errorf("error preparing secret tags query: %w", err)
+	}
+
+	var tags []struct {
+		SecretUUID string `db:"secret_uuid"`
+		SecretTag
+	}
+
+	err = sqlx.SelectContext(ctx, s.q, &tags, s.q.Rebind(query), args...)
+	if err != nil {
+		return fmt.Errorf("cannot select secret tags: %w", err)
+	}
+
+	for _, v := range tags {
+		pos := positions[v.SecretUUID]
+		secrets[pos].Tags = append(secrets[pos].Tags, v.SecretTag)
+	}
+
+	return nil
+}
+
+func (s *PSQLPlainStorage) AddSecretMetadata(ctx context.Context, userUUID string, secretUUID, name string, encryptedName []byte, dataType SecretType) (*SecretMetadata, error) {
+	_, err := s.q.ExecContext(ctx, "INSERT INTO secret_metadata (uuid, owner_uuid, name, encrypted_name, type) VALUES ($1, $2, $3, $4, $5)", secretUUID, userUUID, name, encryptedName, dataType)
 
 	if err != nil {
 		var pgErr *pgconn.PgError
@@ -199,15 +363,16 @@ func (s *PSQLPlainStorage) AddSecretMetadata(ctx context.Context, userUUID strin
 	}
 
 	return &SecretMetadata{
-		UUID:     secretUUID,
-		UserUUID: userUUID,
-		Name:     name,
-		Type:     dataType,
+		UUID:          secretUUID,
+		UserUUID:      userUUID,
+		Name:          name,
+		EncryptedName: encryptedName,
+		Type:          dataType,
 	}, nil
 }
 
 func (s *PSQLPlainStorage) UpdateSecretMetadataUUID(ctx context.Context, userUUID string, oldUUID string, newUUID string, dataType SecretType) error {
-	res, err := s.db.ExecContext(ctx, "UPDATE secret_metadata SET uuid = $1, updated = now() WHERE owner_uuid = $2 AND type = $3 AND uuid = $4", newUUID, userUUID, dataType, oldUUID)
+	res, err := s.q.ExecContext(ctx, "UPDATE secret_metadata SET uuid = $1, updated = now() WHERE owner_uuid = $2 AND type = $3 AND uuid = $4", newUUID, userUUID, dataType, oldUUID)
 	if err != nil {
 		return fmt.Errorf("cannot make update query: %w", err)
 	}
@@ -220,50 +385,200 @@ func (s *PSQLPlainStorage) UpdateSecretMetadataUUID(ctx context.Context, userUUI
 	return nil
 }
 
-func (s *PSQLPlainStorage) RemoveSecretByUUID(ctx context.Context, secretUUID string) error {
-	_, err := s.db.ExecContext(ctx, "DELETE FROM secret_metadata WHERE uuid = $1", secretUUID)
+func (s *PSQLPlainStorage) RenameSecret(ctx context.Context, secretUUID string, name string, encryptedName []byte, dataType SecretType) error {
+	res, err := s.q.ExecContext(ctx, "UPDATE secret_metadata SET name = $1, encrypted_name = $2, type = $3, updated = now() WHERE uuid = $4", name, encryptedName, dataType, secretUUID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == postgrescodes.PostgresErrCodeUniqueViolation {
+				return ErrEntityAlreadyExists
+			}
+		}
 
+		return fmt.Errorf("cannot update secret name: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("cannot remove secret metadata: %w", err)
+		return fmt.Errorf("cannot get count of renamed secrets: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrEntityNotFound
 	}
 
 	return nil
 }
 
-func (s *PSQLPlainStorage) AddPlainSecret(ctx context.Context, userUUID string, name string, dataType SecretType, data []byte) (*PlainSecret, error) {
-	tx, err := s.db.BeginTx(ctx, nil)
+func (s *PSQLPlainStorage) SetSecretLimits(ctx context.Context, secretUUID string, expiresAt *time.Time, maxReads int) error {
+	res, err := s.q.ExecContext(ctx, "UPDATE secret_metadata SET expires_at = $1, max_reads = $2, reads = 0 WHERE uuid = $3", expiresAt, maxReads, secretUUID)
 	if err != nil {
-		return nil, fmt.Errorf("cannot start create secret transaction: %w", err)
+		return fmt.Errorf("cannot set secret limits: %w", err)
 	}
 
-	secretUUID := uuid.New().String()
-	md, err := s.AddSecretMetadata(ctx, userUUID, secretUUID, name, dataType)
+	rowsAffected, err := res.RowsAffected()
 	if err != nil {
-		txErr := tx.Rollback()
-		if txErr != nil {
-			s.logger.Error("Cannot rollback create secret transaction", zap.Error(err))
-		}
+		return fmt.Errorf("cannot get count of limited secrets: %w", err)
+	}
 
-		if errors.Is(ErrEntityAlreadyExists, err) {
-			return nil, ErrEntityAlreadyExists
-		}
+	if rowsAffected == 0 {
+		return ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (s *PSQLPlainStorage) RegisterSecretRead(ctx context.Context, secretUUID string) (*SecretMetadata, error) {
+	var md SecretMetadata
+	err := s.q.QueryRowxContext(
+		ctx,
+		`UPDATE secret_metadata SET reads = reads + 1
+		WHERE uuid = $1 AND (expires_at IS NULL OR expires_at > now()) AND (max_reads = 0 OR reads < max_reads)
+		RETURNING `+secretMetadataColumns,
+		secretUUID,
+	).StructScan(&md)
 
-		return nil, fmt.Errorf("cannot create metadata of plain secret: %w", err)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrEntityNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot register secret read: %w", err)
 	}
 
-	_, err = s.db.ExecContext(ctx, "INSERT INTO plain_secret (uuid, data) VALUES ($1, $2)", md.UUID, data)
+	return &md, nil
+}
+
+func (s *PSQLPlainStorage) GetExpiredSecretsMetadata(ctx context.Context, moment time.Time) ([]SecretMetadata, error) {
+	var secrets []SecretMetadata
+	err := sqlx.SelectContext(
+		ctx,
+		s.q,
+		&secrets,
+		`SELECT `+secretMetadataColumns+` FROM secret_metadata
+		WHERE expires_at <= $1 OR (max_reads > 0 AND reads >= max_reads)`,
+		moment,
+	)
+
 	if err != nil {
-		txErr := tx.Rollback()
-		if txErr != nil {
-			s.logger.Error("Cannot rollback create secret transaction", zap.Error(err))
+		return nil, fmt.Errorf("cannot get expired secrets: %w", err)
+	}
+
+	return secrets, nil
+}
+
+func (s *PSQLPlainStorage) SetSecretFolder(ctx context.Context, secretUUID string, folder string) error {
+	res, err := s.q.ExecContext(ctx, "UPDATE secret_metadata SET folder = $1, updated = now() WHERE uuid = $2", folder, secretUUID)
+	if err != nil {
+		return fmt.Errorf("cannot set secret folder: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("cannot get count of moved secrets: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (s *PSQLPlainStorage) SetMediaInfo(ctx context.Context, secretUUID string, info MediaInfo) error {
+	res, err := s.q.ExecContext(ctx, "UPDATE secret_metadata SET content_hash = $1, media_size = $2, encrypted_media_info = $3 WHERE uuid = $4", info.ContentHash, info.Size, info.EncryptedInfo, secretUUID)
+	if err != nil {
+		return fmt.Errorf("cannot set media info: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("cannot get count of updated secrets: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (s *PSQLPlainStorage) SetSecretTags(ctx context.Context, secretUUID string, tags []SecretTag) error {
+	return s.inTransaction(ctx, func(tx *PSQLPlainStorage) error {
+		res, err := tx.q.ExecContext(ctx, "UPDATE secret_metadata SET updated = now() WHERE uuid = $1", secretUUID)
+		if err != nil {
+			return fmt.Errorf("cannot update secret metadata: %w", err)
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("cannot get count of tagged secrets: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return ErrEntityNotFound
 		}
 
-		return nil, fmt.Errorf("cannot insert plain secret data to table: %w", err)
+		_, err = tx.q.ExecContext(ctx, "DELETE FROM secret_tag WHERE secret_uuid = $1", secretUUID)
+		if err != nil {
+			return fmt.Errorf("cannot remove old secret tags: %w", err)
+		}
+
+		for _, v := range tags {
+			_, err = tx.q.ExecContext(ctx, "INSERT INTO secret_tag (secret_uuid, tag, encrypted_tag) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING", secretUUID, v.Tag, v.EncryptedTag)
+			if err != nil {
+				return fmt.Errorf("cannot insert secret tag: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *PSQLPlainStorage) RemoveSecretByUUID(ctx context.Context, secretUUID string) error {
+	return s.RemoveSecretsByUUID(ctx, []string{secretUUID})
+}
+
+func (s *PSQLPlainStorage) RemoveSecretsByUUID(ctx context.Context, secretUUIDs []string) error {
+	if len(secretUUIDs) == 0 {
+		return nil
 	}
 
-	err = tx.Commit()
+	return s.inTransaction(ctx, func(tx *PSQLPlainStorage) error {
+		for _, table := range []string{"plain_secret", "secret_metadata"} {
+			query, args, err := sqlx.In(`DELETE FROM `+table+` WHERE uuid IN (?)`, secretUUIDs)
+			if err != nil {
+				return fmt.Errorf("error preparing remove secrets query: %w", err)
+			}
+
+			_, err = tx.q.ExecContext(ctx, tx.q.Rebind(query), args...)
+			if err != nil {
+				return fmt.Errorf("cannot remove secrets from %s: %w", table, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *PSQLPlainStorage) AddPlainSecret(ctx context.Context, userUUID string, name string, encryptedName []byte, dataType SecretType, data []byte) (*PlainSecret, error) {
+	var md *SecretMetadata
+	err := s.inTransaction(ctx, func(tx *PSQLPlainStorage) error {
+		var err error
+		md, err = tx.AddSecretMetadata(ctx, userUUID, uuid.New().String(), name, encryptedName, dataType)
+		if err != nil && errors.Is(ErrEntityAlreadyExists, err) {
+			return ErrEntityAlreadyExists
+		} else if err != nil {
+			return fmt.Errorf("cannot create metadata of plain secret: %w", err)
+		}
+
+		_, err = tx.q.ExecContext(ctx, "INSERT INTO plain_secret (uuid, data) VALUES ($1, $2)", md.UUID, data)
+		if err != nil {
+			return fmt.Errorf("cannot insert plain secret data to table: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("cannot commit create secret transaction: %w", err)
+		return nil, err
 	}
 
 	return &PlainSecret{
@@ -272,84 +587,606 @@ func (s *PSQLPlainStorage) AddPlainSecret(ctx context.Context, userUUID string,
 	}, nil
 }
 
+// bulkInsertRows count of rows inserted by one query, keeps count of query params under postgres limit
+const bulkInsertRows = 1000
+
+// mediaChunkRefs count of references to chunk
+type mediaChunkRefs struct {
+	OwnerUUID string `db:"owner_uuid"`
+	Hash      string `db:"hash"`
+	Refs      int    `db:"refs"`
+}
+
+// countHashes counts repeats of hashes, hashes are kept in order of first appearance
+// so concurrent transactions update chunks in the same order
+func countHashes(hashes []string) []mediaChunkRefs {
+	positions := make(map[string]int, len(hashes))
+	counted := make([]mediaChunkRefs, 0, len(hashes))
+	for _, hash := range hashes {
+		if i, ok := positions[hash]; ok {
+			counted[i].Refs++
+
+			continue
+		}
+
+		positions[hash] = len(counted)
+		counted = append(counted, mediaChunkRefs{Hash: hash, Refs: 1})
+	}
+
+	return counted
+}
+
+// orderedHashes returns found hashes in order of requested hashes
+func orderedHashes(requested []string, found []string) []string {
+	set := make(map[string]struct{}, len(found))
+	for _, v := range found {
+		set[v] = struct{}{}
+	}
+
+	rs := make([]string, 0, len(found))
+	for _, v := range requested {
+		if _, ok := set[v]; ok {
+			rs = append(rs, v)
+		}
+	}
+
+	return rs
+}
+
+func (s *PSQLPlainStorage) AddPlainSecrets(ctx context.Context, userUUID string, secrets []NewPlainSecret) ([]PlainSecret, error) {
+	var created []PlainSecret
+	err := s.inTransaction(ctx, func(tx *PSQLPlainStorage) error {
+		var err error
+		created, err = tx.addPlainSecrets(ctx, userUUID, secrets)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *PSQLPlainStorage) addPlainSecrets(ctx context.Context, userUUID string, secrets []NewPlainSecret) ([]PlainSecret, error) {
+	created := make([]PlainSecret, 0, len(secrets))
+	for start := 0; start < len(secrets); start += bulkInsertRows {
+		chunk := secrets[start:min(start+bulkInsertRows, len(secrets))]
+
+		uuids := make([]string, len(chunk))
+		metadataValues := make([]string, len(chunk))
+		metadataArgs := make([]any, 0, len(chunk)*8)
+		dataValues := make([]string, len(chunk))
+		dataArgs := make([]any, 0, len(chunk)*2)
+		for i, v := range chunk {
+			uuids[i] = v.secretUUID()
+
+			metadataValues[i] = `(?, ?, ?, ?, ?, ?, ?, ?)`
+			metadataArgs = append(metadataArgs, uuids[i], userUUID, v.Name, v.EncryptedName, v.Type, v.Folder, v.ExpiresAt, v.MaxReads)
+
+			dataValues[i] = `(?, ?)`
+			dataArgs = append(dataArgs, uuids[i], v.Data)
+		}
+
+		var metadata []SecretMetadata
+		query := `INSERT INTO secret_metadata (uuid, owner_uuid, name, encrypted_name, type, folder, expires_at, max_reads) VALUES ` + strings.Join(metadataValues, `, `) + ` RETURNING ` + secretMetadataColumns
+		err := sqlx.SelectContext(ctx, s.q, &metadata, s.q.Rebind(query), metadataArgs...)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == postgrescodes.PostgresErrCodeUniqueViolation {
+				return nil, ErrEntityAlreadyExists
+			}
+
+			return nil, fmt.Errorf("cannot insert metadata of secrets: %w", err)
+		}
+
+		query = `INSERT INTO plain_secret (uuid, data) VALUES ` + strings.Join(dataValues, `, `)
+		_, err = s.q.ExecContext(ctx, s.q.Rebind(query), dataArgs...)
+		if err != nil {
+			return nil, fmt.Errorf("cannot insert data of secrets: %w", err)
+		}
+
+		// rows returned by insert are not ordered, so restore order of given secrets
+		positions := make(map[string]int, len(metadata))
+		for i, v := range metadata {
+			positions[v.UUID] = i
+		}
+
+		for i, v := range chunk {
+			created = append(created, PlainSecret{Metadata: metadata[positions[uuids[i]]], Data: v.Data})
+		}
+	}
+
+	return created, nil
+}
+
 func (s *PSQLPlainStorage) UpdatePlainSecretDataByName(ctx context.Context, ownerUUID string, name string, secretType SecretType, data []byte) error {
-	var secretUUID string
-	err := s.db.QueryRowContext(ctx, "SELECT uuid FROM secret_metadata WHERE owner_uuid = $1 AND name = $2 AND type = $3", ownerUUID, name, secretType).Scan(&secretUUID)
-	if err != nil && errors.Is(sql.ErrNoRows, err) {
-		return ErrEntityNotFound
+	return s.inTransaction(ctx, func(tx *PSQLPlainStorage) error {
+		var secretUUID string
+		err := tx.q.QueryRowxContext(ctx, "UPDATE secret_metadata SET updated = now() WHERE owner_uuid = $1 AND name = $2 AND type = $3 RETURNING uuid", ownerUUID, name, secretType).Scan(&secretUUID)
+		if err != nil && errors.Is(sql.ErrNoRows, err) {
+			return ErrEntityNotFound
+		} else if err != nil {
+			return fmt.Errorf("error while update metadata: %w", err)
+		}
+
+		if data == nil {
+			_, err = tx.q.ExecContext(ctx, "DELETE FROM plain_secret WHERE uuid = $1", secretUUID)
+		} else {
+			_, err = tx.q.ExecContext(ctx, "INSERT INTO plain_secret (uuid, data) VALUES ($1, $2) ON CONFLICT (uuid) DO UPDATE SET data = $2", secretUUID, data)
+		}
+
+		if err != nil {
+			return fmt.Errorf("cannot update secret data: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (s *PSQLPlainStorage) GetUserSecretByName(ctx context.Context, userUUID string, secretName string, secretType SecretType) (*PlainSecret, error) {
+	var md SecretMetadata
+	err := s.q.
+		QueryRowxContext(ctx, "SELECT "+secretMetadataColumns+" FROM secret_metadata WHERE owner_uuid = $1 AND name = $2 AND type = $3", userUUID, secretName, secretType).
+		StructScan(&md)
+
+	if errors.Is(sql.ErrNoRows, err) {
+		return nil, ErrEntityNotFound
 	} else if err != nil {
-		return fmt.Errorf("error while get secret metadata: %w", err)
+		return nil, fmt.Errorf("error while get secret metadata: %w", err)
+	}
+
+	var content []byte
+	err = s.q.QueryRowxContext(ctx, "SELECT data FROM plain_secret WHERE uuid = $1", md.UUID).Scan(&content)
+	if err != nil && !errors.Is(sql.ErrNoRows, err) {
+		return nil, fmt.Errorf("error while get secret content: %w", err)
 	}
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	mds := []SecretMetadata{md}
+	err = s.loadSecretsTags(ctx, mds)
 	if err != nil {
-		return fmt.Errorf("cannot start update transaction: %w", err)
+		return nil, fmt.Errorf("error while get secret tags: %w", err)
 	}
+	md = mds[0]
+
+	return &PlainSecret{
+		Metadata: md,
+		Data:     content,
+	}, nil
+}
 
-	_, err = s.db.ExecContext(ctx, "UPDATE secret_metadata SET updated = now() WHERE owner_uuid = $1 AND name = $2", ownerUUID, name)
+func (s *PSQLPlainStorage) GetExistingMediaChunks(ctx context.Context, userUUID string, hashes []string) ([]string, error) {
+	if len(hashes) == 0 {
+		return []string{}, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT hash FROM media_chunk WHERE owner_uuid = ? AND hash IN (?)`, userUUID, hashes)
 	if err != nil {
-		txErr := tx.Rollback()
-		if txErr != nil {
-			s.logger.Error("Cannot rollback update transaction", zap.Error(txErr))
+		return nil, fmt.Errorf("error preparing existing chunks query: %w", err)
+	}
+
+	var found []string
+	err = sqlx.SelectContext(ctx, s.q, &found, s.q.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot select existing chunks: %w", err)
+	}
+
+	return orderedHashes(hashes, found), nil
+}
+
+func (s *PSQLPlainStorage) AddMediaChunk(ctx context.Context, userUUID string, hash string, size int64) error {
+	_, err := s.q.ExecContext(ctx, `INSERT INTO media_chunk (owner_uuid, hash, size) VALUES ($1, $2, $3) ON CONFLICT (owner_uuid, hash) DO NOTHING`, userUUID, hash, size)
+	if err != nil {
+		return fmt.Errorf("cannot insert media chunk: %w", err)
+	}
+
+	return nil
+}
+
+func (s *PSQLPlainStorage) SetMediaChunks(ctx context.Context, userUUID string, mediaUUID string, hashes []string) error {
+	return s.inTransaction(ctx, func(tx *PSQLPlainStorage) error {
+		var count int
+		err := tx.q.QueryRowxContext(ctx, `SELECT count(*) FROM media_chunk_ref WHERE media_uuid = $1`, mediaUUID).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("cannot count chunks of media: %w", err)
+		}
+
+		if count > 0 {
+			return ErrEntityAlreadyExists
+		}
+
+		for _, v := range countHashes(hashes) {
+			res, err := tx.q.ExecContext(ctx, `UPDATE media_chunk SET refs = refs + $1 WHERE owner_uuid = $2 AND hash = $3`, v.Refs, userUUID, v.Hash)
+			if err != nil {
+				return fmt.Errorf("cannot increment references of chunk: %w", err)
+			}
+
+			affected, err := res.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("cannot get count of updated chunks: %w", err)
+			}
+
+			if affected == 0 {
+				return ErrEntityNotFound
+			}
 		}
 
-		return fmt.Errorf("error while update metadata: %w", err)
+		for start := 0; start < len(hashes); start += bulkInsertRows {
+			chunk := hashes[start:min(start+bulkInsertRows, len(hashes))]
+
+			values := make([]string, len(chunk))
+			args := make([]any, 0, len(chunk)*4)
+			for i, hash := range chunk {
+				values[i] = `(?, ?, ?, ?)`
+				args = append(args, mediaUUID, start+i, userUUID, hash)
+			}
+
+			_, err = tx.q.ExecContext(ctx, tx.q.Rebind(`INSERT INTO media_chunk_ref (media_uuid, position, owner_uuid, hash) VALUES `+strings.Join(values, `, `)), args...)
+			if err != nil {
+				return fmt.Errorf("cannot insert chunks of media: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *PSQLPlainStorage) GetUserUsage(ctx context.Context, userUUID string) (Usage, error) {
+	usage := Usage{}
+	err := sqlx.GetContext(ctx, s.q, &usage, `SELECT
+		count(*) FILTER (WHERE m.type = $2) AS media_count,
+		count(*) FILTER (WHERE m.type <> $2) AS plain_count,
+		COALESCE(sum(m.media_size) FILTER (WHERE m.type = $2 AND NOT EXISTS (SELECT 1 FROM media_chunk_ref r WHERE r.media_uuid = m.uuid)), 0)::bigint AS media_bytes,
+		COALESCE(sum(octet_length(p.data)), 0)::bigint AS plain_bytes
+		FROM secret_metadata m LEFT JOIN plain_secret p ON p.uuid = m.uuid
+		WHERE m.owner_uuid = $1`, userUUID, SecretTypeMedia)
+	if err != nil {
+		return Usage{}, fmt.Errorf("cannot select usage of secrets: %w", err)
 	}
 
-	if data == nil {
-		_, err = s.db.ExecContext(ctx, "DELETE FROM plain_secret WHERE uuid = $1", secretUUID)
-	} else {
-		_, err = s.db.ExecContext(ctx, "INSERT INTO plain_secret (uuid, data) VALUES ($1, $2) ON CONFLICT (uuid) DO UPDATE SET data = $2", secretUUID, data)
+	var chunksSize int64
+	err = s.q.QueryRowxContext(ctx, "SELECT COALESCE(sum(size), 0)::bigint FROM media_chunk WHERE owner_uuid = $1", userUUID).Scan(&chunksSize)
+	if err != nil {
+		return Usage{}, fmt.Errorf("cannot select size of media chunks: %w", err)
 	}
 
+	usage.MediaBytes += chunksSize
+
+	return usage, nil
+}
+
+func (s *PSQLPlainStorage) GetMediaChunks(ctx context.Context, mediaUUID string) ([]MediaChunk, error) {
+	chunks := make([]MediaChunk, 0)
+	err := sqlx.SelectContext(ctx, s.q, &chunks, `SELECT c.owner_uuid, c.hash, c.size, c.refs, c.created FROM media_chunk_ref r
+		JOIN media_chunk c ON c.owner_uuid = r.owner_uuid AND c.hash = r.hash
+		WHERE r.media_uuid = $1 ORDER BY r.position`, mediaUUID)
 	if err != nil {
-		txErr := tx.Rollback()
-		if txErr != nil {
-			s.logger.Error("Cannot rollback update transaction", zap.Error(txErr))
+		return nil, fmt.Errorf("cannot select chunks of media: %w", err)
+	}
+
+	return chunks, nil
+}
+
+func (s *PSQLPlainStorage) RemoveMediaChunks(ctx context.Context, mediaUUID string) ([]string, error) {
+	released := make([]string, 0)
+	err := s.inTransaction(ctx, func(tx *PSQLPlainStorage) error {
+		var refs []mediaChunkRefs
+		err := sqlx.SelectContext(ctx, tx.q, &refs, `SELECT owner_uuid, hash, count(*) AS refs FROM media_chunk_ref WHERE media_uuid = $1 GROUP BY owner_uuid, hash ORDER BY min(position)`, mediaUUID)
+		if err != nil {
+			return fmt.Errorf("cannot select chunks of media: %w", err)
+		}
+
+		_, err = tx.q.ExecContext(ctx, `DELETE FROM media_chunk_ref WHERE media_uuid = $1`, mediaUUID)
+		if err != nil {
+			return fmt.Errorf("cannot remove chunks of media: %w", err)
 		}
 
-		return fmt.Errorf("cannot update secret data: %w", err)
+		for _, v := range refs {
+			var left int
+			err = tx.q.QueryRowxContext(ctx, `UPDATE media_chunk SET refs = refs - $1 WHERE owner_uuid = $2 AND hash = $3 RETURNING refs`, v.Refs, v.OwnerUUID, v.Hash).Scan(&left)
+			if err != nil {
+				return fmt.Errorf("cannot decrement references of chunk: %w", err)
+			}
+
+			if left > 0 {
+				continue
+			}
+
+			_, err = tx.q.ExecContext(ctx, `DELETE FROM media_chunk WHERE owner_uuid = $1 AND hash = $2`, v.OwnerUUID, v.Hash)
+			if err != nil {
+				return fmt.Errorf("cannot remove unreferenced chunk: %w", err)
+			}
+
+			released = append(released, v.Hash)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return released, nil
+}
+
+func (s *PSQLPlainStorage) GetAllMediaChunks(ctx context.Context) ([]MediaChunk, error) {
+	chunks := make([]MediaChunk, 0)
+	err := sqlx.SelectContext(ctx, s.q, &chunks, `SELECT owner_uuid, hash, size, refs, created FROM media_chunk ORDER BY owner_uuid, hash`)
+	if err != nil {
+		return nil, fmt.Errorf("cannot select media chunks: %w", err)
 	}
 
-	err = tx.Commit()
+	return chunks, nil
+}
+
+func (s *PSQLPlainStorage) RemoveUnusedMediaChunk(ctx context.Context, userUUID string, hash string) error {
+	res, err := s.q.ExecContext(ctx, `DELETE FROM media_chunk WHERE owner_uuid = $1 AND hash = $2 AND refs = 0`, userUUID, hash)
+	if err != nil {
+		return fmt.Errorf("cannot remove unused chunk: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("cannot commit secret update: %w", err)
+		return fmt.Errorf("cannot get count of removed chunks: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrEntityNotFound
 	}
 
 	return nil
 }
 
-func (s *PSQLPlainStorage) GetUserSecretByName(ctx context.Context, userUUID string, secretName string, secretType SecretType) (*PlainSecret, error) {
-	var (
-		secretUUID       string
-		created, updated time.Time
+func (s *PSQLPlainStorage) GetObjectMediaMetadata(ctx context.Context) ([]SecretMetadata, error) {
+	secrets := make([]SecretMetadata, 0)
+	err := sqlx.SelectContext(
+		ctx,
+		s.q,
+		&secrets,
+		`SELECT `+secretMetadataColumns+` FROM secret_metadata
+		WHERE type = $1 AND NOT EXISTS (SELECT 1 FROM media_chunk_ref r WHERE r.media_uuid = secret_metadata.uuid)`,
+		SecretTypeMedia,
 	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get media secrets: %w", err)
+	}
 
-	err := s.db.
-		QueryRowContext(ctx, "SELECT uuid, created, updated FROM secret_metadata WHERE owner_uuid = $1 AND name = $2 AND type = $3", userUUID, secretName, secretType).
-		Scan(&secretUUID, &created, &updated)
+	return secrets, nil
+}
 
-	if errors.Is(sql.ErrNoRows, err) {
+func (s *PSQLPlainStorage) GetPlainSecretsData(ctx context.Context, afterUUID string, limit int) ([]SecretData, error) {
+	secrets := make([]SecretData, 0)
+	err := sqlx.SelectContext(ctx, s.q, &secrets, `SELECT uuid, data FROM plain_secret WHERE uuid::text > $1 ORDER BY uuid::text LIMIT $2`, afterUUID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("cannot select plain secrets data: %w", err)
+	}
+
+	return secrets, nil
+}
+
+func (s *PSQLPlainStorage) ReplacePlainSecretData(ctx context.Context, secretUUID string, old []byte, data []byte) error {
+	res, err := s.q.ExecContext(ctx, `UPDATE plain_secret SET data = $1 WHERE uuid = $2 AND data = $3`, data, secretUUID, old)
+	if err != nil {
+		return fmt.Errorf("cannot replace secret data: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("cannot get count of affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (s *PSQLPlainStorage) GetMediaObjectKey(ctx context.Context, objectKey string) (*MediaObjectKey, error) {
+	var key MediaObjectKey
+	err := sqlx.GetContext(ctx, s.q, &key, `SELECT object_key, kek_id, wrapped_key FROM media_object_key WHERE object_key = $1`, objectKey)
+	if errors.Is(err, sql.ErrNoRows) {
 		return nil, ErrEntityNotFound
 	} else if err != nil {
-		return nil, fmt.Errorf("error while get secret metadata: %w", err)
+		return nil, fmt.Errorf("cannot get media object key: %w", err)
 	}
 
-	var content []byte
-	err = s.db.QueryRowContext(ctx, "SELECT data FROM plain_secret WHERE uuid = $1", secretUUID).Scan(&content)
-	if err != nil && !errors.Is(sql.ErrNoRows, err) {
-		return nil, fmt.Errorf("error while get secret content: %w", err)
+	return &key, nil
+}
+
+func (s *PSQLPlainStorage) SetMediaObjectKey(ctx context.Context, key MediaObjectKey) error {
+	_, err := s.q.ExecContext(
+		ctx,
+		`INSERT INTO media_object_key (object_key, kek_id, wrapped_key) VALUES ($1, $2, $3)
+		ON CONFLICT (object_key) DO UPDATE SET kek_id = excluded.kek_id, wrapped_key = excluded.wrapped_key`,
+		key.ObjectKey,
+		key.KEKID,
+		key.WrappedKey,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot save media object key: %w", err)
 	}
 
-	return &PlainSecret{
-		Metadata: SecretMetadata{
-			UUID:     secretUUID,
-			UserUUID: userUUID,
-			Name:     secretName,
-			Type:     secretType,
-			Created:  created,
-			Updated:  updated,
-		},
-		Data: content,
-	}, nil
+	return nil
+}
+
+func (s *PSQLPlainStorage) RemoveMediaObjectKey(ctx context.Context, objectKey string) error {
+	res, err := s.q.ExecContext(ctx, `DELETE FROM media_object_key WHERE object_key = $1`, objectKey)
+	if err != nil {
+		return fmt.Errorf("cannot remove media object key: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("cannot get count of affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (s *PSQLPlainStorage) GetMediaObjectKeys(ctx context.Context, afterKey string, limit int) ([]MediaObjectKey, error) {
+	keys := make([]MediaObjectKey, 0)
+	err := sqlx.SelectContext(ctx, s.q, &keys, `SELECT object_key, kek_id, wrapped_key FROM media_object_key WHERE object_key > $1 ORDER BY object_key LIMIT $2`, afterKey, limit)
+	if err != nil {
+		return nil, fmt.Errorf("cannot select media object keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (s *PSQLPlainStorage) ReplaceMediaObjectKey(ctx context.Context, old MediaObjectKey, key MediaObjectKey) error {
+	res, err := s.q.ExecContext(
+		ctx,
+		`UPDATE media_object_key SET kek_id = $1, wrapped_key = $2 WHERE object_key = $3 AND wrapped_key = $4`,
+		key.KEKID,
+		key.WrappedKey,
+		old.ObjectKey,
+		old.WrappedKey,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot replace media object key: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("cannot get count of affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (s *PSQLPlainStorage) InSnapshot(ctx context.Context, read func(snapshot PlainStorage) error) error {
+	if s.tx != nil {
+		return read(s)
+	}
+
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("cannot start snapshot: %w", err)
+	}
+
+	// snapshot is read-only, so it's rolled back after read
+	defer tx.Rollback()
+
+	return read(&PSQLPlainStorage{config: s.config, db: s.db, q: tx, tx: tx, logger: s.logger})
+}
+
+func (s *PSQLPlainStorage) GetUsers(ctx context.Context, afterUUID string, limit int) ([]User, error) {
+	rows, err := s.q.QueryxContext(ctx, `SELECT uuid, login, password FROM users WHERE uuid::text > $1 ORDER BY uuid::text LIMIT $2`, afterUUID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("cannot select users: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]User, 0)
+	for rows.Next() {
+		var user User
+		if err = rows.Scan(&user.UUID, &user.Login, &user.PasswordHash); err != nil {
+			return nil, fmt.Errorf("cannot scan user: %w", err)
+		}
+
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read users: %w", err)
+	}
+
+	return users, nil
+}
+
+func (s *PSQLPlainStorage) GetSecrets(ctx context.Context, afterUUID string, limit int) ([]PlainSecret, error) {
+	var rows []struct {
+		SecretMetadata
+		Data []byte `db:"data"`
+	}
+
+	err := sqlx.SelectContext(
+		ctx,
+		s.q,
+		&rows,
+		`SELECT `+secretMetadataColumns+`, (SELECT data FROM plain_secret p WHERE p.uuid = secret_metadata.uuid) AS data
+		FROM secret_metadata WHERE uuid::text > $1 ORDER BY uuid::text LIMIT $2`,
+		afterUUID,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot select secrets: %w", err)
+	}
+
+	mds := make([]SecretMetadata, len(rows))
+	for i, v := range rows {
+		mds[i] = v.SecretMetadata
+	}
+
+	if err = s.loadSecretsTags(ctx, mds); err != nil {
+		return nil, fmt.Errorf("cannot load tags of secrets: %w", err)
+	}
+
+	secrets := make([]PlainSecret, len(rows))
+	for i, v := range rows {
+		secrets[i] = PlainSecret{Metadata: mds[i], Data: v.Data}
+	}
+
+	return secrets, nil
+}
+
+func (s *PSQLPlainStorage) RestoreUser(ctx context.Context, user User) error {
+	_, err := s.q.ExecContext(ctx, "INSERT INTO users (uuid, login, password) VALUES ($1, $2, $3)", user.UUID, user.Login, user.PasswordHash)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == postgrescodes.PostgresErrCodeUniqueViolation {
+			return ErrEntityAlreadyExists
+		}
+
+		return fmt.Errorf("cannot restore user: %w", err)
+	}
+
+	return nil
+}
+
+func (s *PSQLPlainStorage) RestoreSecret(ctx context.Context, secret PlainSecret) error {
+	return s.inTransaction(ctx, func(tx *PSQLPlainStorage) error {
+		md := secret.Metadata
+		_, err := tx.q.ExecContext(
+			ctx,
+			`INSERT INTO secret_metadata (`+secretMetadataColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+			md.UUID, md.UserUUID, md.Name, md.EncryptedName, md.Type, md.Created, md.Updated, md.ExpiresAt,
+			md.MaxReads, md.Reads, md.Folder, md.ContentHash, md.MediaSize, md.EncryptedMediaInfo,
+		)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == postgrescodes.PostgresErrCodeUniqueViolation {
+				return ErrEntityAlreadyExists
+			}
+
+			return fmt.Errorf("cannot restore secret metadata: %w", err)
+		}
+
+		for _, v := range md.Tags {
+			_, err = tx.q.ExecContext(ctx, "INSERT INTO secret_tag (secret_uuid, tag, encrypted_tag) VALUES ($1, $2, $3)", md.UUID, v.Tag, v.EncryptedTag)
+			if err != nil {
+				return fmt.Errorf("cannot restore secret tag: %w", err)
+			}
+		}
+
+		if secret.Data == nil {
+			return nil
+		}
+
+		_, err = tx.q.ExecContext(ctx, "INSERT INTO plain_secret (uuid, data) VALUES ($1, $2)", md.UUID, secret.Data)
+		if err != nil {
+			return fmt.Errorf("cannot restore secret data: %w", err)
+		}
+
+		return nil
+	})
 }
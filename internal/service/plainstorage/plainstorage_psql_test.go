@@ -0,0 +1,47 @@
+package plainstorage_test
+
+import (
+	"github.com/nessai1/gophkeeper/internal/service/config"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage/plainstoragetest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"os"
+	"testing"
+)
+
+// newTestPSQLStorage connects to psql from KEEPER_TEST_PSQL_* environment, skips test if KEEPER_TEST_PSQL_HOST is not set
+func newTestPSQLStorage(t *testing.T) *plainstorage.PSQLPlainStorage {
+	host := os.Getenv("KEEPER_TEST_PSQL_HOST")
+	if host == "" {
+		t.Skip("KEEPER_TEST_PSQL_HOST is not set")
+	}
+
+	defer chdirModuleRoot(t)()
+
+	storage, err := plainstorage.NewPSQLPlainStorage(config.PSQLPlainStorageConfig{
+		Host:     host,
+		Port:     os.Getenv("KEEPER_TEST_PSQL_PORT"),
+		User:     os.Getenv("KEEPER_TEST_PSQL_USER"),
+		Password: os.Getenv("KEEPER_TEST_PSQL_PASSWORD"),
+		DBName:   os.Getenv("KEEPER_TEST_PSQL_DBNAME"),
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	return storage
+}
+
+// chdirModuleRoot changes working directory to root of module, where migrations are loaded from. Returns restore func
+func chdirModuleRoot(t *testing.T) func() {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir("../../.."))
+
+	return func() {
+		require.NoError(t, os.Chdir(wd))
+	}
+}
+
+func TestPSQLPlainStorage(t *testing.T) {
+	plainstoragetest.TestPlainStorage(t, newTestPSQLStorage(t))
+}
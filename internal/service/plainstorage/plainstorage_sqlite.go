@@ -0,0 +1,1172 @@
+package plainstorage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/nessai1/gophkeeper/internal/service/config"
+	"go.uber.org/zap"
+	"net/url"
+	"strings"
+	"time"
+
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	sqlitedriver "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// sqliteBusyTimeout time in milliseconds that connection waits for lock of database held by another connection
+const sqliteBusyTimeout = 5000
+
+// SQLitePlainStorage plain storage in one SQLite file for single-node deployments.
+// All timestamps are written by storage in UTC, so they can be compared as text by SQLite
+type SQLitePlainStorage struct {
+	config config.SQLitePlainStorageConfig
+	db     *sqlx.DB
+	// q executes queries of storage: db or transaction of storage given to InTransaction callback
+	q sqlx.ExtContext
+	// tx transaction of storage, nil for storage out of transaction
+	tx *sqlx.Tx
+	// savepoints count of savepoints opened in transaction
+	savepoints int
+	logger     *zap.Logger
+}
+
+func NewSQLitePlainStorage(cfg config.SQLitePlainStorageConfig, l *zap.Logger) (*SQLitePlainStorage, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("path of sqlite database can't be empty")
+	}
+
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", sqliteBusyTimeout))
+	params.Set("_time_format", "sqlite")
+	// transactions take write lock at begin, so concurrent transactions wait for each other instead of deadlock on upgrade of lock
+	params.Set("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite", "file:"+cfg.Path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("cannot open sqlite database: %w", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		return nil, fmt.Errorf("cannot ping sqlite database: %w", err)
+	}
+
+	err = initSQLiteMigrations(db)
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return nil, fmt.Errorf("cannot init sqlite migrations: %w", err)
+	}
+
+	dbx := sqlx.NewDb(db, "sqlite")
+
+	return &SQLitePlainStorage{
+		config: cfg,
+		db:     dbx,
+		q:      dbx,
+		logger: l,
+	}, nil
+}
+
+func initSQLiteMigrations(db *sql.DB) error {
+	driver, err := sqlite.WithInstance(db, &sqlite.Config{})
+	if err != nil {
+		return err
+	}
+
+	migrations, err := migrate.NewWithDatabaseInstance("file:migrations/sqlite", "sqlite", driver)
+	if err != nil {
+		return fmt.Errorf("error while create migrate DB instance: %s", err.Error())
+	}
+
+	if err = migrations.Up(); err != nil {
+		return fmt.Errorf("cannot up migration: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes database of storage
+func (s *SQLitePlainStorage) Close() error {
+	return s.db.Close()
+}
+
+// InTransaction runs transaction callback with storage, that routes all queries through one transaction.
+// Nested calls on storage of transaction create savepoints, so error of nested callback rollbacks only its queries
+func (s *SQLitePlainStorage) InTransaction(ctx context.Context, transaction func(tx PlainStorage) error) error {
+	return s.inTransaction(ctx, func(tx *SQLitePlainStorage) error {
+		return transaction(tx)
+	})
+}
+
+func (s *SQLitePlainStorage) inTransaction(ctx context.Context, transaction func(tx *SQLitePlainStorage) error) error {
+	if s.tx != nil {
+		return s.inSavepoint(ctx, transaction)
+	}
+
+	tx, txErr := s.db.BeginTxx(ctx, nil)
+	if txErr != nil {
+		return fmt.Errorf("cannot start intransaction: %w", txErr)
+	}
+
+	err := transaction(&SQLitePlainStorage{config: s.config, db: s.db, q: tx, tx: tx, logger: s.logger})
+	if err != nil {
+		txErr := tx.Rollback()
+		if txErr != nil {
+			s.logger.Error("Cannot rollback intransaction", zap.Error(txErr))
+
+			return errors.Join(err, fmt.Errorf("cannot rollback intransaction: %w", txErr))
+		}
+
+		return err
+	}
+
+	txErr = tx.Commit()
+	if txErr != nil {
+		s.logger.Error("Cannot commit intransaction", zap.Error(txErr))
+
+		return fmt.Errorf("cannot commit intransaction: %w", txErr)
+	}
+
+	return nil
+}
+
+func (s *SQLitePlainStorage) inSavepoint(ctx context.Context, transaction func(tx *SQLitePlainStorage) error) error {
+	s.savepoints++
+	savepoint := fmt.Sprintf("savepoint_%d", s.savepoints)
+
+	_, spErr := s.tx.ExecContext(ctx, "SAVEPOINT "+savepoint)
+	if spErr != nil {
+		return fmt.Errorf("cannot create savepoint: %w", spErr)
+	}
+
+	err := transaction(s)
+	if err != nil {
+		_, spErr := s.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint)
+		if spErr != nil {
+			s.logger.Error("Cannot rollback to savepoint", zap.Error(spErr))
+
+			return errors.Join(err, fmt.Errorf("cannot rollback to savepoint: %w", spErr))
+		}
+
+		return err
+	}
+
+	_, spErr = s.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint)
+	if spErr != nil {
+		return fmt.Errorf("cannot release savepoint: %w", spErr)
+	}
+
+	return nil
+}
+
+func (s *SQLitePlainStorage) GetUserByLogin(ctx context.Context, login string) (*User, error) {
+	var user User
+	err := s.q.QueryRowxContext(
+		ctx,
+		"SELECT uuid, login, password FROM users WHERE login = ?",
+		login,
+	).Scan(&user.UUID, &user.Login, &user.PasswordHash)
+
+	if err != nil && errors.Is(sql.ErrNoRows, err) {
+		return nil, ErrEntityNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot get user by login: %w", err)
+	}
+
+	return &user, nil
+}
+
+func (s *SQLitePlainStorage) GetUserByUUID(ctx context.Context, uuid string) (*User, error) {
+	var user User
+	err := s.q.QueryRowxContext(
+		ctx,
+		"SELECT uuid, login, password FROM users WHERE uuid = ?",
+		uuid,
+	).Scan(&user.UUID, &user.Login, &user.PasswordHash)
+
+	if err != nil && errors.Is(sql.ErrNoRows, err) {
+		return nil, ErrEntityNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot get user by uuid: %w", err)
+	}
+
+	return &user, nil
+}
+
+func (s *SQLitePlainStorage) CreateUser(ctx context.Context, login string, password string) (*User, error) {
+	userUUID := uuid.New().String()
+	_, err := s.q.ExecContext(ctx, "INSERT INTO users (uuid, login, password) VALUES (?, ?, ?)", userUUID, login, password)
+
+	if err != nil {
+		if isSQLiteUniqueViolation(err) {
+			return nil, ErrEntityAlreadyExists
+		}
+
+		return nil, fmt.Errorf("cannot create user: %w", err)
+	}
+
+	return &User{
+		UUID:         userUUID,
+		Login:        login,
+		PasswordHash: password,
+	}, nil
+}
+
+func (s *SQLitePlainStorage) GetUserSecretsMetadata(ctx context.Context, userUUID string, filter SecretFilter, page SecretPage) ([]SecretMetadata, error) {
+	query := `SELECT ` + secretMetadataColumns + ` FROM secret_metadata WHERE owner_uuid = ?`
+	args := []any{userUUID}
+
+	if !filter.AnyType {
+		query += ` AND type = ?`
+		args = append(args, filter.Type)
+	}
+
+	if filter.Folder != "" {
+		query += ` AND folder = ?`
+		args = append(args, filter.Folder)
+	}
+
+	if filter.Tag != "" {
+		query += ` AND uuid IN (SELECT secret_uuid FROM secret_tag WHERE tag = ?)`
+		args = append(args, filter.Tag)
+	}
+
+	if filter.Names != nil {
+		if len(filter.Names) == 0 {
+			return []SecretMetadata{}, nil
+		}
+
+		query += ` AND name IN (?` + strings.Repeat(`, ?`, len(filter.Names)-1) + `)`
+		for _, v := range filter.Names {
+			args = append(args, v)
+		}
+	}
+
+	bounds := []struct {
+		condition string
+		value     time.Time
+	}{
+		{` AND created > ?`, filter.CreatedAfter},
+		{` AND created < ?`, filter.CreatedBefore},
+		{` AND updated > ?`, filter.UpdatedAfter},
+		{` AND updated < ?`, filter.UpdatedBefore},
+	}
+	for _, v := range bounds {
+		if !v.value.IsZero() {
+			query += v.condition
+			args = append(args, v.value.UTC())
+		}
+	}
+
+	if !filter.ActiveAt.IsZero() {
+		query += ` AND (expires_at IS NULL OR expires_at > ?) AND (max_reads = 0 OR reads < max_reads)`
+		args = append(args, filter.ActiveAt.UTC())
+	}
+
+	sortColumn := "created"
+	if page.Sort == SecretSortUpdated {
+		sortColumn = "updated"
+	}
+
+	direction, comparison := "ASC", ">"
+	if page.Descending {
+		direction, comparison = "DESC", "<"
+	}
+
+	if page.After != nil {
+		sortValue := page.After.Created.UTC()
+		if page.Sort == SecretSortUpdated {
+			sortValue = page.After.Updated.UTC()
+		}
+
+		query += fmt.Sprintf(` AND (%s, uuid) %s (?, ?)`, sortColumn, comparison)
+		args = append(args, sortValue, page.After.UUID)
+	}
+
+	query += fmt.Sprintf(` ORDER BY %s %s, uuid %s`, sortColumn, direction, direction)
+
+	if page.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, page.Limit)
+	}
+
+	var secrets []SecretMetadata
+	err := sqlx.SelectContext(ctx, s.q, &secrets, query, args...)
+	if err != nil {
+		s.logger.Error("Error while get list of secrets", zap.Error(err))
+
+		return nil, fmt.Errorf("error while get list of secrets")
+	}
+
+	err = s.loadSecretsTags(ctx, secrets)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load tags of secrets list: %w", err)
+	}
+
+	return secrets, nil
+}
+
+// loadSecretsTags fills tags of secrets by one query
+func (s *SQLitePlainStorage) loadSecretsTags(ctx context.Context, secrets []SecretMetadata) error {
+	if len(secrets) == 0 {
+		return nil
+	}
+
+	positions := make(map[string]int, len(secrets))
+	uuids := make([]string, len(secrets))
+	for i, v := range secrets {
+		positions[v.UUID] = i
+		uuids[i] = v.UUID
+	}
+
+	query, args, err := sqlx.In(`SELECT secret_uuid, tag, encrypted_tag FROM secret_tag WHERE secret_uuid IN (?) ORDER BY tag`, uuids)
+	if err != nil {
+		return fmt.Errorf("error preparing secret tags query: %w", err)
+	}
+
+	var tags []struct {
+		SecretUUID string `db:"secret_uuid"`
+		SecretTag
+	}
+
+	err = sqlx.SelectContext(ctx, s.q, &tags, query, args...)
+	if err != nil {
+		return fmt.Errorf("cannot select secret tags: %w", err)
+	}
+
+	for _, v := range tags {
+		pos := positions[v.SecretUUID]
+		secrets[pos].Tags = append(secrets[pos].Tags, v.SecretTag)
+	}
+
+	return nil
+}
+
+func (s *SQLitePlainStorage) AddSecretMetadata(ctx context.Context, userUUID string, secretUUID, name string, encryptedName []byte, dataType SecretType) (*SecretMetadata, error) {
+	now := time.Now().UTC()
+	_, err := s.q.ExecContext(
+		ctx,
+		"INSERT INTO secret_metadata (uuid, owner_uuid, name, encrypted_name, type, created, updated) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		secretUUID, userUUID, name, encryptedName, dataType, now, now,
+	)
+
+	if err != nil {
+		if isSQLiteUniqueViolation(err) {
+			return nil, ErrEntityAlreadyExists
+		}
+
+		return nil, fmt.Errorf("cannot create secret metadata: %w", err)
+	}
+
+	return &SecretMetadata{
+		UUID:          secretUUID,
+		UserUUID:      userUUID,
+		Name:          name,
+		EncryptedName: encryptedName,
+		Type:          dataType,
+		Created:       now,
+		Updated:       now,
+	}, nil
+}
+
+func (s *SQLitePlainStorage) UpdateSecretMetadataUUID(ctx context.Context, userUUID string, oldUUID string, newUUID string, dataType SecretType) error {
+	res, err := s.q.ExecContext(ctx, "UPDATE secret_metadata SET uuid = ?, updated = ? WHERE owner_uuid = ? AND type = ? AND uuid = ?", newUUID, time.Now().UTC(), userUUID, dataType, oldUUID)
+	if err != nil {
+		return fmt.Errorf("cannot make update query: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("cannot get count of updated secrets: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (s *SQLitePlainStorage) RenameSecret(ctx context.Context, secretUUID string, name string, encryptedName []byte, dataType SecretType) error {
+	res, err := s.q.ExecContext(ctx, "UPDATE secret_metadata SET name = ?, encrypted_name = ?, type = ?, updated = ? WHERE uuid = ?", name, encryptedName, dataType, time.Now().UTC(), secretUUID)
+	if err != nil {
+		if isSQLiteUniqueViolation(err) {
+			return ErrEntityAlreadyExists
+		}
+
+		return fmt.Errorf("cannot update secret name: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("cannot get count of renamed secrets: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (s *SQLitePlainStorage) SetSecretLimits(ctx context.Context, secretUUID string, expiresAt *time.Time, maxReads int) error {
+	res, err := s.q.ExecContext(ctx, "UPDATE secret_metadata SET expires_at = ?, max_reads = ?, reads = 0 WHERE uuid = ?", utcTime(expiresAt), maxReads, secretUUID)
+	if err != nil {
+		return fmt.Errorf("cannot set secret limits: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("cannot get count of limited secrets: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (s *SQLitePlainStorage) RegisterSecretRead(ctx context.Context, secretUUID string) (*SecretMetadata, error) {
+	var md SecretMetadata
+	err := s.q.QueryRowxContext(
+		ctx,
+		`UPDATE secret_metadata SET reads = reads + 1
+		WHERE uuid = ? AND (expires_at IS NULL OR expires_at > ?) AND (max_reads = 0 OR reads < max_reads)
+		RETURNING `+secretMetadataColumns,
+		secretUUID, time.Now().UTC(),
+	).StructScan(&md)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrEntityNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot register secret read: %w", err)
+	}
+
+	return &md, nil
+}
+
+func (s *SQLitePlainStorage) GetExpiredSecretsMetadata(ctx context.Context, moment time.Time) ([]SecretMetadata, error) {
+	var secrets []SecretMetadata
+	err := sqlx.SelectContext(
+		ctx,
+		s.q,
+		&secrets,
+		`SELECT `+secretMetadataColumns+` FROM secret_metadata
+		WHERE expires_at <= ? OR (max_reads > 0 AND reads >= max_reads)`,
+		moment.UTC(),
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("cannot get expired secrets: %w", err)
+	}
+
+	return secrets, nil
+}
+
+func (s *SQLitePlainStorage) SetSecretFolder(ctx context.Context, secretUUID string, folder string) error {
+	res, err := s.q.ExecContext(ctx, "UPDATE secret_metadata SET folder = ?, updated = ? WHERE uuid = ?", folder, time.Now().UTC(), secretUUID)
+	if err != nil {
+		return fmt.Errorf("cannot set secret folder: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("cannot get count of moved secrets: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (s *SQLitePlainStorage) SetMediaInfo(ctx context.Context, secretUUID string, info MediaInfo) error {
+	res, err := s.q.ExecContext(ctx, "UPDATE secret_metadata SET content_hash = ?, media_size = ?, encrypted_media_info = ? WHERE uuid = ?", info.ContentHash, info.Size, info.EncryptedInfo, secretUUID)
+	if err != nil {
+		return fmt.Errorf("cannot set media info: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("cannot get count of updated secrets: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (s *SQLitePlainStorage) SetSecretTags(ctx context.Context, secretUUID string, tags []SecretTag) error {
+	return s.inTransaction(ctx, func(tx *SQLitePlainStorage) error {
+		res, err := tx.q.ExecContext(ctx, "UPDATE secret_metadata SET updated = ? WHERE uuid = ?", time.Now().UTC(), secretUUID)
+		if err != nil {
+			return fmt.Errorf("cannot update secret metadata: %w", err)
+		}
+
+		rowsAffected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("cannot get count of tagged secrets: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return ErrEntityNotFound
+		}
+
+		_, err = tx.q.ExecContext(ctx, "DELETE FROM secret_tag WHERE secret_uuid = ?", secretUUID)
+		if err != nil {
+			return fmt.Errorf("cannot remove old secret tags: %w", err)
+		}
+
+		for _, v := range tags {
+			_, err = tx.q.ExecContext(ctx, "INSERT INTO secret_tag (secret_uuid, tag, encrypted_tag) VALUES (?, ?, ?) ON CONFLICT DO NOTHING", secretUUID, v.Tag, v.EncryptedTag)
+			if err != nil {
+				return fmt.Errorf("cannot insert secret tag: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *SQLitePlainStorage) RemoveSecretByUUID(ctx context.Context, secretUUID string) error {
+	return s.RemoveSecretsByUUID(ctx, []string{secretUUID})
+}
+
+func (s *SQLitePlainStorage) RemoveSecretsByUUID(ctx context.Context, secretUUIDs []string) error {
+	if len(secretUUIDs) == 0 {
+		return nil
+	}
+
+	return s.inTransaction(ctx, func(tx *SQLitePlainStorage) error {
+		for _, table := range []string{"plain_secret", "secret_metadata"} {
+			query, args, err := sqlx.In(`DELETE FROM `+table+` WHERE uuid IN (?)`, secretUUIDs)
+			if err != nil {
+				return fmt.Errorf("error preparing remove secrets query: %w", err)
+			}
+
+			_, err = tx.q.ExecContext(ctx, query, args...)
+			if err != nil {
+				return fmt.Errorf("cannot remove secrets from %s: %w", table, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *SQLitePlainStorage) AddPlainSecret(ctx context.Context, userUUID string, name string, encryptedName []byte, dataType SecretType, data []byte) (*PlainSecret, error) {
+	var md *SecretMetadata
+	err := s.inTransaction(ctx, func(tx *SQLitePlainStorage) error {
+		var err error
+		md, err = tx.AddSecretMetadata(ctx, userUUID, uuid.New().String(), name, encryptedName, dataType)
+		if err != nil && errors.Is(ErrEntityAlreadyExists, err) {
+			return ErrEntityAlreadyExists
+		} else if err != nil {
+			return fmt.Errorf("cannot create metadata of plain secret: %w", err)
+		}
+
+		_, err = tx.q.ExecContext(ctx, "INSERT INTO plain_secret (uuid, data) VALUES (?, ?)", md.UUID, data)
+		if err != nil {
+			return fmt.Errorf("cannot insert plain secret data to table: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &PlainSecret{
+		Metadata: *md,
+		Data:     data,
+	}, nil
+}
+
+func (s *SQLitePlainStorage) AddPlainSecrets(ctx context.Context, userUUID string, secrets []NewPlainSecret) ([]PlainSecret, error) {
+	var created []PlainSecret
+	err := s.inTransaction(ctx, func(tx *SQLitePlainStorage) error {
+		var err error
+		created, err = tx.addPlainSecrets(ctx, userUUID, secrets)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *SQLitePlainStorage) addPlainSecrets(ctx context.Context, userUUID string, secrets []NewPlainSecret) ([]PlainSecret, error) {
+	now := time.Now().UTC()
+	created := make([]PlainSecret, 0, len(secrets))
+	for start := 0; start < len(secrets); start += bulkInsertRows {
+		chunk := secrets[start:min(start+bulkInsertRows, len(secrets))]
+
+		uuids := make([]string, len(chunk))
+		metadataValues := make([]string, len(chunk))
+		metadataArgs := make([]any, 0, len(chunk)*10)
+		dataValues := make([]string, len(chunk))
+		dataArgs := make([]any, 0, len(chunk)*2)
+		for i, v := range chunk {
+			uuids[i] = v.secretUUID()
+
+			metadataValues[i] = `(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+			metadataArgs = append(metadataArgs, uuids[i], userUUID, v.Name, v.EncryptedName, v.Type, v.Folder, utcTime(v.ExpiresAt), v.MaxReads, now, now)
+
+			dataValues[i] = `(?, ?)`
+			dataArgs = append(dataArgs, uuids[i], v.Data)
+		}
+
+		var metadata []SecretMetadata
+		query := `INSERT INTO secret_metadata (uuid, owner_uuid, name, encrypted_name, type, folder, expires_at, max_reads, created, updated) VALUES ` + strings.Join(metadataValues, `, `) + ` RETURNING ` + secretMetadataColumns
+		err := sqlx.SelectContext(ctx, s.q, &metadata, query, metadataArgs...)
+		if err != nil {
+			if isSQLiteUniqueViolation(err) {
+				return nil, ErrEntityAlreadyExists
+			}
+
+			return nil, fmt.Errorf("cannot insert metadata of secrets: %w", err)
+		}
+
+		query = `INSERT INTO plain_secret (uuid, data) VALUES ` + strings.Join(dataValues, `, `)
+		_, err = s.q.ExecContext(ctx, query, dataArgs...)
+		if err != nil {
+			return nil, fmt.Errorf("cannot insert data of secrets: %w", err)
+		}
+
+		// rows returned by insert are not ordered, so restore order of given secrets
+		positions := make(map[string]int, len(metadata))
+		for i, v := range metadata {
+			positions[v.UUID] = i
+		}
+
+		for i, v := range chunk {
+			created = append(created, PlainSecret{Metadata: metadata[positions[uuids[i]]], Data: v.Data})
+		}
+	}
+
+	return created, nil
+}
+
+func (s *SQLitePlainStorage) UpdatePlainSecretDataByName(ctx context.Context, ownerUUID string, name string, secretType SecretType, data []byte) error {
+	return s.inTransaction(ctx, func(tx *SQLitePlainStorage) error {
+		var secretUUID string
+		err := tx.q.QueryRowxContext(ctx, "UPDATE secret_metadata SET updated = ? WHERE owner_uuid = ? AND name = ? AND type = ? RETURNING uuid", time.Now().UTC(), ownerUUID, name, secretType).Scan(&secretUUID)
+		if err != nil && errors.Is(sql.ErrNoRows, err) {
+			return ErrEntityNotFound
+		} else if err != nil {
+			return fmt.Errorf("error while update metadata: %w", err)
+		}
+
+		if data == nil {
+			_, err = tx.q.ExecContext(ctx, "DELETE FROM plain_secret WHERE uuid = ?", secretUUID)
+		} else {
+			_, err = tx.q.ExecContext(ctx, "INSERT INTO plain_secret (uuid, data) VALUES (?, ?) ON CONFLICT (uuid) DO UPDATE SET data = excluded.data", secretUUID, data)
+		}
+
+		if err != nil {
+			return fmt.Errorf("cannot update secret data: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (s *SQLitePlainStorage) GetUserSecretByName(ctx context.Context, userUUID string, secretName string, secretType SecretType) (*PlainSecret, error) {
+	var md SecretMetadata
+	err := s.q.
+		QueryRowxContext(ctx, "SELECT "+secretMetadataColumns+" FROM secret_metadata WHERE owner_uuid = ? AND name = ? AND type = ?", userUUID, secretName, secretType).
+		StructScan(&md)
+
+	if errors.Is(sql.ErrNoRows, err) {
+		return nil, ErrEntityNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("error while get secret metadata: %w", err)
+	}
+
+	var content []byte
+	err = s.q.QueryRowxContext(ctx, "SELECT data FROM plain_secret WHERE uuid = ?", md.UUID).Scan(&content)
+	if err != nil && !errors.Is(sql.ErrNoRows, err) {
+		return nil, fmt.Errorf("error while get secret content: %w", err)
+	}
+
+	mds := []SecretMetadata{md}
+	err = s.loadSecretsTags(ctx, mds)
+	if err != nil {
+		return nil, fmt.Errorf("error while get secret tags: %w", err)
+	}
+	md = mds[0]
+
+	return &PlainSecret{
+		Metadata: md,
+		Data:     content,
+	}, nil
+}
+
+// isSQLiteUniqueViolation checks that error is violation of unique or primary key constraint
+func isSQLiteUniqueViolation(err error) bool {
+	var sqliteErr *sqlitedriver.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+
+	return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE || sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
+
+// utcTime converts optional time to UTC, keeps nil as is
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	converted := t.UTC()
+
+	return &converted
+}
+
+func (s *SQLitePlainStorage) GetExistingMediaChunks(ctx context.Context, userUUID string, hashes []string) ([]string, error) {
+	if len(hashes) == 0 {
+		return []string{}, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT hash FROM media_chunk WHERE owner_uuid = ? AND hash IN (?)`, userUUID, hashes)
+	if err != nil {
+		return nil, fmt.Errorf("error preparing existing chunks query: %w", err)
+	}
+
+	var found []string
+	err = sqlx.SelectContext(ctx, s.q, &found, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot select existing chunks: %w", err)
+	}
+
+	return orderedHashes(hashes, found), nil
+}
+
+func (s *SQLitePlainStorage) AddMediaChunk(ctx context.Context, userUUID string, hash string, size int64) error {
+	_, err := s.q.ExecContext(ctx, `INSERT INTO media_chunk (owner_uuid, hash, size, created) VALUES (?, ?, ?, ?) ON CONFLICT (owner_uuid, hash) DO NOTHING`, userUUID, hash, size, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("cannot insert media chunk: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SQLitePlainStorage) SetMediaChunks(ctx context.Context, userUUID string, mediaUUID string, hashes []string) error {
+	return s.inTransaction(ctx, func(tx *SQLitePlainStorage) error {
+		var count int
+		err := tx.q.QueryRowxContext(ctx, `SELECT count(*) FROM media_chunk_ref WHERE media_uuid = ?`, mediaUUID).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("cannot count chunks of media: %w", err)
+		}
+
+		if count > 0 {
+			return ErrEntityAlreadyExists
+		}
+
+		for _, v := range countHashes(hashes) {
+			res, err := tx.q.ExecContext(ctx, `UPDATE media_chunk SET refs = refs + ? WHERE owner_uuid = ? AND hash = ?`, v.Refs, userUUID, v.Hash)
+			if err != nil {
+				return fmt.Errorf("cannot increment references of chunk: %w", err)
+			}
+
+			affected, err := res.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("cannot get count of updated chunks: %w", err)
+			}
+
+			if affected == 0 {
+				return ErrEntityNotFound
+			}
+		}
+
+		for start := 0; start < len(hashes); start += bulkInsertRows {
+			chunk := hashes[start:min(start+bulkInsertRows, len(hashes))]
+
+			values := make([]string, len(chunk))
+			args := make([]any, 0, len(chunk)*4)
+			for i, hash := range chunk {
+				values[i] = `(?, ?, ?, ?)`
+				args = append(args, mediaUUID, start+i, userUUID, hash)
+			}
+
+			_, err = tx.q.ExecContext(ctx, `INSERT INTO media_chunk_ref (media_uuid, position, owner_uuid, hash) VALUES `+strings.Join(values, `, `), args...)
+			if err != nil {
+				return fmt.Errorf("cannot insert chunks of media: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *SQLitePlainStorage) GetUserUsage(ctx context.Context, userUUID string) (Usage, error) {
+	usage := Usage{}
+	err := sqlx.GetContext(ctx, s.q, &usage, `SELECT
+		count(*) FILTER (WHERE m.type = ?) AS media_count,
+		count(*) FILTER (WHERE m.type <> ?) AS plain_count,
+		COALESCE(sum(m.media_size) FILTER (WHERE m.type = ? AND NOT EXISTS (SELECT 1 FROM media_chunk_ref r WHERE r.media_uuid = m.uuid)), 0) AS media_bytes,
+		COALESCE(sum(length(p.data)), 0) AS plain_bytes
+		FROM secret_metadata m LEFT JOIN plain_secret p ON p.uuid = m.uuid
+		WHERE m.owner_uuid = ?`, SecretTypeMedia, SecretTypeMedia, SecretTypeMedia, userUUID)
+	if err != nil {
+		return Usage{}, fmt.Errorf("cannot select usage of secrets: %w", err)
+	}
+
+	var chunksSize int64
+	err = s.q.QueryRowxContext(ctx, "SELECT COALESCE(sum(size), 0) FROM media_chunk WHERE owner_uuid = ?", userUUID).Scan(&chunksSize)
+	if err != nil {
+		return Usage{}, fmt.Errorf("cannot select size of media chunks: %w", err)
+	}
+
+	usage.MediaBytes += chunksSize
+
+	return usage, nil
+}
+
+func (s *SQLitePlainStorage) GetMediaChunks(ctx context.Context, mediaUUID string) ([]MediaChunk, error) {
+	chunks := make([]MediaChunk, 0)
+	err := sqlx.SelectContext(ctx, s.q, &chunks, `SELECT c.owner_uuid, c.hash, c.size, c.refs, c.created FROM media_chunk_ref r
+		JOIN media_chunk c ON c.owner_uuid = r.owner_uuid AND c.hash = r.hash
+		WHERE r.media_uuid = ? ORDER BY r.position`, mediaUUID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot select chunks of media: %w", err)
+	}
+
+	return chunks, nil
+}
+
+func (s *SQLitePlainStorage) RemoveMediaChunks(ctx context.Context, mediaUUID string) ([]string, error) {
+	released := make([]string, 0)
+	err := s.inTransaction(ctx, func(tx *SQLitePlainStorage) error {
+		var refs []mediaChunkRefs
+		err := sqlx.SelectContext(ctx, tx.q, &refs, `SELECT owner_uuid, hash, count(*) AS refs FROM media_chunk_ref WHERE media_uuid = ? GROUP BY owner_uuid, hash ORDER BY min(position)`, mediaUUID)
+		if err != nil {
+			return fmt.Errorf("cannot select chunks of media: %w", err)
+		}
+
+		_, err = tx.q.ExecContext(ctx, `DELETE FROM media_chunk_ref WHERE media_uuid = ?`, mediaUUID)
+		if err != nil {
+			return fmt.Errorf("cannot remove chunks of media: %w", err)
+		}
+
+		for _, v := range refs {
+			var left int
+			err = tx.q.QueryRowxContext(ctx, `UPDATE media_chunk SET refs = refs - ? WHERE owner_uuid = ? AND hash = ? RETURNING refs`, v.Refs, v.OwnerUUID, v.Hash).Scan(&left)
+			if err != nil {
+				return fmt.Errorf("cannot decrement references of chunk: %w", err)
+			}
+
+			if left > 0 {
+				continue
+			}
+
+			_, err = tx.q.ExecContext(ctx, `DELETE FROM media_chunk WHERE owner_uuid = ? AND hash = ?`, v.OwnerUUID, v.Hash)
+			if err != nil {
+				return fmt.Errorf("cannot remove unreferenced chunk: %w", err)
+			}
+
+			released = append(released, v.Hash)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return released, nil
+}
+
+func (s *SQLitePlainStorage) GetAllMediaChunks(ctx context.Context) ([]MediaChunk, error) {
+	chunks := make([]MediaChunk, 0)
+	err := sqlx.SelectContext(ctx, s.q, &chunks, `SELECT owner_uuid, hash, size, refs, created FROM media_chunk ORDER BY owner_uuid, hash`)
+	if err != nil {
+		return nil, fmt.Errorf("cannot select media chunks: %w", err)
+	}
+
+	return chunks, nil
+}
+
+func (s *SQLitePlainStorage) RemoveUnusedMediaChunk(ctx context.Context, userUUID string, hash string) error {
+	res, err := s.q.ExecContext(ctx, `DELETE FROM media_chunk WHERE owner_uuid = ? AND hash = ? AND refs = 0`, userUUID, hash)
+	if err != nil {
+		return fmt.Errorf("cannot remove unused chunk: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("cannot get count of removed chunks: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (s *SQLitePlainStorage) GetObjectMediaMetadata(ctx context.Context) ([]SecretMetadata, error) {
+	secrets := make([]SecretMetadata, 0)
+	err := sqlx.SelectContext(
+		ctx,
+		s.q,
+		&secrets,
+		`SELECT `+secretMetadataColumns+` FROM secret_metadata
+		WHERE type = ? AND NOT EXISTS (SELECT 1 FROM media_chunk_ref r WHERE r.media_uuid = secret_metadata.uuid)`,
+		SecretTypeMedia,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get media secrets: %w", err)
+	}
+
+	return secrets, nil
+}
+
+func (s *SQLitePlainStorage) GetPlainSecretsData(ctx context.Context, afterUUID string, limit int) ([]SecretData, error) {
+	secrets := make([]SecretData, 0)
+	err := sqlx.SelectContext(ctx, s.q, &secrets, `SELECT uuid, data FROM plain_secret WHERE uuid > ? ORDER BY uuid LIMIT ?`, afterUUID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("cannot select plain secrets data: %w", err)
+	}
+
+	return secrets, nil
+}
+
+func (s *SQLitePlainStorage) ReplacePlainSecretData(ctx context.Context, secretUUID string, old []byte, data []byte) error {
+	res, err := s.q.ExecContext(ctx, `UPDATE plain_secret SET data = ? WHERE uuid = ? AND data = ?`, data, secretUUID, old)
+	if err != nil {
+		return fmt.Errorf("cannot replace secret data: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("cannot get count of affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (s *SQLitePlainStorage) GetMediaObjectKey(ctx context.Context, objectKey string) (*MediaObjectKey, error) {
+	var key MediaObjectKey
+	err := sqlx.GetContext(ctx, s.q, &key, `SELECT object_key, kek_id, wrapped_key FROM media_object_key WHERE object_key = ?`, objectKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrEntityNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot get media object key: %w", err)
+	}
+
+	return &key, nil
+}
+
+func (s *SQLitePlainStorage) SetMediaObjectKey(ctx context.Context, key MediaObjectKey) error {
+	_, err := s.q.ExecContext(
+		ctx,
+		`INSERT INTO media_object_key (object_key, kek_id, wrapped_key) VALUES (?, ?, ?)
+		ON CONFLICT (object_key) DO UPDATE SET kek_id = excluded.kek_id, wrapped_key = excluded.wrapped_key`,
+		key.ObjectKey,
+		key.KEKID,
+		key.WrappedKey,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot save media object key: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SQLitePlainStorage) RemoveMediaObjectKey(ctx context.Context, objectKey string) error {
+	res, err := s.q.ExecContext(ctx, `DELETE FROM media_object_key WHERE object_key = ?`, objectKey)
+	if err != nil {
+		return fmt.Errorf("cannot remove media object key: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("cannot get count of affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrEntityNotFound
+	}
+
+	return nil
+}
+
+func (s *SQLitePlainStorage) GetMediaObjectKeys(ctx context.Context, afterKey string, limit int) ([]MediaObjectKey, error) {
+	keys := make([]MediaObjectKey, 0)
+	err := sqlx.SelectContext(ctx, s.q, &keys, `SELECT object_key, kek_id, wrapped_key FROM media_object_key WHERE object_key > ? ORDER BY object_key LIMIT ?`, afterKey, limit)
+	if err != nil {
+		return nil, fmt.Errorf("cannot select media object keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (s *SQLitePlainStorage) ReplaceMediaObjectKey(ctx context.Context, old MediaObjectKey, key MediaObjectKey) error {
+	res, err := s.q.ExecContext(
+		ctx,
+		`UPDATE media_object_key SET kek_id = ?, wrapped_key = ? WHERE object_key = ? AND wrapped_key = ?`,
+		key.KEKID,
+		key.WrappedKey,
+		old.ObjectKey,
+		old.WrappedKey,
+	)
+	if err != nil {
+		return fmt.Errorf("cannot replace media object key: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("cannot get count of affected rows: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return ErrEntityNotFound
+	}
+
+	return nil
+}
+
+// InSnapshot runs read callback in transaction, transaction of WAL database reads snapshot taken by its first read
+func (s *SQLitePlainStorage) InSnapshot(ctx context.Context, read func(snapshot PlainStorage) error) error {
+	if s.tx != nil {
+		return read(s)
+	}
+
+	tx, err := s.db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("cannot start snapshot: %w", err)
+	}
+
+	// snapshot is read-only, so it's rolled back after read
+	defer tx.Rollback()
+
+	return read(&SQLitePlainStorage{config: s.config, db: s.db, q: tx, tx: tx, logger: s.logger})
+}
+
+func (s *SQLitePlainStorage) GetUsers(ctx context.Context, afterUUID string, limit int) ([]User, error) {
+	rows, err := s.q.QueryxContext(ctx, `SELECT uuid, login, password FROM users WHERE uuid > ? ORDER BY uuid LIMIT ?`, afterUUID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("cannot select users: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]User, 0)
+	for rows.Next() {
+		var user User
+		if err = rows.Scan(&user.UUID, &user.Login, &user.PasswordHash); err != nil {
+			return nil, fmt.Errorf("cannot scan user: %w", err)
+		}
+
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read users: %w", err)
+	}
+
+	return users, nil
+}
+
+func (s *SQLitePlainStorage) GetSecrets(ctx context.Context, afterUUID string, limit int) ([]PlainSecret, error) {
+	var rows []struct {
+		SecretMetadata
+		Data []byte `db:"data"`
+	}
+
+	err := sqlx.SelectContext(
+		ctx,
+		s.q,
+		&rows,
+		`SELECT `+secretMetadataColumns+`, (SELECT data FROM plain_secret p WHERE p.uuid = secret_metadata.uuid) AS data
+		FROM secret_metadata WHERE uuid > ? ORDER BY uuid LIMIT ?`,
+		afterUUID,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot select secrets: %w", err)
+	}
+
+	mds := make([]SecretMetadata, len(rows))
+	for i, v := range rows {
+		mds[i] = v.SecretMetadata
+	}
+
+	if err = s.loadSecretsTags(ctx, mds); err != nil {
+		return nil, fmt.Errorf("cannot load tags of secrets: %w", err)
+	}
+
+	secrets := make([]PlainSecret, len(rows))
+	for i, v := range rows {
+		secrets[i] = PlainSecret{Metadata: mds[i], Data: v.Data}
+	}
+
+	return secrets, nil
+}
+
+func (s *SQLitePlainStorage) RestoreUser(ctx context.Context, user User) error {
+	_, err := s.q.ExecContext(ctx, "INSERT INTO users (uuid, login, password) VALUES (?, ?, ?)", user.UUID, user.Login, user.PasswordHash)
+	if err != nil {
+		if isSQLiteUniqueViolation(err) {
+			return ErrEntityAlreadyExists
+		}
+
+		return fmt.Errorf("cannot restore user: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SQLitePlainStorage) RestoreSecret(ctx context.Context, secret PlainSecret) error {
+	return s.inTransaction(ctx, func(tx *SQLitePlainStorage) error {
+		md := secret.Metadata
+		_, err := tx.q.ExecContext(
+			ctx,
+			`INSERT INTO secret_metadata (`+secretMetadataColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			md.UUID, md.UserUUID, md.Name, md.EncryptedName, md.Type, md.Created.UTC(), md.Updated.UTC(), utcTime(md.ExpiresAt),
+			md.MaxReads, md.Reads, md.Folder, md.ContentHash, md.MediaSize, md.EncryptedMediaInfo,
+		)
+		if err != nil {
+			if isSQLiteUniqueViolation(err) {
+				return ErrEntityAlreadyExists
+			}
+
+			return fmt.Errorf("cannot restore secret metadata: %w", err)
+		}
+
+		for _, v := range md.Tags {
+			_, err = tx.q.ExecContext(ctx, "INSERT INTO secret_tag (secret_uuid, tag, encrypted_tag) VALUES (?, ?, ?)", md.UUID, v.Tag, v.EncryptedTag)
+			if err != nil {
+				return fmt.Errorf("cannot restore secret tag: %w", err)
+			}
+		}
+
+		if secret.Data == nil {
+			return nil
+		}
+
+		_, err = tx.q.ExecContext(ctx, "INSERT INTO plain_secret (uuid, data) VALUES (?, ?)", md.UUID, secret.Data)
+		if err != nil {
+			return fmt.Errorf("cannot restore secret data: %w", err)
+		}
+
+		return nil
+	})
+}
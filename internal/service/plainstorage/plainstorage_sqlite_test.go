@@ -0,0 +1,29 @@
+package plainstorage_test
+
+import (
+	"github.com/nessai1/gophkeeper/internal/service/config"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage/plainstoragetest"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"path/filepath"
+	"testing"
+)
+
+func newTestSQLiteStorage(t *testing.T) *plainstorage.SQLitePlainStorage {
+	path := filepath.Join(t.TempDir(), "keeper.db")
+
+	defer chdirModuleRoot(t)()
+
+	storage, err := plainstorage.NewSQLitePlainStorage(config.SQLitePlainStorageConfig{Path: path}, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, storage.Close())
+	})
+
+	return storage
+}
+
+func TestSQLitePlainStorage(t *testing.T) {
+	plainstoragetest.TestPlainStorage(t, newTestSQLiteStorage(t))
+}
@@ -0,0 +1,941 @@
+// Package plainstoragetest contains conformance suite for implementations of plainstorage.PlainStorage
+package plainstoragetest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+// concurrentCalls count of calls made at the same time by concurrency checks
+const concurrentCalls = 16
+
+var errTestRollback = errors.New("test rollback")
+
+// TestPlainStorage checks behavior of storage that all implementations of PlainStorage must share.
+// Every check works with own users, so storage may contain data of other runs
+func TestPlainStorage(t *testing.T, storage plainstorage.PlainStorage) {
+	t.Run("Users", func(t *testing.T) {
+		testUsers(t, storage)
+	})
+
+	t.Run("Secrets", func(t *testing.T) {
+		testSecrets(t, storage)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		testSecretsList(t, storage)
+	})
+
+	t.Run("Limits", func(t *testing.T) {
+		testSecretLimits(t, storage)
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		testBatchSecrets(t, storage)
+	})
+
+	t.Run("Media chunks", func(t *testing.T) {
+		testMediaChunks(t, storage)
+	})
+
+	t.Run("Unused media chunks", func(t *testing.T) {
+		testUnusedMediaChunks(t, storage)
+	})
+
+	t.Run("Object media", func(t *testing.T) {
+		testObjectMedia(t, storage)
+	})
+
+	t.Run("Usage", func(t *testing.T) {
+		testUsage(t, storage)
+	})
+
+	t.Run("Secrets data", func(t *testing.T) {
+		testSecretsData(t, storage)
+	})
+
+	t.Run("Media object keys", func(t *testing.T) {
+		testMediaObjectKeys(t, storage)
+	})
+
+	t.Run("Dump and restore", func(t *testing.T) {
+		testDumpRestore(t, storage)
+	})
+
+	t.Run("Snapshot", func(t *testing.T) {
+		testSnapshot(t, storage)
+	})
+
+	t.Run("Concurrency", func(t *testing.T) {
+		testConcurrency(t, storage)
+	})
+
+	t.Run("Transaction", func(t *testing.T) {
+		testTransactionRollback(t, storage)
+	})
+}
+
+func createTestUser(t *testing.T, storage plainstorage.PlainStorage) *plainstorage.User {
+	user, err := storage.CreateUser(context.Background(), "user_"+uuid.New().String(), "somesecrethash")
+	require.NoError(t, err)
+
+	return user
+}
+
+func listSecretNames(t *testing.T, storage plainstorage.PlainStorage, userUUID string, filter plainstorage.SecretFilter, page plainstorage.SecretPage) []string {
+	secrets, err := storage.GetUserSecretsMetadata(context.Background(), userUUID, filter, page)
+	require.NoError(t, err)
+
+	names := make([]string, len(secrets))
+	for i, v := range secrets {
+		names[i] = v.Name
+	}
+
+	return names
+}
+
+func testUsers(t *testing.T, storage plainstorage.PlainStorage) {
+	ctx := context.Background()
+
+	user := createTestUser(t, storage)
+
+	byLogin, err := storage.GetUserByLogin(ctx, user.Login)
+	require.NoError(t, err)
+	assert.Equal(t, *user, *byLogin)
+
+	byUUID, err := storage.GetUserByUUID(ctx, user.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, *user, *byUUID)
+
+	_, err = storage.CreateUser(ctx, user.Login, "otherhash")
+	assert.ErrorIs(t, err, plainstorage.ErrEntityAlreadyExists)
+
+	_, err = storage.GetUserByLogin(ctx, "missing_"+uuid.New().String())
+	assert.ErrorIs(t, err, plainstorage.ErrEntityNotFound)
+
+	_, err = storage.GetUserByUUID(ctx, uuid.New().String())
+	assert.ErrorIs(t, err, plainstorage.ErrEntityNotFound)
+}
+
+func testSecrets(t *testing.T, storage plainstorage.PlainStorage) {
+	ctx := context.Background()
+
+	user := createTestUser(t, storage)
+	otherUser := createTestUser(t, storage)
+
+	secret, err := storage.AddPlainSecret(ctx, user.UUID, "text_secret", []byte("encrypted"), plainstorage.SecretTypeText, []byte("text"))
+	require.NoError(t, err)
+	assert.Equal(t, "text_secret", secret.Metadata.Name)
+	assert.Equal(t, []byte("text"), secret.Data)
+
+	_, err = storage.AddPlainSecret(ctx, user.UUID, "text_secret", nil, plainstorage.SecretTypeText, []byte("text"))
+	assert.ErrorIs(t, err, plainstorage.ErrEntityAlreadyExists)
+
+	_, err = storage.AddPlainSecret(ctx, user.UUID, "text_secret", nil, plainstorage.SecretTypeCredentials, []byte("credentials"))
+	assert.NoError(t, err)
+
+	_, err = storage.AddPlainSecret(ctx, otherUser.UUID, "text_secret", nil, plainstorage.SecretTypeText, []byte("other"))
+	assert.NoError(t, err)
+
+	got, err := storage.GetUserSecretByName(ctx, user.UUID, "text_secret", plainstorage.SecretTypeText)
+	require.NoError(t, err)
+	assert.Equal(t, secret.Metadata.UUID, got.Metadata.UUID)
+	assert.Equal(t, []byte("encrypted"), got.Metadata.EncryptedName)
+	assert.Equal(t, []byte("text"), got.Data)
+
+	_, err = storage.GetUserSecretByName(ctx, user.UUID, "missing_secret", plainstorage.SecretTypeText)
+	assert.ErrorIs(t, err, plainstorage.ErrEntityNotFound)
+
+	require.NoError(t, storage.UpdatePlainSecretDataByName(ctx, user.UUID, "text_secret", plainstorage.SecretTypeText, []byte("new text")))
+	got, err = storage.GetUserSecretByName(ctx, user.UUID, "text_secret", plainstorage.SecretTypeText)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("new text"), got.Data)
+	assert.False(t, got.Metadata.Updated.Before(got.Metadata.Created))
+
+	err = storage.UpdatePlainSecretDataByName(ctx, user.UUID, "missing_secret", plainstorage.SecretTypeText, []byte("text"))
+	assert.ErrorIs(t, err, plainstorage.ErrEntityNotFound)
+
+	require.NoError(t, storage.SetSecretFolder(ctx, secret.Metadata.UUID, "folder"))
+	require.NoError(t, storage.SetSecretTags(ctx, secret.Metadata.UUID, []plainstorage.SecretTag{
+		{Tag: "second", EncryptedTag: []byte("encrypted second")},
+		{Tag: "first", EncryptedTag: []byte("encrypted first")},
+	}))
+
+	got, err = storage.GetUserSecretByName(ctx, user.UUID, "text_secret", plainstorage.SecretTypeText)
+	require.NoError(t, err)
+	assert.Equal(t, "folder", got.Metadata.Folder)
+	assert.Equal(t, []plainstorage.SecretTag{
+		{Tag: "first", EncryptedTag: []byte("encrypted first")},
+		{Tag: "second", EncryptedTag: []byte("encrypted second")},
+	}, got.Metadata.Tags)
+
+	assert.ErrorIs(t, storage.SetSecretFolder(ctx, uuid.New().String(), "folder"), plainstorage.ErrEntityNotFound)
+	assert.ErrorIs(t, storage.SetSecretTags(ctx, uuid.New().String(), nil), plainstorage.ErrEntityNotFound)
+
+	err = storage.RenameSecret(ctx, secret.Metadata.UUID, "text_secret", nil, plainstorage.SecretTypeCredentials)
+	assert.ErrorIs(t, err, plainstorage.ErrEntityAlreadyExists)
+
+	require.NoError(t, storage.RenameSecret(ctx, secret.Metadata.UUID, "renamed_secret", []byte("encrypted renamed"), plainstorage.SecretTypeCard))
+	renamed, err := storage.GetUserSecretByName(ctx, user.UUID, "renamed_secret", plainstorage.SecretTypeCard)
+	require.NoError(t, err)
+	assert.Equal(t, secret.Metadata.UUID, renamed.Metadata.UUID)
+	assert.Equal(t, []byte("encrypted renamed"), renamed.Metadata.EncryptedName)
+	assert.Equal(t, []byte("new text"), renamed.Data)
+
+	assert.ErrorIs(t, storage.RenameSecret(ctx, uuid.New().String(), "name", nil, plainstorage.SecretTypeText), plainstorage.ErrEntityNotFound)
+
+	mediaUUID := uuid.New().String()
+	_, err = storage.AddSecretMetadata(ctx, user.UUID, mediaUUID, "media_secret", nil, plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+
+	_, err = storage.AddSecretMetadata(ctx, user.UUID, uuid.New().String(), "media_secret", nil, plainstorage.SecretTypeMedia)
+	assert.ErrorIs(t, err, plainstorage.ErrEntityAlreadyExists)
+
+	newMediaUUID := uuid.New().String()
+	require.NoError(t, storage.UpdateSecretMetadataUUID(ctx, user.UUID, mediaUUID, newMediaUUID, plainstorage.SecretTypeMedia))
+	media, err := storage.GetUserSecretByName(ctx, user.UUID, "media_secret", plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+	assert.Equal(t, newMediaUUID, media.Metadata.UUID)
+	assert.Empty(t, media.Data)
+	assert.Nil(t, media.Metadata.ContentHash)
+	assert.Zero(t, media.Metadata.MediaSize)
+
+	contentHash := sha256.Sum256([]byte("media content"))
+	mediaInfo := plainstorage.MediaInfo{Size: 13, ContentHash: contentHash[:], EncryptedInfo: []byte("encrypted info")}
+	require.NoError(t, storage.SetMediaInfo(ctx, newMediaUUID, mediaInfo))
+	media, err = storage.GetUserSecretByName(ctx, user.UUID, "media_secret", plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+	assert.Equal(t, contentHash[:], media.Metadata.ContentHash)
+	assert.Equal(t, int64(13), media.Metadata.MediaSize)
+	assert.Equal(t, []byte("encrypted info"), media.Metadata.EncryptedMediaInfo)
+
+	assert.ErrorIs(t, storage.SetMediaInfo(ctx, uuid.New().String(), mediaInfo), plainstorage.ErrEntityNotFound)
+
+	assert.ErrorIs(t, storage.UpdateSecretMetadataUUID(ctx, user.UUID, mediaUUID, uuid.New().String(), plainstorage.SecretTypeMedia), plainstorage.ErrEntityNotFound)
+
+	require.NoError(t, storage.RemoveSecretByUUID(ctx, renamed.Metadata.UUID))
+	_, err = storage.GetUserSecretByName(ctx, user.UUID, "renamed_secret", plainstorage.SecretTypeCard)
+	assert.ErrorIs(t, err, plainstorage.ErrEntityNotFound)
+
+	// removing of missing secret is not an error, so concurrent removes don't fail
+	assert.NoError(t, storage.RemoveSecretByUUID(ctx, renamed.Metadata.UUID))
+
+	// name of removed secret can be taken again
+	_, err = storage.AddPlainSecret(ctx, user.UUID, "renamed_secret", nil, plainstorage.SecretTypeCard, []byte("card"))
+	assert.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"text_secret"}, listSecretNames(t, storage, otherUser.UUID, plainstorage.SecretFilter{AnyType: true}, plainstorage.SecretPage{}))
+}
+
+func testSecretsList(t *testing.T, storage plainstorage.PlainStorage) {
+	ctx := context.Background()
+
+	user := createTestUser(t, storage)
+
+	names := []string{"charlie", "alpha", "bravo", "alpine", "Alpaca", "al%_pha"}
+	uuids := make(map[string]string, len(names))
+	for _, name := range names {
+		secret, err := storage.AddPlainSecret(ctx, user.UUID, name, nil, plainstorage.SecretTypeText, []byte(name))
+		require.NoError(t, err)
+
+		uuids[name] = secret.Metadata.UUID
+		// keeps created dates of secrets different
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err := storage.AddPlainSecret(ctx, user.UUID, "other_type", nil, plainstorage.SecretTypeCredentials, []byte("credentials"))
+	require.NoError(t, err)
+
+	require.NoError(t, storage.SetSecretFolder(ctx, uuids["alpha"], "folder"))
+	require.NoError(t, storage.SetSecretFolder(ctx, uuids["bravo"], "folder"))
+	require.NoError(t, storage.SetSecretTags(ctx, uuids["bravo"], []plainstorage.SecretTag{{Tag: "tag"}}))
+	require.NoError(t, storage.SetSecretTags(ctx, uuids["charlie"], []plainstorage.SecretTag{{Tag: "tag"}, {Tag: "other_tag"}}))
+
+	textFilter := plainstorage.SecretFilter{Type: plainstorage.SecretTypeText}
+
+	assert.Equal(t, names, listSecretNames(t, storage, user.UUID, textFilter, plainstorage.SecretPage{}))
+	assert.Equal(t, []string{"al%_pha", "Alpaca", "alpine", "bravo", "alpha", "charlie"}, listSecretNames(t, storage, user.UUID, textFilter, plainstorage.SecretPage{Descending: true}))
+	assert.Len(t, listSecretNames(t, storage, user.UUID, plainstorage.SecretFilter{AnyType: true}, plainstorage.SecretPage{}), len(names)+1)
+
+	tests := []struct {
+		name     string
+		filter   plainstorage.SecretFilter
+		expected []string
+	}{
+		{
+			name:     "Folder",
+			filter:   plainstorage.SecretFilter{Type: plainstorage.SecretTypeText, Folder: "folder"},
+			expected: []string{"alpha", "bravo"},
+		},
+		{
+			name:     "Tag",
+			filter:   plainstorage.SecretFilter{Type: plainstorage.SecretTypeText, Tag: "tag"},
+			expected: []string{"charlie", "bravo"},
+		},
+		{
+			name:     "Folder and tag",
+			filter:   plainstorage.SecretFilter{Type: plainstorage.SecretTypeText, Folder: "folder", Tag: "tag"},
+			expected: []string{"bravo"},
+		},
+		{
+			name:     "Names",
+			filter:   plainstorage.SecretFilter{AnyType: true, Names: []string{"alpha", "other_type", "missing"}},
+			expected: []string{"alpha", "other_type"},
+		},
+		{
+			name:     "Empty names",
+			filter:   plainstorage.SecretFilter{AnyType: true, Names: []string{}},
+			expected: []string{},
+		},
+		{
+			name:     "Created in future",
+			filter:   plainstorage.SecretFilter{Type: plainstorage.SecretTypeText, CreatedAfter: time.Now().Add(time.Hour)},
+			expected: []string{},
+		},
+		{
+			name:     "Updated in past",
+			filter:   plainstorage.SecretFilter{Type: plainstorage.SecretTypeText, UpdatedBefore: time.Now().Add(-time.Hour)},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, listSecretNames(t, storage, user.UUID, tt.filter, plainstorage.SecretPage{}))
+		})
+	}
+
+	for _, sort := range []plainstorage.SecretSort{plainstorage.SecretSortCreated, plainstorage.SecretSortUpdated} {
+		for _, descending := range []bool{false, true} {
+			all := listSecretNames(t, storage, user.UUID, textFilter, plainstorage.SecretPage{Sort: sort, Descending: descending})
+
+			paged := make([]string, 0, len(all))
+			page := plainstorage.SecretPage{Sort: sort, Descending: descending, Limit: 4}
+			for {
+				secrets, err := storage.GetUserSecretsMetadata(ctx, user.UUID, textFilter, page)
+				require.NoError(t, err)
+				require.LessOrEqual(t, len(secrets), page.Limit)
+
+				for _, v := range secrets {
+					paged = append(paged, v.Name)
+				}
+
+				if len(secrets) < page.Limit {
+					break
+				}
+
+				cursor := plainstorage.CursorOf(secrets[len(secrets)-1])
+				page.After = &cursor
+			}
+
+			assert.Equal(t, all, paged, "sort %d, descending %t", sort, descending)
+		}
+	}
+}
+
+func testSecretLimits(t *testing.T, storage plainstorage.PlainStorage) {
+	ctx := context.Background()
+
+	user := createTestUser(t, storage)
+
+	oneTime, err := storage.AddPlainSecret(ctx, user.UUID, "one_time", nil, plainstorage.SecretTypeText, []byte("text"))
+	require.NoError(t, err)
+	require.NoError(t, storage.SetSecretLimits(ctx, oneTime.Metadata.UUID, nil, 1))
+
+	expiresAt := time.Now().Add(time.Hour)
+	expiring, err := storage.AddPlainSecret(ctx, user.UUID, "expiring", nil, plainstorage.SecretTypeText, []byte("text"))
+	require.NoError(t, err)
+	require.NoError(t, storage.SetSecretLimits(ctx, expiring.Metadata.UUID, &expiresAt, 0))
+
+	assert.ErrorIs(t, storage.SetSecretLimits(ctx, uuid.New().String(), nil, 1), plainstorage.ErrEntityNotFound)
+
+	got, err := storage.GetUserSecretByName(ctx, user.UUID, "expiring", plainstorage.SecretTypeText)
+	require.NoError(t, err)
+	require.NotNil(t, got.Metadata.ExpiresAt)
+	assert.WithinDuration(t, expiresAt, *got.Metadata.ExpiresAt, time.Millisecond)
+
+	md, err := storage.RegisterSecretRead(ctx, oneTime.Metadata.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, md.Reads)
+	assert.Equal(t, 1, md.MaxReads)
+
+	_, err = storage.RegisterSecretRead(ctx, oneTime.Metadata.UUID)
+	assert.ErrorIs(t, err, plainstorage.ErrEntityNotFound)
+
+	_, err = storage.RegisterSecretRead(ctx, uuid.New().String())
+	assert.ErrorIs(t, err, plainstorage.ErrEntityNotFound)
+
+	active := plainstorage.SecretFilter{Type: plainstorage.SecretTypeText, ActiveAt: time.Now()}
+	assert.Equal(t, []string{"expiring"}, listSecretNames(t, storage, user.UUID, active, plainstorage.SecretPage{}))
+
+	active.ActiveAt = time.Now().Add(2 * time.Hour)
+	assert.Empty(t, listSecretNames(t, storage, user.UUID, active, plainstorage.SecretPage{}))
+
+	isExpired := func(moment time.Time, secretUUID string) bool {
+		expired, err := storage.GetExpiredSecretsMetadata(ctx, moment)
+		require.NoError(t, err)
+
+		for _, v := range expired {
+			if v.UUID == secretUUID {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	assert.True(t, isExpired(time.Now(), oneTime.Metadata.UUID))
+	assert.False(t, isExpired(time.Now(), expiring.Metadata.UUID))
+	assert.True(t, isExpired(time.Now().Add(2*time.Hour), expiring.Metadata.UUID))
+
+	require.NoError(t, storage.SetSecretLimits(ctx, expiring.Metadata.UUID, nil, 0))
+	assert.False(t, isExpired(time.Now().Add(2*time.Hour), expiring.Metadata.UUID))
+}
+
+func testBatchSecrets(t *testing.T, storage plainstorage.PlainStorage) {
+	ctx := context.Background()
+
+	user := createTestUser(t, storage)
+
+	expiresAt := time.Now().Add(time.Hour)
+	created, err := storage.AddPlainSecrets(ctx, user.UUID, []plainstorage.NewPlainSecret{
+		{Name: "first", Type: plainstorage.SecretTypeText, Data: []byte("first"), Folder: "folder"},
+		{Name: "second", EncryptedName: []byte("encrypted"), Type: plainstorage.SecretTypeCard, Data: []byte("second"), ExpiresAt: &expiresAt, MaxReads: 2},
+	})
+	require.NoError(t, err)
+	require.Len(t, created, 2)
+	assert.Equal(t, "first", created[0].Metadata.Name)
+	assert.Equal(t, "folder", created[0].Metadata.Folder)
+	assert.Equal(t, "second", created[1].Metadata.Name)
+	assert.Equal(t, 2, created[1].Metadata.MaxReads)
+	assert.Equal(t, []byte("second"), created[1].Data)
+
+	second, err := storage.GetUserSecretByName(ctx, user.UUID, "second", plainstorage.SecretTypeCard)
+	require.NoError(t, err)
+	assert.Equal(t, created[1].Metadata.UUID, second.Metadata.UUID)
+	assert.Equal(t, []byte("encrypted"), second.Metadata.EncryptedName)
+	assert.Equal(t, []byte("second"), second.Data)
+
+	_, err = storage.AddPlainSecrets(ctx, user.UUID, []plainstorage.NewPlainSecret{
+		{Name: "third", Type: plainstorage.SecretTypeText, Data: []byte("third")},
+		{Name: "first", Type: plainstorage.SecretTypeText, Data: []byte("first")},
+	})
+	assert.ErrorIs(t, err, plainstorage.ErrEntityAlreadyExists)
+	assert.ElementsMatch(t, []string{"first", "second"}, listSecretNames(t, storage, user.UUID, plainstorage.SecretFilter{AnyType: true}, plainstorage.SecretPage{}))
+
+	require.NoError(t, storage.RemoveSecretsByUUID(ctx, []string{created[0].Metadata.UUID, created[1].Metadata.UUID}))
+	assert.Empty(t, listSecretNames(t, storage, user.UUID, plainstorage.SecretFilter{AnyType: true}, plainstorage.SecretPage{}))
+	assert.NoError(t, storage.RemoveSecretsByUUID(ctx, nil))
+}
+
+// testConcurrency checks that concurrent calls keep uniqueness of names and limits of reads
+func chunkHash(content string) string {
+	hash := sha256.Sum256([]byte(content))
+
+	return hex.EncodeToString(hash[:])
+}
+
+func testMediaChunks(t *testing.T, storage plainstorage.PlainStorage) {
+	ctx := context.Background()
+
+	user := createTestUser(t, storage)
+	other := createTestUser(t, storage)
+
+	first, second, third := chunkHash("first"), chunkHash("second"), chunkHash("third")
+	for i, hash := range []string{first, second} {
+		require.NoError(t, storage.AddMediaChunk(ctx, user.UUID, hash, int64(i+1)))
+	}
+	require.NoError(t, storage.AddMediaChunk(ctx, user.UUID, first, 1), "adding existing chunk must be ignored")
+	require.NoError(t, storage.AddMediaChunk(ctx, other.UUID, third, 3))
+
+	existing, err := storage.GetExistingMediaChunks(ctx, user.UUID, []string{third, second, first})
+	require.NoError(t, err)
+	assert.Equal(t, []string{second, first}, existing, "chunks of other users must not be found")
+
+	mediaUUID := uuid.New().String()
+	err = storage.SetMediaChunks(ctx, user.UUID, mediaUUID, []string{first, third})
+	assert.ErrorIs(t, err, plainstorage.ErrEntityNotFound)
+
+	chunks, err := storage.GetMediaChunks(ctx, mediaUUID)
+	require.NoError(t, err)
+	assert.Empty(t, chunks, "failed set must not save chunks of media")
+
+	require.NoError(t, storage.SetMediaChunks(ctx, user.UUID, mediaUUID, []string{second, first, second}))
+	assert.ErrorIs(t, storage.SetMediaChunks(ctx, user.UUID, mediaUUID, []string{first}), plainstorage.ErrEntityAlreadyExists)
+
+	otherMediaUUID := uuid.New().String()
+	require.NoError(t, storage.SetMediaChunks(ctx, user.UUID, otherMediaUUID, []string{first}))
+
+	chunks, err = storage.GetMediaChunks(ctx, mediaUUID)
+	require.NoError(t, err)
+	require.Len(t, chunks, 3)
+	assert.Equal(t, []string{second, first, second}, []string{chunks[0].Hash, chunks[1].Hash, chunks[2].Hash})
+	assert.False(t, chunks[0].Created.IsZero())
+	assert.Equal(t, plainstorage.MediaChunk{OwnerUUID: user.UUID, Hash: second, Size: 2, Refs: 2, Created: chunks[0].Created}, chunks[0])
+	assert.Equal(t, 2, chunks[1].Refs)
+
+	released, err := storage.RemoveMediaChunks(ctx, mediaUUID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{second}, released, "only chunks without references must be released")
+
+	released, err = storage.RemoveMediaChunks(ctx, mediaUUID)
+	require.NoError(t, err)
+	assert.Empty(t, released)
+
+	released, err = storage.RemoveMediaChunks(ctx, otherMediaUUID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{first}, released)
+
+	existing, err = storage.GetExistingMediaChunks(ctx, user.UUID, []string{first, second})
+	require.NoError(t, err)
+	assert.Empty(t, existing)
+}
+
+func testUnusedMediaChunks(t *testing.T, storage plainstorage.PlainStorage) {
+	ctx := context.Background()
+
+	user := createTestUser(t, storage)
+	used, unused := chunkHash("used"), chunkHash("unused")
+	require.NoError(t, storage.AddMediaChunk(ctx, user.UUID, used, 1))
+	require.NoError(t, storage.AddMediaChunk(ctx, user.UUID, unused, 2))
+	require.NoError(t, storage.SetMediaChunks(ctx, user.UUID, uuid.New().String(), []string{used}))
+
+	all, err := storage.GetAllMediaChunks(ctx)
+	require.NoError(t, err)
+
+	refs := make(map[string]int)
+	for _, v := range all {
+		if v.OwnerUUID == user.UUID {
+			assert.False(t, v.Created.IsZero())
+			refs[v.Hash] = v.Refs
+		}
+	}
+	assert.Equal(t, map[string]int{used: 1, unused: 0}, refs)
+
+	assert.ErrorIs(t, storage.RemoveUnusedMediaChunk(ctx, user.UUID, used), plainstorage.ErrEntityNotFound, "used chunk must not be removed")
+	require.NoError(t, storage.RemoveUnusedMediaChunk(ctx, user.UUID, unused))
+	assert.ErrorIs(t, storage.RemoveUnusedMediaChunk(ctx, user.UUID, unused), plainstorage.ErrEntityNotFound)
+
+	existing, err := storage.GetExistingMediaChunks(ctx, user.UUID, []string{used, unused})
+	require.NoError(t, err)
+	assert.Equal(t, []string{used}, existing)
+}
+
+func testObjectMedia(t *testing.T, storage plainstorage.PlainStorage) {
+	ctx := context.Background()
+
+	user := createTestUser(t, storage)
+	objectUUID, chunkedUUID := uuid.New().String(), uuid.New().String()
+	_, err := storage.AddSecretMetadata(ctx, user.UUID, objectUUID, "object.bin", nil, plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+	_, err = storage.AddSecretMetadata(ctx, user.UUID, chunkedUUID, "chunked.bin", nil, plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+	_, err = storage.AddPlainSecret(ctx, user.UUID, "text", nil, plainstorage.SecretTypeText, []byte("text"))
+	require.NoError(t, err)
+
+	hash := chunkHash("object media")
+	require.NoError(t, storage.AddMediaChunk(ctx, user.UUID, hash, 1))
+	require.NoError(t, storage.SetMediaChunks(ctx, user.UUID, chunkedUUID, []string{hash}))
+
+	media, err := storage.GetObjectMediaMetadata(ctx)
+	require.NoError(t, err)
+
+	found := make([]string, 0)
+	for _, v := range media {
+		if v.UserUUID == user.UUID {
+			found = append(found, v.UUID)
+		}
+	}
+	assert.Equal(t, []string{objectUUID}, found, "chunked media and plain secrets must not be listed")
+}
+
+func testSecretsData(t *testing.T, storage plainstorage.PlainStorage) {
+	ctx := context.Background()
+
+	user := createTestUser(t, storage)
+	created := make(map[string]string)
+	for _, name := range []string{"first", "second", "third"} {
+		secret, err := storage.AddPlainSecret(ctx, user.UUID, name, nil, plainstorage.SecretTypeText, []byte(name+" data"))
+		require.NoError(t, err)
+		created[secret.Metadata.UUID] = name + " data"
+	}
+
+	_, err := storage.AddSecretMetadata(ctx, user.UUID, uuid.New().String(), "media.bin", nil, plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+
+	found := make(map[string]string)
+	after := ""
+	for {
+		page, err := storage.GetPlainSecretsData(ctx, after, 2)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(page), 2)
+
+		if len(page) == 0 {
+			break
+		}
+
+		for _, v := range page {
+			require.Greater(t, v.UUID, after, "secrets must be ordered by UUID")
+			after = v.UUID
+
+			if _, ok := created[v.UUID]; ok {
+				found[v.UUID] = string(v.Data)
+			}
+		}
+	}
+	assert.Equal(t, created, found, "all plain secrets must be listed, media must not")
+
+	secret, err := storage.GetUserSecretByName(ctx, user.UUID, "first", plainstorage.SecretTypeText)
+	require.NoError(t, err)
+
+	err = storage.ReplacePlainSecretData(ctx, secret.Metadata.UUID, []byte("stale data"), []byte("new data"))
+	assert.ErrorIs(t, err, plainstorage.ErrEntityNotFound, "changed data must not be replaced")
+
+	require.NoError(t, storage.ReplacePlainSecretData(ctx, secret.Metadata.UUID, []byte("first data"), []byte("new data")))
+
+	secret, err = storage.GetUserSecretByName(ctx, user.UUID, "first", plainstorage.SecretTypeText)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("new data"), secret.Data)
+}
+
+func testMediaObjectKeys(t *testing.T, storage plainstorage.PlainStorage) {
+	ctx := context.Background()
+
+	prefix := uuid.New().String()
+	first := plainstorage.MediaObjectKey{ObjectKey: prefix + "-a", KEKID: "old", WrappedKey: []byte("first key")}
+	second := plainstorage.MediaObjectKey{ObjectKey: prefix + "-b", KEKID: "old", WrappedKey: []byte("second key")}
+
+	_, err := storage.GetMediaObjectKey(ctx, first.ObjectKey)
+	assert.ErrorIs(t, err, plainstorage.ErrEntityNotFound)
+
+	require.NoError(t, storage.SetMediaObjectKey(ctx, first))
+	require.NoError(t, storage.SetMediaObjectKey(ctx, plainstorage.MediaObjectKey{ObjectKey: second.ObjectKey, KEKID: "old", WrappedKey: []byte("replaced")}))
+	require.NoError(t, storage.SetMediaObjectKey(ctx, second), "key of object must be replaced")
+
+	key, err := storage.GetMediaObjectKey(ctx, second.ObjectKey)
+	require.NoError(t, err)
+	assert.Equal(t, second, *key)
+
+	keys, err := storage.GetMediaObjectKeys(ctx, prefix, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []plainstorage.MediaObjectKey{first}, keys)
+
+	keys, err = storage.GetMediaObjectKeys(ctx, first.ObjectKey, 2)
+	require.NoError(t, err)
+	require.NotEmpty(t, keys)
+	assert.Equal(t, second, keys[0])
+
+	rotated := plainstorage.MediaObjectKey{ObjectKey: first.ObjectKey, KEKID: "new", WrappedKey: []byte("rotated key")}
+	err = storage.ReplaceMediaObjectKey(ctx, plainstorage.MediaObjectKey{ObjectKey: first.ObjectKey, WrappedKey: []byte("stale key")}, rotated)
+	assert.ErrorIs(t, err, plainstorage.ErrEntityNotFound, "key must be replaced only if it's not changed")
+
+	require.NoError(t, storage.ReplaceMediaObjectKey(ctx, first, rotated))
+	key, err = storage.GetMediaObjectKey(ctx, first.ObjectKey)
+	require.NoError(t, err)
+	assert.Equal(t, rotated, *key)
+	assert.ErrorIs(t, storage.ReplaceMediaObjectKey(ctx, first, rotated), plainstorage.ErrEntityNotFound)
+
+	require.NoError(t, storage.RemoveMediaObjectKey(ctx, first.ObjectKey))
+	assert.ErrorIs(t, storage.RemoveMediaObjectKey(ctx, first.ObjectKey), plainstorage.ErrEntityNotFound)
+	_, err = storage.GetMediaObjectKey(ctx, first.ObjectKey)
+	assert.ErrorIs(t, err, plainstorage.ErrEntityNotFound)
+}
+
+func testUsage(t *testing.T, storage plainstorage.PlainStorage) {
+	ctx := context.Background()
+
+	user := createTestUser(t, storage)
+	other := createTestUser(t, storage)
+
+	usage, err := storage.GetUserUsage(ctx, user.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, plainstorage.Usage{}, usage)
+
+	_, err = storage.AddPlainSecret(ctx, user.UUID, "text", nil, plainstorage.SecretTypeText, []byte("text"))
+	require.NoError(t, err)
+	_, err = storage.AddPlainSecret(ctx, user.UUID, "card", nil, plainstorage.SecretTypeCard, []byte("card!"))
+	require.NoError(t, err)
+	_, err = storage.AddPlainSecret(ctx, other.UUID, "text", nil, plainstorage.SecretTypeText, []byte("other text"))
+	require.NoError(t, err)
+
+	objectUUID := uuid.New().String()
+	_, err = storage.AddSecretMetadata(ctx, user.UUID, objectUUID, "object", nil, plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+	require.NoError(t, storage.SetMediaInfo(ctx, objectUUID, plainstorage.MediaInfo{Size: 100}))
+
+	// chunked media is counted by its chunks, shared and not committed chunks are counted once
+	chunkedUUID := uuid.New().String()
+	_, err = storage.AddSecretMetadata(ctx, user.UUID, chunkedUUID, "chunked", nil, plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+	require.NoError(t, storage.AddMediaChunk(ctx, user.UUID, chunkHash("first"), 10))
+	require.NoError(t, storage.AddMediaChunk(ctx, user.UUID, chunkHash("second"), 20))
+	require.NoError(t, storage.AddMediaChunk(ctx, user.UUID, chunkHash("pending"), 5))
+	require.NoError(t, storage.AddMediaChunk(ctx, other.UUID, chunkHash("first"), 10))
+	require.NoError(t, storage.SetMediaChunks(ctx, user.UUID, chunkedUUID, []string{chunkHash("first"), chunkHash("second"), chunkHash("first")}))
+	require.NoError(t, storage.SetMediaInfo(ctx, chunkedUUID, plainstorage.MediaInfo{Size: 40}))
+
+	usage, err = storage.GetUserUsage(ctx, user.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, plainstorage.Usage{MediaCount: 2, PlainCount: 2, MediaBytes: 135, PlainBytes: 9}, usage)
+}
+
+func testConcurrency(t *testing.T, storage plainstorage.PlainStorage) {
+	ctx := context.Background()
+
+	user := createTestUser(t, storage)
+
+	var created, existing int
+	mu := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for i := 0; i < concurrentCalls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			// every call adds own secret and competes for common one
+			_, err := storage.AddPlainSecret(ctx, user.UUID, fmt.Sprintf("secret_%d", i), nil, plainstorage.SecretTypeText, []byte("text"))
+			assert.NoError(t, err)
+
+			_, err = storage.AddPlainSecret(ctx, user.UUID, "common_secret", nil, plainstorage.SecretTypeText, []byte("text"))
+
+			mu.Lock()
+			defer mu.Unlock()
+			if errors.Is(err, plainstorage.ErrEntityAlreadyExists) {
+				existing++
+			} else if assert.NoError(t, err) {
+				created++
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, created)
+	assert.Equal(t, concurrentCalls-1, existing)
+	assert.Len(t, listSecretNames(t, storage, user.UUID, plainstorage.SecretFilter{Type: plainstorage.SecretTypeText}, plainstorage.SecretPage{}), concurrentCalls+1)
+
+	limited, err := storage.AddPlainSecret(ctx, user.UUID, "limited_secret", nil, plainstorage.SecretTypeText, []byte("text"))
+	require.NoError(t, err)
+
+	maxReads := concurrentCalls / 2
+	require.NoError(t, storage.SetSecretLimits(ctx, limited.Metadata.UUID, nil, maxReads))
+
+	var reads, rejected int
+	for i := 0; i < concurrentCalls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := storage.RegisterSecretRead(ctx, limited.Metadata.UUID)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if errors.Is(err, plainstorage.ErrEntityNotFound) {
+				rejected++
+			} else if assert.NoError(t, err) {
+				reads++
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, maxReads, reads)
+	assert.Equal(t, concurrentCalls-maxReads, rejected)
+}
+
+// dumpUserSecrets returns secrets of user by pages of all secrets
+func dumpUserSecrets(t *testing.T, storage plainstorage.PlainStorage, userUUID string) []plainstorage.PlainSecret {
+	var secrets []plainstorage.PlainSecret
+	after := ""
+	for {
+		page, err := storage.GetSecrets(context.Background(), after, 2)
+		require.NoError(t, err)
+
+		if len(page) == 0 {
+			return secrets
+		}
+
+		for _, v := range page {
+			require.Greater(t, v.Metadata.UUID, after, "secrets must be ordered by UUID")
+			after = v.Metadata.UUID
+
+			if v.Metadata.UserUUID == userUUID {
+				secrets = append(secrets, v)
+			}
+		}
+	}
+}
+
+func testDumpRestore(t *testing.T, storage plainstorage.PlainStorage) {
+	ctx := context.Background()
+
+	user := createTestUser(t, storage)
+	secret, err := storage.AddPlainSecret(ctx, user.UUID, "text", []byte("encrypted name"), plainstorage.SecretTypeText, []byte("text data"))
+	require.NoError(t, err)
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+	require.NoError(t, storage.SetSecretLimits(ctx, secret.Metadata.UUID, &expiresAt, 3))
+	require.NoError(t, storage.SetSecretFolder(ctx, secret.Metadata.UUID, "folder"))
+	require.NoError(t, storage.SetSecretTags(ctx, secret.Metadata.UUID, []plainstorage.SecretTag{{Tag: "tag", EncryptedTag: []byte("encrypted tag")}}))
+
+	mediaUUID := uuid.New().String()
+	_, err = storage.AddSecretMetadata(ctx, user.UUID, mediaUUID, "media.bin", nil, plainstorage.SecretTypeMedia)
+	require.NoError(t, err)
+	require.NoError(t, storage.SetMediaInfo(ctx, mediaUUID, plainstorage.MediaInfo{Size: 10, ContentHash: []byte("hash"), EncryptedInfo: []byte("info")}))
+
+	users := make(map[string]plainstorage.User)
+	after := ""
+	for {
+		page, err := storage.GetUsers(ctx, after, 2)
+		require.NoError(t, err)
+		if len(page) == 0 {
+			break
+		}
+
+		for _, v := range page {
+			require.Greater(t, v.UUID, after, "users must be ordered by UUID")
+			after = v.UUID
+			users[v.UUID] = v
+		}
+	}
+	assert.Equal(t, *user, users[user.UUID])
+
+	dumped := dumpUserSecrets(t, storage, user.UUID)
+	require.Len(t, dumped, 2)
+
+	// dump is restored as other user with other secrets
+	restoredUser := plainstorage.User{UUID: uuid.New().String(), Login: "restored_" + uuid.New().String(), PasswordHash: user.PasswordHash}
+	require.NoError(t, storage.RestoreUser(ctx, restoredUser))
+	assert.ErrorIs(t, storage.RestoreUser(ctx, restoredUser), plainstorage.ErrEntityAlreadyExists)
+
+	restored, err := storage.GetUserByUUID(ctx, restoredUser.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, restoredUser, *restored)
+
+	for _, v := range dumped {
+		v.Metadata.UUID = uuid.New().String()
+		v.Metadata.UserUUID = restoredUser.UUID
+		require.NoError(t, storage.RestoreSecret(ctx, v))
+		assert.ErrorIs(t, storage.RestoreSecret(ctx, v), plainstorage.ErrEntityAlreadyExists)
+	}
+
+	restoredSecrets := dumpUserSecrets(t, storage, restoredUser.UUID)
+	require.Len(t, restoredSecrets, 2)
+
+	normalize := func(secrets []plainstorage.PlainSecret) map[string]plainstorage.PlainSecret {
+		rs := make(map[string]plainstorage.PlainSecret)
+		for _, v := range secrets {
+			assert.False(t, v.Metadata.Created.IsZero())
+			v.Metadata.Created = v.Metadata.Created.UTC()
+			v.Metadata.Updated = v.Metadata.Updated.UTC()
+			if v.Metadata.ExpiresAt != nil {
+				expires := v.Metadata.ExpiresAt.UTC()
+				v.Metadata.ExpiresAt = &expires
+			}
+			v.Metadata.UUID = ""
+			v.Metadata.UserUUID = ""
+			rs[v.Metadata.Name] = v
+		}
+
+		return rs
+	}
+	assert.Equal(t, normalize(dumped), normalize(restoredSecrets), "restored secrets must keep all fields")
+	assert.Equal(t, []byte("text data"), normalize(restoredSecrets)["text"].Data)
+	assert.Nil(t, normalize(restoredSecrets)["media.bin"].Data, "media must be restored without plain content")
+}
+
+func testSnapshot(t *testing.T, storage plainstorage.PlainStorage) {
+	ctx := context.Background()
+
+	user := createTestUser(t, storage)
+	err := storage.InSnapshot(ctx, func(snapshot plainstorage.PlainStorage) error {
+		_, err := snapshot.GetUserByUUID(ctx, user.UUID)
+		require.NoError(t, err)
+
+		_, err = storage.AddPlainSecret(ctx, user.UUID, "after snapshot", nil, plainstorage.SecretTypeText, []byte("data"))
+		require.NoError(t, err)
+
+		assert.Empty(t, dumpUserSecrets(t, snapshot, user.UUID), "snapshot must not see changes made after its start")
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Len(t, dumpUserSecrets(t, storage, user.UUID), 1)
+}
+
+// testTransactionRollback checks that storage commits successful transactions, rollbacks failed ones
+// and rollbacks only failed savepoint of nested transaction
+func testTransactionRollback(t *testing.T, storage plainstorage.PlainStorage) {
+	ctx := context.Background()
+
+	user := createTestUser(t, storage)
+
+	secretNames := func() []string {
+		return listSecretNames(t, storage, user.UUID, plainstorage.SecretFilter{AnyType: true}, plainstorage.SecretPage{})
+	}
+
+	t.Run("Rollback", func(t *testing.T) {
+		err := storage.InTransaction(ctx, func(tx plainstorage.PlainStorage) error {
+			_, err := tx.AddPlainSecret(ctx, user.UUID, "rolled_back", nil, plainstorage.SecretTypeText, []byte("text"))
+			require.NoError(t, err)
+
+			return errTestRollback
+		})
+		assert.ErrorIs(t, err, errTestRollback)
+		assert.Empty(t, secretNames())
+	})
+
+	t.Run("Commit", func(t *testing.T) {
+		err := storage.InTransaction(ctx, func(tx plainstorage.PlainStorage) error {
+			_, err := tx.AddPlainSecret(ctx, user.UUID, "committed", nil, plainstorage.SecretTypeText, []byte("text"))
+
+			return err
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"committed"}, secretNames())
+	})
+
+	t.Run("Savepoint", func(t *testing.T) {
+		err := storage.InTransaction(ctx, func(tx plainstorage.PlainStorage) error {
+			_, err := tx.AddPlainSecret(ctx, user.UUID, "outer", nil, plainstorage.SecretTypeText, []byte("text"))
+			require.NoError(t, err)
+
+			err = tx.InTransaction(ctx, func(tx plainstorage.PlainStorage) error {
+				_, err := tx.AddPlainSecret(ctx, user.UUID, "inner", nil, plainstorage.SecretTypeText, []byte("text"))
+				require.NoError(t, err)
+
+				return errTestRollback
+			})
+			assert.ErrorIs(t, err, errTestRollback)
+
+			return nil
+		})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"committed", "outer"}, secretNames())
+	})
+
+	t.Run("Remove rollback", func(t *testing.T) {
+		secret, err := storage.GetUserSecretByName(ctx, user.UUID, "committed", plainstorage.SecretTypeText)
+		require.NoError(t, err)
+
+		err = storage.InTransaction(ctx, func(tx plainstorage.PlainStorage) error {
+			require.NoError(t, tx.RemoveSecretByUUID(ctx, secret.Metadata.UUID))
+
+			return errTestRollback
+		})
+		assert.ErrorIs(t, err, errTestRollback)
+
+		restored, err := storage.GetUserSecretByName(ctx, user.UUID, "committed", plainstorage.SecretTypeText)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("text"), restored.Data)
+	})
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"go.uber.org/zap"
+	"time"
+)
+
+// runReaper periodically removes expired and exhausted secrets until context is done
+func (s *Server) runReaper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.reapExpiredSecrets(ctx)
+			s.reapUploadSessions(time.Now())
+		}
+	}
+}
+
+// reapExpiredSecrets removes all secrets that expired or have no reads left, returns count of removed secrets
+func (s *Server) reapExpiredSecrets(ctx context.Context) int {
+	secrets, err := s.plainStorage.GetExpiredSecretsMetadata(ctx, time.Now())
+	if err != nil {
+		s.logger.Error("Cannot get expired secrets for reap", zap.Error(err))
+
+		return 0
+	}
+
+	removed := 0
+	for _, md := range secrets {
+		err = s.removeSecret(ctx, md)
+		if err != nil {
+			s.logger.Error("Cannot reap expired secret", zap.Error(err), zap.String("secret_uuid", md.UUID))
+
+			continue
+		}
+
+		removed++
+	}
+
+	if removed > 0 {
+		s.logger.Info("Expired secrets reaped", zap.Int("count", removed))
+	}
+
+	return removed
+}
+
+// removeSecret removes secret metadata with its content, media object removes after metadata,
+// so secret can't be read even if media storage fails
+func (s *Server) removeSecret(ctx context.Context, md plainstorage.SecretMetadata) error {
+	err := s.plainStorage.RemoveSecretByUUID(ctx, md.UUID)
+	if err != nil {
+		return fmt.Errorf("cannot remove secret metadata: %w", err)
+	}
+
+	if md.Type == plainstorage.SecretTypeMedia {
+		err = s.removeMediaContent(ctx, md.UserUUID, md.UUID)
+		if err != nil {
+			s.logger.Error("Cannot delete media of removed secret", zap.Error(err), zap.String("media_uuid", md.UUID))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestServer_reapExpiredSecrets(t *testing.T) {
+	server, _, plain, err := NewTestServer()
+	require.NoError(t, err)
+
+	userUUID := uuid.New().String()
+	expired := time.Now().Add(-time.Minute)
+	actual := time.Now().Add(time.Hour)
+
+	plain.SecretList = append(plain.SecretList,
+		plainstorage.PlainSecret{Metadata: plainstorage.SecretMetadata{UUID: uuid.New().String(), UserUUID: userUUID, Name: "expired", Type: plainstorage.SecretTypeText, ExpiresAt: &expired}},
+		plainstorage.PlainSecret{Metadata: plainstorage.SecretMetadata{UUID: uuid.New().String(), UserUUID: userUUID, Name: "exhausted", Type: plainstorage.SecretTypeText, MaxReads: 1, Reads: 1}},
+		plainstorage.PlainSecret{Metadata: plainstorage.SecretMetadata{UUID: uuid.New().String(), UserUUID: userUUID, Name: "actual", Type: plainstorage.SecretTypeText, ExpiresAt: &actual, MaxReads: 2, Reads: 1}},
+		plainstorage.PlainSecret{Metadata: plainstorage.SecretMetadata{UUID: uuid.New().String(), UserUUID: userUUID, Name: "endless", Type: plainstorage.SecretTypeText}},
+	)
+
+	assert.Equal(t, 2, server.reapExpiredSecrets(context.TODO()))
+	require.Len(t, plain.SecretList, 2)
+	assert.Equal(t, "actual", plain.SecretList[0].Metadata.Name)
+	assert.Equal(t, "endless", plain.SecretList[1].Metadata.Name)
+	assert.Equal(t, 0, server.reapExpiredSecrets(context.TODO()))
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/logger"
+	"github.com/nessai1/gophkeeper/internal/service/atrest"
+	"github.com/nessai1/gophkeeper/internal/service/config"
+	"log"
+	"os"
+)
+
+// RunRotateKEK rewraps data keys of plain secrets and media objects by current key-encryption key of config.
+// To rotate key, set new key as current key and old key as previous key, restart service and run rotate-kek,
+// after that previous key can be removed from config
+func RunRotateKEK() {
+	c, err := config.FetchConfig()
+	if err != nil {
+		log.Fatalf("Cannot fetch config for service: %s", err.Error())
+	}
+
+	if c.Encryption == nil {
+		log.Fatalf("Service config has no encryption keys")
+	}
+
+	l, err := logger.BuildLogger(logger.LevelDev, os.Stdout)
+	if err != nil {
+		log.Fatalf("Cannot build logger: %s", err.Error())
+	}
+
+	keyring, err := atrest.LoadKeyring(*c.Encryption)
+	if err != nil {
+		log.Fatalf("Cannot load encryption keys: %s", err.Error())
+	}
+
+	// rotation works with stored data, so plain storage is not decorated by encryption
+	s, err := buildPlainStorage(c.PlainStorageConfig, l)
+	if err != nil {
+		log.Fatalf("Cannot build plain storage: %s", err.Error())
+	}
+
+	report, err := atrest.Rotate(context.Background(), s, keyring)
+	fmt.Printf("Rewrapped by key %s: %d secrets, %d media objects; %d secrets encrypted, %d skipped as changed during rotation\n",
+		keyring.CurrentID(), report.Secrets, report.Objects, report.EncryptedSecrets, report.Skipped,
+	)
+
+	if err != nil {
+		log.Fatalf("Cannot rotate key-encryption key: %s", err.Error())
+	}
+}
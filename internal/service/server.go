@@ -1,7 +1,9 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"github.com/nessai1/gophkeeper/internal/service/chunkstorage"
 	"github.com/nessai1/gophkeeper/internal/service/config"
 	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
 	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
@@ -11,9 +13,12 @@ import (
 	"log"
 	"net"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/nessai1/gophkeeper/internal/logger"
-	"github.com/nessai1/gophkeeper/internal/service/mediastorage/s3storage"
 
 	pb "github.com/nessai1/gophkeeper/api/proto"
 )
@@ -37,15 +42,13 @@ func Run() {
 		log.Fatalf("Cannot build logger: %s", err.Error())
 	}
 
-	log.Println("Load media storage (s3)")
-	if c.S3Config == nil {
-		log.Fatalf("Cannot find S3 config fields")
-	}
-
-	ms, err := s3storage.NewStorage(*c.S3Config, l)
+	// ctx is cancelled on shutdown signal, background jobs and detached work of server stop by it
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
+	ms, s, err := buildStorages(ctx, c, l)
 	if err != nil {
-		log.Fatalf("Cannot build S3 storage")
+		log.Fatalf("Cannot build storages: %s", err.Error())
 	}
 
 	listen, err := net.Listen("tcp", c.Address)
@@ -53,20 +56,11 @@ func Run() {
 		log.Fatalf("Cannot listen '%s' address for service: %s", c.Address, err)
 	}
 
-	if c.PlainStorageConfig == nil || c.PlainStorageConfig.PSQLStorage == nil {
-		log.Fatalf("No one plain storage configured!")
-	}
-
-	log.Println("Load plain storage (postgres)")
-
-	s, err := plainstorage.NewPSQLPlainStorage(*c.PlainStorageConfig.PSQLStorage, l)
-	if err != nil {
-		log.Fatalf("Cannot build plain psql storage: %s", err.Error())
-	}
-
 	server := Server{
+		ctx:          ctx,
 		mediaStorage: ms,
 		plainStorage: s,
+		chunkStorage: chunkstorage.NewStorage(ms, s, l),
 		logger:       l,
 		config:       c,
 	}
@@ -80,13 +74,31 @@ func Run() {
 		serverOptions = append(serverOptions, grpc.Creds(creds))
 		log.Println("Server runs on TLS")
 	}
+	server.runBackground(func(ctx context.Context) {
+		server.runReaper(ctx, time.Duration(c.ReaperInterval))
+	})
+	if c.Fsck.Interval > 0 {
+		server.runBackground(func(ctx context.Context) {
+			server.runFsck(ctx, c.Fsck)
+		})
+	}
+
 	gRPCServer := grpc.NewServer(serverOptions...)
 	pb.RegisterKeeperServiceServer(gRPCServer, &server)
 
+	go func() {
+		<-ctx.Done()
+		log.Println("Stopping service")
+		gRPCServer.GracefulStop()
+	}()
+
 	log.Printf("Service started at %s", c.Address)
 	if err := gRPCServer.Serve(listen); err != nil {
 		log.Fatalf("Error while run gRPC server: %s", err.Error())
 	}
+
+	server.background.Wait()
+	log.Println("Service stopped")
 }
 
 func buildTLSCredentials(creds *config.TLSCredentials) (credentials.TransportCredentials, error) {
@@ -99,10 +111,37 @@ func buildTLSCredentials(creds *config.TLSCredentials) (credentials.TransportCre
 }
 
 type Server struct {
+	// ctx context of server lifetime, it's cancelled on shutdown. Work detached from requests runs with it
+	ctx context.Context
+	// background jobs of server, shutdown waits for them
+	background sync.WaitGroup
+
 	plainStorage plainstorage.PlainStorage
 	mediaStorage mediastorage.MediaStorage
-	logger       *zap.Logger
-	config       config.Config
+	// chunkStorage keeps chunks of chunked media in mediaStorage
+	chunkStorage *chunkstorage.ChunkStorage
+	// uploadSessions uploads of media that can be continued after failure of stream
+	uploadSessions uploadSessions
+	logger         *zap.Logger
+	config         config.Config
 
 	pb.UnimplementedKeeperServiceServer
 }
+
+// cleanupTimeout max duration of cleanup after failed request, like abort of upload or delete of not saved media
+const cleanupTimeout = 30 * time.Second
+
+// cleanupContext returns context of cleanup after failed request. Cleanup isn't cancelled by gone client,
+// but is bounded by cleanupTimeout and shutdown of server
+func (s *Server) cleanupContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(s.ctx, cleanupTimeout)
+}
+
+// runBackground runs job of server with server context, shutdown waits for job to return
+func (s *Server) runBackground(job func(ctx context.Context)) {
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+		job(s.ctx)
+	}()
+}
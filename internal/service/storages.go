@@ -0,0 +1,247 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/nessai1/gophkeeper/internal/service/atrest"
+	"github.com/nessai1/gophkeeper/internal/service/config"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage/fsstorage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage/mirrorstorage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage/multistorage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage/s3storage"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"go.uber.org/zap"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultMirrorRepairGracePeriod age of objects after that they are copied by mirror repair
+const defaultMirrorRepairGracePeriod = config.Duration(time.Hour)
+
+// mediaStorageDriver builds media storage by driver-specific options of config, background jobs of storage stop when ctx is done
+type mediaStorageDriver func(ctx context.Context, options json.RawMessage, l *zap.Logger) (mediastorage.MediaStorage, error)
+
+// plainStorageDriver builds plain storage by driver-specific options of config
+type plainStorageDriver func(options json.RawMessage, l *zap.Logger) (plainstorage.PlainStorage, error)
+
+// mediaStorageDrivers registry of media storage drivers by names, it's filled by init because multi driver
+// builds its backends by registry
+var mediaStorageDrivers map[string]mediaStorageDriver
+
+var plainStorageDrivers = map[string]plainStorageDriver{
+	config.DriverPostgres: func(options json.RawMessage, l *zap.Logger) (plainstorage.PlainStorage, error) {
+		cfg := config.PSQLPlainStorageConfig{}
+		if err := decodeDriverOptions(options, &cfg); err != nil {
+			return nil, err
+		}
+
+		return plainstorage.NewPSQLPlainStorage(cfg, l)
+	},
+	config.DriverSQLite: func(options json.RawMessage, l *zap.Logger) (plainstorage.PlainStorage, error) {
+		cfg := config.SQLitePlainStorageConfig{}
+		if err := decodeDriverOptions(options, &cfg); err != nil {
+			return nil, err
+		}
+
+		return plainstorage.NewSQLitePlainStorage(cfg, l)
+	},
+	config.DriverMemory: func(_ json.RawMessage, l *zap.Logger) (plainstorage.PlainStorage, error) {
+		l.Warn("Plain storage keeps users and secrets in memory, they are lost on service stop")
+
+		return &plainstorage.MemoryStorage{}, nil
+	},
+}
+
+func init() {
+	mediaStorageDrivers = map[string]mediaStorageDriver{
+		config.DriverS3: func(_ context.Context, options json.RawMessage, l *zap.Logger) (mediastorage.MediaStorage, error) {
+			cfg := config.S3Config{}
+			if err := decodeDriverOptions(options, &cfg); err != nil {
+				return nil, err
+			}
+
+			return s3storage.NewStorage(cfg, l)
+		},
+		config.DriverFilesystem: func(_ context.Context, options json.RawMessage, l *zap.Logger) (mediastorage.MediaStorage, error) {
+			cfg := config.FilesystemConfig{}
+			if err := decodeDriverOptions(options, &cfg); err != nil {
+				return nil, err
+			}
+
+			return fsstorage.NewStorage(cfg, l)
+		},
+		config.DriverMemory: func(_ context.Context, _ json.RawMessage, l *zap.Logger) (mediastorage.MediaStorage, error) {
+			l.Warn("Media storage keeps objects in memory, they are lost on service stop")
+
+			return mediastorage.NewMemoryStorage(), nil
+		},
+		config.DriverMulti:  buildMultiMediaStorage,
+		config.DriverMirror: buildMirrorMediaStorage,
+	}
+}
+
+// buildStorages builds media and plain storages of config, both storages are encrypted at rest if encryption is configured.
+// Background jobs of storages run until ctx is done
+func buildStorages(ctx context.Context, c config.Config, l *zap.Logger) (mediastorage.MediaStorage, plainstorage.PlainStorage, error) {
+	ms, s, err := buildStoredDataStorages(ctx, c, l)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if c.Encryption == nil {
+		return ms, s, nil
+	}
+
+	keyring, err := atrest.LoadKeyring(*c.Encryption)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot load encryption keys: %w", err)
+	}
+
+	log.Printf("Storages are encrypted at rest by key %s", keyring.CurrentID())
+
+	return atrest.NewMediaStorage(ms, s, keyring), atrest.NewPlainStorage(s, keyring), nil
+}
+
+// buildStoredDataStorages builds media and plain storages of config without encryption at rest,
+// they work with data as it's stored, like backup and restore do
+func buildStoredDataStorages(ctx context.Context, c config.Config, l *zap.Logger) (mediastorage.MediaStorage, plainstorage.PlainStorage, error) {
+	ms, err := buildMediaStorage(ctx, c, l)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot build media storage: %w", err)
+	}
+
+	s, err := buildPlainStorage(c.PlainStorageConfig, l)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot build plain storage: %w", err)
+	}
+
+	return ms, s, nil
+}
+
+// buildMediaStorage builds media storage by configured driver
+func buildMediaStorage(ctx context.Context, c config.Config, l *zap.Logger) (mediastorage.MediaStorage, error) {
+	if c.MediaStorage == nil {
+		return nil, fmt.Errorf("no one media storage configured")
+	}
+
+	return buildMediaStorageDriver(ctx, *c.MediaStorage, l)
+}
+
+func buildMediaStorageDriver(ctx context.Context, cfg config.DriverConfig, l *zap.Logger) (mediastorage.MediaStorage, error) {
+	driver, ok := mediaStorageDrivers[cfg.Driver]
+	if !ok {
+		return nil, fmt.Errorf("unknown media storage driver '%s', available drivers: %s", cfg.Driver, driverNames(mediaStorageDrivers))
+	}
+
+	log.Printf("Load media storage (%s)", cfg.Driver)
+
+	ms, err := driver(ctx, cfg.Options, l)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build %s media storage: %w", cfg.Driver, err)
+	}
+
+	return ms, nil
+}
+
+func buildMultiMediaStorage(ctx context.Context, options json.RawMessage, l *zap.Logger) (mediastorage.MediaStorage, error) {
+	cfg := config.MultiStorageConfig{}
+	if err := decodeDriverOptions(options, &cfg); err != nil {
+		return nil, err
+	}
+
+	backends := make([]mediastorage.MediaStorage, len(cfg.Backends))
+	for i, backend := range cfg.Backends {
+		ms, err := buildMediaStorageDriver(ctx, backend, l)
+		if err != nil {
+			return nil, fmt.Errorf("cannot build backend %d: %w", i, err)
+		}
+
+		backends[i] = ms
+	}
+
+	return multistorage.NewStorage(backends)
+}
+
+// buildMirrorMediaStorage builds mirror of two backends and starts its repair if repair interval is set
+func buildMirrorMediaStorage(ctx context.Context, options json.RawMessage, l *zap.Logger) (mediastorage.MediaStorage, error) {
+	cfg := config.MirrorStorageConfig{}
+	if err := decodeDriverOptions(options, &cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.RepairInterval < 0 {
+		return nil, fmt.Errorf("repair interval of mirror can't be negative")
+	}
+
+	if cfg.RepairGracePeriod <= 0 {
+		cfg.RepairGracePeriod = defaultMirrorRepairGracePeriod
+	}
+
+	primary, err := buildMediaStorageDriver(ctx, cfg.Primary, l)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build primary backend: %w", err)
+	}
+
+	secondary, err := buildMediaStorageDriver(ctx, cfg.Secondary, l)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build secondary backend: %w", err)
+	}
+
+	ms := mirrorstorage.NewStorage(primary, secondary, l)
+	if cfg.RepairInterval > 0 {
+		go ms.RunRepair(ctx, time.Duration(cfg.RepairInterval), time.Duration(cfg.RepairGracePeriod))
+	}
+
+	return ms, nil
+}
+
+// buildPlainStorage builds plain storage by configured driver
+func buildPlainStorage(cfg *config.PlainStorageConfig, l *zap.Logger) (plainstorage.PlainStorage, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("no one plain storage configured")
+	}
+
+	driver, ok := plainStorageDrivers[cfg.Driver]
+	if !ok {
+		return nil, fmt.Errorf("unknown plain storage driver '%s', available drivers: %s", cfg.Driver, driverNames(plainStorageDrivers))
+	}
+
+	log.Printf("Load plain storage (%s)", cfg.Driver)
+
+	s, err := driver(cfg.Options, l)
+	if err != nil {
+		return nil, fmt.Errorf("cannot build %s plain storage: %w", cfg.Driver, err)
+	}
+
+	return s, nil
+}
+
+// decodeDriverOptions decodes options of driver to v, unknown options are rejected to catch typos in config
+func decodeDriverOptions(options json.RawMessage, v any) error {
+	if len(options) == 0 {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(options))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("cannot decode driver options: %w", err)
+	}
+
+	return nil
+}
+
+func driverNames[T any](drivers map[string]T) string {
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
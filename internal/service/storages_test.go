@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/nessai1/gophkeeper/internal/service/config"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage/fsstorage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage/mirrorstorage"
+	"github.com/nessai1/gophkeeper/internal/service/mediastorage/multistorage"
+	"github.com/nessai1/gophkeeper/internal/service/plainstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"testing"
+)
+
+func TestBuildMediaStorage(t *testing.T) {
+	multiOptions, err := json.Marshal(config.MultiStorageConfig{Backends: []config.DriverConfig{
+		{Driver: config.DriverMemory},
+		{Driver: config.DriverFilesystem, Options: json.RawMessage(`{"dir": "` + t.TempDir() + `"}`)},
+	}})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		driver   config.DriverConfig
+		expected mediastorage.MediaStorage
+		wantErr  bool
+	}{
+		{
+			name:     "Memory",
+			driver:   config.DriverConfig{Driver: config.DriverMemory},
+			expected: &mediastorage.MemoryStorage{},
+		},
+		{
+			name:     "Filesystem",
+			driver:   config.DriverConfig{Driver: config.DriverFilesystem, Options: json.RawMessage(`{"dir": "` + t.TempDir() + `", "quota": "1MB"}`)},
+			expected: &fsstorage.FSStorage{},
+		},
+		{
+			name:     "Multi",
+			driver:   config.DriverConfig{Driver: config.DriverMulti, Options: multiOptions},
+			expected: &multistorage.MultiStorage{},
+		},
+		{
+			name: "Mirror",
+			driver: config.DriverConfig{Driver: config.DriverMirror, Options: json.RawMessage(`{
+				"primary": {"driver": "memory"},
+				"secondary": {"driver": "memory"}
+			}`)},
+			expected: &mirrorstorage.MirrorStorage{},
+		},
+		{
+			name:    "Mirror without secondary",
+			driver:  config.DriverConfig{Driver: config.DriverMirror, Options: json.RawMessage(`{"primary": {"driver": "memory"}}`)},
+			wantErr: true,
+		},
+		{
+			name:    "Multi without backends",
+			driver:  config.DriverConfig{Driver: config.DriverMulti, Options: json.RawMessage(`{"backends": []}`)},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown option",
+			driver:  config.DriverConfig{Driver: config.DriverFilesystem, Options: json.RawMessage(`{"directory": "media"}`)},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown driver",
+			driver:  config.DriverConfig{Driver: "ftp"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ms, err := buildMediaStorage(context.Background(), config.Config{MediaStorage: &tt.driver}, zap.NewNop())
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.IsType(t, tt.expected, ms)
+		})
+	}
+}
+
+func TestBuildPlainStorage(t *testing.T) {
+	s, err := buildPlainStorage(&config.PlainStorageConfig{DriverConfig: config.DriverConfig{Driver: config.DriverMemory}}, zap.NewNop())
+	require.NoError(t, err)
+	assert.IsType(t, &plainstorage.MemoryStorage{}, s)
+
+	_, err = buildPlainStorage(&config.PlainStorageConfig{DriverConfig: config.DriverConfig{Driver: "mysql"}}, zap.NewNop())
+	assert.Error(t, err)
+}
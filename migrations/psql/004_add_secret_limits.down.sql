@@ -0,0 +1,6 @@
+BEGIN;
+DROP INDEX IF EXISTS secret_metadata_expires_at;
+ALTER TABLE secret_metadata DROP COLUMN IF EXISTS reads;
+ALTER TABLE secret_metadata DROP COLUMN IF EXISTS max_reads;
+ALTER TABLE secret_metadata DROP COLUMN IF EXISTS expires_at;
+COMMIT;
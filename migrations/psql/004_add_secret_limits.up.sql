@@ -0,0 +1,7 @@
+BEGIN;
+ALTER TABLE secret_metadata ADD COLUMN IF NOT EXISTS expires_at timestamptz;
+ALTER TABLE secret_metadata ADD COLUMN IF NOT EXISTS max_reads integer not null default 0;
+ALTER TABLE secret_metadata ADD COLUMN IF NOT EXISTS reads integer not null default 0;
+
+CREATE INDEX IF NOT EXISTS secret_metadata_expires_at ON secret_metadata (expires_at) WHERE expires_at IS NOT NULL;
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+DROP INDEX IF EXISTS secret_tag_tag;
+DROP TABLE IF EXISTS secret_tag;
+DROP INDEX IF EXISTS secret_metadata_owner_uuid_folder;
+ALTER TABLE secret_metadata DROP COLUMN IF EXISTS folder;
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX IF EXISTS secret_metadata_owner_uuid_type_updated;
+DROP INDEX IF EXISTS secret_metadata_owner_uuid_type_created;
+COMMIT;
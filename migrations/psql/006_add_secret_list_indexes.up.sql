@@ -0,0 +1,4 @@
+BEGIN;
+CREATE INDEX IF NOT EXISTS secret_metadata_owner_uuid_type_created ON secret_metadata (owner_uuid, type, created, uuid);
+CREATE INDEX IF NOT EXISTS secret_metadata_owner_uuid_type_updated ON secret_metadata (owner_uuid, type, updated, uuid);
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+DROP TABLE IF EXISTS media_chunk_ref;
+DROP TABLE IF EXISTS media_chunk;
+COMMIT;
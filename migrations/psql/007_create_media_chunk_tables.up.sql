@@ -0,0 +1,21 @@
+BEGIN;
+CREATE TABLE IF NOT EXISTS media_chunk (
+    owner_uuid uuid not null,
+    hash varchar(64) not null,
+    size bigint not null,
+    refs integer not null default 0,
+    created timestamptz not null default now(),
+    PRIMARY KEY (owner_uuid, hash)
+);
+
+CREATE TABLE IF NOT EXISTS media_chunk_ref (
+    media_uuid uuid not null,
+    position integer not null,
+    owner_uuid uuid not null,
+    hash varchar(64) not null,
+    PRIMARY KEY (media_uuid, position),
+    FOREIGN KEY (owner_uuid, hash) REFERENCES media_chunk (owner_uuid, hash)
+);
+
+CREATE INDEX IF NOT EXISTS media_chunk_ref_owner_uuid_hash ON media_chunk_ref (owner_uuid, hash);
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE secret_metadata DROP COLUMN IF EXISTS content_hash;
+COMMIT;
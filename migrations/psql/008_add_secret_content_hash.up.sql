@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE secret_metadata ADD COLUMN IF NOT EXISTS content_hash bytea;
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE secret_metadata DROP COLUMN IF EXISTS encrypted_media_info;
+ALTER TABLE secret_metadata DROP COLUMN IF EXISTS media_size;
+COMMIT;
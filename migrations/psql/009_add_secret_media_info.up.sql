@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE secret_metadata ADD COLUMN IF NOT EXISTS media_size bigint not null default 0;
+ALTER TABLE secret_metadata ADD COLUMN IF NOT EXISTS encrypted_media_info bytea;
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+DROP TABLE IF EXISTS media_object_key;
+COMMIT;
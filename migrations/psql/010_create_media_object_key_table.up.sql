@@ -0,0 +1,7 @@
+BEGIN;
+CREATE TABLE IF NOT EXISTS media_object_key (
+    object_key text not null primary key,
+    kek_id varchar(64) not null,
+    wrapped_key bytea not null
+);
+COMMIT;
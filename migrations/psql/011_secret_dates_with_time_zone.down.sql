@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE secret_metadata ALTER COLUMN updated TYPE timestamp;
+ALTER TABLE secret_metadata ALTER COLUMN created TYPE timestamp;
+COMMIT;
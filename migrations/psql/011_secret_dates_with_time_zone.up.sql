@@ -0,0 +1,5 @@
+BEGIN;
+-- dates without time zone are read in time zone of session, so they are converted by it
+ALTER TABLE secret_metadata ALTER COLUMN created TYPE timestamptz;
+ALTER TABLE secret_metadata ALTER COLUMN updated TYPE timestamptz;
+COMMIT;
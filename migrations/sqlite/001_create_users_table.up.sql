@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS users (
+    uuid text primary key,
+    login varchar(255) not null unique,
+    password varchar(255) not null
+);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS secret_metadata (
+    uuid text not null primary key,
+    owner_uuid text not null,
+    name varchar(255) not null,
+    type smallint not null,
+    created timestamp not null,
+    updated timestamp not null,
+    UNIQUE(owner_uuid, name, type)
+);
+
+CREATE TABLE IF NOT EXISTS plain_secret (
+    uuid text primary key,
+    data blob not null,
+    FOREIGN KEY (uuid) REFERENCES secret_metadata (uuid) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS secret_metadata_owner_uuid ON secret_metadata (owner_uuid);
+CREATE INDEX IF NOT EXISTS secret_metadata_owner_uuid_name_type ON secret_metadata (owner_uuid, name, type);
@@ -0,0 +1 @@
+ALTER TABLE secret_metadata ADD COLUMN encrypted_name blob;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS secret_metadata_expires_at;
+ALTER TABLE secret_metadata DROP COLUMN reads;
+ALTER TABLE secret_metadata DROP COLUMN max_reads;
+ALTER TABLE secret_metadata DROP COLUMN expires_at;
@@ -0,0 +1,5 @@
+ALTER TABLE secret_metadata ADD COLUMN expires_at timestamp;
+ALTER TABLE secret_metadata ADD COLUMN max_reads integer not null default 0;
+ALTER TABLE secret_metadata ADD COLUMN reads integer not null default 0;
+
+CREATE INDEX IF NOT EXISTS secret_metadata_expires_at ON secret_metadata (expires_at) WHERE expires_at IS NOT NULL;
@@ -0,0 +1,13 @@
+ALTER TABLE secret_metadata ADD COLUMN folder varchar(255) not null default '';
+
+CREATE INDEX IF NOT EXISTS secret_metadata_owner_uuid_folder ON secret_metadata (owner_uuid, folder);
+
+CREATE TABLE IF NOT EXISTS secret_tag (
+    secret_uuid text not null,
+    tag varchar(255) not null,
+    encrypted_tag blob,
+    PRIMARY KEY (secret_uuid, tag),
+    FOREIGN KEY (secret_uuid) REFERENCES secret_metadata (uuid) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS secret_tag_tag ON secret_tag (tag);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS media_chunk_ref;
+DROP TABLE IF EXISTS media_chunk;
@@ -0,0 +1 @@
+ALTER TABLE secret_metadata DROP COLUMN content_hash;
@@ -0,0 +1,2 @@
+ALTER TABLE secret_metadata DROP COLUMN encrypted_media_info;
+ALTER TABLE secret_metadata DROP COLUMN media_size;
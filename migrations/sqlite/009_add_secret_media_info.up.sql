@@ -0,0 +1,2 @@
+ALTER TABLE secret_metadata ADD COLUMN media_size integer not null default 0;
+ALTER TABLE secret_metadata ADD COLUMN encrypted_media_info blob;
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS media_object_key (
+    object_key text not null primary key,
+    kek_id varchar(64) not null,
+    wrapped_key blob not null
+);
@@ -0,0 +1,249 @@
+// Package argon2d implements data-dependent variant Argon2d of key derivation function Argon2 version 1.3.
+// golang.org/x/crypto/argon2 has only Argon2i and Argon2id, but Argon2d is default key derivation of KeePass
+// databases. Implementation follows golang.org/x/crypto/argon2 (BSD license) and RFC 9106
+package argon2d
+
+import (
+	"encoding/binary"
+	"golang.org/x/crypto/blake2b"
+	"math/bits"
+)
+
+// Version version of Argon2 implemented by package
+const Version = 0x13
+
+const (
+	blockLength = 128
+	syncPoints  = 4
+	// modeArgon2d type of Argon2 hashed into initial block
+	modeArgon2d = 0
+)
+
+type block [blockLength]uint64
+
+// Key derives key of keyLen bytes from password and salt by Argon2d. Memory is given in KiB, time and threads
+// must be greater than zero
+func Key(password, salt []byte, time, memory uint32, threads uint8, keyLen uint32) []byte {
+	return deriveKey(password, salt, nil, nil, time, memory, threads, keyLen)
+}
+
+func deriveKey(password, salt, secret, data []byte, time, memory uint32, threads uint8, keyLen uint32) []byte {
+	if time < 1 {
+		panic("argon2d: number of rounds too small")
+	}
+	if threads < 1 {
+		panic("argon2d: parallelism degree too low")
+	}
+
+	h0 := initHash(password, salt, secret, data, time, memory, uint32(threads), keyLen)
+
+	memory = memory / (syncPoints * uint32(threads)) * (syncPoints * uint32(threads))
+	if memory < 2*syncPoints*uint32(threads) {
+		memory = 2 * syncPoints * uint32(threads)
+	}
+
+	b := initBlocks(&h0, memory, uint32(threads))
+	processBlocks(b, time, memory, uint32(threads))
+
+	return extractKey(b, memory, uint32(threads), keyLen)
+}
+
+func initHash(password, salt, secret, data []byte, time, memory, threads, keyLen uint32) [blake2b.Size + 8]byte {
+	var h0 [blake2b.Size + 8]byte
+
+	params := make([]byte, 0, 24)
+	for _, v := range []uint32{threads, keyLen, memory, time, Version, modeArgon2d} {
+		params = binary.LittleEndian.AppendUint32(params, v)
+	}
+
+	b2, _ := blake2b.New512(nil)
+	b2.Write(params)
+	for _, v := range [][]byte{password, salt, secret, data} {
+		b2.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(v))))
+		b2.Write(v)
+	}
+	b2.Sum(h0[:0])
+
+	return h0
+}
+
+func initBlocks(h0 *[blake2b.Size + 8]byte, memory, threads uint32) []block {
+	var raw [1024]byte
+	b := make([]block, memory)
+	for lane := uint32(0); lane < threads; lane++ {
+		j := lane * (memory / threads)
+		binary.LittleEndian.PutUint32(h0[blake2b.Size+4:], lane)
+
+		for i := uint32(0); i < 2; i++ {
+			binary.LittleEndian.PutUint32(h0[blake2b.Size:], i)
+			blake2bHash(raw[:], h0[:])
+			for k := range b[j+i] {
+				b[j+i][k] = binary.LittleEndian.Uint64(raw[k*8:])
+			}
+		}
+	}
+
+	return b
+}
+
+// processBlocks fills memory, segments of one slice don't depend on each other, so lanes are processed in turn
+func processBlocks(b []block, time, memory, threads uint32) {
+	lanes := memory / threads
+	segments := lanes / syncPoints
+
+	for n := uint32(0); n < time; n++ {
+		for slice := uint32(0); slice < syncPoints; slice++ {
+			for lane := uint32(0); lane < threads; lane++ {
+				index := uint32(0)
+				if n == 0 && slice == 0 {
+					// the first two blocks of lane are already generated
+					index = 2
+				}
+
+				offset := lane*lanes + slice*segments + index
+				for index < segments {
+					prev := offset - 1
+					if index == 0 && slice == 0 {
+						prev += lanes
+					}
+
+					// Argon2d takes reference block by content of previous block
+					ref := indexAlpha(b[prev][0], lanes, segments, threads, n, slice, lane, index)
+					processBlockXOR(&b[offset], &b[prev], &b[ref])
+					index, offset = index+1, offset+1
+				}
+			}
+		}
+	}
+}
+
+func extractKey(b []block, memory, threads, keyLen uint32) []byte {
+	lanes := memory / threads
+	for lane := uint32(0); lane < threads-1; lane++ {
+		for i, v := range b[(lane*lanes)+lanes-1] {
+			b[memory-1][i] ^= v
+		}
+	}
+
+	var raw [1024]byte
+	for i, v := range b[memory-1] {
+		binary.LittleEndian.PutUint64(raw[i*8:], v)
+	}
+
+	key := make([]byte, keyLen)
+	blake2bHash(key, raw[:])
+
+	return key
+}
+
+func indexAlpha(rand uint64, lanes, segments, threads, n, slice, lane, index uint32) uint32 {
+	refLane := uint32(rand>>32) % threads
+	if n == 0 && slice == 0 {
+		refLane = lane
+	}
+
+	m, s := 3*segments, ((slice+1)%syncPoints)*segments
+	if lane == refLane {
+		m += index
+	}
+
+	if n == 0 {
+		m, s = slice*segments, 0
+		if slice == 0 || lane == refLane {
+			m += index
+		}
+	}
+
+	if index == 0 || lane == refLane {
+		m--
+	}
+
+	p := rand & 0xFFFFFFFF
+	p = (p * p) >> 32
+	p = (p * uint64(m)) >> 32
+
+	return refLane*lanes + uint32((uint64(s)+uint64(m)-(p+1))%uint64(lanes))
+}
+
+// processBlockXOR computes compression function G of in1 and in2 and xors it into out
+func processBlockXOR(out, in1, in2 *block) {
+	var t block
+	for i := range t {
+		t[i] = in1[i] ^ in2[i]
+	}
+
+	// rows of 16 words are permuted first, then columns of pairs of words
+	for i := 0; i < blockLength; i += 16 {
+		permute(&t, i, i+1, i+2, i+3, i+4, i+5, i+6, i+7, i+8, i+9, i+10, i+11, i+12, i+13, i+14, i+15)
+	}
+	for i := 0; i < blockLength/8; i += 2 {
+		permute(&t, i, i+1, 16+i, 16+i+1, 32+i, 32+i+1, 48+i, 48+i+1, 64+i, 64+i+1, 80+i, 80+i+1, 96+i, 96+i+1, 112+i, 112+i+1)
+	}
+
+	for i := range t {
+		out[i] ^= in1[i] ^ in2[i] ^ t[i]
+	}
+}
+
+// permute applies BlaMka round to 16 words of block given by indexes
+func permute(t *block, i ...int) {
+	mix(t, i[0], i[4], i[8], i[12])
+	mix(t, i[1], i[5], i[9], i[13])
+	mix(t, i[2], i[6], i[10], i[14])
+	mix(t, i[3], i[7], i[11], i[15])
+	mix(t, i[0], i[5], i[10], i[15])
+	mix(t, i[1], i[6], i[11], i[12])
+	mix(t, i[2], i[7], i[8], i[13])
+	mix(t, i[3], i[4], i[9], i[14])
+}
+
+func mix(t *block, a, b, c, d int) {
+	t[a] = blaMka(t[a], t[b])
+	t[d] = bits.RotateLeft64(t[d]^t[a], -32)
+	t[c] = blaMka(t[c], t[d])
+	t[b] = bits.RotateLeft64(t[b]^t[c], -24)
+	t[a] = blaMka(t[a], t[b])
+	t[d] = bits.RotateLeft64(t[d]^t[a], -16)
+	t[c] = blaMka(t[c], t[d])
+	t[b] = bits.RotateLeft64(t[b]^t[c], -63)
+}
+
+func blaMka(x, y uint64) uint64 {
+	return x + y + 2*uint64(uint32(x))*uint64(uint32(y))
+}
+
+// blake2bHash computes variable-length hash H' of in and writes it to out
+func blake2bHash(out []byte, in []byte) {
+	outLen := len(out)
+	prefix := binary.LittleEndian.AppendUint32(nil, uint32(outLen))
+
+	if outLen <= blake2b.Size {
+		b2, _ := blake2b.New(outLen, nil)
+		b2.Write(prefix)
+		b2.Write(in)
+		b2.Sum(out[:0])
+
+		return
+	}
+
+	b2, _ := blake2b.New512(nil)
+	b2.Write(prefix)
+	b2.Write(in)
+	buffer := b2.Sum(nil)
+
+	for {
+		copy(out, buffer[:32])
+		out = out[32:]
+		if len(out) <= blake2b.Size {
+			break
+		}
+
+		sum := blake2b.Sum512(buffer)
+		buffer = sum[:]
+	}
+
+	// the last part has size of rest of output
+	last, _ := blake2b.New(len(out), nil)
+	last.Write(buffer)
+	last.Sum(out[:0])
+}
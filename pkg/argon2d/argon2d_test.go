@@ -0,0 +1,21 @@
+package argon2d
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDeriveKey(t *testing.T) {
+	// test vector of Argon2d from RFC 9106, section 5.1
+	key := deriveKey(
+		bytes.Repeat([]byte{0x01}, 32),
+		bytes.Repeat([]byte{0x02}, 16),
+		bytes.Repeat([]byte{0x03}, 8),
+		bytes.Repeat([]byte{0x04}, 12),
+		3, 32, 4, 32,
+	)
+
+	assert.Equal(t, "512b391b6f1162975371d30919734294f868e3be3984f3c1a13a4db9fabe4acb", hex.EncodeToString(key))
+}
@@ -0,0 +1,21 @@
+package bytesize
+
+import (
+	"strconv"
+	"strings"
+)
+
+// String formats size by the biggest unit that fits it with one decimal place, like "512 B" or "1.5 MB"
+func (b ByteSize) String() string {
+	for _, v := range units {
+		if b < v.size || v.size == 1 {
+			continue
+		}
+
+		value := strconv.FormatFloat(float64(b)/float64(v.size), 'f', 1, 64)
+
+		return strings.TrimSuffix(value, ".0") + " " + v.suffix
+	}
+
+	return strconv.FormatInt(int64(b), 10) + " B"
+}
@@ -0,0 +1,27 @@
+package bytesize
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestByteSize_String(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     ByteSize
+		expected string
+	}{
+		{name: "Zero", size: 0, expected: "0 B"},
+		{name: "Bytes", size: 1023, expected: "1023 B"},
+		{name: "Whole unit", size: KB, expected: "1 KB"},
+		{name: "Fraction", size: MB + MB/2, expected: "1.5 MB"},
+		{name: "Rounded", size: 10*GB + 1, expected: "10 GB"},
+		{name: "Negative", size: -5, expected: "-5 B"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.size.String())
+		})
+	}
+}
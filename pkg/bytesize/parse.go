@@ -0,0 +1,51 @@
+package bytesize
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var units = []struct {
+	suffix string
+	size   ByteSize
+}{
+	{"EB", EB},
+	{"PB", PB},
+	{"TB", TB},
+	{"GB", GB},
+	{"MB", MB},
+	{"KB", KB},
+	{"B", 1},
+}
+
+// Parse parses size like "512MB", "10 GB" or "1024" (bytes). Units are binary: 1KB is 1024 bytes
+func Parse(s string) (ByteSize, error) {
+	raw := strings.ToUpper(strings.TrimSpace(s))
+
+	unit := ByteSize(1)
+	for _, v := range units {
+		if strings.HasSuffix(raw, v.suffix) {
+			raw = strings.TrimSpace(strings.TrimSuffix(raw, v.suffix))
+			unit = v.size
+
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size '%s': %w", s, err)
+	}
+
+	if n < 0 {
+		return 0, fmt.Errorf("size '%s' can't be negative", s)
+	}
+
+	if n > math.MaxInt64/int64(unit) {
+		return 0, fmt.Errorf("size '%s' is too big", s)
+	}
+
+	return ByteSize(n) * unit, nil
+}
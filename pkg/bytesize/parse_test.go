@@ -0,0 +1,38 @@
+package bytesize
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		expected ByteSize
+		wantErr  bool
+	}{
+		{name: "Bytes", raw: "1024", expected: KB},
+		{name: "Bytes with unit", raw: "10B", expected: 10},
+		{name: "Megabytes", raw: "512MB", expected: 512 * MB},
+		{name: "Lower case with space", raw: "10 gb", expected: 10 * GB},
+		{name: "Negative", raw: "-1KB", wantErr: true},
+		{name: "Fraction", raw: "1.5GB", wantErr: true},
+		{name: "Unknown unit", raw: "10XB", wantErr: true},
+		{name: "Overflow", raw: "9000EB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size, err := Parse(tt.raw)
+			if tt.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, size)
+		})
+	}
+}
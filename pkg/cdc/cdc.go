@@ -0,0 +1,176 @@
+// Package cdc splits streams to content-defined chunks. Boundaries of chunks depend on content only,
+// so insert or remove of bytes changes only chunks around edited place and other chunks stay the same
+package cdc
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nessai1/gophkeeper/pkg/bytesize"
+	"io"
+	"math/bits"
+)
+
+// windowSize count of last bytes that affect rolling hash, chunks can't be shorter than window
+const windowSize = 64
+
+// Config bounds of chunk sizes in bytes. Chunks of the same content are equal only if they split with equal config
+type Config struct {
+	MinSize int
+	// AvgSize expected size of chunk, rounds down to power of two
+	AvgSize int
+	MaxSize int
+}
+
+// DefaultConfig keeps encrypted chunks under default 4MB limit of gRPC messages
+var DefaultConfig = Config{
+	MinSize: int(256 * bytesize.KB),
+	AvgSize: int(bytesize.MB),
+	MaxSize: int(2 * bytesize.MB),
+}
+
+func (c Config) validate() error {
+	if c.MinSize < windowSize {
+		return fmt.Errorf("min chunk size must be at least %d bytes", windowSize)
+	}
+
+	if c.AvgSize < c.MinSize || c.MaxSize < c.AvgSize {
+		return errors.New("chunk sizes must be ordered as min <= avg <= max")
+	}
+
+	return nil
+}
+
+// Chunker reads stream and splits it by gear rolling hash with normalized chunking (FastCDC):
+// cut points before average size are harder to find and after average size are easier,
+// so sizes of chunks concentrate around average
+type Chunker struct {
+	reader io.Reader
+	config Config
+
+	// maskSmall used before average size, has more bits than maskLarge used after it
+	maskSmall uint64
+	maskLarge uint64
+
+	buf   []byte
+	start int
+	end   int
+	eof   bool
+}
+
+func NewChunker(reader io.Reader, config Config) (*Chunker, error) {
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid chunker config: %w", err)
+	}
+
+	avgBits := bits.Len(uint(config.AvgSize)) - 1
+
+	return &Chunker{
+		reader:    reader,
+		config:    config,
+		maskSmall: topBitsMask(avgBits + 1),
+		maskLarge: topBitsMask(avgBits - 1),
+		buf:       make([]byte, 2*config.MaxSize),
+	}, nil
+}
+
+// topBitsMask returns mask of n high bits: high bits of gear hash depend on all bytes of window
+func topBitsMask(n int) uint64 {
+	if n <= 0 {
+		return 0
+	}
+
+	return ^uint64(0) << (64 - n)
+}
+
+// Next returns next chunk of stream or io.EOF after last chunk. Chunk is valid until next call
+func (c *Chunker) Next() ([]byte, error) {
+	if c.end-c.start < c.config.MaxSize && !c.eof {
+		if err := c.fill(); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.start == c.end {
+		return nil, io.EOF
+	}
+
+	size := c.cutPoint(c.buf[c.start:c.end])
+	chunk := c.buf[c.start : c.start+size]
+	c.start += size
+
+	return chunk, nil
+}
+
+// fill moves unread bytes to start of buffer and reads stream until buffer is full or stream ends
+func (c *Chunker) fill() error {
+	c.end = copy(c.buf, c.buf[c.start:c.end])
+	c.start = 0
+
+	n, err := io.ReadFull(c.reader, c.buf[c.end:])
+	c.end += n
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		c.eof = true
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("cannot read stream for chunking: %w", err)
+	}
+
+	return nil
+}
+
+// cutPoint returns size of chunk at start of data
+func (c *Chunker) cutPoint(data []byte) int {
+	n := len(data)
+	if n <= c.config.MinSize {
+		return n
+	}
+
+	if n > c.config.MaxSize {
+		n = c.config.MaxSize
+	}
+
+	normal := min(c.config.AvgSize, n)
+
+	var hash uint64
+	i := c.config.MinSize - windowSize
+	for ; i < c.config.MinSize; i++ {
+		hash = hash<<1 + gear[data[i]]
+	}
+
+	for ; i < normal; i++ {
+		hash = hash<<1 + gear[data[i]]
+		if hash&c.maskSmall == 0 {
+			return i + 1
+		}
+	}
+
+	for ; i < n; i++ {
+		hash = hash<<1 + gear[data[i]]
+		if hash&c.maskLarge == 0 {
+			return i + 1
+		}
+	}
+
+	return n
+}
+
+// gear random values of bytes for rolling hash. Table is generated by fixed seed and must never change:
+// other values move boundaries of all chunks and break deduplication of already stored content
+var gear = buildGearTable(0x6b65657065726364)
+
+// buildGearTable generates table by splitmix64
+func buildGearTable(seed uint64) [256]uint64 {
+	var table [256]uint64
+	for i := range table {
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+		z = (z ^ z>>27) * 0x94d049bb133111eb
+		table[i] = z ^ z>>31
+	}
+
+	return table
+}
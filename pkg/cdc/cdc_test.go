@@ -0,0 +1,123 @@
+package cdc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+var testConfig = Config{MinSize: 256, AvgSize: 1024, MaxSize: 4096}
+
+func splitChunks(t *testing.T, content []byte, config Config) [][]byte {
+	chunker, err := NewChunker(bytes.NewReader(content), config)
+	require.NoError(t, err)
+
+	chunks := make([][]byte, 0)
+	for {
+		chunk, err := chunker.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		chunks = append(chunks, bytes.Clone(chunk))
+	}
+
+	return chunks
+}
+
+func randomContent(seed int64, size int) []byte {
+	content := make([]byte, size)
+	rand.New(rand.NewSource(seed)).Read(content)
+
+	return content
+}
+
+func TestChunker_Sizes(t *testing.T) {
+	content := randomContent(1, 512*1024)
+	chunks := splitChunks(t, content, testConfig)
+
+	assert.Equal(t, content, bytes.Join(chunks, nil))
+	for i, v := range chunks {
+		assert.LessOrEqual(t, len(v), testConfig.MaxSize)
+		if i != len(chunks)-1 {
+			assert.GreaterOrEqual(t, len(v), testConfig.MinSize)
+		}
+	}
+
+	avg := len(content) / len(chunks)
+	assert.InDelta(t, testConfig.AvgSize, avg, float64(testConfig.AvgSize)/2, "sizes of chunks must be around average")
+}
+
+func TestChunker_Empty(t *testing.T) {
+	assert.Empty(t, splitChunks(t, nil, testConfig))
+	assert.Equal(t, [][]byte{[]byte("short")}, splitChunks(t, []byte("short"), testConfig))
+}
+
+func TestChunker_ShiftResistance(t *testing.T) {
+	content := randomContent(2, 256*1024)
+	edited := append(append(bytes.Clone(content[:1000]), []byte("inserted bytes")...), content[1000:]...)
+
+	hashes := make(map[[32]byte]struct{})
+	for _, v := range splitChunks(t, content, testConfig) {
+		hashes[sha256.Sum256(v)] = struct{}{}
+	}
+
+	editedChunks := splitChunks(t, edited, testConfig)
+	changed := 0
+	for _, v := range editedChunks {
+		if _, ok := hashes[sha256.Sum256(v)]; !ok {
+			changed++
+		}
+	}
+
+	assert.LessOrEqual(t, changed, 3, "insert must change only chunks around edited place")
+}
+
+func TestChunker_Deterministic(t *testing.T) {
+	content := randomContent(3, 128*1024)
+
+	// reader that returns short parts must not change boundaries
+	chunker, err := NewChunker(&shortReader{data: content}, testConfig)
+	require.NoError(t, err)
+
+	expected := splitChunks(t, content, testConfig)
+	for _, v := range expected {
+		chunk, err := chunker.Next()
+		require.NoError(t, err)
+		assert.Equal(t, v, chunk)
+	}
+
+	_, err = chunker.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestNewChunker_InvalidConfig(t *testing.T) {
+	for _, v := range []Config{
+		{MinSize: 16, AvgSize: 1024, MaxSize: 4096},
+		{MinSize: 2048, AvgSize: 1024, MaxSize: 4096},
+		{MinSize: 256, AvgSize: 8192, MaxSize: 4096},
+	} {
+		_, err := NewChunker(bytes.NewReader(nil), v)
+		assert.Error(t, err)
+	}
+}
+
+type shortReader struct {
+	data []byte
+}
+
+func (r *shortReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+
+	n := copy(p[:min(len(p), 100)], r.data)
+	r.data = r.data[n:]
+
+	return n, nil
+}
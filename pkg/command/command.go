@@ -2,11 +2,12 @@ package command
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"golang.org/x/term"
+	"io"
 	"os"
 	"strings"
-	"syscall"
 )
 
 // Command info about command
@@ -17,18 +18,56 @@ type Command struct {
 	Args []string
 }
 
+var (
+	// stdin reader shared by all reads, so lines piped to stdin aren't lost between reads
+	stdin     *bufio.Reader
+	stdinFile *os.File
+
+	// promptOutput writer of prompts, nil for stdout
+	promptOutput io.Writer
+)
+
+// SetPromptOutput sets writer of prompts, prompts are written to stderr when stdout is used for results of command
+func SetPromptOutput(w io.Writer) {
+	promptOutput = w
+}
+
+func prompts() io.Writer {
+	if promptOutput == nil {
+		return os.Stdout
+	}
+
+	return promptOutput
+}
+
+func input() *bufio.Reader {
+	if stdin == nil || stdinFile != os.Stdin {
+		stdin, stdinFile = bufio.NewReader(os.Stdin), os.Stdin
+	}
+
+	return stdin
+}
+
+// readLine reads line of stdin, last line of piped stdin may have no line break
+func readLine() (string, error) {
+	val, err := input().ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && val != "") {
+		return "", err
+	}
+
+	return strings.Trim(val, "\n"), nil
+}
+
 // ReadCommand prompts user to enter a command to input, writes command anchor to output
 func ReadCommand() (*Command, error) {
-	fmt.Print("> ")
+	fmt.Fprint(prompts(), "> ")
 
-	reader := bufio.NewReader(os.Stdin)
-	text, err := reader.ReadString('\n')
+	text, err := readLine()
 	if err != nil {
 		return nil, fmt.Errorf("cannot read command: %w", err)
 	}
 
 	strs := strings.Split(text, " ")
-	strs[len(strs)-1] = strings.Trim(strs[len(strs)-1], "\n")
 
 	return &Command{
 		Name: strings.TrimSpace(strs[0]),
@@ -37,26 +76,38 @@ func ReadCommand() (*Command, error) {
 }
 
 func AskText(prompt string) (string, error) {
-	fmt.Printf("%s: ", prompt)
-	r := bufio.NewReader(os.Stdin)
-	val, err := r.ReadString('\n')
+	fmt.Fprintf(prompts(), "%s: ", prompt)
+
+	val, err := readLine()
 	if err != nil {
 		return "", fmt.Errorf("cannot ask text: %w", err)
 	}
 
-	return strings.Trim(val, "\n"), nil
+	return val, nil
 }
 
+// AskSecret asks secret without echo, secret is read as plain line if stdin isn't terminal
 func AskSecret(prompt string) (string, error) {
-	fmt.Printf("%s: ", prompt)
+	fmt.Fprintf(prompts(), "%s: ", prompt)
 
 	// int convert for windows syscall support
-	secret, err := term.ReadPassword(int(syscall.Stdin))
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		secret, err := readLine()
+		if err != nil {
+			return "", fmt.Errorf("cannot read secret: %w", err)
+		}
+		fmt.Fprintf(prompts(), "\n")
+
+		return secret, nil
+	}
+
+	secret, err := term.ReadPassword(fd)
 
 	if err != nil {
 		return "", fmt.Errorf("cannot read secret: %w", err)
 	}
-	fmt.Printf("\n")
+	fmt.Fprintf(prompts(), "\n")
 
 	return string(secret), err
 }
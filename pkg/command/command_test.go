@@ -93,3 +93,30 @@ func mockStd() (rollback func(), err error) {
 	os.Stdout = mockOut
 	return rollback, nil
 }
+
+func TestAsk(t *testing.T) {
+	rollback, err := mockStd()
+	require.NoError(t, err)
+	defer rollback()
+
+	_, err = os.Stdin.Write([]byte("octocat\nhunter2\nlast line"))
+	require.NoError(t, err)
+
+	_, err = os.Stdin.Seek(0, 0)
+	require.NoError(t, err)
+
+	login, err := AskText("Enter login")
+	require.NoError(t, err)
+	assert.Equal(t, "octocat", login)
+
+	password, err := AskSecret("Enter password")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", password)
+
+	text, err := AskText("Enter text")
+	require.NoError(t, err)
+	assert.Equal(t, "last line", text)
+
+	_, err = AskText("Enter text")
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	scoreMatch       = 1
+	scoreConsecutive = 5
+	scoreWordStart   = 8
+	scoreGap         = -1
+	scoreSubstring   = 50
+	scorePrefix      = 25
+	scoreExact       = 100
+)
+
+// Score ranks how good text matches query: all runes of query must appear in text in the same order (case-insensitive).
+// Returns false if text doesn't match query. Exact matches, prefixes, substrings and matches on word starts ranks higher
+func Score(query, text string) (int, bool) {
+	query = strings.ToLower(query)
+	lowerText := strings.ToLower(text)
+
+	if query == "" {
+		return 0, true
+	}
+
+	score := 0
+	queryPos := 0
+	lastMatch := -1
+	var prev rune
+	for i, r := range []rune(lowerText) {
+		if queryPos == len(query) {
+			break
+		}
+
+		q, size := utf8.DecodeRuneInString(query[queryPos:])
+		if r == q {
+			score += scoreMatch
+			if lastMatch == i-1 {
+				score += scoreConsecutive
+			} else if lastMatch >= 0 {
+				score += scoreGap * (i - lastMatch - 1)
+			}
+
+			if i == 0 || isSeparator(prev) {
+				score += scoreWordStart
+			}
+
+			lastMatch = i
+			queryPos += size
+		}
+
+		prev = r
+	}
+
+	if queryPos != len(query) {
+		return 0, false
+	}
+
+	if lowerText == query {
+		score += scoreExact
+	} else if strings.HasPrefix(lowerText, query) {
+		score += scorePrefix
+	}
+
+	if strings.Contains(lowerText, query) {
+		score += scoreSubstring
+	}
+
+	return score, true
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
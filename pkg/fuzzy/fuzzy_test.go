@@ -0,0 +1,68 @@
+package fuzzy
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		text    string
+		matched bool
+	}{
+		{
+			name:    "Empty query",
+			query:   "",
+			text:    "github",
+			matched: true,
+		},
+		{
+			name:    "Exact match",
+			query:   "github",
+			text:    "GitHub",
+			matched: true,
+		},
+		{
+			name:    "Subsequence match",
+			query:   "ghb",
+			text:    "work/github",
+			matched: true,
+		},
+		{
+			name:    "Wrong order",
+			query:   "bhg",
+			text:    "github",
+			matched: false,
+		},
+		{
+			name:    "Missing rune",
+			query:   "gitlab",
+			text:    "github",
+			matched: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, matched := Score(tt.query, tt.text)
+			assert.Equal(t, tt.matched, matched)
+		})
+	}
+}
+
+func TestScoreRanking(t *testing.T) {
+	ranked := []string{"github", "github-enterprise", "work/github", "mygithubtoken", "git-hub-token", "gxixtxhxuxb"}
+
+	prevScore := 0
+	for i, text := range ranked {
+		score, matched := Score("github", text)
+		assert.True(t, matched, text)
+		if i > 0 {
+			assert.Less(t, score, prevScore, text)
+		}
+
+		prevScore = score
+	}
+}